@@ -0,0 +1,216 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"sort"
+)
+
+var ErrInvalidProofIndex = errors.New("proof index out of range")
+
+// A StoredTree is a Tree which keeps all of its nodes, so that proofs can be
+// created for any leaf, or any set of leaves, after the tree is built. It has
+// the same root and proofs as Tree, at the cost of a memory footprint in
+// O(n) in the number of leaves.
+type StoredTree struct {
+	hash hash.Hash
+
+	// data pushed data
+	data [][]byte
+
+	// levels[0] are the leaf sums, and levels[i+1][j] is the node joining
+	// levels[i][2j] and levels[i][2j+1], or levels[i][2j] if it has no
+	// sibling (an orphan elevated to the next level). The last level is the
+	// root.
+	levels [][][]byte
+}
+
+// NewStoredTree creates a new StoredTree. The provided hash will be used for
+// all hashing operations within the StoredTree.
+func NewStoredTree(h hash.Hash) *StoredTree {
+	return &StoredTree{
+		hash: h,
+	}
+}
+
+// Push adds data to the tree, updating the nodes on the path from the new
+// leaf to the root.
+func (t *StoredTree) Push(data []byte) {
+	t.data = append(t.data, append(data[:0:0], data...))
+	if len(t.levels) == 0 {
+		t.levels = append(t.levels, nil)
+	}
+	t.levels[0] = append(t.levels[0], leafSum(t.hash, data))
+
+	// update the last node of each level
+	for height := 0; len(t.levels[height]) > 1; height++ {
+		if height+1 == len(t.levels) {
+			t.levels = append(t.levels, nil)
+		}
+		level := t.levels[height]
+		last := len(level) - 1
+		var parent []byte
+		if last%2 == 0 {
+			parent = level[last]
+		} else {
+			parent = nodeSum(t.hash, level[last-1], level[last])
+		}
+		if last/2 == len(t.levels[height+1]) {
+			t.levels[height+1] = append(t.levels[height+1], parent)
+		} else {
+			t.levels[height+1][last/2] = parent
+		}
+	}
+}
+
+// Root returns the Merkle root of the data that has been pushed, or nil if
+// the tree is empty.
+func (t *StoredTree) Root() []byte {
+	if len(t.data) == 0 {
+		return nil
+	}
+	root := t.levels[len(t.levels)-1][0]
+	// Return a copy to prevent leaking a pointer to internal data.
+	return append(root[:0:0], root...)
+}
+
+// NumLeaves returns the number of leaves of the tree.
+func (t *StoredTree) NumLeaves() uint64 {
+	return uint64(len(t.data))
+}
+
+// Prove creates a proof that the leaf at proofIndex is an element of the
+// Merkle tree. It is the same proof as Tree.Prove, verified by VerifyProof.
+func (t *StoredTree) Prove(proofIndex uint64) (merkleRoot []byte, proofSet [][]byte, numLeaves uint64, err error) {
+	merkleRoot, proofSet, _, numLeaves, err = t.ProveMulti([]uint64{proofIndex})
+	return
+}
+
+// ProveMulti creates a proof that the leaves at the given indexes are
+// elements of the Merkle tree. Siblings shared by the paths of several
+// leaves, or which can be computed from the proved leaves, appear only once.
+//
+// The proof set starts with the data of the leaves at proofIndexes, the
+// distinct indexes in increasing order, followed by the siblings needed to
+// compute the root, level by level from the leaves and in increasing order of
+// position in each level. It is verified by VerifyMultiProof.
+func (t *StoredTree) ProveMulti(indexes []uint64) (merkleRoot []byte, proofSet [][]byte, proofIndexes []uint64, numLeaves uint64, err error) {
+	numLeaves = t.NumLeaves()
+	proofIndexes = sortedDistinct(indexes)
+	if len(proofIndexes) == 0 || proofIndexes[len(proofIndexes)-1] >= numLeaves {
+		return nil, nil, nil, 0, ErrInvalidProofIndex
+	}
+
+	for _, i := range proofIndexes {
+		proofSet = append(proofSet, append(t.data[i][:0:0], t.data[i]...))
+	}
+
+	positions := proofIndexes
+	for height := 0; height < len(t.levels)-1; height++ {
+		level := t.levels[height]
+		var next []uint64
+		for k := 0; k < len(positions); k++ {
+			p := positions[k]
+			sibling := p ^ 1
+			switch {
+			case sibling >= uint64(len(level)):
+				// orphan, elevated
+			case k+1 < len(positions) && positions[k+1] == sibling:
+				// computed from the next proved node
+				k++
+			default:
+				proofSet = append(proofSet, append(level[sibling][:0:0], level[sibling]...))
+			}
+			next = append(next, p/2)
+		}
+		positions = next
+	}
+
+	return t.Root(), proofSet, proofIndexes, numLeaves, nil
+}
+
+// VerifyMultiProof takes a Merkle root, a proofSet, and strictly increasing
+// proofIndexes, as returned by StoredTree.ProveMulti, and returns true if the
+// first len(proofIndexes) elements of the proof set are the leaves of data at
+// proofIndexes in the Merkle root. False is returned if the proof set or
+// Merkle root is nil, and if 'numLeaves' equals 0.
+func VerifyMultiProof(h hash.Hash, merkleRoot []byte, proofSet [][]byte, proofIndexes []uint64, numLeaves uint64) bool {
+	if merkleRoot == nil || len(proofIndexes) == 0 || len(proofSet) < len(proofIndexes) {
+		return false
+	}
+	for i := range proofIndexes {
+		if proofIndexes[i] >= numLeaves || (i > 0 && proofIndexes[i] <= proofIndexes[i-1]) {
+			return false
+		}
+	}
+
+	// the known nodes of the current level, and their positions
+	positions := proofIndexes
+	sums := make([][]byte, len(proofIndexes))
+	for i := range proofIndexes {
+		sums[i] = leafSum(h, proofSet[i])
+	}
+	next := len(proofIndexes)
+
+	for width := numLeaves; width > 1; width = (width + 1) / 2 {
+		var nextPositions []uint64
+		var nextSums [][]byte
+		for k := 0; k < len(positions); k++ {
+			p := positions[k]
+			sibling := p ^ 1
+			var sum []byte
+			switch {
+			case sibling >= width:
+				// orphan, elevated
+				sum = sums[k]
+			case k+1 < len(positions) && positions[k+1] == sibling:
+				sum = nodeSum(h, sums[k], sums[k+1])
+				k++
+			default:
+				if next >= len(proofSet) {
+					return false
+				}
+				if p%2 == 0 {
+					sum = nodeSum(h, sums[k], proofSet[next])
+				} else {
+					sum = nodeSum(h, proofSet[next], sums[k])
+				}
+				next++
+			}
+			nextPositions = append(nextPositions, p/2)
+			nextSums = append(nextSums, sum)
+		}
+		positions, sums = nextPositions, nextSums
+	}
+
+	return next == len(proofSet) && bytes.Equal(sums[0], merkleRoot)
+}
+
+// sortedDistinct returns the distinct elements of s in increasing order.
+func sortedDistinct(s []uint64) []uint64 {
+	res := append([]uint64{}, s...)
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	n := 0
+	for i := range res {
+		if i == 0 || res[i] != res[i-1] {
+			res[n] = res[i]
+			n++
+		}
+	}
+	return res[:n]
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merkletree
+
+import (
+	"crypto/sha256"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomData(rng *rand.Rand, n int) [][]byte {
+	res := make([][]byte, n)
+	for i := range res {
+		res[i] = make([]byte, 1+rng.Intn(40))
+		rng.Read(res[i])
+	}
+	return res
+}
+
+func TestStoredTree(t *testing.T) {
+	assert := require.New(t)
+	rng := rand.New(rand.NewSource(1)) //#nosec G404 weak rng is fine here
+	h := sha256.New()
+
+	assert.Nil(NewStoredTree(h).Root())
+
+	for _, n := range []int{1, 2, 3, 5, 7, 8, 13, 32, 33} {
+		data := randomData(rng, n)
+		stored := NewStoredTree(h)
+		for i := range data {
+			stored.Push(data[i])
+		}
+
+		// same root and proofs as Tree
+		for index := 0; index < n; index++ {
+			tree := New(h)
+			assert.NoError(tree.SetIndex(uint64(index)))
+			for i := range data {
+				tree.Push(data[i])
+			}
+			root, proofSet, _, numLeaves := tree.Prove()
+
+			sRoot, sProofSet, sNumLeaves, err := stored.Prove(uint64(index))
+			assert.NoError(err)
+			assert.Equal(root, sRoot)
+			assert.Equal(proofSet, sProofSet, "n=%d index=%d", n, index)
+			assert.Equal(numLeaves, sNumLeaves)
+			assert.True(VerifyProof(h, sRoot, sProofSet, uint64(index), sNumLeaves))
+		}
+
+		_, _, _, err := stored.Prove(uint64(n))
+		assert.ErrorIs(err, ErrInvalidProofIndex)
+	}
+}
+
+func TestMultiProof(t *testing.T) {
+	assert := require.New(t)
+	rng := rand.New(rand.NewSource(2)) //#nosec G404 weak rng is fine here
+	h := sha256.New()
+
+	for _, n := range []int{1, 2, 3, 7, 8, 13, 64, 100} {
+		data := randomData(rng, n)
+		stored := NewStoredTree(h)
+		for i := range data {
+			stored.Push(data[i])
+		}
+
+		for _, nbIndexes := range []int{1, 2, 5, n} {
+			indexes := make([]uint64, nbIndexes)
+			for i := range indexes {
+				indexes[i] = uint64(rng.Intn(n))
+			}
+			root, proofSet, proofIndexes, numLeaves, err := stored.ProveMulti(indexes)
+			assert.NoError(err)
+			assert.True(VerifyMultiProof(h, root, proofSet, proofIndexes, numLeaves), "n=%d indexes=%v", n, indexes)
+			for i, index := range proofIndexes {
+				assert.Equal(data[index], proofSet[i])
+			}
+
+			// shared siblings are not repeated
+			nbSiblings := len(proofSet) - len(proofIndexes)
+			if len(proofIndexes) == n {
+				assert.Equal(0, nbSiblings)
+			}
+
+			// wrong data, index or proof set
+			wrong := append([][]byte{}, proofSet...)
+			wrong[0] = append([]byte{1}, wrong[0]...)
+			assert.False(VerifyMultiProof(h, root, wrong, proofIndexes, numLeaves))
+			if nbSiblings > 0 {
+				assert.False(VerifyMultiProof(h, root, proofSet[:len(proofSet)-1], proofIndexes, numLeaves))
+			}
+			assert.False(VerifyMultiProof(h, root, append(proofSet, root), proofIndexes, numLeaves))
+			if proofIndexes[0] > 0 {
+				wrongIndexes := append([]uint64{}, proofIndexes...)
+				wrongIndexes[0]--
+				assert.False(VerifyMultiProof(h, root, proofSet, wrongIndexes, numLeaves))
+			}
+			assert.False(VerifyMultiProof(h, nil, proofSet, proofIndexes, numLeaves))
+			assert.False(VerifyMultiProof(h, root, proofSet, proofIndexes, 0))
+		}
+	}
+
+	// a multiproof is smaller than the individual proofs
+	stored := NewStoredTree(h)
+	for _, d := range randomData(rng, 1024) {
+		stored.Push(d)
+	}
+	_, proofSet, proofIndexes, _, err := stored.ProveMulti([]uint64{0, 1, 2, 3, 512})
+	assert.NoError(err)
+	assert.Equal(5+7+9, len(proofSet)) // levels 2 to 8 for 0..3, 0 to 8 for 512
+	assert.Len(proofIndexes, 5)
+
+	_, _, _, _, err = stored.ProveMulti(nil)
+	assert.ErrorIs(err, ErrInvalidProofIndex)
+}
@@ -104,6 +104,18 @@ func nodeSum(h hash.Hash, a, b []byte) []byte {
 	return sum(h, a, b)
 }
 
+// LeafSum returns the hash of a leaf of data, as computed in a Tree, so that
+// other accumulators can share its domain separation.
+func LeafSum(h hash.Hash, data []byte) []byte {
+	return leafSum(h, data)
+}
+
+// NodeSum returns the hash of a parent of two sibling nodes, as computed in a
+// Tree.
+func NodeSum(h hash.Hash, a, b []byte) []byte {
+	return nodeSum(h, a, b)
+}
+
 // joinSubTrees combines two equal sized subTrees into a larger subTree.
 func joinSubTrees(h hash.Hash, a, b *subTree) *subTree {
 	// if DEBUG {
@@ -0,0 +1,76 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mmr
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// WriteTo writes the binary encoding of the MMR: its size as an 8 bytes
+// big-endian integer, followed by the roots of its perfect subtrees, level by
+// level from the leaves and from left to right.
+func (m *MMR) WriteTo(w io.Writer) (int64, error) {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], m.size)
+	n, err := w.Write(buf[:])
+	written := int64(n)
+	if err != nil {
+		return written, err
+	}
+	for _, level := range m.levels {
+		for _, node := range level {
+			n, err = w.Write(node)
+			written += int64(n)
+			if err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+// ReadFrom decodes MMR data from reader. The MMR must have been created with
+// New, with the hash used to build the encoded MMR.
+func (m *MMR) ReadFrom(r io.Reader) (int64, error) {
+	var buf [8]byte
+	n, err := io.ReadFull(r, buf[:])
+	read := int64(n)
+	if err != nil {
+		return read, err
+	}
+	size := binary.BigEndian.Uint64(buf[:])
+	if size > 1<<48 {
+		return read, errors.New("invalid MMR size")
+	}
+
+	m.size = size
+	m.levels = nil
+	digestSize := m.hash.Size()
+	for h := 0; size>>h > 0; h++ {
+		level := make([][]byte, size>>h)
+		for j := range level {
+			level[j] = make([]byte, digestSize)
+			n, err = io.ReadFull(r, level[j])
+			read += int64(n)
+			if err != nil {
+				return read, err
+			}
+		}
+		m.levels = append(m.levels, level)
+	}
+	return read, nil
+}
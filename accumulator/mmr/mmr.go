@@ -0,0 +1,267 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package mmr provides a Merkle Mountain Range, an append-only log
+// accumulator with inclusion proofs against the roots of past sizes and
+// consistency proofs between two sizes, following RFC 9162 (Certificate
+// Transparency).
+//
+// An MMR of n leaves stores the perfect binary trees of its peaks, one for
+// each bit set in n, largest first. Its root bags the peaks from right to
+// left, which makes it the Merkle tree hash of RFC 9162, and the root of a
+// merkletree.Tree of the same data: leaves and nodes are hashed with
+// merkletree.LeafSum and merkletree.NodeSum.
+package mmr
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+)
+
+var (
+	ErrInvalidIndex = errors.New("leaf index out of range")
+	ErrInvalidSize  = errors.New("invalid tree size")
+)
+
+// MMR Merkle Mountain Range. It stores the roots of all the perfect subtrees,
+// that is O(n) digests for n leaves.
+type MMR struct {
+	hash hash.Hash
+
+	// levels[h][j] root of the perfect subtree of the leaves [j⋅2ʰ, (j+1)⋅2ʰ)
+	levels [][][]byte
+	size   uint64
+}
+
+// New creates an empty MMR. The provided hash will be used for all hashing
+// operations within the MMR.
+func New(h hash.Hash) *MMR {
+	return &MMR{
+		hash: h,
+	}
+}
+
+// Push appends a leaf of data, merging the peaks of equal heights.
+func (m *MMR) Push(data []byte) {
+	m.push(merkletree.LeafSum(m.hash, data))
+}
+
+// push appends a leaf of hash leaf.
+func (m *MMR) push(leaf []byte) {
+	if len(m.levels) == 0 {
+		m.levels = append(m.levels, nil)
+	}
+	m.levels[0] = append(m.levels[0], leaf)
+	for h := 0; len(m.levels[h])%2 == 0; h++ {
+		if h+1 == len(m.levels) {
+			m.levels = append(m.levels, nil)
+		}
+		n := len(m.levels[h])
+		m.levels[h+1] = append(m.levels[h+1], merkletree.NodeSum(m.hash, m.levels[h][n-2], m.levels[h][n-1]))
+	}
+	m.size++
+}
+
+// Size returns the number of leaves.
+func (m *MMR) Size() uint64 {
+	return m.size
+}
+
+// Peaks returns the roots of the perfect subtrees of the MMR, from the largest
+// (leftmost) to the smallest.
+func (m *MMR) Peaks() [][]byte {
+	var res [][]byte
+	for h := len(m.levels) - 1; h >= 0; h-- {
+		if m.size>>h&1 == 1 {
+			peak := m.levels[h][len(m.levels[h])-1]
+			res = append(res, append(peak[:0:0], peak...))
+		}
+	}
+	return res
+}
+
+// Root returns the root of the MMR, bagging its peaks from right to left, or
+// nil if it is empty.
+func (m *MMR) Root() []byte {
+	peaks := m.Peaks()
+	if len(peaks) == 0 {
+		return nil
+	}
+	root := peaks[len(peaks)-1]
+	for i := len(peaks) - 2; i >= 0; i-- {
+		root = merkletree.NodeSum(m.hash, peaks[i], root)
+	}
+	return root
+}
+
+// RootAt returns the root of the MMR when it had size leaves.
+func (m *MMR) RootAt(size uint64) ([]byte, error) {
+	if size == 0 || size > m.size {
+		return nil, ErrInvalidSize
+	}
+	return cloneAll([][]byte{m.subtreeRoot(0, size)})[0], nil
+}
+
+// InclusionProof returns the audit path of the leaf at index in the tree of
+// the first size leaves, as in RFC 9162 §2.1.3.1: the roots of the subtrees
+// needed to compute the root, from the leaf up. Prefixed by the data of the
+// leaf, it is also a proof verified by merkletree.VerifyProof.
+func (m *MMR) InclusionProof(index, size uint64) ([][]byte, error) {
+	if size == 0 || size > m.size {
+		return nil, ErrInvalidSize
+	}
+	if index >= size {
+		return nil, ErrInvalidIndex
+	}
+	return cloneAll(m.path(index, 0, size)), nil
+}
+
+// ConsistencyProof returns a proof that the tree of the first oldSize leaves
+// is a prefix of the tree of the first newSize leaves, as in RFC 9162
+// §2.1.4.1.
+func (m *MMR) ConsistencyProof(oldSize, newSize uint64) ([][]byte, error) {
+	if oldSize == 0 || oldSize > newSize || newSize > m.size {
+		return nil, ErrInvalidSize
+	}
+	return cloneAll(m.subproof(oldSize, 0, newSize, true)), nil
+}
+
+// path returns PATH(index, D[start:end]).
+func (m *MMR) path(index, start, end uint64) [][]byte {
+	if end-start == 1 {
+		return nil
+	}
+	k := splitPoint(end - start)
+	if index < k {
+		return append(m.path(index, start, start+k), m.subtreeRoot(start+k, end))
+	}
+	return append(m.path(index-k, start+k, end), m.subtreeRoot(start, start+k))
+}
+
+// subproof returns SUBPROOF(size, D[start:end], complete).
+func (m *MMR) subproof(size, start, end uint64, complete bool) [][]byte {
+	if size == end-start {
+		if complete {
+			return nil
+		}
+		return [][]byte{m.subtreeRoot(start, end)}
+	}
+	k := splitPoint(end - start)
+	if size <= k {
+		return append(m.subproof(size, start, start+k, complete), m.subtreeRoot(start+k, end))
+	}
+	return append(m.subproof(size-k, start+k, end, false), m.subtreeRoot(start, start+k))
+}
+
+// subtreeRoot returns the Merkle tree hash of the leaves [start, end), where
+// start is a multiple of the largest power of two smaller than end - start,
+// as in the recursions of RFC 9162.
+func (m *MMR) subtreeRoot(start, end uint64) []byte {
+	n := end - start
+	if n&(n-1) == 0 {
+		h := bits.TrailingZeros64(n)
+		return m.levels[h][start>>h]
+	}
+	k := splitPoint(n)
+	return merkletree.NodeSum(m.hash, m.subtreeRoot(start, start+k), m.subtreeRoot(start+k, end))
+}
+
+// splitPoint returns the largest power of two smaller than n > 1.
+func splitPoint(n uint64) uint64 {
+	return 1 << (bits.Len64(n-1) - 1)
+}
+
+// VerifyInclusion returns true if proof proves that data is the leaf at index
+// in the tree of size leaves of the given root, following RFC 9162
+// §2.1.3.2.
+func VerifyInclusion(h hash.Hash, root, data []byte, index, size uint64, proof [][]byte) bool {
+	if index >= size || root == nil {
+		return false
+	}
+	fn, sn := index, size-1
+	r := merkletree.LeafSum(h, data)
+	for _, p := range proof {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = merkletree.NodeSum(h, p, r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkletree.NodeSum(h, r, p)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(r, root)
+}
+
+// VerifyConsistency returns true if proof proves that the tree of oldSize
+// leaves of root oldRoot is a prefix of the tree of newSize leaves of root
+// newRoot, following RFC 9162 §2.1.4.2.
+func VerifyConsistency(h hash.Hash, oldRoot, newRoot []byte, oldSize, newSize uint64, proof [][]byte) bool {
+	if oldSize == 0 || oldSize > newSize || oldRoot == nil || newRoot == nil {
+		return false
+	}
+	if oldSize == newSize {
+		return len(proof) == 0 && bytes.Equal(oldRoot, newRoot)
+	}
+	if len(proof) == 0 {
+		return false
+	}
+	if oldSize&(oldSize-1) == 0 {
+		proof = append([][]byte{oldRoot}, proof...)
+	}
+	fn, sn := oldSize-1, newSize-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = merkletree.NodeSum(h, c, fr)
+			sr = merkletree.NodeSum(h, c, sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = merkletree.NodeSum(h, sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(fr, oldRoot) && bytes.Equal(sr, newRoot)
+}
+
+// cloneAll returns a copy of digests, to prevent leaking pointers to internal
+// data.
+func cloneAll(digests [][]byte) [][]byte {
+	res := make([][]byte, len(digests))
+	for i := range digests {
+		res[i] = append(digests[i][:0:0], digests[i]...)
+	}
+	return res
+}
@@ -0,0 +1,191 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mmr
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/stretchr/testify/require"
+)
+
+const testSize = 37
+
+func leafData(i int) []byte {
+	return []byte{byte(i), byte(i >> 8), 42}
+}
+
+func testMMR() *MMR {
+	m := New(sha256.New())
+	for i := 0; i < testSize; i++ {
+		m.Push(leafData(i))
+	}
+	return m
+}
+
+func TestRoot(t *testing.T) {
+	assert := require.New(t)
+
+	h := sha256.New()
+	m := New(h)
+	assert.Nil(m.Root())
+	_, err := m.RootAt(0)
+	assert.ErrorIs(err, ErrInvalidSize)
+
+	// same roots as merkletree.Tree
+	tree := merkletree.New(h)
+	var roots [][]byte
+	for i := 0; i < testSize; i++ {
+		m.Push(leafData(i))
+		tree.Push(leafData(i))
+		assert.Equal(tree.Root(), m.Root(), "size=%d", i+1)
+		assert.Len(m.Peaks(), bitCount(i+1))
+		roots = append(roots, m.Root())
+	}
+
+	// historical roots
+	for size := 1; size <= testSize; size++ {
+		root, err := m.RootAt(uint64(size))
+		assert.NoError(err)
+		assert.Equal(roots[size-1], root)
+	}
+	_, err = m.RootAt(testSize + 1)
+	assert.ErrorIs(err, ErrInvalidSize)
+}
+
+func bitCount(n int) int {
+	res := 0
+	for ; n > 0; n >>= 1 {
+		res += n & 1
+	}
+	return res
+}
+
+func TestInclusionProof(t *testing.T) {
+	assert := require.New(t)
+
+	h := sha256.New()
+	m := testMMR()
+	for size := uint64(1); size <= testSize; size++ {
+		root, err := m.RootAt(size)
+		assert.NoError(err)
+		for index := uint64(0); index < size; index++ {
+			proof, err := m.InclusionProof(index, size)
+			assert.NoError(err)
+			data := leafData(int(index))
+			assert.True(VerifyInclusion(h, root, data, index, size, proof), "index=%d size=%d", index, size)
+
+			// compatible with merkletree proofs
+			proofSet := append([][]byte{data}, proof...)
+			assert.True(merkletree.VerifyProof(h, root, proofSet, index, size))
+
+			// wrong data, index, size or proof
+			assert.False(VerifyInclusion(h, root, leafData(int(index)+1), index, size, proof))
+			if size > 1 {
+				assert.False(VerifyInclusion(h, root, data, (index+1)%size, size, proof))
+				assert.False(VerifyInclusion(h, root, data, index, size, proof[1:]))
+			}
+			assert.False(VerifyInclusion(h, root, data, index, size, append(proof, root)))
+		}
+	}
+
+	_, err := m.InclusionProof(5, 5)
+	assert.ErrorIs(err, ErrInvalidIndex)
+	_, err = m.InclusionProof(0, testSize+1)
+	assert.ErrorIs(err, ErrInvalidSize)
+}
+
+func TestConsistencyProof(t *testing.T) {
+	assert := require.New(t)
+
+	h := sha256.New()
+	m := testMMR()
+	for newSize := uint64(1); newSize <= testSize; newSize++ {
+		newRoot, err := m.RootAt(newSize)
+		assert.NoError(err)
+		for oldSize := uint64(1); oldSize <= newSize; oldSize++ {
+			oldRoot, err := m.RootAt(oldSize)
+			assert.NoError(err)
+			proof, err := m.ConsistencyProof(oldSize, newSize)
+			assert.NoError(err)
+			assert.True(VerifyConsistency(h, oldRoot, newRoot, oldSize, newSize, proof), "old=%d new=%d", oldSize, newSize)
+
+			// wrong roots, sizes or proof
+			if oldSize == newSize {
+				assert.Empty(proof)
+				continue
+			}
+			assert.False(VerifyConsistency(h, newRoot, newRoot, oldSize, newSize, proof))
+			assert.False(VerifyConsistency(h, oldRoot, oldRoot, oldSize, newSize, proof))
+			assert.False(VerifyConsistency(h, oldRoot, newRoot, oldSize, newSize, proof[:len(proof)-1]))
+			assert.False(VerifyConsistency(h, oldRoot, newRoot, oldSize, newSize, append(proof, oldRoot)))
+		}
+	}
+
+	// a different history is not consistent
+	other := New(h)
+	for i := 0; i < 20; i++ {
+		other.Push(leafData(i + 1))
+	}
+	oldRoot := other.Root()
+	newRoot, err := m.RootAt(30)
+	assert.NoError(err)
+	proof, err := m.ConsistencyProof(20, 30)
+	assert.NoError(err)
+	assert.False(VerifyConsistency(h, oldRoot, newRoot, 20, 30, proof))
+
+	_, err = m.ConsistencyProof(0, 3)
+	assert.ErrorIs(err, ErrInvalidSize)
+	_, err = m.ConsistencyProof(4, 3)
+	assert.ErrorIs(err, ErrInvalidSize)
+}
+
+func TestSerialization(t *testing.T) {
+	assert := require.New(t)
+
+	for _, size := range []int{0, 1, 2, 7, testSize} {
+		m := New(sha256.New())
+		for i := 0; i < size; i++ {
+			m.Push(leafData(i))
+		}
+		var buf bytes.Buffer
+		written, err := m.WriteTo(&buf)
+		assert.NoError(err)
+
+		decoded := New(sha256.New())
+		read, err := decoded.ReadFrom(&buf)
+		assert.NoError(err)
+		assert.Equal(written, read)
+		assert.Equal(m.size, decoded.size)
+		assert.Equal(m.Root(), decoded.Root())
+
+		// the decoded MMR can be extended and proves the same
+		m.Push(leafData(size))
+		decoded.Push(leafData(size))
+		assert.Equal(m.levels, decoded.levels)
+	}
+}
+
+func BenchmarkPush(b *testing.B) {
+	m := New(sha256.New())
+	data := leafData(0)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Push(data)
+	}
+}
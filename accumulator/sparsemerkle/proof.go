@@ -0,0 +1,144 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparsemerkle
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"math/bits"
+)
+
+var ErrInvalidProof = errors.New("invalid sparse Merkle proof")
+
+// Proof Merkle proof of the value of a key, or of its absence.
+type Proof struct {
+	// Siblings roots of the sibling subtrees on the path of the key, from
+	// the leaf level (height 0) up
+	Siblings [Depth][]byte
+}
+
+// CompressedProof Proof where the roots of empty sibling subtrees are
+// omitted.
+type CompressedProof struct {
+	// Bitmap bit i (bit i%8 of byte i/8) is set if the sibling subtree of
+	// height i is not empty
+	Bitmap [Depth / 8]byte
+
+	// Siblings roots of the non-empty sibling subtrees, from the leaf level
+	// up
+	Siblings [][]byte
+}
+
+// Prove returns a proof of the value of key, which is a proof of
+// non-membership if the key is not set.
+func (t *Tree) Prove(key []byte) (Proof, error) {
+	k, err := toKey(key)
+	if err != nil {
+		return Proof{}, err
+	}
+	var proof Proof
+	for level := range proof.Siblings {
+		proof.Siblings[level] = cloneBytes(t.node(level, flipBit(&k, level)))
+	}
+	return proof, nil
+}
+
+// ProveCompressed returns the compressed proof of the value of key.
+func (t *Tree) ProveCompressed(key []byte) (CompressedProof, error) {
+	proof, err := t.Prove(key)
+	if err != nil {
+		return CompressedProof{}, err
+	}
+	return proof.compress(&t.defaults), nil
+}
+
+// VerifyProof returns true if proof proves that key has the given value in
+// the tree of the given root. A nil value verifies that the key is not set.
+func VerifyProof(h hash.Hash, root, key, value []byte, proof *Proof) bool {
+	k, err := toKey(key)
+	if err != nil || root == nil {
+		return false
+	}
+	var current []byte
+	if value == nil {
+		current = make([]byte, h.Size())
+	} else if current, err = sum(h, value); err != nil {
+		return false
+	}
+	for level := range proof.Siblings {
+		if bit(&k, level) == 0 {
+			current, err = sum(h, current, proof.Siblings[level])
+		} else {
+			current, err = sum(h, proof.Siblings[level], current)
+		}
+		if err != nil {
+			return false
+		}
+	}
+	return bytes.Equal(current, root)
+}
+
+// VerifyCompressedProof is VerifyProof for a compressed proof.
+func VerifyCompressedProof(h hash.Hash, root, key, value []byte, proof *CompressedProof) bool {
+	p, err := proof.Decompress(h)
+	if err != nil {
+		return false
+	}
+	return VerifyProof(h, root, key, value, &p)
+}
+
+// Compress returns proof without the roots of empty subtrees, which depend
+// on the hash.
+func (proof *Proof) Compress(h hash.Hash) CompressedProof {
+	defaults := defaultHashes(h)
+	return proof.compress(&defaults)
+}
+
+func (proof *Proof) compress(defaults *[Depth + 1][]byte) CompressedProof {
+	var res CompressedProof
+	for level, sibling := range proof.Siblings {
+		if !bytes.Equal(sibling, defaults[level]) {
+			res.Bitmap[level/8] |= 1 << (level % 8)
+			res.Siblings = append(res.Siblings, cloneBytes(sibling))
+		}
+	}
+	return res
+}
+
+// Decompress returns the full proof, with the roots of empty subtrees for
+// the hash.
+func (proof *CompressedProof) Decompress(h hash.Hash) (Proof, error) {
+	nbSiblings := 0
+	for _, b := range proof.Bitmap {
+		nbSiblings += bits.OnesCount8(b)
+	}
+	if nbSiblings != len(proof.Siblings) {
+		return Proof{}, ErrInvalidProof
+	}
+
+	defaults := defaultHashes(h)
+	var res Proof
+	next := 0
+	for level := range res.Siblings {
+		if proof.Bitmap[level/8]>>(level%8)&1 == 1 {
+			res.Siblings[level] = cloneBytes(proof.Siblings[next])
+			next++
+		} else {
+			res.Siblings[level] = defaults[level]
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,123 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparsemerkle
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProof(t *testing.T) {
+	for _, th := range testHashes {
+		t.Run(th.name, func(t *testing.T) {
+			assert := require.New(t)
+			rng := rand.New(rand.NewSource(2)) //#nosec G404 weak rng is fine here
+			h := th.new()
+
+			tree := New(h)
+			keys := make([][]byte, 10)
+			values := make([][]byte, len(keys))
+			for i := range keys {
+				keys[i] = randomKey(rng)
+				values[i] = th.values(rng)
+				assert.NoError(tree.Set(keys[i], values[i]))
+			}
+			root := tree.Root()
+			absent := randomKey(rng)
+			neighbour := append([]byte{}, keys[0]...)
+			neighbour[KeySize-1] ^= 1
+
+			// membership
+			for i := range keys {
+				proof, err := tree.Prove(keys[i])
+				assert.NoError(err)
+				assert.True(VerifyProof(h, root, keys[i], values[i], &proof))
+				assert.False(VerifyProof(h, root, keys[i], nil, &proof))
+				assert.False(VerifyProof(h, root, keys[(i+1)%len(keys)], values[i], &proof))
+				assert.False(VerifyProof(h, root, keys[i], values[(i+1)%len(keys)], &proof))
+
+				compressed, err := tree.ProveCompressed(keys[i])
+				assert.NoError(err)
+				assert.Equal(compressed, proof.Compress(h))
+				assert.Less(len(compressed.Siblings), 16)
+				assert.True(VerifyCompressedProof(h, root, keys[i], values[i], &compressed))
+				decompressed, err := compressed.Decompress(h)
+				assert.NoError(err)
+				assert.Equal(proof, decompressed)
+			}
+
+			// non-membership
+			for _, key := range [][]byte{absent, neighbour} {
+				proof, err := tree.Prove(key)
+				assert.NoError(err)
+				assert.True(VerifyProof(h, root, key, nil, &proof))
+				assert.False(VerifyProof(h, root, key, values[0], &proof))
+
+				compressed, err := tree.ProveCompressed(key)
+				assert.NoError(err)
+				assert.True(VerifyCompressedProof(h, root, key, nil, &compressed))
+			}
+
+			// proofs of an updated tree
+			proof, err := tree.Prove(keys[0])
+			assert.NoError(err)
+			assert.NoError(tree.Delete(keys[0]))
+			assert.False(VerifyProof(h, tree.Root(), keys[0], values[0], &proof))
+			proof, err = tree.Prove(keys[0])
+			assert.NoError(err)
+			assert.True(VerifyProof(h, tree.Root(), keys[0], nil, &proof))
+
+			// tampered proofs
+			compressed, err := tree.ProveCompressed(keys[1])
+			assert.NoError(err)
+			tampered := compressed
+			tampered.Siblings = tampered.Siblings[1:]
+			assert.False(VerifyCompressedProof(h, tree.Root(), keys[1], values[1], &tampered))
+			tampered = compressed
+			tampered.Bitmap[20] ^= 1
+			assert.False(VerifyCompressedProof(h, tree.Root(), keys[1], values[1], &tampered))
+			assert.False(VerifyProof(h, nil, keys[1], values[1], &proof))
+			assert.False(VerifyProof(h, tree.Root(), keys[1][1:], values[1], &proof))
+			_, err = tree.Prove(keys[1][1:])
+			assert.ErrorIs(err, ErrInvalidKeySize)
+		})
+	}
+}
+
+func BenchmarkVerifyProof(b *testing.B) {
+	for _, th := range testHashes {
+		b.Run(th.name, func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1)) //#nosec G404 weak rng is fine here
+			h := th.new()
+			tree := New(h)
+			key, value := randomKey(rng), th.values(rng)
+			if err := tree.Set(key, value); err != nil {
+				b.Fatal(err)
+			}
+			root := tree.Root()
+			proof, err := tree.Prove(key)
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = VerifyProof(h, root, key, value, &proof)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package sparsemerkle provides a fixed-depth sparse Merkle tree keyed by
+// 256-bit keys, with membership and non-membership proofs.
+//
+// The tree has 2²⁵⁶ leaves, the leaf of a key being at the position given by
+// its bits, most significant first from the root. A leaf is Hash(value) if the
+// key is set and a zero digest otherwise, and a node is Hash(left ∥ right).
+// Empty subtrees are never stored: their roots only depend on their height
+// and are computed once.
+//
+// Hashes are only fed digests and values, so that any hash.Hash can be used,
+// including the MiMC hashes of the hash package, for which values must be
+// encodings of field elements.
+package sparsemerkle
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+)
+
+const (
+	// KeySize size in bytes of the keys
+	KeySize = 32
+
+	// Depth number of levels of the tree below the root
+	Depth = 8 * KeySize
+)
+
+var (
+	ErrInvalidKeySize = errors.New("invalid key size")
+	ErrNilValue       = errors.New("nil value, use Delete to remove a key")
+)
+
+// Tree sparse Merkle tree. Only the values and the nodes of non-empty
+// subtrees are stored.
+type Tree struct {
+	hash hash.Hash
+
+	// defaults[l] root of an empty subtree of height l
+	defaults [Depth + 1][]byte
+
+	// nodes roots of the non-empty subtrees, by nodeID
+	nodes map[string][]byte
+
+	values map[[KeySize]byte][]byte
+}
+
+// New returns an empty tree. The provided hash is used for all hashing
+// operations within the Tree.
+func New(h hash.Hash) *Tree {
+	t := &Tree{
+		hash:   h,
+		nodes:  make(map[string][]byte),
+		values: make(map[[KeySize]byte][]byte),
+	}
+	t.defaults = defaultHashes(h)
+	return t
+}
+
+// Root returns the Merkle root of the tree.
+func (t *Tree) Root() []byte {
+	return cloneBytes(t.node(Depth, &[KeySize]byte{}))
+}
+
+// Get returns the value of key, or nil if it is not set.
+func (t *Tree) Get(key []byte) ([]byte, error) {
+	k, err := toKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return cloneBytes(t.values[k]), nil
+}
+
+// Set sets the value of key. The value must be a valid input of the hash.
+func (t *Tree) Set(key, value []byte) error {
+	k, err := toKey(key)
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		return ErrNilValue
+	}
+	leaf, err := sum(t.hash, value)
+	if err != nil {
+		return err
+	}
+	if err := t.update(&k, leaf); err != nil {
+		return err
+	}
+	t.values[k] = cloneBytes(value)
+	return nil
+}
+
+// Delete unsets key. It does nothing if the key is not set.
+func (t *Tree) Delete(key []byte) error {
+	k, err := toKey(key)
+	if err != nil {
+		return err
+	}
+	if _, ok := t.values[k]; !ok {
+		return nil
+	}
+	if err := t.update(&k, t.defaults[0]); err != nil {
+		return err
+	}
+	delete(t.values, k)
+	return nil
+}
+
+// update sets the leaf of key and recomputes the nodes on its path.
+func (t *Tree) update(key *[KeySize]byte, leaf []byte) error {
+	current := leaf
+	t.setNode(0, key, current)
+	for level := 0; level < Depth; level++ {
+		sibling := t.node(level, flipBit(key, level))
+		var err error
+		if bit(key, level) == 0 {
+			current, err = sum(t.hash, current, sibling)
+		} else {
+			current, err = sum(t.hash, sibling, current)
+		}
+		if err != nil {
+			return err
+		}
+		t.setNode(level+1, key, current)
+	}
+	return nil
+}
+
+// node returns the root of the subtree of height level containing key.
+func (t *Tree) node(level int, key *[KeySize]byte) []byte {
+	if n, ok := t.nodes[nodeID(level, key)]; ok {
+		return n
+	}
+	return t.defaults[level]
+}
+
+func (t *Tree) setNode(level int, key *[KeySize]byte, n []byte) {
+	id := nodeID(level, key)
+	if bytes.Equal(n, t.defaults[level]) {
+		delete(t.nodes, id)
+	} else {
+		t.nodes[id] = n
+	}
+}
+
+// defaultHashes returns the roots of empty subtrees of each height.
+func defaultHashes(h hash.Hash) [Depth + 1][]byte {
+	var res [Depth + 1][]byte
+	res[0] = make([]byte, h.Size())
+	for level := 1; level <= Depth; level++ {
+		var err error
+		if res[level], err = sum(h, res[level-1], res[level-1]); err != nil {
+			panic(err) // digests are valid inputs of the hash
+		}
+	}
+	return res
+}
+
+// nodeID identifies the subtree of height level containing key: the level
+// followed by the key with its level least significant bits cleared.
+func nodeID(level int, key *[KeySize]byte) string {
+	var id [2 + KeySize]byte
+	id[0], id[1] = byte(level>>8), byte(level)
+	copy(id[2:], key[:])
+	for i := 0; i < level/8; i++ {
+		id[2+KeySize-1-i] = 0
+	}
+	if level < Depth {
+		id[2+KeySize-1-level/8] &^= 1<<(level%8) - 1
+	}
+	return string(id[:])
+}
+
+// bit returns the bit of key which chooses between the children of the
+// nodes at height level+1, the level-th least significant one.
+func bit(key *[KeySize]byte, level int) byte {
+	return key[KeySize-1-level/8] >> (level % 8) & 1
+}
+
+// flipBit returns key with its level-th least significant bit flipped, a key
+// of the sibling subtree at height level.
+func flipBit(key *[KeySize]byte, level int) *[KeySize]byte {
+	res := *key
+	res[KeySize-1-level/8] ^= 1 << (level % 8)
+	return &res
+}
+
+func toKey(key []byte) ([KeySize]byte, error) {
+	var res [KeySize]byte
+	if len(key) != KeySize {
+		return res, ErrInvalidKeySize
+	}
+	copy(res[:], key)
+	return res, nil
+}
+
+// sum returns the hash of the concatenation of data.
+func sum(h hash.Hash, data ...[]byte) ([]byte, error) {
+	h.Reset()
+	for _, d := range data {
+		if _, err := h.Write(d); err != nil {
+			return nil, err
+		}
+	}
+	return h.Sum(nil), nil
+}
+
+func cloneBytes(b []byte) []byte {
+	if b == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(b)), b...)
+}
@@ -0,0 +1,169 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sparsemerkle
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	gchash "github.com/consensys/gnark-crypto/hash"
+	"github.com/stretchr/testify/require"
+)
+
+// testHashes hashes to test with, and a function returning valid values for
+// each
+var testHashes = []struct {
+	name   string
+	new    func() hash.Hash
+	values func(*rand.Rand) []byte
+}{
+	{"sha256", sha256.New, func(rng *rand.Rand) []byte {
+		res := make([]byte, 1+rng.Intn(64))
+		rng.Read(res)
+		return res
+	}},
+	{"mimc", gchash.MIMC_BN254.New, func(rng *rand.Rand) []byte {
+		var v fr.Element
+		v.SetUint64(rng.Uint64())
+		b := v.Bytes()
+		return b[:]
+	}},
+}
+
+func randomKey(rng *rand.Rand) []byte {
+	res := make([]byte, KeySize)
+	rng.Read(res)
+	return res
+}
+
+func TestTree(t *testing.T) {
+	for _, th := range testHashes {
+		t.Run(th.name, func(t *testing.T) {
+			assert := require.New(t)
+			rng := rand.New(rand.NewSource(1)) //#nosec G404 weak rng is fine here
+
+			tree := New(th.new())
+			emptyRoot := tree.Root()
+
+			keys := make([][]byte, 20)
+			values := make([][]byte, len(keys))
+			for i := range keys {
+				keys[i] = randomKey(rng)
+				values[i] = th.values(rng)
+			}
+			// keys sharing long prefixes
+			keys[1] = append([]byte{}, keys[0]...)
+			keys[1][KeySize-1] ^= 1
+			keys[2] = append([]byte{}, keys[0]...)
+			keys[2][0] ^= 0x80
+
+			for i := range keys {
+				assert.NoError(tree.Set(keys[i], values[i]))
+			}
+			for i := range keys {
+				v, err := tree.Get(keys[i])
+				assert.NoError(err)
+				assert.Equal(values[i], v)
+			}
+			root := tree.Root()
+
+			// the root does not depend on the order of insertion
+			other := New(th.new())
+			for _, i := range rng.Perm(len(keys)) {
+				assert.NoError(other.Set(keys[i], values[i]))
+			}
+			assert.Equal(root, other.Root())
+
+			// update
+			newValue := th.values(rng)
+			assert.NoError(tree.Set(keys[5], newValue))
+			assert.NotEqual(root, tree.Root())
+			assert.NoError(tree.Set(keys[5], values[5]))
+			assert.Equal(root, tree.Root())
+
+			// delete
+			assert.NoError(tree.Delete(randomKey(rng)))
+			assert.Equal(root, tree.Root())
+			for i := range keys {
+				assert.NoError(tree.Delete(keys[i]))
+				v, err := tree.Get(keys[i])
+				assert.NoError(err)
+				assert.Nil(v)
+			}
+			assert.Equal(emptyRoot, tree.Root())
+			assert.Empty(tree.nodes, "empty subtrees must not be stored")
+
+			// invalid inputs
+			assert.ErrorIs(tree.Set(keys[0][1:], values[0]), ErrInvalidKeySize)
+			assert.ErrorIs(tree.Set(keys[0], nil), ErrNilValue)
+			_, err := tree.Get(keys[0][1:])
+			assert.ErrorIs(err, ErrInvalidKeySize)
+			assert.ErrorIs(tree.Delete(keys[0][1:]), ErrInvalidKeySize)
+		})
+	}
+}
+
+func TestRoot(t *testing.T) {
+	assert := require.New(t)
+
+	// the leaf of the zero key is the leftmost one
+	h := sha256.New()
+	tree := New(h)
+	value := []byte("value")
+	assert.NoError(tree.Set(make([]byte, KeySize), value))
+
+	current := sha256.Sum256(value)
+	empty := make([]byte, sha256.Size)
+	for level := 0; level < Depth; level++ {
+		current = sha256.Sum256(append(current[:], empty...))
+		e := sha256.Sum256(append(append([]byte{}, empty...), empty...))
+		empty = e[:]
+	}
+	assert.Equal(current[:], tree.Root())
+	assert.Equal(empty, New(h).Root())
+}
+
+func TestInvalidValue(t *testing.T) {
+	// MiMC only hashes field elements
+	tree := New(gchash.MIMC_BN254.New())
+	root := tree.Root()
+	key := make([]byte, KeySize)
+	require.Error(t, tree.Set(key, bytes.Repeat([]byte{0xff}, fr.Bytes)))
+	require.Equal(t, root, tree.Root())
+}
+
+func BenchmarkSet(b *testing.B) {
+	for _, th := range testHashes {
+		b.Run(th.name, func(b *testing.B) {
+			rng := rand.New(rand.NewSource(1)) //#nosec G404 weak rng is fine here
+			tree := New(th.new())
+			keys := make([][]byte, b.N)
+			values := make([][]byte, b.N)
+			for i := range keys {
+				keys[i] = randomKey(rng)
+				values[i] = th.values(rng)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				_ = tree.Set(keys[i], values[i])
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/ipa"
+)
+
+// maxProofLength bound on the number of elements of the lists of a decoded
+// proof
+const maxProofLength = 1 << 20
+
+// WriteTo writes binary encoding of the Proof: each list is prefixed by its
+// length as a 4 bytes big-endian integer, points are serialized with
+// ipa.PointBytes, followed by the multiproof.
+func (proof *Proof) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	write := func(b []byte) error {
+		m, err := w.Write(b)
+		n += int64(m)
+		return err
+	}
+	writeLength := func(l int) error {
+		var buf [4]byte
+		binary.BigEndian.PutUint32(buf[:], uint32(l))
+		return write(buf[:])
+	}
+
+	if err := writeLength(len(proof.DepthExtensionPresent)); err != nil {
+		return n, err
+	}
+	if err := write(proof.DepthExtensionPresent); err != nil {
+		return n, err
+	}
+	if err := writeLength(len(proof.OtherStems)); err != nil {
+		return n, err
+	}
+	for i := range proof.OtherStems {
+		if err := write(proof.OtherStems[i][:]); err != nil {
+			return n, err
+		}
+	}
+	if err := writeLength(len(proof.Commitments)); err != nil {
+		return n, err
+	}
+	for i := range proof.Commitments {
+		b := ipa.PointBytes(&proof.Commitments[i])
+		if err := write(b[:]); err != nil {
+			return n, err
+		}
+	}
+	m, err := proof.Multiproof.WriteTo(w)
+	return n + m, err
+}
+
+// ReadFrom decodes Proof data from reader.
+func (proof *Proof) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	read := func(b []byte) error {
+		m, err := io.ReadFull(r, b)
+		n += int64(m)
+		return err
+	}
+	readLength := func() (int, error) {
+		var buf [4]byte
+		if err := read(buf[:]); err != nil {
+			return 0, err
+		}
+		l := binary.BigEndian.Uint32(buf[:])
+		if l > maxProofLength {
+			return 0, errors.New("invalid proof length")
+		}
+		return int(l), nil
+	}
+
+	l, err := readLength()
+	if err != nil {
+		return n, err
+	}
+	proof.DepthExtensionPresent = make([]byte, l)
+	if err := read(proof.DepthExtensionPresent); err != nil {
+		return n, err
+	}
+	if l, err = readLength(); err != nil {
+		return n, err
+	}
+	proof.OtherStems = make([][StemSize]byte, l)
+	for i := range proof.OtherStems {
+		if err := read(proof.OtherStems[i][:]); err != nil {
+			return n, err
+		}
+	}
+	if l, err = readLength(); err != nil {
+		return n, err
+	}
+	proof.Commitments = make([]bandersnatch.PointAffine, l)
+	var buf [ipa.PointSize]byte
+	for i := range proof.Commitments {
+		if err := read(buf[:]); err != nil {
+			return n, err
+		}
+		if err := ipa.SetPointBytes(&proof.Commitments[i], buf[:]); err != nil {
+			return n, err
+		}
+	}
+	m, err := proof.Multiproof.ReadFrom(r)
+	return n + m, err
+}
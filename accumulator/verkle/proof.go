@@ -0,0 +1,438 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/ipa"
+)
+
+var (
+	ErrInvalidProof = errors.New("invalid verkle proof")
+	ErrNoKeys       = errors.New("no key to prove")
+)
+
+// extension status of a proved stem, in the low 3 bits of
+// Proof.DepthExtensionPresent
+const (
+	extensionAbsent  = 0 // the path of the stem ends in an empty child
+	extensionOther   = 1 // the path of the stem ends in the leaf of another stem
+	extensionPresent = 2 // the path of the stem ends in its leaf
+)
+
+// transcriptLabel label of the transcript of the multiproof, in the Ethereum
+// Verkle specification
+const transcriptLabel = "vt"
+
+// Proof proof of the values, or absence, of a set of keys in a trie, given
+// its root commitment.
+//
+// implements io.ReaderFrom and io.WriterTo
+type Proof struct {
+	// DepthExtensionPresent for each distinct stem of the proved keys, in
+	// increasing order, depth << 3 | extension status where depth is the
+	// length of the path to the leaf or empty child the stem leads to
+	DepthExtensionPresent []byte
+
+	// OtherStems stems of the leaves the proved stems lead to, when none of
+	// them is a proved stem, in increasing order of path
+	OtherStems [][StemSize]byte
+
+	// Commitments commitments of the nodes on the paths of the proved stems,
+	// the root excluded, and of the suffix commitments C₁ and C₂ of the
+	// opened values, in increasing order of path
+	Commitments []bandersnatch.PointAffine
+
+	// Multiproof aggregated openings of the commitments
+	Multiproof ipa.MultiProof
+}
+
+// Prove returns a proof of the values (or absence) of keys in the trie.
+func (t *Tree) Prove(keys [][]byte) (*Proof, error) {
+	stems, err := groupKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+	t.Commit()
+
+	// walk the paths of the stems
+	nodes := map[string]node{"": t.root}
+	proof := &Proof{DepthExtensionPresent: make([]byte, len(stems))}
+	for i := range stems {
+		s := &stems[i]
+		n := node(t.root)
+		for s.depth = 0; ; s.depth++ {
+			internal, ok := n.(*internalNode)
+			if !ok {
+				break
+			}
+			n = internal.children[s.stem[s.depth]]
+			nodes[string(s.stem[:s.depth+1])] = n
+		}
+		switch leaf := n.(type) {
+		case nil:
+			s.status = extensionAbsent
+		case *leafNode:
+			s.status = extensionOther
+			if leaf.stem == s.stem {
+				s.status = extensionPresent
+			}
+		}
+		proof.DepthExtensionPresent[i] = byte(s.depth<<3) | s.status
+	}
+
+	p, err := newPlan(stems)
+	if err != nil {
+		return nil, err
+	}
+	for _, path := range p.otherPaths {
+		proof.OtherStems = append(proof.OtherStems, nodes[path].(*leafNode).stem)
+	}
+
+	// commitments and committed vectors, by path
+	evaluations := make(map[string][]fr.Element)
+	commitment := func(path string) *bandersnatch.PointAffine {
+		if n, ok := nodes[path]; ok {
+			return n.commitment()
+		}
+		leaf := nodes[path[:len(path)-1]].(*leafNode)
+		return leaf.suffixCommitment(suffixOf(path))
+	}
+	for _, path := range p.commitments {
+		proof.Commitments = append(proof.Commitments, *commitment(path))
+	}
+
+	digests := make([]ipa.Digest, len(p.openings))
+	polynomials := make([][]fr.Element, len(p.openings))
+	points := make([]uint8, len(p.openings))
+	for i, o := range p.openings {
+		if _, ok := evaluations[o.path]; !ok {
+			switch n := nodes[o.path].(type) {
+			case *internalNode:
+				evaluations[o.path] = n.evaluations()
+			case *leafNode:
+				evaluations[o.path] = n.evaluations()
+			default:
+				leaf := nodes[o.path[:len(o.path)-1]].(*leafNode)
+				evaluations[o.path] = leaf.suffixEvaluations(int(suffixOf(o.path)) / (width / 2))
+			}
+		}
+		digests[i] = *commitment(o.path)
+		polynomials[i] = evaluations[o.path]
+		points[i] = o.index
+	}
+
+	proof.Multiproof, err = t.config.MultiOpen(digests, polynomials, points, ipa.NewTranscript(transcriptLabel))
+	if err != nil {
+		return nil, err
+	}
+	return proof, nil
+}
+
+// Verify verifies that proof proves that the keys have the given values in
+// the trie of root commitment root, a nil value meaning that the key is
+// absent.
+func Verify(config *ipa.Config, root *bandersnatch.PointAffine, keys, values [][]byte, proof *Proof) error {
+	if len(values) != len(keys) {
+		return ErrInvalidProof
+	}
+	stems, err := groupKeys(keys)
+	if err != nil {
+		return err
+	}
+	if len(proof.DepthExtensionPresent) != len(stems) {
+		return ErrInvalidProof
+	}
+	for i := range stems {
+		stems[i].depth = int(proof.DepthExtensionPresent[i] >> 3)
+		stems[i].status = proof.DepthExtensionPresent[i] & 7
+		if stems[i].status > extensionPresent {
+			return ErrInvalidProof
+		}
+	}
+	p, err := newPlan(stems)
+	if err != nil {
+		return err
+	}
+	if len(proof.Commitments) != len(p.commitments) || len(proof.OtherStems) != len(p.otherPaths) {
+		return ErrInvalidProof
+	}
+
+	// values of the proved keys
+	keyValues := make(map[[KeySize]byte][]byte, len(keys))
+	for i := range keys {
+		if values[i] != nil && len(values[i]) != ValueSize {
+			return ErrInvalidValueSize
+		}
+		var key [KeySize]byte
+		copy(key[:], keys[i])
+		if v, ok := keyValues[key]; ok && string(v) != string(values[i]) {
+			return ErrInvalidProof
+		}
+		keyValues[key] = values[i]
+	}
+
+	// commitments and stems of the leaves, by path
+	commitments := map[string]*bandersnatch.PointAffine{"": root}
+	for i, path := range p.commitments {
+		commitments[path] = &proof.Commitments[i]
+	}
+	for i, path := range p.otherPaths {
+		if string(proof.OtherStems[i][:len(path)]) != path {
+			return ErrInvalidProof
+		}
+		p.leafStems[path] = proof.OtherStems[i]
+	}
+	for i := range stems {
+		s := &stems[i]
+		if s.status == extensionPresent {
+			continue
+		}
+		if s.status == extensionOther && p.leafStems[string(s.stem[:s.depth])] == s.stem {
+			return ErrInvalidProof
+		}
+
+		// the keys of absent stems are absent
+		var key [KeySize]byte
+		copy(key[:], s.stem[:])
+		for _, suffix := range s.suffixes {
+			key[StemSize] = suffix
+			if keyValues[key] != nil {
+				return ErrInvalidProof
+			}
+		}
+	}
+
+	digests := make([]ipa.Digest, len(p.openings))
+	points := make([]uint8, len(p.openings))
+	evaluations := make([]fr.Element, len(p.openings))
+	for i, o := range p.openings {
+		digests[i] = *commitments[o.path]
+		points[i] = o.index
+		switch kind, ok := p.nodes[o.path]; {
+		case ok && kind == kindInternal:
+			child := o.path + string([]byte{o.index})
+			if p.nodes[child] != kindEmpty {
+				evaluations[i] = ipa.MapToScalarField(commitments[child])
+			}
+		case ok && kind == kindLeaf:
+			switch o.index {
+			case 0:
+				evaluations[i].SetOne()
+			case 1:
+				stem := p.leafStems[o.path]
+				evaluations[i] = stemToScalar(stem[:])
+			default:
+				suffix := byte(o.index-2) * (width / 2)
+				evaluations[i] = ipa.MapToScalarField(commitments[suffixPath(o.path, suffix)])
+			}
+		default:
+			// suffix commitment
+			leafPath := o.path[:len(o.path)-1]
+			var key [KeySize]byte
+			stem := p.leafStems[leafPath]
+			copy(key[:], stem[:])
+			key[StemSize] = suffixOf(o.path) + o.index/2
+			lo, hi := valueToScalars(keyValues[key])
+			evaluations[i] = lo
+			if o.index%2 == 1 {
+				evaluations[i] = hi
+			}
+		}
+	}
+
+	if err := config.MultiVerify(digests, points, evaluations, &proof.Multiproof, ipa.NewTranscript(transcriptLabel)); err != nil {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// provedStem stem of proved keys, with the position it leads to in the trie.
+type provedStem struct {
+	stem     [StemSize]byte
+	suffixes []byte
+	depth    int
+	status   byte
+}
+
+// groupKeys returns the distinct stems of keys in increasing order, with their
+// distinct suffixes.
+func groupKeys(keys [][]byte) ([]provedStem, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoKeys
+	}
+	sorted := make([][]byte, len(keys))
+	for i := range keys {
+		if len(keys[i]) != KeySize {
+			return nil, ErrInvalidKeySize
+		}
+		sorted[i] = keys[i]
+	}
+	sort.Slice(sorted, func(i, j int) bool { return string(sorted[i]) < string(sorted[j]) })
+
+	var res []provedStem
+	for i, key := range sorted {
+		if i > 0 && string(key) == string(sorted[i-1]) {
+			continue
+		}
+		if len(res) == 0 || string(res[len(res)-1].stem[:]) != string(key[:StemSize]) {
+			res = append(res, provedStem{})
+			copy(res[len(res)-1].stem[:], key)
+		}
+		s := &res[len(res)-1]
+		s.suffixes = append(s.suffixes, key[StemSize])
+	}
+	return res, nil
+}
+
+// kinds of nodes on the paths of a proof
+const (
+	kindInternal = iota
+	kindLeaf
+	kindEmpty
+)
+
+// opening of the commitment at path, at index.
+type opening struct {
+	path  string
+	index uint8
+}
+
+// plan lists what a proof of the given stems, leading to the given positions,
+// is made of. Paths are strings of the successive child indexes from the
+// root; the suffix commitment C₁ (resp. C₂) of a leaf at path p is at path
+// p ∥ 0 (resp. p ∥ 1), which is never the path of a node.
+type plan struct {
+	// nodes kinds of the nodes on the paths
+	nodes map[string]int
+
+	// leafStems stems of the leaves, by path
+	leafStems map[string][StemSize]byte
+
+	// commitments paths of the commitments of the proof, in increasing order
+	commitments []string
+
+	// otherPaths paths of the leaves of other stems, in increasing order
+	otherPaths []string
+
+	// openings sorted openings of the multiproof
+	openings []opening
+}
+
+// newPlan returns the plan of a proof, checking that the positions of the
+// stems are consistent.
+func newPlan(stems []provedStem) (*plan, error) {
+	p := &plan{
+		nodes:     make(map[string]int),
+		leafStems: make(map[string][StemSize]byte),
+	}
+	setKind := func(path string, kind int) error {
+		if k, ok := p.nodes[path]; ok && k != kind {
+			return ErrInvalidProof
+		}
+		p.nodes[path] = kind
+		return nil
+	}
+	openings := make(map[opening]struct{})
+	commitments := make(map[string]struct{})
+	others := make(map[string]struct{})
+
+	for _, s := range stems {
+		if s.depth < 1 || s.depth > StemSize {
+			return nil, ErrInvalidProof
+		}
+		for j := 0; j < s.depth; j++ {
+			path := string(s.stem[:j])
+			if err := setKind(path, kindInternal); err != nil {
+				return nil, err
+			}
+			openings[opening{path, s.stem[j]}] = struct{}{}
+			if j > 0 {
+				commitments[path] = struct{}{}
+			}
+		}
+
+		path := string(s.stem[:s.depth])
+		if s.status == extensionAbsent {
+			if err := setKind(path, kindEmpty); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err := setKind(path, kindLeaf); err != nil {
+			return nil, err
+		}
+		commitments[path] = struct{}{}
+		openings[opening{path, 0}] = struct{}{}
+		openings[opening{path, 1}] = struct{}{}
+		if s.status == extensionOther {
+			others[path] = struct{}{}
+			continue
+		}
+
+		// a single stem can be present at a given path
+		if _, ok := p.leafStems[path]; ok {
+			return nil, ErrInvalidProof
+		}
+		p.leafStems[path] = s.stem
+		for _, suffix := range s.suffixes {
+			sp := suffixPath(path, suffix)
+			commitments[sp] = struct{}{}
+			openings[opening{path, 2 + suffix/(width/2)}] = struct{}{}
+			openings[opening{sp, 2 * (suffix % (width / 2))}] = struct{}{}
+			openings[opening{sp, 2*(suffix%(width/2)) + 1}] = struct{}{}
+		}
+	}
+
+	// the stems of leaves where no proved stem is present are in the proof
+	for path := range others {
+		if _, ok := p.leafStems[path]; !ok {
+			p.otherPaths = append(p.otherPaths, path)
+		}
+	}
+	sort.Strings(p.otherPaths)
+
+	for path := range commitments {
+		p.commitments = append(p.commitments, path)
+	}
+	sort.Strings(p.commitments)
+
+	for o := range openings {
+		p.openings = append(p.openings, o)
+	}
+	sort.Slice(p.openings, func(i, j int) bool {
+		if p.openings[i].path != p.openings[j].path {
+			return p.openings[i].path < p.openings[j].path
+		}
+		return p.openings[i].index < p.openings[j].index
+	})
+
+	return p, nil
+}
+
+// suffixPath returns the path of the suffix commitment of suffix, for the
+// leaf at path.
+func suffixPath(path string, suffix byte) string {
+	return path + string([]byte{suffix / (width / 2)})
+}
+
+// suffixOf returns the first suffix of the suffix commitment at path.
+func suffixOf(path string) byte {
+	return path[len(path)-1] * (width / 2)
+}
@@ -0,0 +1,296 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/rand"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/ipa"
+	"github.com/stretchr/testify/require"
+)
+
+// proofInstance returns a trie and keys to prove: present keys, absent keys
+// of present stems, absent keys leading to empty children and to leaves of
+// other stems.
+func proofInstance(t testing.TB) (*Tree, [][]byte) {
+	rng := rand.New(rand.NewSource(5)) //#nosec G404 weak rng is fine here
+	tree := New(testConfig)
+	var present [][]byte
+	for i := 0; i < 16; i++ {
+		key := randomBytes(rng, KeySize)
+		require.NoError(t, tree.Insert(key, randomBytes(rng, ValueSize)))
+		present = append(present, key)
+	}
+
+	// a stem sharing a long prefix with another one
+	deep := append([]byte{}, present[0]...)
+	deep[20] ^= 1
+	require.NoError(t, tree.Insert(deep, randomBytes(rng, ValueSize)))
+
+	keys := [][]byte{present[0], present[3], present[7], deep}
+	// same stem, other suffixes in both halves
+	for _, suffix := range []byte{0, 127, 128, 255} {
+		k := append([]byte{}, present[1]...)
+		k[StemSize] = suffix
+		keys = append(keys, k)
+	}
+	// absent stems: below a leaf of another stem, in an empty child, next
+	// to the deep stems
+	other := append([]byte{}, present[2]...)
+	other[10] ^= 1
+	keys = append(keys, other, randomBytes(rng, KeySize))
+	other = append([]byte{}, present[0]...)
+	other[25] ^= 1
+	keys = append(keys, other)
+	// duplicate
+	keys = append(keys, present[3])
+
+	return tree, keys
+}
+
+func values(t testing.TB, tree *Tree, keys [][]byte) [][]byte {
+	res := make([][]byte, len(keys))
+	for i := range keys {
+		var err error
+		res[i], err = tree.Get(keys[i])
+		require.NoError(t, err)
+	}
+	return res
+}
+
+func TestProof(t *testing.T) {
+	assert := require.New(t)
+
+	tree, keys := proofInstance(t)
+	root := tree.Commit()
+	vals := values(t, tree, keys)
+
+	proof, err := tree.Prove(keys)
+	assert.NoError(err)
+	assert.NoError(Verify(testConfig, &root, keys, vals, proof))
+
+	// each key separately
+	for i := range keys {
+		p, err := tree.Prove(keys[i : i+1])
+		assert.NoError(err)
+		assert.NoError(Verify(testConfig, &root, keys[i:i+1], vals[i:i+1], p))
+	}
+
+	// serialization
+	var buf bytes.Buffer
+	_, err = proof.WriteTo(&buf)
+	assert.NoError(err)
+	encoded := append([]byte{}, buf.Bytes()...)
+	var decoded Proof
+	_, err = decoded.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.NoError(Verify(testConfig, &root, keys, vals, &decoded))
+	_, err = decoded.WriteTo(&buf)
+	assert.NoError(err)
+	assert.Equal(encoded, buf.Bytes())
+
+	// wrong values
+	for i := range keys {
+		wrong := append([][]byte{}, vals...)
+		if wrong[i] == nil {
+			wrong[i] = make([]byte, ValueSize)
+		} else {
+			wrong[i] = nil
+		}
+		assert.ErrorIs(Verify(testConfig, &root, keys, wrong, proof), ErrInvalidProof, "key %d", i)
+	}
+	wrong := append([][]byte{}, vals...)
+	wrong[0] = append([]byte{}, wrong[0]...)
+	wrong[0][0]++
+	assert.ErrorIs(Verify(testConfig, &root, keys, wrong, proof), ErrInvalidProof)
+
+	// wrong root
+	assert.ErrorIs(Verify(testConfig, &testConfig.Basis[0], keys, vals, proof), ErrInvalidProof)
+
+	// wrong key set
+	assert.ErrorIs(Verify(testConfig, &root, keys[1:], vals[1:], proof), ErrInvalidProof)
+
+	// wrong positions
+	for i := range proof.DepthExtensionPresent {
+		for _, delta := range []byte{1, 1 << 3} {
+			tampered := *proof
+			tampered.DepthExtensionPresent = append([]byte{}, proof.DepthExtensionPresent...)
+			tampered.DepthExtensionPresent[i] ^= delta
+			assert.Error(Verify(testConfig, &root, keys, vals, &tampered))
+		}
+	}
+
+	// proofs of the updated trie
+	assert.NoError(tree.Insert(keys[4], make([]byte, ValueSize)))
+	assert.NoError(tree.Delete(keys[0]))
+	root = tree.Commit()
+	vals = values(t, tree, keys)
+	proof, err = tree.Prove(keys)
+	assert.NoError(err)
+	assert.NoError(Verify(testConfig, &root, keys, vals, proof))
+
+	// empty trie
+	empty := New(testConfig)
+	root = empty.Commit()
+	proof, err = empty.Prove(keys)
+	assert.NoError(err)
+	assert.NoError(Verify(testConfig, &root, keys, make([][]byte, len(keys)), proof))
+
+	_, err = tree.Prove(nil)
+	assert.ErrorIs(err, ErrNoKeys)
+}
+
+func TestProofWrongCommitments(t *testing.T) {
+	assert := require.New(t)
+
+	tree, keys := proofInstance(t)
+	root := tree.Commit()
+	vals := values(t, tree, keys)
+	proof, err := tree.Prove(keys)
+	assert.NoError(err)
+
+	tampered := *proof
+	tampered.Commitments = append(tampered.Commitments, root)
+	assert.ErrorIs(Verify(testConfig, &root, keys, vals, &tampered), ErrInvalidProof)
+	tampered = *proof
+	tampered.Commitments = append([]ipa.Digest{}, proof.Commitments...)
+	tampered.Commitments[0], tampered.Commitments[1] = tampered.Commitments[1], tampered.Commitments[0]
+	assert.ErrorIs(Verify(testConfig, &root, keys, vals, &tampered), ErrInvalidProof)
+}
+
+// TestGoVerkle checks the roots and the proofs against those of go-verkle, and
+// that its proofs verify.
+func TestGoVerkle(t *testing.T) {
+	data, err := os.ReadFile("testdata/go-verkle.json")
+	require.NoError(t, err)
+	var tests []struct {
+		Name   string
+		Insert [][2]string
+		Root   string
+		Keys   []string
+		Values []*string
+		Proof  struct {
+			OtherStems            []string
+			DepthExtensionPresent string
+			CommitmentsByPath     []string
+			D                     string
+			IPAProof              struct {
+				CL, CR          []string
+				FinalEvaluation string
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(data, &tests))
+	require.NotEmpty(t, tests)
+
+	for _, test := range tests {
+		t.Run(test.Name, func(t *testing.T) {
+			assert := require.New(t)
+			decode := func(s string) []byte {
+				b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+				assert.NoError(err)
+				return b
+			}
+			decodePoint := func(s string) bandersnatch.PointAffine {
+				var p bandersnatch.PointAffine
+				assert.NoError(ipa.SetPointBytes(&p, decode(s)))
+				return p
+			}
+
+			tree := New(testConfig)
+			for _, kv := range test.Insert {
+				assert.NoError(tree.Insert(decode(kv[0]), decode(kv[1])))
+			}
+			root := tree.RootBytes()
+			assert.Equal(test.Root, hex.EncodeToString(root[:]))
+
+			keys := make([][]byte, len(test.Keys))
+			vals := make([][]byte, len(test.Keys))
+			for i := range test.Keys {
+				keys[i] = decode(test.Keys[i])
+				if test.Values[i] != nil {
+					vals[i] = decode(*test.Values[i])
+				}
+			}
+			assert.Equal(vals, values(t, tree, keys))
+
+			// the proof of go-verkle
+			var expected Proof
+			expected.DepthExtensionPresent = decode(test.Proof.DepthExtensionPresent)
+			for _, s := range test.Proof.OtherStems {
+				var stem [StemSize]byte
+				copy(stem[:], decode(s))
+				expected.OtherStems = append(expected.OtherStems, stem)
+			}
+			for _, s := range test.Proof.CommitmentsByPath {
+				expected.Commitments = append(expected.Commitments, decodePoint(s))
+			}
+			expected.Multiproof.D = decodePoint(test.Proof.D)
+			for i := range test.Proof.IPAProof.CL {
+				expected.Multiproof.IPA.L = append(expected.Multiproof.IPA.L, decodePoint(test.Proof.IPAProof.CL[i]))
+				expected.Multiproof.IPA.R = append(expected.Multiproof.IPA.R, decodePoint(test.Proof.IPAProof.CR[i]))
+			}
+			a, err := fr.BigEndian.Element((*[fr.Bytes]byte)(decode(test.Proof.IPAProof.FinalEvaluation)))
+			assert.NoError(err)
+			expected.Multiproof.IPA.A = a
+
+			var rootPoint bandersnatch.PointAffine
+			assert.NoError(ipa.SetPointBytes(&rootPoint, root[:]))
+			assert.NoError(Verify(testConfig, &rootPoint, keys, vals, &expected))
+
+			proof, err := tree.Prove(keys)
+			assert.NoError(err)
+			var encoded, expectedEncoded bytes.Buffer
+			_, err = proof.WriteTo(&encoded)
+			assert.NoError(err)
+			_, err = expected.WriteTo(&expectedEncoded)
+			assert.NoError(err)
+			assert.Equal(expectedEncoded.Bytes(), encoded.Bytes())
+		})
+	}
+}
+
+func BenchmarkProve(b *testing.B) {
+	tree, keys := proofInstance(b)
+	tree.Commit()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = tree.Prove(keys)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	tree, keys := proofInstance(b)
+	root := tree.Commit()
+	vals := values(b, tree, keys)
+	proof, err := tree.Prove(keys)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Verify(testConfig, &root, keys, vals, proof)
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package verkle implements a width-256 Verkle trie, following the Ethereum
+// Verkle specification (EIP-6800).
+//
+// Keys and values are 32 bytes. The first 31 bytes of a key are its stem and
+// the last one its suffix: the 256 keys sharing a stem are stored in a single
+// leaf (extension) node, placed below the internal nodes of the shortest
+// prefix of the stem which distinguishes it from the other stems.
+//
+// Nodes are committed to with the IPA vector commitment on Bandersnatch:
+//
+//   - an internal node commits to ∑ᵢ Map(Cᵢ)⋅Gᵢ, where Cᵢ is the commitment of
+//     its i-th child (Map(C) = 0 for an empty child) and Map is
+//     ipa.MapToScalarField,
+//   - a leaf node commits to 1⋅G₀ + stem⋅G₁ + Map(C₁)⋅G₂ + Map(C₂)⋅G₃, where C₁
+//     (resp. C₂) commits to the values of the suffixes 0..127 (resp.
+//     128..255), each value v being the two scalars v[:16] + 2¹²⁸ and v[16:]
+//     (little endian), at indexes 2⋅(suffix mod 128) and 2⋅(suffix mod 128)+1.
+//
+// The root commitment only depends on the content of the trie. Proofs of the
+// values (or absence) of many keys are aggregated in a single IPA multiproof,
+// whose openings and transcript are those of the specification: the roots and
+// the proofs are the ones of go-verkle, up to the encoding of Proof.
+package verkle
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/ipa"
+)
+
+const (
+	// KeySize size in bytes of the keys
+	KeySize = 32
+
+	// ValueSize size in bytes of the values
+	ValueSize = 32
+
+	// StemSize size in bytes of the stem of a key
+	StemSize = KeySize - 1
+
+	// width number of children of an internal node
+	width = ipa.DomainSize
+)
+
+var (
+	ErrInvalidKeySize   = errors.New("invalid key size")
+	ErrInvalidValueSize = errors.New("invalid value size")
+)
+
+// Tree Verkle trie. The zero value is not usable, use New.
+type Tree struct {
+	config *ipa.Config
+	root   *internalNode
+}
+
+// node of the trie: *internalNode or *leafNode. Empty children are nil.
+type node interface {
+	// commit updates the commitment of the node and of its descendants, if
+	// they were modified since it was last computed.
+	commit(config *ipa.Config)
+
+	// commitment returns the last computed commitment of the node.
+	commitment() *bandersnatch.PointAffine
+}
+
+type internalNode struct {
+	children [width]node
+	c        bandersnatch.PointAffine
+	dirty    bool
+}
+
+type leafNode struct {
+	stem   [StemSize]byte
+	values [width][]byte
+	c      bandersnatch.PointAffine
+
+	// c1, c2 commitments to the values of the suffixes 0..127 and 128..255
+	c1, c2 bandersnatch.PointAffine
+	dirty  bool
+}
+
+// New returns an empty Verkle trie using the given IPA configuration, which
+// is ipa.NewConfig() for the Ethereum specification.
+func New(config *ipa.Config) *Tree {
+	return &Tree{
+		config: config,
+		root:   &internalNode{},
+	}
+}
+
+// Get returns the value of key, or nil if it is not in the trie.
+func (t *Tree) Get(key []byte) ([]byte, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKeySize
+	}
+	n := t.root
+	for depth := 0; ; depth++ {
+		switch child := n.children[key[depth]].(type) {
+		case *internalNode:
+			n = child
+		case *leafNode:
+			if string(child.stem[:]) != string(key[:StemSize]) {
+				return nil, nil
+			}
+			return cloneValue(child.values[key[StemSize]]), nil
+		default:
+			return nil, nil
+		}
+	}
+}
+
+// Insert sets the value of key, inserting it if it is not in the trie or
+// updating it otherwise.
+func (t *Tree) Insert(key, value []byte) error {
+	if len(key) != KeySize {
+		return ErrInvalidKeySize
+	}
+	if len(value) != ValueSize {
+		return ErrInvalidValueSize
+	}
+	n := t.root
+	for depth := 0; ; depth++ {
+		n.dirty = true
+		index := key[depth]
+		switch child := n.children[index].(type) {
+		case *internalNode:
+			n = child
+			continue
+		case *leafNode:
+			if string(child.stem[:]) == string(key[:StemSize]) {
+				child.values[key[StemSize]] = cloneValue(value)
+				child.dirty = true
+				return nil
+			}
+			// split: internal nodes down to the first byte where the stems
+			// differ
+			for child.stem[depth+1] == key[depth+1] {
+				next := &internalNode{dirty: true}
+				n.children[index] = next
+				n = next
+				depth++
+				index = key[depth]
+			}
+			next := &internalNode{dirty: true}
+			next.children[child.stem[depth+1]] = child
+			n.children[index] = next
+			n = next
+			depth++
+		}
+		leaf := &leafNode{dirty: true}
+		copy(leaf.stem[:], key)
+		leaf.values[key[StemSize]] = cloneValue(value)
+		n.children[key[depth]] = leaf
+		return nil
+	}
+}
+
+// Delete removes key from the trie. It does nothing if the key is not in the
+// trie. The resulting trie is the same as if the key had never been
+// inserted.
+func (t *Tree) Delete(key []byte) error {
+	if len(key) != KeySize {
+		return ErrInvalidKeySize
+	}
+	path := []*internalNode{t.root}
+	for depth := 0; ; depth++ {
+		switch child := path[depth].children[key[depth]].(type) {
+		case *internalNode:
+			path = append(path, child)
+			continue
+		case *leafNode:
+			if string(child.stem[:]) != string(key[:StemSize]) || child.values[key[StemSize]] == nil {
+				return nil
+			}
+			child.values[key[StemSize]] = nil
+			child.dirty = true
+			if child.isEmpty() {
+				path[depth].children[key[depth]] = nil
+			}
+		default:
+			return nil
+		}
+		break
+	}
+
+	for depth := len(path) - 1; depth >= 0; depth-- {
+		path[depth].dirty = true
+
+		// an internal node left with a single leaf is replaced by the leaf
+		if depth == 0 {
+			continue
+		}
+		if leaf := path[depth].singleLeaf(); leaf != nil {
+			path[depth-1].children[key[depth-1]] = leaf
+		}
+	}
+	return nil
+}
+
+// Commit returns the root commitment of the trie, updating the commitments
+// of the nodes modified since the last call.
+func (t *Tree) Commit() bandersnatch.PointAffine {
+	t.root.commit(t.config)
+	return t.root.c
+}
+
+// RootBytes returns the serialized root commitment of the trie.
+func (t *Tree) RootBytes() [ipa.PointSize]byte {
+	c := t.Commit()
+	return ipa.PointBytes(&c)
+}
+
+// singleLeaf returns the only child of n if it is a leaf, nil otherwise.
+func (n *internalNode) singleLeaf() *leafNode {
+	var res node
+	for _, child := range n.children {
+		if child == nil {
+			continue
+		}
+		if res != nil {
+			return nil
+		}
+		res = child
+	}
+	leaf, _ := res.(*leafNode)
+	return leaf
+}
+
+func (n *internalNode) commit(config *ipa.Config) {
+	if !n.dirty {
+		return
+	}
+	for _, child := range n.children {
+		if child != nil {
+			child.commit(config)
+		}
+	}
+	n.c = commitTo(config, n.evaluations())
+	n.dirty = false
+}
+
+func (n *internalNode) commitment() *bandersnatch.PointAffine {
+	return &n.c
+}
+
+// evaluations returns the committed vector, Map(Cᵢ) for each child.
+func (n *internalNode) evaluations() []fr.Element {
+	res := make([]fr.Element, width)
+	for i, child := range n.children {
+		if child != nil {
+			res[i] = ipa.MapToScalarField(child.commitment())
+		}
+	}
+	return res
+}
+
+func (n *leafNode) commit(config *ipa.Config) {
+	if !n.dirty {
+		return
+	}
+	n.c1 = commitTo(config, n.suffixEvaluations(0))
+	n.c2 = commitTo(config, n.suffixEvaluations(1))
+	n.c = commitTo(config, n.evaluations())
+	n.dirty = false
+}
+
+func (n *leafNode) commitment() *bandersnatch.PointAffine {
+	return &n.c
+}
+
+// evaluations returns the committed vector (1, stem, Map(C₁), Map(C₂), 0, …).
+func (n *leafNode) evaluations() []fr.Element {
+	res := make([]fr.Element, width)
+	res[0].SetOne()
+	res[1] = stemToScalar(n.stem[:])
+	res[2] = ipa.MapToScalarField(&n.c1)
+	res[3] = ipa.MapToScalarField(&n.c2)
+	return res
+}
+
+// suffixEvaluations returns the vector committed to in C₁ (half = 0) or C₂
+// (half = 1).
+func (n *leafNode) suffixEvaluations(half int) []fr.Element {
+	res := make([]fr.Element, width)
+	for i := 0; i < width/2; i++ {
+		res[2*i], res[2*i+1] = valueToScalars(n.values[half*width/2+i])
+	}
+	return res
+}
+
+// suffixCommitment returns C₁ or C₂, depending on suffix.
+func (n *leafNode) suffixCommitment(suffix byte) *bandersnatch.PointAffine {
+	if suffix < width/2 {
+		return &n.c1
+	}
+	return &n.c2
+}
+
+func (n *leafNode) isEmpty() bool {
+	for i := range n.values {
+		if n.values[i] != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// commitTo commits to a vector of DomainSize elements.
+func commitTo(config *ipa.Config, values []fr.Element) bandersnatch.PointAffine {
+	res, err := config.Commit(values)
+	if err != nil {
+		panic(err) // len(values) == ipa.DomainSize
+	}
+	return res
+}
+
+// stemToScalar returns the stem as a little-endian integer.
+func stemToScalar(stem []byte) fr.Element {
+	var buf [fr.Bytes]byte
+	copy(buf[:], stem)
+	res, _ := fr.LittleEndian.Element(&buf) // the stem is smaller than the modulus
+	return res
+}
+
+// valueToScalars returns the low and high scalars of a value: v[:16] + 2¹²⁸
+// and v[16:] as little-endian integers, or zeros if the value is absent.
+func valueToScalars(value []byte) (lo, hi fr.Element) {
+	if value == nil {
+		return
+	}
+	var buf [fr.Bytes]byte
+	copy(buf[:], value[:ValueSize/2])
+	buf[ValueSize/2] = 1 // 2¹²⁸ marks the presence of the value
+	lo, _ = fr.LittleEndian.Element(&buf)
+	buf = [fr.Bytes]byte{}
+	copy(buf[:], value[ValueSize/2:])
+	hi, _ = fr.LittleEndian.Element(&buf)
+	return
+}
+
+func cloneValue(value []byte) []byte {
+	if value == nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(value)), value...)
+}
@@ -0,0 +1,204 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package verkle
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/ipa"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig configuration re-used across tests
+var testConfig = ipa.NewConfig()
+
+func randomBytes(rng *rand.Rand, n int) []byte {
+	res := make([]byte, n)
+	rng.Read(res)
+	return res
+}
+
+// leScalar returns the little-endian integer b, plus 2^(8⋅len(b)) if marker
+// is set, as a scalar.
+func leScalar(b []byte, marker bool) fr.Element {
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	v := new(big.Int).SetBytes(be)
+	if marker {
+		v.SetBit(v, 8*len(b), 1)
+	}
+	var res fr.Element
+	res.SetBigInt(v)
+	return res
+}
+
+func TestLeafCommitment(t *testing.T) {
+	assert := require.New(t)
+	rng := rand.New(rand.NewSource(42)) //#nosec G404 weak rng is fine here
+
+	// a single leaf, with values in both halves
+	key1, value1 := randomBytes(rng, KeySize), randomBytes(rng, ValueSize)
+	key1[StemSize] = 5
+	key2, value2 := append([]byte{}, key1...), make([]byte, ValueSize)
+	key2[StemSize] = 200
+
+	tree := New(testConfig)
+	assert.NoError(tree.Insert(key1, value1))
+	assert.NoError(tree.Insert(key2, value2))
+
+	// C₁ = (v[:16] + 2¹²⁸)⋅G₁₀ + v[16:]⋅G₁₁, C₂ = (0 + 2¹²⁸)⋅G₁₄₄
+	c1Values := make([]fr.Element, ipa.DomainSize)
+	c1Values[10] = leScalar(value1[:16], true)
+	c1Values[11] = leScalar(value1[16:], false)
+	c2Values := make([]fr.Element, ipa.DomainSize)
+	c2Values[144] = leScalar(value2[:16], true)
+	c1, err := testConfig.Commit(c1Values)
+	assert.NoError(err)
+	c2, err := testConfig.Commit(c2Values)
+	assert.NoError(err)
+
+	// C = G₀ + stem⋅G₁ + Map(C₁)⋅G₂ + Map(C₂)⋅G₃
+	leafValues := []fr.Element{fr.One(), leScalar(key1[:StemSize], false), ipa.MapToScalarField(&c1), ipa.MapToScalarField(&c2)}
+	c, err := testConfig.Commit(leafValues)
+	assert.NoError(err)
+
+	rootValues := make([]fr.Element, ipa.DomainSize)
+	rootValues[key1[0]] = ipa.MapToScalarField(&c)
+	expected, err := testConfig.Commit(rootValues)
+	assert.NoError(err)
+
+	root := tree.Commit()
+	assert.True(ipa.EqualPoints(&expected, &root))
+
+	// empty trie
+	root = New(testConfig).Commit()
+	b := ipa.PointBytes(&root)
+	assert.Equal([ipa.PointSize]byte{}, b)
+}
+
+func TestInsertGetDelete(t *testing.T) {
+	assert := require.New(t)
+	rng := rand.New(rand.NewSource(1)) //#nosec G404 weak rng is fine here
+
+	// keys with shared stems and long common prefixes
+	var keys [][]byte
+	for i := 0; i < 20; i++ {
+		key := randomBytes(rng, KeySize)
+		keys = append(keys, key)
+		for j := 0; j < 3; j++ {
+			k := append([]byte{}, key...)
+			k[rng.Intn(KeySize)] ^= byte(1 + rng.Intn(255))
+			keys = append(keys, k)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		k := append([]byte{}, keys[i]...)
+		k[StemSize]++
+		keys = append(keys, k)
+	}
+	values := make(map[string][]byte)
+	for _, key := range keys {
+		values[string(key)] = randomBytes(rng, ValueSize)
+	}
+
+	tree := New(testConfig)
+	for _, key := range keys {
+		assert.NoError(tree.Insert(key, values[string(key)]))
+	}
+	for _, key := range keys {
+		v, err := tree.Get(key)
+		assert.NoError(err)
+		assert.Equal(values[string(key)], v)
+	}
+	absent := randomBytes(rng, KeySize)
+	v, err := tree.Get(absent)
+	assert.NoError(err)
+	assert.Nil(v)
+	root := tree.Commit()
+
+	// the root does not depend on the order of insertion
+	other := New(testConfig)
+	for _, i := range rng.Perm(len(keys)) {
+		assert.NoError(other.Insert(keys[i], values[string(keys[i])]))
+	}
+	otherRoot := other.Commit()
+	assert.True(ipa.EqualPoints(&root, &otherRoot))
+
+	// update
+	newValue := randomBytes(rng, ValueSize)
+	assert.NoError(tree.Insert(keys[3], newValue))
+	v, err = tree.Get(keys[3])
+	assert.NoError(err)
+	assert.Equal(newValue, v)
+	updatedRoot := tree.Commit()
+	assert.False(ipa.EqualPoints(&root, &updatedRoot))
+	assert.NoError(tree.Insert(keys[3], values[string(keys[3])]))
+	updatedRoot = tree.Commit()
+	assert.True(ipa.EqualPoints(&root, &updatedRoot))
+
+	// deleting keys gives the trie where they were never inserted
+	assert.NoError(tree.Delete(absent))
+	deleted := keys[:len(keys)/2]
+	for _, key := range deleted {
+		assert.NoError(tree.Delete(key))
+		v, err := tree.Get(key)
+		assert.NoError(err)
+		assert.Nil(v)
+	}
+	expected := New(testConfig)
+	for _, key := range keys[len(keys)/2:] {
+		assert.NoError(expected.Insert(key, values[string(key)]))
+	}
+	assert.Equal(expected.RootBytes(), tree.RootBytes())
+
+	for _, key := range keys[len(keys)/2:] {
+		assert.NoError(tree.Delete(key))
+	}
+	assert.Equal(New(testConfig).RootBytes(), tree.RootBytes())
+
+	// a zero value is not an absent value
+	assert.NoError(tree.Insert(keys[0], make([]byte, ValueSize)))
+	assert.NotEqual(New(testConfig).RootBytes(), tree.RootBytes())
+
+	// invalid inputs
+	assert.ErrorIs(tree.Insert(keys[0][1:], newValue), ErrInvalidKeySize)
+	assert.ErrorIs(tree.Insert(keys[0], newValue[1:]), ErrInvalidValueSize)
+	_, err = tree.Get(keys[0][1:])
+	assert.ErrorIs(err, ErrInvalidKeySize)
+	assert.ErrorIs(tree.Delete(keys[0][1:]), ErrInvalidKeySize)
+}
+
+func BenchmarkInsertCommit(b *testing.B) {
+	rng := rand.New(rand.NewSource(1)) //#nosec G404 weak rng is fine here
+	keys := make([][]byte, 1000)
+	for i := range keys {
+		keys[i] = randomBytes(rng, KeySize)
+	}
+	value := randomBytes(rng, ValueSize)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		tree := New(testConfig)
+		for _, key := range keys {
+			_ = tree.Insert(key, value)
+		}
+		tree.Commit()
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package minpk provides BLS signatures on the bls12-377 curve, with
+// public keys in G1 and signatures in G2 (minimal-pubkey-size variant).
+//
+// The implementation follows the proof of possession scheme of the IETF draft
+// with the ciphersuite BLS_SIG_BLS12377G2_XMD:SHA-256_SSWU_RO_POP_.
+// Messages are hashed to G2 with HashToG2 (RFC 9380).
+//
+// Aggregation of signatures is supported through Aggregate, FastAggregateVerify
+// (same message) and AggregateVerify (distinct messages). Both verifications
+// are only secure if every public key comes with a verified proof of possession
+// (see ProvePossession and VerifyPossession), which prevents rogue key attacks.
+//
+// Documentation:
+// - IETF draft: https://datatracker.ietf.org/doc/html/draft-irtf-cfrg-bls-signature-05
+// - RFC 9380: https://datatracker.ietf.org/doc/html/rfc9380
+package minpk
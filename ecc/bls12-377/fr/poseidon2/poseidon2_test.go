@@ -0,0 +1,182 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReferenceVectors checks the round keys and the permutation with the
+// default number of rounds against the reference implementation
+// https://github.com/HorizenLabs/poseidon2
+//
+// The reference implementation has no instance on this field, the vectors were
+// computed with an independent implementation of the reference which matches
+// its vectors on bn254 and bls12-381.
+func TestReferenceVectors(t *testing.T) {
+	assert := require.New(t)
+
+	for _, v := range []struct {
+		width    int
+		roundKey string
+		expected []string
+	}{
+		{
+			width:    2,
+			roundKey: "0x0a98f8c2a1e98b96b78d7db2f9e2d0a3fe6803e9784b68fea2e4f3eca21c4c2e",
+			expected: []string{
+				"0x088a31db828c4e54cf7a09099b93cac6a1a51f33e1de65d162ffb96a518593c2",
+				"0x08831f785eba1b59b66330f842e553bcab749a0e98f3b0f38a17a7d639a34ca0",
+			},
+		},
+		{
+			width:    3,
+			roundKey: "0x0307d480bee26c7209f492a4fe798a26d79f6be92f3b4910fedb02a23316c7c3",
+			expected: []string{
+				"0x0b88ecada2b0231972ab29f6fc4b671c3ca8361e61dd989bdac506a3e89b60d6",
+				"0x0688962c41e803e8e5ffe421f75979295c00f58cc659a2bb0ba8d8448af6d1fe",
+				"0x10bf370aad7e946ff3ca27466b464444d9ce11b2d293e54e3bf31da1e8dd21b5",
+			},
+		},
+	} {
+		h := NewPermutation(v.width, DefaultNbFullRounds, DefaultNbPartialRounds)
+
+		// first round key
+		var rc fr.Element
+		_, err := rc.SetString(v.roundKey)
+		assert.NoError(err)
+		assert.True(rc.Equal(&h.Parameters().RoundKeys[0][0]), "round key mismatch, width %d", v.width)
+
+		// permutation of (0, 1, .., t-1)
+		input := make([]fr.Element, v.width)
+		for i := range input {
+			input[i].SetUint64(uint64(i))
+		}
+		assert.NoError(h.Permutation(input))
+		for i := range input {
+			var expected fr.Element
+			_, err = expected.SetString(v.expected[i])
+			assert.NoError(err)
+			assert.True(expected.Equal(&input[i]), "mismatch at index %d, width %d", i, v.width)
+		}
+	}
+}
+
+func TestParameters(t *testing.T) {
+	assert := require.New(t)
+
+	for _, width := range []int{2, 3} {
+		params := NewParameters(width, DefaultNbFullRounds, DefaultNbPartialRounds)
+		assert.Equal(DefaultNbFullRounds+DefaultNbPartialRounds, len(params.RoundKeys))
+		for i, rk := range params.RoundKeys {
+			if i >= DefaultNbFullRounds/2 && i < DefaultNbFullRounds/2+DefaultNbPartialRounds {
+				assert.Equal(1, len(rk))
+			} else {
+				assert.Equal(width, len(rk))
+			}
+		}
+	}
+
+	assert.Panics(func() { NewParameters(4, DefaultNbFullRounds, DefaultNbPartialRounds) })
+	assert.Panics(func() { NewParameters(3, DefaultNbFullRounds+1, DefaultNbPartialRounds) })
+}
+
+func TestPermutation(t *testing.T) {
+	assert := require.New(t)
+
+	h := NewPermutation(2, DefaultNbFullRounds, DefaultNbPartialRounds)
+	assert.ErrorIs(h.Permutation(make([]fr.Element, 3)), ErrInvalidSizebuffer)
+
+	var a, b [2]fr.Element
+	a[0].SetRandom()
+	a[1].SetRandom()
+	b = a
+	assert.NoError(h.Permutation(a[:]))
+	assert.NotEqual(a, b)
+
+	// deterministic
+	assert.NoError(h.Permutation(b[:]))
+	assert.Equal(a, b)
+}
+
+func TestCompress(t *testing.T) {
+	assert := require.New(t)
+
+	h := NewPermutation(2, DefaultNbFullRounds, DefaultNbPartialRounds)
+	var x, y fr.Element
+	x.SetRandom()
+	y.SetRandom()
+	bx, by := x.Bytes(), y.Bytes()
+
+	xy, err := h.Compress(bx[:], by[:])
+	assert.NoError(err)
+	assert.Equal(fr.Bytes, len(xy))
+	yx, err := h.Compress(by[:], bx[:])
+	assert.NoError(err)
+	assert.NotEqual(xy, yx)
+
+	_, err = h.Compress(bx[1:], by[:])
+	assert.ErrorIs(err, ErrInvalidSizebuffer)
+}
+
+func TestHash(t *testing.T) {
+	assert := require.New(t)
+
+	var x fr.Element
+	x.SetRandom()
+	bx := x.Bytes()
+	var zero [fr.Bytes]byte
+
+	h := NewPoseidon2()
+	_, err := h.Write(bx[:])
+	assert.NoError(err)
+	d1 := h.Sum(nil)
+	assert.Equal(h.Size(), len(d1))
+
+	// Sum doesn't change the state
+	assert.Equal(d1, h.Sum(nil))
+
+	// trailing zeroes are not ignored thanks to the padding
+	_, err = h.Write(zero[:])
+	assert.NoError(err)
+	assert.NotEqual(d1, h.Sum(nil))
+
+	h.Reset()
+	_, err = h.Write(bx[:])
+	assert.NoError(err)
+	assert.Equal(d1, h.Sum(nil))
+
+	// invalid input
+	h.Reset()
+	_, err = h.Write(append(bx[:], 1))
+	assert.Error(err)
+}
+
+func BenchmarkPermutation(b *testing.B) {
+	h := NewPermutation(DefaultWidth, DefaultNbFullRounds, DefaultNbPartialRounds)
+	var tmp [DefaultWidth]fr.Element
+	tmp[0].SetRandom()
+	tmp[1].SetRandom()
+	tmp[2].SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = h.Permutation(tmp[:])
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package rpo implements the Rescue-Prime Optimized (RPO) permutation
+//
+// RPO is a variant of the Rescue-Prime arithmetization oriented permutation,
+// see the [original paper] by Ashur, Kindi, Meier, Szepieniec and Threadbonnet
+// for the full details. Each round applies an MDS matrix, round keys, the s-box
+// x ↦ xᵅ, and then again an MDS matrix, round keys, and the inverse s-box
+// x ↦ x^(α⁻¹).
+//
+// The instance over bls12-377 has a state of 3 elements with a capacity of
+// 1 element(s), α = 11 and 10 rounds. The MDS matrix is a Cauchy
+// matrix, and the digests are made of 1 element(s).
+// The round keys are derived from SHAKE256 as in the Rescue-Prime specification,
+// and the number of rounds from its Gröbner basis bound with the security margin
+// of RPO.
+//
+// The package provides:
+//   - the permutation itself (see [NewPermutation] and [NewParameters]);
+//   - a compression function for Merkle trees (see [Permutation.Compress]);
+//   - a sponge construction implementing [hash.Hash] (see [NewRPO]).
+//
+// # Hash input format
+//
+// As for MiMC, the sponge absorbs field elements. The input byte slice is
+// interpreted as a sequence of big endian encoded field elements, each of them
+// strictly less than the field modulus.
+//
+// The sponge is the one of RPO-256: the first element of the capacity is set to
+// the number of absorbed elements modulo the rate, the elements overwrite the
+// rate, and the last block is padded with zeroes.
+//
+// [original paper]: https://eprint.iacr.org/2022/1577.pdf
+package rpo
@@ -275,10 +275,8 @@ func genRandom(seed, i, j int64, buf *bytes.Buffer) fr.Element {
 // multiplications are done.
 func mulMod(pLagrangeCosetBitReversed, qLagrangeCosetBitReversed []fr.Element) []fr.Element {
 
-	res := make([]fr.Element, len(pLagrangeCosetBitReversed))
-	for i := 0; i < len(pLagrangeCosetBitReversed); i++ {
-		res[i].Mul(&pLagrangeCosetBitReversed[i], &qLagrangeCosetBitReversed[i])
-	}
+	res := make(fr.Vector, len(pLagrangeCosetBitReversed))
+	res.Mul(pLagrangeCosetBitReversed, qLagrangeCosetBitReversed)
 
 	// NOT fft inv for now, wait until every part of the keys have been multiplied
 	// r.Domain.FFTInverse(res, fft.DIT, true)
@@ -287,13 +285,11 @@ func mulMod(pLagrangeCosetBitReversed, qLagrangeCosetBitReversed []fr.Element) [
 
 }
 
-// mulMod + accumulate in res.
-func mulModAcc(res []fr.Element, pLagrangeCosetBitReversed, qLagrangeCosetBitReversed []fr.Element) {
-	var t fr.Element
-	for i := 0; i < len(pLagrangeCosetBitReversed); i++ {
-		t.Mul(&pLagrangeCosetBitReversed[i], &qLagrangeCosetBitReversed[i])
-		res[i].Add(&res[i], &t)
-	}
+// mulMod + accumulate in res. qLagrangeCosetBitReversed is overwritten with
+// the product.
+func mulModAcc(res, pLagrangeCosetBitReversed, qLagrangeCosetBitReversed fr.Vector) {
+	qLagrangeCosetBitReversed.Mul(pLagrangeCosetBitReversed, qLagrangeCosetBitReversed)
+	res.Add(res, qLagrangeCosetBitReversed)
 }
 
 // Returns a clone of the RSis parameters with a fresh and empty buffer. Does not
@@ -15,4 +15,13 @@
 // Code generated by consensys/gnark-crypto DO NOT EDIT
 
 // Package kzg provides a KZG commitment scheme.
+//
+// The SRS can be generated by a multi-party Powers-of-Tau ceremony (see
+// Transcript), secure as long as one participant is honest.
+//
+// The opening proofs at all the points of a domain, or on all the cosets of
+// one of its subgroups, can be computed at once with OpenAll and OpenAllCosets.
+//
+// Many polynomials, each at its own set of points, can be opened with a proof
+// of two G₁ elements with MultiOpen and MultiVerify (SHPLONK).
 package kzg
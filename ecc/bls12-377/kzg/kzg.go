@@ -37,6 +37,7 @@ var (
 	ErrVerifyOpeningProof            = errors.New("can't verify opening proof")
 	ErrVerifyBatchOpeningSinglePoint = errors.New("can't verify batch opening proof at single point")
 	ErrMinSRSSize                    = errors.New("minimum srs size is 2")
+	ErrInvalidMultiExpTable          = errors.New("the bases of the table are not the SRS points")
 )
 
 // Digest commitment of a polynomial.
@@ -45,6 +46,49 @@ type Digest = bls12377.G1Affine
 // ProvingKey used to create or open commitments
 type ProvingKey struct {
 	G1 []bls12377.G1Affine // [G₁ [α]G₁ , [α²]G₁, ... ]
+
+	// precomputed multiples of G1, used by Commit if set (see Precompute)
+	table *bls12377.G1MultiExpTable
+}
+
+// Precompute builds a table of precomputed multiples of pk.G1 with window size c
+// (or the best one if c is 0), that Commit uses to speed up the commitments.
+// The table is ⌈fr.Bits/c⌉ times larger than pk.G1, see bls12377.G1MultiExpTable.
+func (pk *ProvingKey) Precompute(c int) error {
+	table, err := bls12377.NewG1MultiExpTable(pk.G1, c)
+	if err != nil {
+		return err
+	}
+	pk.table = table
+	return nil
+}
+
+// SetMultiExpTable sets the table used by Commit, for instance after reading it
+// from disk. Its bases must be the first points of pk.G1. A nil table
+// removes the current one.
+//
+// Only the bases are checked here: the other points of the table are checked
+// by G1MultiExpTable.ReadFrom, not by G1MultiExpTable.UnsafeReadFrom, whose
+// tables must come from a trusted source.
+func (pk *ProvingKey) SetMultiExpTable(table *bls12377.G1MultiExpTable) error {
+	if table != nil {
+		if table.NbBases() > len(pk.G1) {
+			return ErrInvalidMultiExpTable
+		}
+		bases := table.Bases()
+		for i := range bases {
+			if !bases[i].Equal(&pk.G1[i]) {
+				return ErrInvalidMultiExpTable
+			}
+		}
+	}
+	pk.table = table
+	return nil
+}
+
+// MultiExpTable returns the table used by Commit, or nil if there is none.
+func (pk *ProvingKey) MultiExpTable() *bls12377.G1MultiExpTable {
+	return pk.table
 }
 
 // VerifyingKey used to verify opening proofs
@@ -167,6 +211,8 @@ type BatchOpeningProof struct {
 
 // Commit commits to a polynomial using a multi exponentiation with the SRS.
 // It is assumed that the polynomial is in canonical form, in Montgomery form.
+// If the proving key has a table of precomputed multiples covering the
+// polynomial, the multi exponentiation uses it.
 func Commit(p []fr.Element, pk ProvingKey, nbTasks ...int) (Digest, error) {
 
 	if len(p) == 0 || len(p) > len(pk.G1) {
@@ -179,6 +225,12 @@ func Commit(p []fr.Element, pk ProvingKey, nbTasks ...int) (Digest, error) {
 	if len(nbTasks) > 0 {
 		config.NbTasks = nbTasks[0]
 	}
+	if pk.table != nil && len(p) <= pk.table.NbBases() {
+		if _, err := res.MultiExpWithTable(pk.table, p, config); err != nil {
+			return Digest{}, err
+		}
+		return res, nil
+	}
 	if _, err := res.MultiExp(pk.G1[:len(p)], p, config); err != nil {
 		return Digest{}, err
 	}
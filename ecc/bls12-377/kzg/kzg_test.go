@@ -165,6 +165,29 @@ func TestSerializationSRS(t *testing.T) {
 	t.Run("unsafe whole SRS round-trip", testutils.UnsafeBinaryMarshalerRoundTrip(srs))
 }
 
+func TestSerializationMultiOpeningProof(t *testing.T) {
+	hf := sha256.New()
+
+	polynomials, digests, points := multiOpeningInstance(t)
+	proof, err := MultiOpen(polynomials, digests, points, hf, testSrs.Pk)
+	assert.NoError(t, err)
+	t.Run("proof round-trip", testutils.SerializationRoundTrip(&proof))
+
+	// a single polynomial opened at a single point
+	single, err := MultiOpen(polynomials[:1], digests[:1], [][]fr.Element{points[0][:1]}, hf, testSrs.Pk)
+	assert.NoError(t, err)
+	t.Run("single point proof round-trip", testutils.SerializationRoundTrip(&single))
+
+	// the decoded proof is still accepted by the verifier
+	var buf bytes.Buffer
+	_, err = proof.WriteTo(&buf)
+	assert.NoError(t, err)
+	var decoded MultiOpeningProof
+	_, err = decoded.ReadFrom(&buf)
+	assert.NoError(t, err)
+	assert.NoError(t, MultiVerify(digests, &decoded, points, hf, testSrs.Vk))
+}
+
 func TestCommit(t *testing.T) {
 
 	// create a polynomial
@@ -198,6 +221,39 @@ func TestCommit(t *testing.T) {
 
 }
 
+func TestCommitWithTable(t *testing.T) {
+	assert := require.New(t)
+
+	pk := ProvingKey{G1: testSrs.Pk.G1[:128]}
+	assert.NoError(pk.Precompute(0))
+	assert.NotNil(pk.MultiExpTable())
+
+	// commitments with and without the table match, including for polynomials
+	// larger than the table
+	for _, size := range []int{1, 60, 128, 200} {
+		f := randomPolynomial(size)
+		expected, err := Commit(f, testSrs.Pk)
+		assert.NoError(err)
+		if size > len(pk.G1) {
+			pk.G1 = testSrs.Pk.G1
+		}
+		digest, err := Commit(f, pk)
+		assert.NoError(err)
+		assert.True(expected.Equal(&digest), "size %d", size)
+	}
+
+	// the table must be built on the points of the proving key
+	table, err := bls12377.NewG1MultiExpTable(testSrs.Pk.G1[1:9], 0)
+	assert.NoError(err)
+	assert.ErrorIs(pk.SetMultiExpTable(table), ErrInvalidMultiExpTable)
+	table, err = bls12377.NewG1MultiExpTable(testSrs.Pk.G1[:9], 0)
+	assert.NoError(err)
+	assert.NoError(pk.SetMultiExpTable(table))
+	assert.ErrorIs((&ProvingKey{G1: testSrs.Pk.G1[:8]}).SetMultiExpTable(table), ErrInvalidMultiExpTable)
+	assert.NoError(pk.SetMultiExpTable(nil))
+	assert.Nil(pk.MultiExpTable())
+}
+
 func TestVerifySinglePoint(t *testing.T) {
 
 	// create a polynomial
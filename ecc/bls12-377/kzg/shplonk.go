@@ -0,0 +1,401 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidNbPoints = errors.New("number of point sets is not the same as the number of polynomials")
+	ErrInvalidPointSet = errors.New("point sets must be non empty and made of distinct points")
+)
+
+// MultiOpeningProof opening proof of many polynomials, each at its own set of
+// points, following the SHPLONK scheme of Boneh, Drake, Fisch and Gabizon
+// (https://eprint.iacr.org/2020/081, section 4).
+//
+// implements io.ReaderFrom and io.WriterTo
+type MultiOpeningProof struct {
+	// W commitment to ∑ᵢγⁱ(fᵢ-rᵢ)/Z_{Sᵢ}, where rᵢ interpolates fᵢ on Sᵢ and Z_{Sᵢ} vanishes on Sᵢ
+	W bls12377.G1Affine
+
+	// WPrime commitment to L/(X-z), where L(X) = ∑ᵢγⁱZ_{T\Sᵢ}(z)(fᵢ(X)-rᵢ(z)) - Z_T(z)W(X)
+	WPrime bls12377.G1Affine
+
+	// ClaimedValues purported values, ClaimedValues[i][j] = fᵢ(points[i][j])
+	ClaimedValues [][]fr.Element
+}
+
+// MultiOpen creates an opening proof of a list of polynomials, the i-th
+// polynomial being opened on the set of points points[i]. The proof is made of
+// two G₁ elements whatever the number of polynomials and points.
+// It's an interactive protocol, made non-interactive using Fiat Shamir.
+//
+// * polynomials is the list of polynomials to open, in canonical form
+// * digests is the list of committed polynomials to open, need to derive the challenges using Fiat Shamir.
+// * points is the list of sets of points, points[i] being made of distinct points
+// * dataTranscript extra data that might be needed to derive the challenges
+func MultiOpen(polynomials [][]fr.Element, digests []Digest, points [][]fr.Element, hf hash.Hash, pk ProvingKey, dataTranscript ...[]byte) (MultiOpeningProof, error) {
+
+	// check for invalid sizes
+	if len(digests) != len(polynomials) {
+		return MultiOpeningProof{}, ErrInvalidNbDigests
+	}
+	if len(points) != len(polynomials) {
+		return MultiOpeningProof{}, ErrInvalidNbPoints
+	}
+	if len(polynomials) == 0 {
+		return MultiOpeningProof{}, ErrZeroNbDigests
+	}
+	largestPoly := 0
+	for i := range polynomials {
+		if len(polynomials[i]) == 0 || len(polynomials[i]) > len(pk.G1) {
+			return MultiOpeningProof{}, ErrInvalidPolynomialSize
+		}
+		if len(polynomials[i]) > largestPoly {
+			largestPoly = len(polynomials[i])
+		}
+		if !distinctPoints(points[i]) {
+			return MultiOpeningProof{}, ErrInvalidPointSet
+		}
+	}
+
+	// compute the purported values
+	var res MultiOpeningProof
+	res.ClaimedValues = make([][]fr.Element, len(polynomials))
+	for i := range polynomials {
+		res.ClaimedValues[i] = make([]fr.Element, len(points[i]))
+		p := polynomials[i]
+		parallel.Execute(len(points[i]), func(start, end int) {
+			for j := start; j < end; j++ {
+				res.ClaimedValues[i][j] = eval(p, points[i][j])
+			}
+		})
+	}
+
+	// derive the challenge γ, binded to the points, the commitments and the claimed values
+	fs := fiatshamir.NewTranscript(hf, "gamma", "z")
+	gamma, err := deriveMultiOpeningGamma(fs, digests, points, res.ClaimedValues, dataTranscript...)
+	if err != nil {
+		return MultiOpeningProof{}, err
+	}
+	gammai := make([]fr.Element, len(polynomials))
+	gammai[0].SetOne()
+	for i := 1; i < len(gammai); i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+
+	// W = ∑ᵢγⁱ(fᵢ-rᵢ)/Z_{Sᵢ}, where (fᵢ-rᵢ)/Z_{Sᵢ} is the quotient of the
+	// euclidean division of fᵢ by Z_{Sᵢ}
+	w := make([]fr.Element, largestPoly)
+	for i := range polynomials {
+		q := make([]fr.Element, len(polynomials[i]))
+		copy(q, polynomials[i])
+		for j := 0; j < len(points[i]) && len(q) > 0; j++ {
+			q = dividePolyByXminusA(q, fr.Element{}, points[i][j])
+		}
+		parallel.Execute(len(q), func(start, end int) {
+			var t fr.Element
+			for j := start; j < end; j++ {
+				t.Mul(&q[j], &gammai[i])
+				w[j].Add(&w[j], &t)
+			}
+		})
+	}
+	if res.W, err = Commit(w, pk); err != nil {
+		return MultiOpeningProof{}, err
+	}
+
+	// derive the challenge z, binded to W
+	z, err := deriveMultiOpeningZ(fs, &res.W)
+	if err != nil {
+		return MultiOpeningProof{}, err
+	}
+
+	// L(X) = ∑ᵢγⁱZ_{T\Sᵢ}(z)(fᵢ(X)-rᵢ(z)) - Z_T(z)W(X)
+	coeffs, zT, err := multiOpeningCoefficients(points, gammai, z)
+	if err != nil {
+		return MultiOpeningProof{}, err
+	}
+	l := make([]fr.Element, largestPoly)
+	for i := range polynomials {
+		p := polynomials[i]
+		parallel.Execute(len(p), func(start, end int) {
+			var t fr.Element
+			for j := start; j < end; j++ {
+				t.Mul(&p[j], &coeffs[i])
+				l[j].Add(&l[j], &t)
+			}
+		})
+		var t fr.Element
+		ri := lagrangeEval(points[i], res.ClaimedValues[i], z)
+		t.Mul(&ri, &coeffs[i])
+		l[0].Sub(&l[0], &t)
+	}
+	parallel.Execute(len(w), func(start, end int) {
+		var t fr.Element
+		for j := start; j < end; j++ {
+			t.Mul(&w[j], &zT)
+			l[j].Sub(&l[j], &t)
+		}
+	})
+
+	// W' = [L(τ)/(τ-z)]₁, L(z) = 0 by construction
+	h := dividePolyByXminusA(l, fr.Element{}, z)
+	if len(h) > 0 {
+		if res.WPrime, err = Commit(h, pk); err != nil {
+			return MultiOpeningProof{}, err
+		}
+	}
+
+	return res, nil
+}
+
+// MultiVerify verifies an opening proof of a list of polynomials, the i-th
+// polynomial being opened on the set of points points[i].
+//
+// * digests list of committed polynomials
+// * proof proof of correct opening on the digests
+// * points the list of sets of points at which the openings are done
+// * dataTranscript extra data that might be needed to derive the challenges
+func MultiVerify(digests []Digest, proof *MultiOpeningProof, points [][]fr.Element, hf hash.Hash, vk VerifyingKey, dataTranscript ...[]byte) error {
+
+	// check for invalid sizes
+	if len(digests) != len(proof.ClaimedValues) {
+		return ErrInvalidNbDigests
+	}
+	if len(points) != len(digests) {
+		return ErrInvalidNbPoints
+	}
+	if len(digests) == 0 {
+		return ErrZeroNbDigests
+	}
+	for i := range points {
+		if len(points[i]) != len(proof.ClaimedValues[i]) || !distinctPoints(points[i]) {
+			return ErrInvalidPointSet
+		}
+	}
+
+	// derive the challenges γ and z
+	fs := fiatshamir.NewTranscript(hf, "gamma", "z")
+	gamma, err := deriveMultiOpeningGamma(fs, digests, points, proof.ClaimedValues, dataTranscript...)
+	if err != nil {
+		return err
+	}
+	z, err := deriveMultiOpeningZ(fs, &proof.W)
+	if err != nil {
+		return err
+	}
+	gammai := make([]fr.Element, len(digests))
+	gammai[0].SetOne()
+	for i := 1; i < len(gammai); i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+	coeffs, zT, err := multiOpeningCoefficients(points, gammai, z)
+	if err != nil {
+		return err
+	}
+
+	// F = ∑ᵢγⁱZ_{T\Sᵢ}(z)([fᵢ(τ)]₁-[rᵢ(z)]₁) - Z_T(z)W, and we check that
+	// e(F + zW', [1]₂)⋅e(-W', [τ]₂) = 1
+	bases := make([]bls12377.G1Affine, 0, len(digests)+3)
+	bases = append(bases, digests...)
+	bases = append(bases, vk.G1, proof.W, proof.WPrime)
+	scalars := make([]fr.Element, len(bases))
+	copy(scalars, coeffs)
+	var t fr.Element
+	for i := range points {
+		ri := lagrangeEval(points[i], proof.ClaimedValues[i], z)
+		t.Mul(&ri, &coeffs[i])
+		scalars[len(digests)].Sub(&scalars[len(digests)], &t)
+	}
+	scalars[len(digests)+1].Neg(&zT)
+	scalars[len(digests)+2].Set(&z)
+
+	var f bls12377.G1Affine
+	if _, err = f.MultiExp(bases, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	var wPrimeNeg bls12377.G1Affine
+	wPrimeNeg.Neg(&proof.WPrime)
+
+	check, err := bls12377.PairingCheckFixedQ(
+		[]bls12377.G1Affine{f, wPrimeNeg},
+		vk.Lines[:],
+	)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// multiOpeningCoefficients returns the coefficients γⁱZ_{T\Sᵢ}(z) and Z_T(z),
+// where Sᵢ = points[i] and T is the union of the Sᵢ.
+func multiOpeningCoefficients(points [][]fr.Element, gammai []fr.Element, z fr.Element) ([]fr.Element, fr.Element, error) {
+
+	// T, without duplicates
+	var t []fr.Element
+	inT := make(map[fr.Element]struct{})
+	for i := range points {
+		for j := range points[i] {
+			if _, ok := inT[points[i][j]]; !ok {
+				inT[points[i][j]] = struct{}{}
+				t = append(t, points[i][j])
+			}
+		}
+	}
+
+	// Z_T(z)
+	var zT, tmp fr.Element
+	zT.SetOne()
+	for i := range t {
+		tmp.Sub(&z, &t[i])
+		zT.Mul(&zT, &tmp)
+	}
+
+	// Z_{T\Sᵢ}(z) = Z_T(z)/Z_{Sᵢ}(z), z being random it is not in T
+	if zT.IsZero() {
+		return nil, fr.Element{}, ErrVerifyOpeningProof
+	}
+	coeffs := make([]fr.Element, len(points))
+	for i := range points {
+		coeffs[i].SetOne()
+		for j := range points[i] {
+			tmp.Sub(&z, &points[i][j])
+			coeffs[i].Mul(&coeffs[i], &tmp)
+		}
+	}
+	coeffs = fr.BatchInvert(coeffs)
+	for i := range coeffs {
+		coeffs[i].Mul(&coeffs[i], &zT).Mul(&coeffs[i], &gammai[i])
+	}
+
+	return coeffs, zT, nil
+}
+
+// lagrangeEval evaluates at z the polynomial of degree < len(xs) interpolating
+// the values ys at the distinct points xs.
+func lagrangeEval(xs, ys []fr.Element, z fr.Element) fr.Element {
+
+	// rᵢ(z) = ∑ⱼyⱼ∏_{k≠j}(z-xₖ)/(xⱼ-xₖ)
+	num := make([]fr.Element, len(xs))
+	den := make([]fr.Element, len(xs))
+	var tmp fr.Element
+	for j := range xs {
+		num[j].SetOne()
+		den[j].SetOne()
+		for k := range xs {
+			if k == j {
+				continue
+			}
+			tmp.Sub(&z, &xs[k])
+			num[j].Mul(&num[j], &tmp)
+			tmp.Sub(&xs[j], &xs[k])
+			den[j].Mul(&den[j], &tmp)
+		}
+	}
+	den = fr.BatchInvert(den)
+
+	var res fr.Element
+	for j := range xs {
+		tmp.Mul(&num[j], &den[j]).Mul(&tmp, &ys[j])
+		res.Add(&res, &tmp)
+	}
+	return res
+}
+
+// distinctPoints returns true if points is non empty and made of distinct points.
+func distinctPoints(points []fr.Element) bool {
+	if len(points) == 0 {
+		return false
+	}
+	seen := make(map[fr.Element]struct{}, len(points))
+	for i := range points {
+		if _, ok := seen[points[i]]; ok {
+			return false
+		}
+		seen[points[i]] = struct{}{}
+	}
+	return true
+}
+
+// deriveMultiOpeningGamma derives the challenge γ used to combine the
+// polynomials using Fiat Shamir.
+func deriveMultiOpeningGamma(fs *fiatshamir.Transcript, digests []Digest, points, claimedValues [][]fr.Element, dataTranscript ...[]byte) (fr.Element, error) {
+
+	for i := range digests {
+		if err := fs.Bind("gamma", digests[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := range points {
+		for j := range points[i] {
+			if err := fs.Bind("gamma", points[i][j].Marshal()); err != nil {
+				return fr.Element{}, err
+			}
+		}
+	}
+	for i := range claimedValues {
+		for j := range claimedValues[i] {
+			if err := fs.Bind("gamma", claimedValues[i][j].Marshal()); err != nil {
+				return fr.Element{}, err
+			}
+		}
+	}
+	for i := 0; i < len(dataTranscript); i++ {
+		if err := fs.Bind("gamma", dataTranscript[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	gammaByte, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var gamma fr.Element
+	gamma.SetBytes(gammaByte)
+
+	return gamma, nil
+}
+
+// deriveMultiOpeningZ derives the challenge z, at which the combined
+// polynomial is opened, using Fiat Shamir.
+func deriveMultiOpeningZ(fs *fiatshamir.Transcript, w *bls12377.G1Affine) (fr.Element, error) {
+	if err := fs.Bind("z", w.Marshal()); err != nil {
+		return fr.Element{}, err
+	}
+	zByte, err := fs.ComputeChallenge("z")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var z fr.Element
+	z.SetBytes(zByte)
+
+	return z, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// multiOpeningInstance returns polynomials of various sizes, their
+// commitments and point sets of various sizes, some points being shared.
+func multiOpeningInstance(t testing.TB) ([][]fr.Element, []Digest, [][]fr.Element) {
+	sizes := []int{40, 1, 12, 64, 3}
+	nbPoints := []int{3, 2, 1, 5, 4}
+
+	var shared fr.Element
+	shared.SetRandom()
+
+	polynomials := make([][]fr.Element, len(sizes))
+	digests := make([]Digest, len(sizes))
+	points := make([][]fr.Element, len(sizes))
+	for i := range sizes {
+		polynomials[i] = randomPolynomial(sizes[i])
+		var err error
+		digests[i], err = Commit(polynomials[i], testSrs.Pk)
+		require.NoError(t, err)
+		points[i] = make([]fr.Element, nbPoints[i])
+		points[i][0] = shared
+		for j := 1; j < nbPoints[i]; j++ {
+			points[i][j].SetRandom()
+		}
+	}
+	return polynomials, digests, points
+}
+
+func TestMultiOpen(t *testing.T) {
+	assert := require.New(t)
+
+	polynomials, digests, points := multiOpeningInstance(t)
+	hf := sha256.New()
+
+	proof, err := MultiOpen(polynomials, digests, points, hf, testSrs.Pk)
+	assert.NoError(err)
+
+	// claimed values
+	for i := range polynomials {
+		for j := range points[i] {
+			expected := eval(polynomials[i], points[i][j])
+			assert.True(expected.Equal(&proof.ClaimedValues[i][j]), "inconsistent claimed values")
+		}
+	}
+
+	// correct proof
+	assert.NoError(MultiVerify(digests, &proof, points, hf, testSrs.Vk))
+
+	// extended transcript
+	var salt fr.Element
+	salt.SetRandom()
+	proofExtendedTranscript, err := MultiOpen(polynomials, digests, points, hf, testSrs.Pk, salt.Marshal())
+	assert.NoError(err)
+	assert.NoError(MultiVerify(digests, &proofExtendedTranscript, points, hf, testSrs.Vk, salt.Marshal()))
+	assert.Error(MultiVerify(digests, &proofExtendedTranscript, points, hf, testSrs.Vk))
+
+	{
+		// wrong claimed value
+		var wrong MultiOpeningProof
+		wrong.W, wrong.WPrime = proof.W, proof.WPrime
+		wrong.ClaimedValues = make([][]fr.Element, len(proof.ClaimedValues))
+		for i := range wrong.ClaimedValues {
+			wrong.ClaimedValues[i] = append([]fr.Element{}, proof.ClaimedValues[i]...)
+		}
+		wrong.ClaimedValues[3][2].Double(&wrong.ClaimedValues[3][2])
+		assert.ErrorIs(MultiVerify(digests, &wrong, points, hf, testSrs.Vk), ErrVerifyOpeningProof)
+	}
+	{
+		// wrong point
+		var p fr.Element
+		p.SetRandom()
+		wrongPoints := append([][]fr.Element{}, points...)
+		wrongPoints[0] = append([]fr.Element{p}, points[0][1:]...)
+		assert.ErrorIs(MultiVerify(digests, &proof, wrongPoints, hf, testSrs.Vk), ErrVerifyOpeningProof)
+	}
+	{
+		// wrong proof with zero quotients
+		wrong := proof
+		wrong.W.X.SetZero()
+		wrong.W.Y.SetZero()
+		wrong.WPrime.X.SetZero()
+		wrong.WPrime.Y.SetZero()
+		assert.Error(MultiVerify(digests, &wrong, points, hf, testSrs.Vk))
+	}
+
+	// invalid inputs
+	_, err = MultiOpen(polynomials, digests[1:], points, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbDigests)
+	_, err = MultiOpen(polynomials, digests, points[1:], hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbPoints)
+	duplicates := append([][]fr.Element{}, points...)
+	duplicates[1] = []fr.Element{points[1][0], points[1][0]}
+	_, err = MultiOpen(polynomials, digests, duplicates, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPointSet)
+	assert.ErrorIs(MultiVerify(digests, &proof, duplicates, hf, testSrs.Vk), ErrInvalidPointSet)
+}
+
+func TestMultiOpenSinglePoint(t *testing.T) {
+	assert := require.New(t)
+
+	// a single polynomial at a single point, and at more points than its size
+	hf := sha256.New()
+	p := randomPolynomial(20)
+	digest, err := Commit(p, testSrs.Pk)
+	assert.NoError(err)
+	for _, nbPoints := range []int{1, 20, 25} {
+		points := make([]fr.Element, nbPoints)
+		for i := range points {
+			points[i].SetRandom()
+		}
+		proof, err := MultiOpen([][]fr.Element{p}, []Digest{digest}, [][]fr.Element{points}, hf, testSrs.Pk)
+		assert.NoError(err)
+		assert.NoError(MultiVerify([]Digest{digest}, &proof, [][]fr.Element{points}, hf, testSrs.Vk), "nbPoints=%d", nbPoints)
+	}
+}
+
+func BenchmarkMultiOpen(b *testing.B) {
+	polynomials, digests, points := multiOpeningInstance(b)
+	hf := sha256.New()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = MultiOpen(polynomials, digests, points, hf, testSrs.Pk)
+	}
+}
+
+func BenchmarkMultiVerify(b *testing.B) {
+	polynomials, digests, points := multiOpeningInstance(b)
+	hf := sha256.New()
+	proof, err := MultiOpen(polynomials, digests, points, hf, testSrs.Pk)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = MultiVerify(digests, &proof, points, hf, testSrs.Vk)
+	}
+}
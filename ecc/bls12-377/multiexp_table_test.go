@@ -0,0 +1,374 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls12377
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-377/fr"
+)
+
+// multiExpTableBasesG1 returns the points [i]g for i in [1, n]
+func multiExpTableBasesG1(n int) []G1Affine {
+	bases := make([]G1Affine, n)
+	var g G1Jac
+	g.Set(&g1Gen)
+	for i := range bases {
+		bases[i].FromJacobian(&g)
+		g.AddAssign(&g1Gen)
+	}
+	return bases
+}
+
+func TestMultiExpTableG1(t *testing.T) {
+	const nbBases = 137
+
+	bases := multiExpTableBasesG1(nbBases)
+	bases[5].setInfinity()
+	scalars := make([]fr.Element, nbBases)
+	fillBenchScalars(scalars)
+	scalars[7].SetZero()
+	scalars[8].SetOne()
+	scalars[9].SetOne().Neg(&scalars[9])
+
+	cRange := append([]uint64{0}, implementedCsG1()...)
+	if testing.Short() {
+		cRange = cRange[:2]
+	}
+
+	for _, c := range cRange {
+		table, err := NewG1MultiExpTable(bases, int(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if table.NbBases() != nbBases {
+			t.Fatal("wrong number of bases")
+		}
+		tableBases := table.Bases()
+		for i := range bases {
+			if !tableBases[i].Equal(&bases[i]) {
+				t.Fatal("wrong bases")
+			}
+		}
+
+		// all the bases, and a prefix of the bases
+		for _, n := range []int{nbBases, nbBases / 2, 1, 0} {
+			for _, nbTasks := range []int{0, 1, 5} {
+				var expected, got G1Jac
+				config := ecc.MultiExpConfig{NbTasks: nbTasks}
+				if _, err := expected.MultiExp(bases[:n], scalars[:n], config); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := got.MultiExpWithTable(table, scalars[:n], config); err != nil {
+					t.Fatal(err)
+				}
+				if !expected.Equal(&got) {
+					t.Fatalf("c=%d, n=%d, nbTasks=%d: mismatch with MultiExp", table.WindowSize(), n, nbTasks)
+				}
+			}
+		}
+
+		var p G1Jac
+		if _, err := p.MultiExpWithTable(table, make([]fr.Element, nbBases+1), ecc.MultiExpConfig{}); err == nil {
+			t.Fatal("expected an error with more scalars than bases")
+		}
+	}
+
+	if _, err := NewG1MultiExpTable(bases, 1); err == nil {
+		t.Fatal("expected an error with an invalid window size")
+	}
+}
+
+func TestMultiExpTableG1Serialization(t *testing.T) {
+	const nbBases = 20
+
+	bases := multiExpTableBasesG1(nbBases)
+	scalars := make([]fr.Element, nbBases)
+	fillBenchScalars(scalars)
+
+	table, err := NewG1MultiExpTable(bases, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected G1Affine
+	if _, err := expected.MultiExpWithTable(table, scalars, ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	written, err := table.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != int64(buf.Len()) {
+		t.Fatal("wrong number of bytes written")
+	}
+	serialized := buf.Bytes()
+
+	for _, unsafe := range []bool{false, true} {
+		var decoded G1MultiExpTable
+		var read int64
+		if unsafe {
+			read, err = decoded.UnsafeReadFrom(bytes.NewReader(serialized))
+		} else {
+			read, err = decoded.ReadFrom(bytes.NewReader(serialized))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read != written {
+			t.Fatal("wrong number of bytes read")
+		}
+		var got G1Affine
+		if _, err := got.MultiExpWithTable(&decoded, scalars, ecc.MultiExpConfig{}); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(&expected) {
+			t.Fatal("mismatch after deserialization")
+		}
+	}
+
+	// truncated table
+	var decoded G1MultiExpTable
+	if _, err := decoded.ReadFrom(bytes.NewReader(serialized[:len(serialized)-1])); err == nil {
+		t.Fatal("expected an error with a truncated table")
+	}
+
+	// table whose points are in the subgroup, but are not the multiples of the bases
+	nbChunks := int(computeNbChunks(table.c))
+	table.points[1], table.points[nbChunks+1] = table.points[nbChunks+1], table.points[1]
+	buf.Reset()
+	if _, err := table.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decoded.ReadFrom(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Fatal("expected an error with inconsistent multiples")
+	}
+	if _, err := decoded.UnsafeReadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkMultiExpTableG1(b *testing.B) {
+	const nbSamples = 1 << 16
+
+	samplePoints := make([]G1Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	fillBenchScalars(sampleScalars)
+	fillBenchBasesG1(samplePoints)
+
+	table, err := NewG1MultiExpTable(samplePoints, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var testPoint G1Affine
+	for i := 10; i <= 16; i += 2 {
+		using := 1 << i
+
+		b.Run(fmt.Sprintf("%d points-MultiExp", using), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], sampleScalars[:using], ecc.MultiExpConfig{})
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d points-MultiExpWithTable", using), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpWithTable(table, sampleScalars[:using], ecc.MultiExpConfig{})
+			}
+		})
+	}
+}
+
+// multiExpTableBasesG2 returns the points [i]g for i in [1, n]
+func multiExpTableBasesG2(n int) []G2Affine {
+	bases := make([]G2Affine, n)
+	var g G2Jac
+	g.Set(&g2Gen)
+	for i := range bases {
+		bases[i].FromJacobian(&g)
+		g.AddAssign(&g2Gen)
+	}
+	return bases
+}
+
+func TestMultiExpTableG2(t *testing.T) {
+	const nbBases = 137
+
+	bases := multiExpTableBasesG2(nbBases)
+	bases[5].setInfinity()
+	scalars := make([]fr.Element, nbBases)
+	fillBenchScalars(scalars)
+	scalars[7].SetZero()
+	scalars[8].SetOne()
+	scalars[9].SetOne().Neg(&scalars[9])
+
+	cRange := append([]uint64{0}, implementedCsG2()...)
+	if testing.Short() {
+		cRange = cRange[:2]
+	}
+
+	for _, c := range cRange {
+		table, err := NewG2MultiExpTable(bases, int(c))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if table.NbBases() != nbBases {
+			t.Fatal("wrong number of bases")
+		}
+		tableBases := table.Bases()
+		for i := range bases {
+			if !tableBases[i].Equal(&bases[i]) {
+				t.Fatal("wrong bases")
+			}
+		}
+
+		// all the bases, and a prefix of the bases
+		for _, n := range []int{nbBases, nbBases / 2, 1, 0} {
+			for _, nbTasks := range []int{0, 1, 5} {
+				var expected, got G2Jac
+				config := ecc.MultiExpConfig{NbTasks: nbTasks}
+				if _, err := expected.MultiExp(bases[:n], scalars[:n], config); err != nil {
+					t.Fatal(err)
+				}
+				if _, err := got.MultiExpWithTable(table, scalars[:n], config); err != nil {
+					t.Fatal(err)
+				}
+				if !expected.Equal(&got) {
+					t.Fatalf("c=%d, n=%d, nbTasks=%d: mismatch with MultiExp", table.WindowSize(), n, nbTasks)
+				}
+			}
+		}
+
+		var p G2Jac
+		if _, err := p.MultiExpWithTable(table, make([]fr.Element, nbBases+1), ecc.MultiExpConfig{}); err == nil {
+			t.Fatal("expected an error with more scalars than bases")
+		}
+	}
+
+	if _, err := NewG2MultiExpTable(bases, 1); err == nil {
+		t.Fatal("expected an error with an invalid window size")
+	}
+}
+
+func TestMultiExpTableG2Serialization(t *testing.T) {
+	const nbBases = 20
+
+	bases := multiExpTableBasesG2(nbBases)
+	scalars := make([]fr.Element, nbBases)
+	fillBenchScalars(scalars)
+
+	table, err := NewG2MultiExpTable(bases, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var expected G2Affine
+	if _, err := expected.MultiExpWithTable(table, scalars, ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	written, err := table.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written != int64(buf.Len()) {
+		t.Fatal("wrong number of bytes written")
+	}
+	serialized := buf.Bytes()
+
+	for _, unsafe := range []bool{false, true} {
+		var decoded G2MultiExpTable
+		var read int64
+		if unsafe {
+			read, err = decoded.UnsafeReadFrom(bytes.NewReader(serialized))
+		} else {
+			read, err = decoded.ReadFrom(bytes.NewReader(serialized))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if read != written {
+			t.Fatal("wrong number of bytes read")
+		}
+		var got G2Affine
+		if _, err := got.MultiExpWithTable(&decoded, scalars, ecc.MultiExpConfig{}); err != nil {
+			t.Fatal(err)
+		}
+		if !got.Equal(&expected) {
+			t.Fatal("mismatch after deserialization")
+		}
+	}
+
+	// truncated table
+	var decoded G2MultiExpTable
+	if _, err := decoded.ReadFrom(bytes.NewReader(serialized[:len(serialized)-1])); err == nil {
+		t.Fatal("expected an error with a truncated table")
+	}
+
+	// table whose points are in the subgroup, but are not the multiples of the bases
+	nbChunks := int(computeNbChunks(table.c))
+	table.points[1], table.points[nbChunks+1] = table.points[nbChunks+1], table.points[1]
+	buf.Reset()
+	if _, err := table.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decoded.ReadFrom(bytes.NewReader(buf.Bytes())); err == nil {
+		t.Fatal("expected an error with inconsistent multiples")
+	}
+	if _, err := decoded.UnsafeReadFrom(bytes.NewReader(buf.Bytes())); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func BenchmarkMultiExpTableG2(b *testing.B) {
+	const nbSamples = 1 << 16
+
+	samplePoints := make([]G2Affine, nbSamples)
+	sampleScalars := make([]fr.Element, nbSamples)
+	fillBenchScalars(sampleScalars)
+	fillBenchBasesG2(samplePoints)
+
+	table, err := NewG2MultiExpTable(samplePoints, 0)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	var testPoint G2Affine
+	for i := 10; i <= 16; i += 2 {
+		using := 1 << i
+
+		b.Run(fmt.Sprintf("%d points-MultiExp", using), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExp(samplePoints[:using], sampleScalars[:using], ecc.MultiExpConfig{})
+			}
+		})
+
+		b.Run(fmt.Sprintf("%d points-MultiExpWithTable", using), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				testPoint.MultiExpWithTable(table, sampleScalars[:using], ecc.MultiExpConfig{})
+			}
+		})
+	}
+}
@@ -264,6 +264,58 @@ func TestEddsaSHA256(t *testing.T) {
 
 }
 
+func TestBatchVerify(t *testing.T) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 weak rng is fine here
+
+	hFunc := hash.MIMC_BLS12_377.New()
+
+	const n = 10
+	publicKeys := make([]PublicKey, n)
+	sigs := make([][]byte, n)
+	messages := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, err := GenerateKey(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeys[i] = privKey.PublicKey
+		var frMsg fr.Element
+		frMsg.SetUint64(uint64(i))
+		msgBin := frMsg.Bytes()
+		messages[i] = msgBin[:]
+		if sigs[i], err = privKey.Sign(messages[i], hFunc); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// valid batch
+	ok, idx, err := BatchVerify(publicKeys, sigs, messages, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok || idx != -1 {
+		t.Fatal("valid batch should be accepted")
+	}
+
+	// invalid signature at index 7
+	messages[7], messages[8] = messages[8], messages[7]
+	ok, idx, err = BatchVerify(publicKeys, sigs, messages, hFunc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok || idx != 7 {
+		t.Fatal("invalid batch should be rejected at index 7")
+	}
+	messages[7], messages[8] = messages[8], messages[7]
+
+	// wrong sizes
+	if _, _, err = BatchVerify(publicKeys[1:], sigs, messages, hFunc); err != errLenMismatch {
+		t.Fatal("expected length mismatch error")
+	}
+}
+
 // benchmarks
 
 func BenchmarkVerify(b *testing.B) {
@@ -289,3 +341,30 @@ func BenchmarkVerify(b *testing.B) {
 		pubKey.Verify(signature, msgBin[:], hFunc)
 	}
 }
+
+func BenchmarkBatchVerify(b *testing.B) {
+
+	src := rand.NewSource(0)
+	r := rand.New(src) //#nosec G404 weak rng is fine here
+
+	hFunc := hash.MIMC_BLS12_377.New()
+
+	const n = 128
+	publicKeys := make([]PublicKey, n)
+	sigs := make([][]byte, n)
+	messages := make([][]byte, n)
+	for i := 0; i < n; i++ {
+		privKey, _ := GenerateKey(r)
+		publicKeys[i] = privKey.PublicKey
+		var frMsg fr.Element
+		frMsg.SetRandom()
+		msgBin := frMsg.Bytes()
+		messages[i] = msgBin[:]
+		sigs[i], _ = privKey.Sign(messages[i], hFunc)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		BatchVerify(publicKeys, sigs, messages, hFunc)
+	}
+}
@@ -89,7 +89,7 @@ func GenerateKey(rand io.Reader) (*PrivateKey, error) {
 
 	privateKey := new(PrivateKey)
 	k.FillBytes(privateKey.scalar[:sizeFr])
-	privateKey.PublicKey.A.ScalarMultiplication(&g, k)
+	privateKey.PublicKey.A.ScalarMultiplicationConstantTime(&g, k)
 	return privateKey, nil
 }
 
@@ -183,31 +183,37 @@ func (privKey *PrivateKey) Public() signature.PublicKey {
 	return &pub
 }
 
-// Sign performs the ECDSA signature
-//
-// k ← 𝔽r (random)
-// P = k ⋅ g1Gen
-// r = x_P (mod order)
-// s = k⁻¹ . (m + sk ⋅ r)
-// signature = {r, s}
-//
-// SEC 1, Version 2.0, Section 4.1.3
-func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
-	scalar, r, s, kInv := new(big.Int), new(big.Int), new(big.Int), new(big.Int)
+// hashMessage returns the hash of the message if hFunc is not nil, the message
+// itself otherwise.
+func hashMessage(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	dataToHash := make([]byte, len(message))
+	copy(dataToHash[:], message[:])
+	hFunc.Reset()
+	if _, err := hFunc.Write(dataToHash[:]); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
+
+// sign computes the signature (r, s) of the hashed message m, drawing the
+// nonces from nextK until r and s are both non-zero.
+func (privKey *PrivateKey) sign(m *big.Int, nextK func() (*big.Int, error)) (r, s *big.Int, err error) {
+	r, s = new(big.Int), new(big.Int)
+
+	scalar, kInv := new(big.Int), new(big.Int)
 	scalar.SetBytes(privKey.scalar[:sizeFr])
 	for {
 		for {
-			csprng, err := nonce(privKey, message)
-			if err != nil {
-				return nil, err
-			}
-			k, err := randFieldElement(csprng)
+			k, err := nextK()
 			if err != nil {
-				return nil, err
+				return nil, nil, err
 			}
 
 			var P bls12378.G1Affine
-			P.ScalarMultiplicationBase(k)
+			P.ScalarMultiplicationBaseConstantTime(k)
 			kInv.ModInverse(k, order)
 
 			P.X.BigInt(r)
@@ -218,23 +224,6 @@ func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error)
 			}
 		}
 		s.Mul(r, scalar)
-
-		var m *big.Int
-		if hFunc != nil {
-			// compute the hash of the message as an integer
-			dataToHash := make([]byte, len(message))
-			copy(dataToHash[:], message[:])
-			hFunc.Reset()
-			_, err := hFunc.Write(dataToHash[:])
-			if err != nil {
-				return nil, err
-			}
-			hramBin := hFunc.Sum(nil)
-			m = HashToInt(hramBin)
-		} else {
-			m = HashToInt(message)
-		}
-
 		s.Add(m, s).
 			Mul(kInv, s).
 			Mod(s, order) // order != 0
@@ -243,11 +232,77 @@ func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error)
 		}
 	}
 
+	return r, s, nil
+}
+
+// randomNonces returns a nonce generator for sign, drawing each nonce from a
+// fresh CSPRNG seeded by the private key, system entropy and the message.
+func (privKey *PrivateKey) randomNonces(message []byte) func() (*big.Int, error) {
+	return func() (*big.Int, error) {
+		csprng, err := nonce(privKey, message)
+		if err != nil {
+			return nil, err
+		}
+		return randFieldElement(csprng)
+	}
+}
+
+// deterministicNonces returns a nonce generator for sign, deriving the nonces
+// from the private key and the message hash h1 per RFC 6979.
+func (privKey *PrivateKey) deterministicNonces(h1 []byte, newHash func() hash.Hash) func() (*big.Int, error) {
+	return newRFC6979(privKey.scalar[:sizeFr], h1, newHash).next
+}
+
+// Sign performs the ECDSA signature
+//
+// k ← 𝔽r (random)
+// P = k ⋅ g1Gen
+// r = x_P (mod order)
+// s = k⁻¹ . (m + sk ⋅ r)
+// signature = {r, s}
+//
+// SEC 1, Version 2.0, Section 4.1.3
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	h, err := hashMessage(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+	r, s, err := privKey.sign(HashToInt(h), privKey.randomNonces(message))
+	if err != nil {
+		return nil, err
+	}
+	return signatureBytes(r, s), nil
+}
+
+// SignDeterministic performs the ECDSA signature with the nonce k derived
+// deterministically from the private key and the message hash, per RFC 6979.
+//
+// k ← HMAC-DRBG(sk, H(m)) (RFC 6979, Section 3.2)
+// P = k ⋅ g1Gen
+// r = x_P (mod order)
+// s = k⁻¹ . (m + sk ⋅ r)
+// signature = {r, s}
+//
+// hFunc is the (optional) hash applied to the message, as in Sign. newHash
+// instantiates the hash function underlying the HMAC-DRBG (e.g. sha256.New);
+// it is chosen independently of hFunc.
+func (privKey *PrivateKey) SignDeterministic(message []byte, hFunc hash.Hash, newHash func() hash.Hash) ([]byte, error) {
+	h, err := hashMessage(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+	r, s, err := privKey.sign(HashToInt(h), privKey.deterministicNonces(h, newHash))
+	if err != nil {
+		return nil, err
+	}
+	return signatureBytes(r, s), nil
+}
+
+func signatureBytes(r, s *big.Int) []byte {
 	var sig Signature
 	r.FillBytes(sig.R[:sizeFr])
 	s.FillBytes(sig.S[:sizeFr])
-
-	return sig.Bytes(), nil
+	return sig.Bytes()
 }
 
 // Verify validates the ECDSA signature
@@ -269,21 +324,11 @@ func (publicKey *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (boo
 
 	sInv := new(big.Int).ModInverse(s, order)
 
-	var m *big.Int
-	if hFunc != nil {
-		// compute the hash of the message as an integer
-		dataToHash := make([]byte, len(message))
-		copy(dataToHash[:], message[:])
-		hFunc.Reset()
-		_, err := hFunc.Write(dataToHash[:])
-		if err != nil {
-			return false, err
-		}
-		hramBin := hFunc.Sum(nil)
-		m = HashToInt(hramBin)
-	} else {
-		m = HashToInt(message)
+	h, err := hashMessage(message, hFunc)
+	if err != nil {
+		return false, err
 	}
+	m := HashToInt(h)
 
 	u1 := new(big.Int).Mul(m, sInv)
 	u1.Mod(u1, order)
@@ -201,6 +201,164 @@ func (vector Vector) Swap(i, j int) {
 	vector[i], vector[j] = vector[j], vector[i]
 }
 
+// Add adds two vectors element-wise and stores the result in vector.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Add(a, b Vector) {
+	if len(a) != len(b) || len(a) != len(*vector) {
+		panic("vector.Add: vectors don't have the same length")
+	}
+	v := *vector
+	executeVector(len(v), func(start, end int) {
+		vectorAdd(v[start:end], a[start:end], b[start:end])
+	})
+}
+
+// Sub subtracts two vectors element-wise and stores the result in vector.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Sub(a, b Vector) {
+	if len(a) != len(b) || len(a) != len(*vector) {
+		panic("vector.Sub: vectors don't have the same length")
+	}
+	v := *vector
+	executeVector(len(v), func(start, end int) {
+		vectorSub(v[start:end], a[start:end], b[start:end])
+	})
+}
+
+// ScalarMul multiplies a vector by a scalar element-wise and stores the result in vector.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) ScalarMul(a Vector, b *Element) {
+	if len(a) != len(*vector) {
+		panic("vector.ScalarMul: vectors don't have the same length")
+	}
+	v := *vector
+	s := *b
+	executeVector(len(v), func(start, end int) {
+		vectorScalarMul(v[start:end], a[start:end], &s)
+	})
+}
+
+// Mul multiplies two vectors element-wise (Hadamard product) and stores the result in vector.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) Mul(a, b Vector) {
+	if len(a) != len(b) || len(a) != len(*vector) {
+		panic("vector.Mul: vectors don't have the same length")
+	}
+	v := *vector
+	executeVector(len(v), func(start, end int) {
+		vectorMul(v[start:end], a[start:end], b[start:end])
+	})
+}
+
+// AXPY sets vector to alpha * x + vector element-wise.
+// It panics if the vectors don't have the same length.
+func (vector *Vector) AXPY(alpha *Element, x Vector) {
+	if len(x) != len(*vector) {
+		panic("vector.AXPY: vectors don't have the same length")
+	}
+	v := *vector
+	s := *alpha
+	executeVector(len(v), func(start, end int) {
+		vectorAXPY(v[start:end], &s, x[start:end])
+	})
+}
+
+// Sum computes the sum of all elements in the vector.
+func (vector Vector) Sum() (res Element) {
+	var lock sync.Mutex
+	executeVector(len(vector), func(start, end int) {
+		var partial Element
+		vectorSum(&partial, vector[start:end])
+		lock.Lock()
+		res.Add(&res, &partial)
+		lock.Unlock()
+	})
+	return
+}
+
+// InnerProduct computes the inner product of two vectors.
+// It panics if the vectors don't have the same length.
+func (vector Vector) InnerProduct(other Vector) (res Element) {
+	if len(vector) != len(other) {
+		panic("vector.InnerProduct: vectors don't have the same length")
+	}
+	var lock sync.Mutex
+	executeVector(len(vector), func(start, end int) {
+		var partial Element
+		vectorInnerProduct(&partial, vector[start:end], other[start:end])
+		lock.Lock()
+		res.Add(&res, &partial)
+		lock.Unlock()
+	})
+	return
+}
+
+func addVecGeneric(res, a, b Vector) {
+	for i := 0; i < len(res); i++ {
+		res[i].Add(&a[i], &b[i])
+	}
+}
+
+func subVecGeneric(res, a, b Vector) {
+	for i := 0; i < len(res); i++ {
+		res[i].Sub(&a[i], &b[i])
+	}
+}
+
+func sumVecGeneric(res *Element, a Vector) {
+	res.SetZero()
+	for i := 0; i < len(a); i++ {
+		res.Add(res, &a[i])
+	}
+}
+
+func scalarMulVecGeneric(res, a Vector, b *Element) {
+	for i := 0; i < len(res); i++ {
+		res[i].Mul(&a[i], b)
+	}
+}
+
+func mulVecGeneric(res, a, b Vector) {
+	for i := 0; i < len(res); i++ {
+		res[i].Mul(&a[i], &b[i])
+	}
+}
+
+func axpyVecGeneric(res Vector, alpha *Element, x Vector) {
+	var tmp Element
+	for i := 0; i < len(res); i++ {
+		tmp.Mul(alpha, &x[i])
+		res[i].Add(&res[i], &tmp)
+	}
+}
+
+func innerProductVecGeneric(res *Element, a, b Vector) {
+	var tmp Element
+	res.SetZero()
+	for i := 0; i < len(a); i++ {
+		tmp.Mul(&a[i], &b[i])
+		res.Add(res, &tmp)
+	}
+}
+
+// minVectorChunk is the minimal number of elements processed by a go routine
+// in the element-wise operations on vectors, smaller vectors being processed
+// sequentially.
+const minVectorChunk = 1 << 10
+
+// executeVector splits the nbIterations element-wise operations of work in
+// chunks of at least minVectorChunk elements, processed in parallel.
+func executeVector(nbIterations int, work func(int, int)) {
+	if nbIterations == 0 {
+		return
+	}
+	nbTasks := nbIterations / minVectorChunk
+	if nbCpus := runtime.NumCPU(); nbTasks > nbCpus {
+		nbTasks = nbCpus
+	}
+	execute(nbIterations, work, nbTasks)
+}
+
 // TODO @gbotrel make a public package out of that.
 // execute executes the work function in parallel.
 // this is copy paste from internal/parallel/parallel.go
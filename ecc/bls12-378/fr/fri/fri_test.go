@@ -18,6 +18,7 @@ package fri
 
 import (
 	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"math/big"
 	"testing"
@@ -26,6 +27,7 @@ import (
 	"github.com/leanovate/gopter"
 	"github.com/leanovate/gopter/gen"
 	"github.com/leanovate/gopter/prop"
+	"github.com/stretchr/testify/require"
 )
 
 // logFiber returns u, v such that {g^u, g^v} = f⁻¹((g²)^{_p})
@@ -208,10 +210,206 @@ func TestFRI(t *testing.T) {
 		gen.Int32Range(0, int32(rho*size)),
 	))
 
+	properties.Property("[radix 4 and 8] Derive queries position: points should belong the correct fiber", prop.ForAll(
+
+		func(m int32, iopp IOPP) bool {
+
+			_s := iopp.New(uint64(size), sha256.New())
+			s := _s.(radixFri)
+
+			var g fr.Element
+
+			n := int(s.domain.Cardinality)
+			_m := int(m) % (n >> s.logRadices[0])
+			pos := s.deriveQueriesPositions(_m)
+			g.Set(&s.domain.Generator)
+
+			for i := 0; i < len(pos)-1; i++ {
+
+				// all the points of the fiber g^{pos[i]+k*n/r} are mapped
+				// by x -> xʳ to (gʳ)^{pos[i]}
+				r := 1 << s.logRadices[i]
+				var gr, g1, g2 fr.Element
+				gr.Exp(g, big.NewInt(int64(r)))
+				g1.Exp(gr, big.NewInt(int64(pos[i])))
+				for k := 0; k < r; k++ {
+					g2.Exp(g, big.NewInt(int64(pos[i]+k*n/r))).
+						Exp(g2, big.NewInt(int64(r)))
+					if !g1.Equal(&g2) {
+						return false
+					}
+				}
+
+				// (gʳ)^{pos[i]} belongs to the fiber pos[i+1] of the next domain
+				n = n / r
+				nbLeaves := n >> s.logRadices[i+1]
+				if pos[i+1] != pos[i]%nbLeaves {
+					return false
+				}
+				g2.Exp(gr, big.NewInt(int64(pos[i+1]+(pos[i]/nbLeaves)*nbLeaves)))
+				if !g1.Equal(&g2) {
+					return false
+				}
+				g.Set(&gr)
+			}
+			return true
+		},
+		gen.Int32Range(0, int32(rho*size)),
+		gen.OneConstOf(RADIX_4_FRI, RADIX_8_FRI),
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 
 }
 
+func TestFRIRadixTwoCompatibility(t *testing.T) {
+	// the proofs of RADIX_2_FRI with the default parameters must not change
+	const expected = "2be1fba7d70306f0cda958deaacb29990651ee291c1000f3e1155f93027cc8e5"
+
+	p := randomPolynomial(64, 5)
+	proof, err := RADIX_2_FRI.New(64, sha256.New()).BuildProofOfProximity(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := sha256.New()
+	for _, r := range proof.Rounds {
+		for _, in := range r.Interactions {
+			for _, mp := range in {
+				h.Write(mp.MerkleRoot)
+				for _, b := range mp.ProofSet {
+					h.Write(b)
+				}
+			}
+		}
+		h.Write(r.Evaluation.Marshal())
+	}
+	if hex.EncodeToString(h.Sum(nil)) != expected {
+		t.Fatal("the proof of RADIX_2_FRI changed")
+	}
+}
+
+func TestFRIRadix(t *testing.T) {
+	assert := require.New(t)
+
+	// 2⁹ is not a power of 4, nor of 8
+	for _, size := range []uint64{1, 2, 8, 512, 1024} {
+		p := randomPolynomial(size, 42)
+		for _, iopp := range []IOPP{RADIX_2_FRI, RADIX_4_FRI, RADIX_8_FRI} {
+			if iopp == RADIX_2_FRI && size == 1 {
+				// RADIX_2_FRI folds the polynomial at least once
+				continue
+			}
+			for _, blowup := range []int{2, 4, 16} {
+				name := fmt.Sprintf("size=%d/radix=%d/blowup=%d", size, 2<<iopp, blowup)
+
+				s := iopp.New(size, sha256.New(), WithBlowup(blowup), WithNbQueries(8))
+				proof, err := s.BuildProofOfProximity(p)
+				assert.NoError(err, name)
+				assert.NoError(s.VerifyProofOfProximity(proof), name)
+
+				// opening of the polynomial
+				pos := uint64(3) % (uint64(blowup) * size)
+				openingProof, err := s.Open(p, pos)
+				assert.NoError(err, name)
+				assert.NoError(s.VerifyOpening(pos, openingProof, proof), name)
+
+				// the evaluation of the fully folded polynomial and a committed
+				// evaluation, to be tampered with
+				evaluation := &proof.Evaluation
+				var leaf []byte
+				if iopp == RADIX_2_FRI {
+					assert.Equal(8, len(proof.Rounds), name)
+					assert.Equal(0, len(proof.Queries), name)
+					evaluation = &proof.Rounds[0].Evaluation
+					leaf = proof.Rounds[0].Interactions[0][0].ProofSet[0]
+				} else {
+					assert.Equal(8, len(proof.Queries), name)
+					assert.Equal(0, len(proof.Rounds), name)
+					leaf = proof.Queries[0].Interactions[0].ProofSet[0]
+				}
+
+				var one fr.Element
+				one.SetOne()
+				evaluation.Add(evaluation, &one)
+				assert.Error(s.VerifyProofOfProximity(proof), name)
+				evaluation.Sub(evaluation, &one)
+
+				leaf[len(leaf)-1] ^= 1
+				assert.Error(s.VerifyProofOfProximity(proof), name)
+				leaf[len(leaf)-1] ^= 1
+
+				assert.NoError(s.VerifyProofOfProximity(proof), name)
+			}
+		}
+	}
+}
+
+func TestFRISoundness(t *testing.T) {
+	assert := require.New(t)
+
+	// a polynomial of twice the allowed degree should be rejected: each query
+	// succeeds with probability at most about 1/ρ.
+	size := uint64(256)
+	p := randomPolynomial(2*size, 7)
+	for _, iopp := range []IOPP{RADIX_2_FRI, RADIX_4_FRI, RADIX_8_FRI} {
+		s := iopp.New(size, sha256.New(), WithNbQueries(16))
+		proof, err := s.BuildProofOfProximity(p)
+		assert.NoError(err)
+		assert.ErrorIs(s.VerifyProofOfProximity(proof), ErrProximityTestFolding)
+	}
+}
+
+func TestFRIGrinding(t *testing.T) {
+	assert := require.New(t)
+
+	size := uint64(256)
+	p := randomPolynomial(size, 3)
+
+	s := RADIX_4_FRI.New(size, sha256.New(), WithGrinding(8), WithSecurityLevel(40)).(radixFri)
+	assert.Equal(11, s.nbQueries, "(40-8)/log₂(8) queries, rounded up")
+
+	proof, err := s.BuildProofOfProximity(p)
+	assert.NoError(err)
+	assert.NoError(s.VerifyProofOfProximity(proof))
+
+	// the nonce is the smallest solution, so the previous ones are invalid
+	if proof.Nonce > 0 {
+		proof.Nonce--
+		assert.ErrorIs(s.VerifyProofOfProximity(proof), ErrProofOfWork)
+		proof.Nonce++
+	}
+
+	// a proof without proof of work is rejected, except with probability 2⁻⁸
+	sNoGrinding := RADIX_4_FRI.New(size, sha256.New(), WithSecurityLevel(40)).(radixFri)
+	assert.Equal(14, sNoGrinding.nbQueries)
+	proof, err = RADIX_4_FRI.New(size, sha256.New(), WithNbQueries(11)).BuildProofOfProximity(p)
+	assert.NoError(err)
+	if err = s.VerifyProofOfProximity(proof); err != nil {
+		assert.ErrorIs(err, ErrProofOfWork)
+	}
+}
+
+func TestNbQueries(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(100, NbQueries(100, 2, 0))
+	assert.Equal(34, NbQueries(100, 8, 0))
+	assert.Equal(27, NbQueries(100, 8, 20))
+	assert.Equal(25, NbQueries(100, 16, 0))
+	assert.Equal(1, NbQueries(10, 16, 20))
+	assert.Panics(func() { NbQueries(100, 3, 0) })
+	assert.Panics(func() { RADIX_2_FRI.New(16, sha256.New(), WithBlowup(1)) })
+	{
+		s := RADIX_2_FRI.New(16, sha256.New(), WithGrinding(8))
+		p := make([]fr.Element, 16)
+		_, err := s.BuildProofOfProximity(p)
+		assert.ErrorIs(err, ErrGrindingNotSupported)
+		proof, err := RADIX_2_FRI.New(16, sha256.New()).BuildProofOfProximity(p)
+		assert.NoError(err)
+		assert.ErrorIs(s.VerifyProofOfProximity(proof), ErrGrindingNotSupported)
+	}
+}
+
 // Benchmarks
 
 func BenchmarkProximityVerification(b *testing.B) {
@@ -238,3 +436,27 @@ func BenchmarkProximityVerification(b *testing.B) {
 
 	}
 }
+
+func BenchmarkProximityProof(b *testing.B) {
+
+	const size = 1 << 14
+	p := make([]fr.Element, size)
+	for k := 0; k < size; k++ {
+		p[k].SetRandom()
+	}
+
+	for _, iopp := range []IOPP{RADIX_2_FRI, RADIX_4_FRI, RADIX_8_FRI} {
+		var opts []Option
+		if iopp != RADIX_2_FRI {
+			opts = append(opts, WithGrinding(16))
+		}
+		opts = append(opts, WithSecurityLevel(100))
+		iop := iopp.New(uint64(size), sha256.New(), opts...)
+		b.Run(fmt.Sprintf("radix %d", 2<<iopp), func(b *testing.B) {
+			b.ResetTimer()
+			for l := 0; l < b.N; l++ {
+				_, _ = iop.BuildProofOfProximity(p)
+			}
+		})
+	}
+}
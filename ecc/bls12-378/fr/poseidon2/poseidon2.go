@@ -0,0 +1,312 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+)
+
+var (
+	ErrInvalidSizebuffer = errors.New("the size of the input should match the size of the hash buffer")
+)
+
+const (
+	// sBoxDegree is the degree d of the s-box x ↦ xᵈ, with gcd(d, r-1) = 1
+	// so that the s-box is a permutation of fr.
+	sBoxDegree = 5
+
+	// DefaultWidth is the width of the permutation used by the sponge
+	// construction returned by NewPoseidon2.
+	DefaultWidth = 3
+
+	// DefaultNbFullRounds is the number of full rounds ensuring 128 bits of
+	// security for widths 2 and 3, following the round numbers script of the
+	// reference implementation.
+	DefaultNbFullRounds = 8
+
+	// DefaultNbPartialRounds is the number of partial rounds ensuring 128 bits
+	// of security for widths 2 and 3, following the round numbers script of the
+	// reference implementation.
+	DefaultNbPartialRounds = 56
+)
+
+// Parameters describe parameters of the poseidon2 permutation
+type Parameters struct {
+
+	// size of the state
+	Width int
+
+	// number of full rounds (even number)
+	NbFullRounds int
+
+	// number of partial rounds
+	NbPartialRounds int
+
+	// round keys: ordered by round, a full round has Width keys, a partial
+	// round has a single key
+	RoundKeys [][]fr.Element
+}
+
+// NewParameters returns a new set of parameters for the Poseidon2 permutation.
+// The round keys are derived with the Grain LFSR of the reference
+// implementation.
+//
+// It panics if width is not 2 or 3, or if nbFullRounds is odd.
+func NewParameters(width, nbFullRounds, nbPartialRounds int) *Parameters {
+	if width != 2 && width != 3 {
+		panic("poseidon2: only widths 2 and 3 are supported")
+	}
+	if nbFullRounds%2 != 0 {
+		panic("poseidon2: the number of full rounds must be even")
+	}
+	p := Parameters{Width: width, NbFullRounds: nbFullRounds, NbPartialRounds: nbPartialRounds}
+	p.initRC()
+	return &p
+}
+
+// String returns a description of the parameters, e.g. "Poseidon2-bls12-378[t=3,rF=8,rP=56,d=5]"
+func (p *Parameters) String() string {
+	return fmt.Sprintf("Poseidon2-bls12-378[t=%d,rF=%d,rP=%d,d=%d]", p.Width, p.NbFullRounds, p.NbPartialRounds, sBoxDegree)
+}
+
+// initRC initiate round keys. Only one entry is non zero for the internal
+// rounds, cf https://eprint.iacr.org/2023/323.pdf page 9
+func (p *Parameters) initRC() {
+	g := newGrainLFSR(p.Width, p.NbFullRounds, p.NbPartialRounds)
+
+	rf := p.NbFullRounds / 2
+	p.RoundKeys = make([][]fr.Element, p.NbFullRounds+p.NbPartialRounds)
+	for i := 0; i < len(p.RoundKeys); i++ {
+		n := p.Width
+		if i >= rf && i < rf+p.NbPartialRounds {
+			n = 1
+		}
+		p.RoundKeys[i] = make([]fr.Element, n)
+		for j := 0; j < n; j++ {
+			g.nextElement(&p.RoundKeys[i][j])
+		}
+	}
+}
+
+// grainLFSR is the self-shrinking Grain LFSR used to derive the round
+// constants, as specified in the appendix of https://eprint.iacr.org/2019/458.pdf
+type grainLFSR struct {
+	state [80]uint8
+	pos   int
+}
+
+func newGrainLFSR(width, nbFullRounds, nbPartialRounds int) *grainLFSR {
+	var g grainLFSR
+	i := 0
+	push := func(v uint64, nbBits int) {
+		for j := nbBits - 1; j >= 0; j-- {
+			g.state[i] = uint8(v>>uint(j)) & 1
+			i++
+		}
+	}
+	push(1, 2) // prime field
+	push(0, 4) // s-box x ↦ xᵈ
+	push(fr.Bits, 12)
+	push(uint64(width), 12)
+	push(uint64(nbFullRounds), 10)
+	push(uint64(nbPartialRounds), 10)
+	push(1<<30-1, 30)
+
+	// discard the first 160 bits
+	for j := 0; j < 160; j++ {
+		g.clock()
+	}
+	return &g
+}
+
+// clock updates the state and returns the new bit
+func (g *grainLFSR) clock() uint8 {
+	s, p := &g.state, g.pos
+	b := s[(p+62)%80] ^ s[(p+51)%80] ^ s[(p+38)%80] ^ s[(p+23)%80] ^ s[(p+13)%80] ^ s[p]
+	s[p] = b
+	g.pos = (p + 1) % 80
+	return b
+}
+
+// nextBit returns the next output bit: bits are produced in pairs, the second
+// one is output only if the first one is set.
+func (g *grainLFSR) nextBit() uint8 {
+	for {
+		b1 := g.clock()
+		b2 := g.clock()
+		if b1 == 1 {
+			return b2
+		}
+	}
+}
+
+// nextElement samples fr.Bits bits (big endian) and rejects them until they
+// represent an integer smaller than the modulus.
+func (g *grainLFSR) nextElement(z *fr.Element) {
+	var v big.Int
+	modulus := fr.Modulus()
+	for {
+		v.SetUint64(0)
+		for i := 0; i < fr.Bits; i++ {
+			v.Lsh(&v, 1)
+			if g.nextBit() == 1 {
+				v.SetBit(&v, 0, 1)
+			}
+		}
+		if v.Cmp(modulus) < 0 {
+			z.SetBigInt(&v)
+			return
+		}
+	}
+}
+
+// Permutation stores the buffer of the poseidon2 permutation and provides poseidon2 permutation
+// methods on the buffer
+type Permutation struct {
+	// parameters describing the instance
+	params *Parameters
+}
+
+// NewPermutation returns a new poseidon2 permutation instance.
+// It panics if t is not 2 or 3, or if rf is odd.
+func NewPermutation(t, rf, rp int) *Permutation {
+	return &Permutation{params: NewParameters(t, rf, rp)}
+}
+
+// NewPermutationWithParameters returns a new poseidon2 permutation instance
+// using the provided parameters.
+func NewPermutationWithParameters(params *Parameters) *Permutation {
+	return &Permutation{params: params}
+}
+
+// Parameters returns the parameters of the permutation.
+func (h *Permutation) Parameters() *Parameters {
+	return h.params
+}
+
+// sBox applies the sBox on buffer[index]
+func (h *Permutation) sBox(index int, input []fr.Element) {
+	var tmp fr.Element
+	tmp.Set(&input[index])
+	// sbox degree is 5
+	input[index].Square(&input[index]).
+		Square(&input[index]).
+		Mul(&input[index], &tmp)
+}
+
+// matMulExternalInPlace multiplies the state by the external matrix circ(2,1)
+// (resp. circ(2,1,1) when the width is 3), that is it adds the sum of the
+// entries to each entry.
+func (h *Permutation) matMulExternalInPlace(input []fr.Element) {
+	var sum fr.Element
+	sum.Set(&input[0])
+	for i := 1; i < h.params.Width; i++ {
+		sum.Add(&sum, &input[i])
+	}
+	for i := 0; i < h.params.Width; i++ {
+		input[i].Add(&input[i], &sum)
+	}
+}
+
+// matMulInternalInPlace multiplies the state by the internal matrix
+// [[2,1],[1,3]] (resp. [[2,1,1],[1,2,1],[1,1,3]] when the width is 3).
+func (h *Permutation) matMulInternalInPlace(input []fr.Element) {
+	var sum fr.Element
+	sum.Set(&input[0])
+	for i := 1; i < h.params.Width; i++ {
+		sum.Add(&sum, &input[i])
+	}
+	// the last diagonal entry is 2, the other ones are 1
+	last := h.params.Width - 1
+	input[last].Double(&input[last])
+	for i := 0; i < h.params.Width; i++ {
+		input[i].Add(&input[i], &sum)
+	}
+}
+
+// addRoundKeyInPlace adds the round-th key to the buffer
+func (h *Permutation) addRoundKeyInPlace(round int, input []fr.Element) {
+	for i := 0; i < len(h.params.RoundKeys[round]); i++ {
+		input[i].Add(&input[i], &h.params.RoundKeys[round][i])
+	}
+}
+
+// Permutation applies the permutation on input, and stores the result in input.
+func (h *Permutation) Permutation(input []fr.Element) error {
+	if len(input) != h.params.Width {
+		return ErrInvalidSizebuffer
+	}
+
+	// external matrix multiplication, cf https://eprint.iacr.org/2023/323.pdf page 14 (part 6)
+	h.matMulExternalInPlace(input)
+
+	rf := h.params.NbFullRounds / 2
+	for i := 0; i < rf; i++ {
+		// one round = matMulExternal(sBox_Full(addRoundKey))
+		h.addRoundKeyInPlace(i, input)
+		for j := 0; j < h.params.Width; j++ {
+			h.sBox(j, input)
+		}
+		h.matMulExternalInPlace(input)
+	}
+
+	for i := rf; i < rf+h.params.NbPartialRounds; i++ {
+		// one round = matMulInternal(sBox_sparse(addRoundKey))
+		h.addRoundKeyInPlace(i, input)
+		h.sBox(0, input)
+		h.matMulInternalInPlace(input)
+	}
+	for i := rf + h.params.NbPartialRounds; i < h.params.NbFullRounds+h.params.NbPartialRounds; i++ {
+		// one round = matMulExternal(sBox_Full(addRoundKey))
+		h.addRoundKeyInPlace(i, input)
+		for j := 0; j < h.params.Width; j++ {
+			h.sBox(j, input)
+		}
+		h.matMulExternalInPlace(input)
+	}
+
+	return nil
+}
+
+// Compress is used in BinaryMerkleTree. The inputs are the big endian
+// encodings of two field elements left and right, the output is the big
+// endian encoding of P(left, right, 0...)[1] + right, where P is the
+// permutation. The feed-forward makes the compression function one-way.
+func (h *Permutation) Compress(left []byte, right []byte) ([]byte, error) {
+	if len(left) != fr.Bytes || len(right) != fr.Bytes {
+		return nil, ErrInvalidSizebuffer
+	}
+	x := make([]fr.Element, h.params.Width)
+	if err := x[0].SetBytesCanonical(left); err != nil {
+		return nil, err
+	}
+	if err := x[1].SetBytesCanonical(right); err != nil {
+		return nil, err
+	}
+	var y fr.Element
+	y.Set(&x[1])
+	if err := h.Permutation(x); err != nil {
+		return nil, err
+	}
+	x[1].Add(&x[1], &y)
+	res := x[1].Bytes()
+	return res[:], nil
+}
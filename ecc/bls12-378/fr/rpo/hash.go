@@ -0,0 +1,126 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package rpo
+
+import (
+	"errors"
+	"hash"
+
+	fr "github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+)
+
+const (
+	// BlockSize size that RPO consumes
+	BlockSize = fr.Bytes
+)
+
+// digest is a sponge construction over the RPO permutation. The first Capacity
+// entries of the state are the capacity, the remaining Rate entries are the
+// rate.
+type digest struct {
+	perm *Permutation
+	data []fr.Element // data to hash
+}
+
+// NewRPO returns a sponge hash function over the RPO permutation with
+// DefaultNbRounds rounds.
+func NewRPO() hash.Hash {
+	return NewRPOFromParameters(DefaultNbRounds)
+}
+
+// NewRPOFromParameters returns a sponge hash function over the RPO permutation
+// with the given number of rounds.
+func NewRPOFromParameters(nbRounds int) hash.Hash {
+	return &digest{perm: NewPermutation(nbRounds)}
+}
+
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = d.data[:0]
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	return append(b, encodeDigest(d.checksum())...)
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return DigestSize * fr.Bytes
+}
+
+// BlockSize returns the hash's underlying block size.
+// The Write method must be able to accept any amount
+// of data, but it may operate more efficiently if all writes
+// are a multiple of the block size.
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+//
+// Each []byte block of size BlockSize represents a big endian fr.Element.
+//
+// If len(p) is not a multiple of BlockSize and any of the []byte in p represent an integer
+// larger than fr.Modulus, this function returns an error.
+//
+// To hash arbitrary data ([]byte not representing canonical field elements) use fr.Hash first
+func (d *digest) Write(p []byte) (int, error) {
+	// we usually expect multiple of block size. But sometimes we hash short
+	// values (FS transcript). Instead of forcing to hash to field, we left-pad the
+	// input here.
+	if len(p) > 0 && len(p) < BlockSize {
+		pp := make([]byte, BlockSize)
+		copy(pp[len(pp)-len(p):], p)
+		p = pp
+	}
+
+	var start int
+	for start = 0; start < len(p); start += BlockSize {
+		if elem, err := fr.BigEndian.Element((*[BlockSize]byte)(p[start : start+BlockSize])); err == nil {
+			d.data = append(d.data, elem)
+		} else {
+			return 0, err
+		}
+	}
+
+	if start != len(p) {
+		return 0, errors.New("invalid input length: must represent a list of field elements, expects a []byte of len m*BlockSize")
+	}
+	return len(p), nil
+}
+
+// checksum absorbs the data as the RPO-256 sponge: the first element of the
+// capacity is the number of elements modulo Rate, the elements overwrite the
+// rate, Rate at a time, and the last block is padded with zeroes. It returns
+// the state, whose first DigestSize rate elements are the digest.
+func (d *digest) checksum() []fr.Element {
+	state := make([]fr.Element, Width)
+	state[0].SetUint64(uint64(len(d.data) % Rate))
+
+	for i := 0; i < len(d.data); i += Rate {
+		n := copy(state[Capacity:], d.data[i:])
+		for j := Capacity + n; j < Width; j++ {
+			state[j].SetZero()
+		}
+		// the width of the state is correct by construction
+		_ = d.perm.Permutation(state)
+	}
+
+	return state
+}
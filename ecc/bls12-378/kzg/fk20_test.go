@@ -0,0 +1,130 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr/fft"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAll(t *testing.T) {
+	assert := require.New(t)
+
+	for _, n := range []uint64{2, 16, 32} {
+		domain := fft.NewDomain(n)
+		for _, size := range []int{2, int(n)/2 + 1, int(n)} {
+			p := randomPolynomial(size)
+			digest, err := Commit(p, testSrs.Pk)
+			assert.NoError(err)
+
+			proofs, err := OpenAll(p, domain, testSrs.Pk)
+			assert.NoError(err)
+			assert.Len(proofs, int(n))
+
+			var point fr.Element
+			point.SetOne()
+			for j := range proofs {
+				expected, err := Open(p, point, testSrs.Pk)
+				assert.NoError(err)
+				assert.True(expected.ClaimedValue.Equal(&proofs[j].ClaimedValue), "n=%d size=%d j=%d", n, size, j)
+				assert.True(expected.H.Equal(&proofs[j].H), "n=%d size=%d j=%d", n, size, j)
+				assert.NoError(Verify(&digest, &proofs[j], point, testSrs.Vk))
+				point.Mul(&point, &domain.Generator)
+			}
+		}
+	}
+
+	// errors
+	domain := fft.NewDomain(4)
+	_, err := OpenAll(randomPolynomial(5), domain, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidDomain)
+	_, err = OpenAllCosets(randomPolynomial(4), domain, 3, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidCosetSize)
+	_, err = OpenAllCosets(randomPolynomial(4), domain, 8, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidCosetSize)
+}
+
+func TestOpenAllCosets(t *testing.T) {
+	assert := require.New(t)
+
+	const n = 32
+	domain := fft.NewDomain(n)
+	p := randomPolynomial(n - 3)
+
+	for _, cosetSize := range []int{1, 2, 4, 8, 32} {
+		proofs, err := OpenAllCosets(p, domain, cosetSize, testSrs.Pk)
+		assert.NoError(err)
+		nbCosets := n / cosetSize
+		assert.Len(proofs, nbCosets)
+
+		// c = ω^(i⋅cosetSize)
+		var generator, c fr.Element
+		generator.Exp(domain.Generator, big.NewInt(int64(cosetSize)))
+		c.SetOne()
+		for i := range proofs {
+			q := divideByXPowerMinusC(p, cosetSize, c)
+			expected, err := Commit(q, testSrs.Pk)
+			assert.NoError(err)
+			assert.True(expected.Equal(&proofs[i]), "cosetSize=%d i=%d", cosetSize, i)
+			c.Mul(&c, &generator)
+		}
+	}
+}
+
+// divideByXPowerMinusC returns the quotient of p by Xˡ - c, with at least
+// one coefficient.
+func divideByXPowerMinusC(p []fr.Element, l int, c fr.Element) []fr.Element {
+	if len(p) <= l {
+		return make([]fr.Element, 1)
+	}
+	r := make([]fr.Element, len(p))
+	copy(r, p)
+	q := make([]fr.Element, len(p)-l)
+	for k := len(r) - 1; k >= l; k-- {
+		q[k-l] = r[k]
+		var t fr.Element
+		t.Mul(&r[k], &c)
+		r[k-l].Add(&r[k-l], &t)
+	}
+	return q
+}
+
+func BenchmarkOpenAll(b *testing.B) {
+	const n = 256
+	domain := fft.NewDomain(n)
+	p := randomPolynomial(n)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = OpenAll(p, domain, testSrs.Pk)
+	}
+}
+
+func BenchmarkOpenAllCosets16(b *testing.B) {
+	const n = 256
+	domain := fft.NewDomain(n)
+	p := randomPolynomial(n)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = OpenAllCosets(p, domain, 16, testSrs.Pk)
+	}
+}
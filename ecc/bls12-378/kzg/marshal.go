@@ -154,6 +154,7 @@ func (pk *ProvingKey) ReadFrom(r io.Reader) (int64, error) {
 	if err := dec.Decode(&pk.G1); err != nil {
 		return dec.BytesRead(), err
 	}
+	pk.table = nil
 	return dec.BytesRead(), nil
 }
 
@@ -165,6 +166,7 @@ func (pk *ProvingKey) UnsafeReadFrom(r io.Reader) (int64, error) {
 	if err := dec.Decode(&pk.G1); err != nil {
 		return dec.BytesRead(), err
 	}
+	pk.table = nil
 	return dec.BytesRead(), nil
 }
 
@@ -289,3 +291,135 @@ func (proof *BatchOpeningProof) ReadFrom(r io.Reader) (int64, error) {
 
 	return dec.BytesRead(), nil
 }
+
+// WriteTo writes binary encoding of a MultiOpeningProof
+func (proof *MultiOpeningProof) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12378.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&proof.W,
+		&proof.WPrime,
+		proof.ClaimedValues,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes MultiOpeningProof data from reader.
+func (proof *MultiOpeningProof) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12378.NewDecoder(r)
+	toDecode := []interface{}{
+		&proof.W,
+		&proof.WPrime,
+		&proof.ClaimedValues,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of an UpdateProof
+func (proof *UpdateProof) WriteTo(w io.Writer) (int64, error) {
+	enc := bls12378.NewEncoder(w)
+
+	toEncode := []interface{}{
+		&proof.Tau,
+		&proof.S,
+		&proof.SX,
+		&proof.RX,
+	}
+
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom decodes UpdateProof data from reader.
+func (proof *UpdateProof) ReadFrom(r io.Reader) (int64, error) {
+	dec := bls12378.NewDecoder(r)
+
+	toDecode := []interface{}{
+		&proof.Tau,
+		&proof.S,
+		&proof.SX,
+		&proof.RX,
+	}
+
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+
+	return dec.BytesRead(), nil
+}
+
+// WriteTo writes binary encoding of a Transcript: the SRS, the number of
+// contributions and the contributions.
+func (t *Transcript) WriteTo(w io.Writer) (int64, error) {
+	n, err := t.SRS.WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+
+	enc := bls12378.NewEncoder(w)
+	if err = enc.Encode(uint32(len(t.Contributions))); err != nil {
+		return n + enc.BytesWritten(), err
+	}
+	n += enc.BytesWritten()
+
+	for i := range t.Contributions {
+		m, err := t.Contributions[i].WriteTo(w)
+		n += m
+		if err != nil {
+			return n, err
+		}
+	}
+
+	return n, nil
+}
+
+// ReadFrom decodes Transcript data from reader.
+func (t *Transcript) ReadFrom(r io.Reader) (int64, error) {
+	n, err := t.SRS.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+
+	dec := bls12378.NewDecoder(r)
+	var nbContributions uint32
+	if err = dec.Decode(&nbContributions); err != nil {
+		return n + dec.BytesRead(), err
+	}
+	n += dec.BytesRead()
+
+	// the contributions are appended as they are decoded, so that the
+	// allocations are bounded by the size of the data, not by the count
+	t.Contributions = nil
+	for i := uint32(0); i < nbContributions; i++ {
+		var proof UpdateProof
+		m, err := proof.ReadFrom(r)
+		n += m
+		if err != nil {
+			return n, err
+		}
+		t.Contributions = append(t.Contributions, proof)
+	}
+
+	return n, nil
+}
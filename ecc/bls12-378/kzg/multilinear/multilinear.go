@@ -0,0 +1,498 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package multilinear
+
+import (
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-378/fr/polynomial"
+	"github.com/consensys/gnark-crypto/fiat-shamir"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidNbDigests      = errors.New("number of digests is not the same as the number of polynomials")
+	ErrZeroNbDigests         = errors.New("number of digests is zero")
+	ErrInvalidPolynomialSize = errors.New("invalid polynomial size (not a power of 2 or larger than SRS)")
+	ErrInvalidNbVariables    = errors.New("number of coordinates is not the number of variables")
+	ErrVerifyOpeningProof    = errors.New("can't verify opening proof")
+	ErrMinSRSSize            = errors.New("minimum number of variables is 1")
+)
+
+// Digest commitment of a multilinear polynomial.
+type Digest = bls12378.G1Affine
+
+// ProvingKey used to create or open commitments
+type ProvingKey struct {
+	// G1[i] Lagrange basis [eq(b, (τᵢ₊₁, …, τₙ))]₁ for b ∈ {0,1}ⁿ⁻ⁱ, in the
+	// order of polynomial.MultiLin, so that a polynomial in the n-i last
+	// variables is committed to with G1[i].
+	G1 [][]bls12378.G1Affine
+}
+
+// VerifyingKey used to verify opening proofs
+type VerifyingKey struct {
+	G1  bls12378.G1Affine   // [1]₁
+	G2  bls12378.G2Affine   // [1]₂
+	Tau []bls12378.G2Affine // [τ₁]₂, …, [τₙ]₂
+}
+
+// SRS must be computed through MPC and comprises the ProvingKey and the VerifyingKey
+type SRS struct {
+	Pk ProvingKey
+	Vk VerifyingKey
+}
+
+// NewSRS returns a new SRS for polynomials in up to len(trapdoors) variables,
+// using trapdoors as the secrets τ₁, …, τₙ.
+//
+// In production, a SRS generated through MPC should be used.
+//
+// implements io.ReaderFrom and io.WriterTo
+func NewSRS(trapdoors []*big.Int) (*SRS, error) {
+	n := len(trapdoors)
+	if n < 1 {
+		return nil, ErrMinSRSSize
+	}
+
+	taus := make([]fr.Element, n)
+	for i := range taus {
+		taus[i].SetBigInt(trapdoors[i])
+	}
+
+	var srs SRS
+	_, _, gen1Aff, gen2Aff := bls12378.Generators()
+	srs.Vk.G1 = gen1Aff
+	srs.Vk.G2 = gen2Aff
+	srs.Vk.Tau = make([]bls12378.G2Affine, n)
+	for i := range taus {
+		srs.Vk.Tau[i].ScalarMultiplication(&gen2Aff, trapdoors[i])
+	}
+
+	// G1[i] = [eq(·, (τᵢ₊₁, …, τₙ))]₁
+	srs.Pk.G1 = make([][]bls12378.G1Affine, n+1)
+	for i := range srs.Pk.G1 {
+		eq := make(polynomial.MultiLin, 1<<(n-i))
+		eq[0].SetOne()
+		eq.Eq(taus[i:])
+		srs.Pk.G1[i] = bls12378.BatchScalarMultiplicationG1(&gen1Aff, eq)
+	}
+
+	return &srs, nil
+}
+
+// OpeningProof multilinear KZG proof for opening at a single point.
+//
+// implements io.ReaderFrom and io.WriterTo
+type OpeningProof struct {
+	// Quotients commitments to the qᵢ such that f - f(z) = ∑ᵢ(Xᵢ-zᵢ)qᵢ(Xᵢ₊₁, …, Xₖ)
+	Quotients []bls12378.G1Affine
+
+	// ClaimedValue purported value
+	ClaimedValue fr.Element
+}
+
+// BatchOpeningProof opening proof for many polynomials at the same point
+//
+// implements io.ReaderFrom and io.WriterTo
+type BatchOpeningProof struct {
+	// Quotients commitments to the quotients of ∑ᵢγⁱfᵢ
+	Quotients []bls12378.G1Affine
+
+	// ClaimedValues purported values
+	ClaimedValues []fr.Element
+}
+
+// Commit commits to a multilinear polynomial using a multi exponentiation with the SRS.
+// A polynomial in k variables is committed to with the trapdoors τₙ₋ₖ₊₁, …, τₙ.
+func Commit(p polynomial.MultiLin, pk ProvingKey, nbTasks ...int) (Digest, error) {
+
+	bases, err := lagrangeBasis(p, pk)
+	if err != nil {
+		return Digest{}, err
+	}
+
+	var res bls12378.G1Affine
+
+	config := ecc.MultiExpConfig{}
+	if len(nbTasks) > 0 {
+		config.NbTasks = nbTasks[0]
+	}
+	if _, err := res.MultiExp(bases, p, config); err != nil {
+		return Digest{}, err
+	}
+
+	return res, nil
+}
+
+// Open computes an opening proof of the multilinear polynomial p at the given
+// point, made of one commitment per variable.
+func Open(p polynomial.MultiLin, point []fr.Element, pk ProvingKey) (OpeningProof, error) {
+	if _, err := lagrangeBasis(p, pk); err != nil {
+		return OpeningProof{}, err
+	}
+	k := p.NumVars()
+	if len(point) != k {
+		return OpeningProof{}, ErrInvalidNbVariables
+	}
+	level := len(pk.G1) - 1 - k
+
+	// f(X₁, …, Xₖ) = f(z₁, X₂, …, Xₖ) + (X₁-z₁)(f(1, X₂, …, Xₖ) - f(0, X₂, …, Xₖ))
+	// so that q₁ = f(1, X₂, …, Xₖ) - f(0, X₂, …, Xₖ), and we recurse on f(z₁, X₂, …, Xₖ)
+	res := OpeningProof{
+		Quotients: make([]bls12378.G1Affine, k),
+	}
+	f := p.Clone()
+	q := make([]fr.Element, len(f)/2)
+	for i := 0; i < k; i++ {
+		mid := len(f) / 2
+		q = q[:mid]
+		parallel.Execute(mid, func(start, end int) {
+			for j := start; j < end; j++ {
+				q[j].Sub(&f[mid+j], &f[j])
+			}
+		})
+		if _, err := res.Quotients[i].MultiExp(pk.G1[level+i+1], q, ecc.MultiExpConfig{}); err != nil {
+			return OpeningProof{}, err
+		}
+		f.Fold(point[i])
+	}
+	res.ClaimedValue = f[0]
+
+	return res, nil
+}
+
+// Verify verifies a multilinear KZG opening proof at a single point, that is
+//
+//	e([f(τ)]₁ - [f(z)]₁, [1]₂) = ∏ᵢe([qᵢ(τ)]₁, [τᵢ-zᵢ]₂)
+//
+// which is checked with k+1 pairings as
+//
+//	e([f(τ)]₁ - [f(z)]₁ + ∑ᵢzᵢ[qᵢ(τ)]₁, [1]₂)⋅∏ᵢe(-[qᵢ(τ)]₁, [τᵢ]₂) = 1
+func Verify(commitment *Digest, proof *OpeningProof, point []fr.Element, vk VerifyingKey) error {
+	k := len(point)
+	if len(proof.Quotients) != k || k > len(vk.Tau) {
+		return ErrInvalidNbVariables
+	}
+
+	// [f(τ) - f(z) + ∑ᵢzᵢqᵢ(τ)]₁
+	bases := make([]bls12378.G1Affine, 0, k+2)
+	bases = append(bases, *commitment, vk.G1)
+	bases = append(bases, proof.Quotients...)
+	scalars := make([]fr.Element, 0, k+2)
+	scalars = append(scalars, fr.One(), fr.Element{})
+	scalars[1].Neg(&proof.ClaimedValue)
+	scalars = append(scalars, point...)
+	var left bls12378.G1Affine
+	if _, err := left.MultiExp(bases, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+
+	P := make([]bls12378.G1Affine, k+1)
+	P[0] = left
+	for i := range proof.Quotients {
+		P[i+1].Neg(&proof.Quotients[i])
+	}
+	Q := make([]bls12378.G2Affine, 0, k+1)
+	Q = append(Q, vk.G2)
+	Q = append(Q, vk.Tau[len(vk.Tau)-k:]...)
+
+	check, err := bls12378.PairingCheck(P, Q)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// BatchOpenSinglePoint creates a batch opening proof at point of a list of polynomials.
+// It's an interactive protocol, made non-interactive using Fiat Shamir.
+//
+// * point is the point at which the polynomials are opened.
+// * digests is the list of committed polynomials to open, need to derive the challenge using Fiat Shamir.
+// * polynomials is the list of polynomials to open, they must have the same number of variables.
+// * dataTranscript extra data that might be needed to derive the challenge used for folding
+func BatchOpenSinglePoint(polynomials []polynomial.MultiLin, digests []Digest, point []fr.Element, hf hash.Hash, pk ProvingKey, dataTranscript ...[]byte) (BatchOpeningProof, error) {
+
+	// check for invalid sizes
+	nbDigests := len(digests)
+	if nbDigests != len(polynomials) {
+		return BatchOpeningProof{}, ErrInvalidNbDigests
+	}
+	if nbDigests == 0 {
+		return BatchOpeningProof{}, ErrZeroNbDigests
+	}
+	for i := range polynomials {
+		if len(polynomials[i]) != len(polynomials[0]) {
+			return BatchOpeningProof{}, ErrInvalidPolynomialSize
+		}
+	}
+	if len(point) != polynomials[0].NumVars() {
+		return BatchOpeningProof{}, ErrInvalidNbVariables
+	}
+
+	var res BatchOpeningProof
+
+	// compute the purported values
+	res.ClaimedValues = make([]fr.Element, len(polynomials))
+	parallel.Execute(len(polynomials), func(start, end int) {
+		for i := start; i < end; i++ {
+			res.ClaimedValues[i] = polynomials[i].Evaluate(point, nil)
+		}
+	})
+
+	// derive the challenge γ, binded to the point and the commitments
+	gamma, err := deriveGamma(point, digests, res.ClaimedValues, hf, dataTranscript...)
+	if err != nil {
+		return BatchOpeningProof{}, err
+	}
+
+	// ∑ᵢγⁱfᵢ
+	folded := polynomials[0].Clone()
+	var gammai fr.Element
+	gammai.SetOne()
+	for i := 1; i < len(polynomials); i++ {
+		gammai.Mul(&gammai, &gamma)
+		p := polynomials[i]
+		parallel.Execute(len(p), func(start, end int) {
+			var t fr.Element
+			for j := start; j < end; j++ {
+				t.Mul(&p[j], &gammai)
+				folded[j].Add(&folded[j], &t)
+			}
+		})
+	}
+
+	proof, err := Open(folded, point, pk)
+	if err != nil {
+		return BatchOpeningProof{}, err
+	}
+	res.Quotients = proof.Quotients
+
+	return res, nil
+}
+
+// FoldProof fold the digests and the proofs in batchOpeningProof using Fiat Shamir
+// to obtain an opening proof at a single point.
+//
+// * digests list of digests on which batchOpeningProof is based
+// * batchOpeningProof opening proof of digests
+// * transcript extra data needed to derive the challenge used for folding.
+// * returns the folded version of batchOpeningProof, Digest, the folded version of digests
+func FoldProof(digests []Digest, batchOpeningProof *BatchOpeningProof, point []fr.Element, hf hash.Hash, dataTranscript ...[]byte) (OpeningProof, Digest, error) {
+
+	nbDigests := len(digests)
+
+	// check consistency between numbers of claims vs number of digests
+	if nbDigests != len(batchOpeningProof.ClaimedValues) {
+		return OpeningProof{}, Digest{}, ErrInvalidNbDigests
+	}
+	if nbDigests == 0 {
+		return OpeningProof{}, Digest{}, ErrZeroNbDigests
+	}
+
+	// derive the challenge γ, binded to the point and the commitments
+	gamma, err := deriveGamma(point, digests, batchOpeningProof.ClaimedValues, hf, dataTranscript...)
+	if err != nil {
+		return OpeningProof{}, Digest{}, err
+	}
+
+	// fold the claimed values and digests
+	// gammai = [1,γ,γ²,..,γⁿ⁻¹]
+	gammai := make([]fr.Element, nbDigests)
+	gammai[0].SetOne()
+	for i := 1; i < nbDigests; i++ {
+		gammai[i].Mul(&gammai[i-1], &gamma)
+	}
+
+	var res OpeningProof
+	var foldedDigests Digest
+	if _, err = foldedDigests.MultiExp(digests, gammai, ecc.MultiExpConfig{}); err != nil {
+		return OpeningProof{}, Digest{}, err
+	}
+	var t fr.Element
+	for i := range gammai {
+		t.Mul(&batchOpeningProof.ClaimedValues[i], &gammai[i])
+		res.ClaimedValue.Add(&res.ClaimedValue, &t)
+	}
+	res.Quotients = batchOpeningProof.Quotients
+
+	return res, foldedDigests, nil
+}
+
+// BatchVerifySinglePoint verifies a batched opening proof at a single point of a list of polynomials.
+//
+// * digests list of digests on which opening proof is done
+// * batchOpeningProof proof of correct opening on the digests
+// * dataTranscript extra data that might be needed to derive the challenge used for the folding
+func BatchVerifySinglePoint(digests []Digest, batchOpeningProof *BatchOpeningProof, point []fr.Element, hf hash.Hash, vk VerifyingKey, dataTranscript ...[]byte) error {
+
+	// fold the proof
+	foldedProof, foldedDigest, err := FoldProof(digests, batchOpeningProof, point, hf, dataTranscript...)
+	if err != nil {
+		return err
+	}
+
+	// verify the foldedProof against the foldedDigest
+	return Verify(&foldedDigest, &foldedProof, point, vk)
+}
+
+// BatchVerifyMultiPoints batch verifies a list of opening proofs at different points,
+// possibly of polynomials with different numbers of variables.
+// The purpose of the batching is to have only n+1 pairings for verifying
+// several proofs, where n is the number of variables of the SRS.
+//
+// * digests list of committed polynomials
+// * proofs list of opening proofs, one for each digest
+// * points the list of points at which the opening are done
+func BatchVerifyMultiPoints(digests []Digest, proofs []OpeningProof, points [][]fr.Element, vk VerifyingKey) error {
+
+	// check consistency nb proofs vs nb digests
+	if len(digests) != len(proofs) || len(digests) != len(points) {
+		return ErrInvalidNbDigests
+	}
+	if len(digests) == 0 {
+		return ErrZeroNbDigests
+	}
+
+	// if only one digest, call Verify
+	if len(digests) == 1 {
+		return Verify(&digests[0], &proofs[0], points[0], vk)
+	}
+
+	n := len(vk.Tau)
+	for i := range points {
+		if len(proofs[i].Quotients) != len(points[i]) || len(points[i]) > n {
+			return ErrInvalidNbVariables
+		}
+	}
+
+	// sample random numbers λⱼ for sampling
+	randomNumbers := make([]fr.Element, len(digests))
+	randomNumbers[0].SetOne()
+	for i := 1; i < len(randomNumbers); i++ {
+		if _, err := randomNumbers[i].SetRandom(); err != nil {
+			return err
+		}
+	}
+
+	// left = ∑ⱼλⱼ([fⱼ(τ)]₁ - [fⱼ(zⱼ)]₁ + ∑ᵢzⱼᵢ[qⱼᵢ(τ)]₁)
+	// right[t] = ∑ⱼλⱼ[qⱼᵢ(τ)]₁ where qⱼᵢ is paired with [τₜ]₂
+	var foldedEvals, t fr.Element
+	leftBases := append([]bls12378.G1Affine{}, digests...)
+	leftScalars := append([]fr.Element{}, randomNumbers...)
+	rightBases := make([][]bls12378.G1Affine, n)
+	rightScalars := make([][]fr.Element, n)
+	for j := range proofs {
+		t.Mul(&proofs[j].ClaimedValue, &randomNumbers[j])
+		foldedEvals.Add(&foldedEvals, &t)
+		offset := n - len(points[j])
+		for i := range proofs[j].Quotients {
+			t.Mul(&points[j][i], &randomNumbers[j])
+			leftBases = append(leftBases, proofs[j].Quotients[i])
+			leftScalars = append(leftScalars, t)
+			rightBases[offset+i] = append(rightBases[offset+i], proofs[j].Quotients[i])
+			rightScalars[offset+i] = append(rightScalars[offset+i], randomNumbers[j])
+		}
+	}
+	leftBases = append(leftBases, vk.G1)
+	leftScalars = append(leftScalars, *foldedEvals.Neg(&foldedEvals))
+
+	config := ecc.MultiExpConfig{}
+	P := make([]bls12378.G1Affine, 1, n+1)
+	Q := make([]bls12378.G2Affine, 1, n+1)
+	if _, err := P[0].MultiExp(leftBases, leftScalars, config); err != nil {
+		return err
+	}
+	Q[0] = vk.G2
+	for i := range rightBases {
+		if len(rightBases[i]) == 0 {
+			continue
+		}
+		var r bls12378.G1Affine
+		if _, err := r.MultiExp(rightBases[i], rightScalars[i], config); err != nil {
+			return err
+		}
+		r.Neg(&r)
+		P = append(P, r)
+		Q = append(Q, vk.Tau[i])
+	}
+
+	check, err := bls12378.PairingCheck(P, Q)
+	if err != nil {
+		return err
+	}
+	if !check {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
+
+// lagrangeBasis returns the part of the SRS used to commit to p, checking that
+// the size of p is a power of 2 and that p has no more variables than the SRS.
+func lagrangeBasis(p polynomial.MultiLin, pk ProvingKey) ([]bls12378.G1Affine, error) {
+	k := p.NumVars()
+	if len(p) == 0 || len(p) != 1<<k || k >= len(pk.G1) {
+		return nil, ErrInvalidPolynomialSize
+	}
+	return pk.G1[len(pk.G1)-1-k], nil
+}
+
+// deriveGamma derives a challenge using Fiat Shamir to fold proofs.
+func deriveGamma(point []fr.Element, digests []Digest, claimedValues []fr.Element, hf hash.Hash, dataTranscript ...[]byte) (fr.Element, error) {
+
+	// derive the challenge gamma, binded to the point and the commitments
+	fs := fiatshamir.NewTranscript(hf, "gamma")
+	for i := range point {
+		if err := fs.Bind("gamma", point[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := range digests {
+		if err := fs.Bind("gamma", digests[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+	for i := range claimedValues {
+		if err := fs.Bind("gamma", claimedValues[i].Marshal()); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	for i := 0; i < len(dataTranscript); i++ {
+		if err := fs.Bind("gamma", dataTranscript[i]); err != nil {
+			return fr.Element{}, err
+		}
+	}
+
+	gammaByte, err := fs.ComputeChallenge("gamma")
+	if err != nil {
+		return fr.Element{}, err
+	}
+	var gamma fr.Element
+	gamma.SetBytes(gammaByte)
+
+	return gamma, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/utils/testutils"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestScalarMultiplicationConstantTime(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[Extended] constant time scalar multiplication should match the scalar multiplication", prop.ForAll(
+		func(s, m big.Int) bool {
+			params := GetEdwardsCurve()
+
+			var q, expected, res PointExtended
+			q.FromAffine(&params.Base)
+			q.ScalarMultiplication(&q, &m)
+			expected.ScalarMultiplication(&q, &s)
+			res.ScalarMultiplicationConstantTime(&q, &s)
+			return res.Equal(&expected)
+		},
+		GenBigInt(),
+		GenBigInt(),
+	))
+
+	properties.Property("[Affine] constant time scalar multiplication should match the scalar multiplication", prop.ForAll(
+		func(s, m big.Int) bool {
+			params := GetEdwardsCurve()
+
+			var q, expected, res PointAffine
+			q.ScalarMultiplication(&params.Base, &m)
+			expected.ScalarMultiplication(&q, &s)
+			res.ScalarMultiplicationConstantTime(&q, &s)
+			return res.Equal(&expected)
+		},
+		GenBigInt(),
+		GenBigInt(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// edge cases
+	params := GetEdwardsCurve()
+	var base PointExtended
+	base.FromAffine(&params.Base)
+	for _, s := range constantTimeTestScalars() {
+		var expected, res PointExtended
+		var expectedAff, resAff PointAffine
+		expected.scalarMulWindowed(&base, s)
+		res.ScalarMultiplicationConstantTime(&base, s)
+		if !res.Equal(&expected) {
+			t.Fatalf("[Extended] mismatch for s=%s", s.String())
+		}
+		expectedAff.FromExtended(&expected)
+		resAff.ScalarMultiplicationConstantTime(&params.Base, s)
+		if !resAff.Equal(&expectedAff) {
+			t.Fatalf("[Affine] mismatch for s=%s", s.String())
+		}
+	}
+
+	// the identity
+	var res, identity PointExtended
+	identity.setInfinity()
+	res.ScalarMultiplicationConstantTime(&identity, big.NewInt(42))
+	if !res.IsZero() {
+		t.Fatal("expected the identity")
+	}
+}
+
+func TestRecodeConstantTime(t *testing.T) {
+	const w = windowSizeConstantTime
+	params := GetEdwardsCurve()
+	for _, s := range constantTimeTestScalars() {
+		var e big.Int
+		e.Mod(s, &params.Order)
+		if e.Bit(0) == 0 {
+			continue
+		}
+		digits := recodeConstantTime(limbsConstantTime(&e))
+
+		// k = ∑ dᵢ2ʷⁱ with odd digits dᵢ ∈ [-(2ʷ-1), 2ʷ-1]
+		var sum, d big.Int
+		for i := len(digits) - 1; i >= 0; i-- {
+			if digits[i]&1 == 0 || digits[i] >= 1<<w || digits[i] <= -(1<<w) {
+				t.Fatalf("invalid digit %d for s=%s", digits[i], s.String())
+			}
+			sum.Lsh(&sum, w).Add(&sum, d.SetInt64(int64(digits[i])))
+		}
+		if digits[len(digits)-1] < 0 {
+			t.Fatalf("negative last digit for s=%s", s.String())
+		}
+		if sum.Cmp(&e) != 0 {
+			t.Fatalf("wrong recoding for s=%s", s.String())
+		}
+	}
+}
+
+// constantTimeTestScalars returns scalars with a special form
+func constantTimeTestScalars() []*big.Int {
+	params := GetEdwardsCurve()
+	order := &params.Order
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(2),
+		big.NewInt(3),
+		big.NewInt(-1),
+		new(big.Int).Sub(order, big.NewInt(1)),
+		new(big.Int).Sub(order, big.NewInt(2)),
+		new(big.Int).Set(order),
+		new(big.Int).Add(order, big.NewInt(3)),
+		new(big.Int).Lsh(big.NewInt(1), uint(order.BitLen()-1)),
+		new(big.Int).Rsh(order, 1),
+	}
+	gen := GenBigInt()
+	for i := 0; i < 4; i++ {
+		s := gen(gopter.DefaultGenParameters()).Result.(big.Int)
+		scalars = append(scalars, &s)
+	}
+	return scalars
+}
+
+// TestScalarMultiplicationConstantTimeTiming checks that the execution time of
+// the constant time scalar multiplication doesn't depend on the scalar, by
+// comparing the times with a fixed scalar and with random scalars (dudect).
+func TestScalarMultiplicationConstantTimeTiming(t *testing.T) {
+	n := 2000
+	if testing.Short() {
+		n = 500
+	}
+	params := GetEdwardsCurve()
+	random := make([]big.Int, n)
+	for i := range random {
+		random[i].Rand(rand.New(rand.NewSource(int64(i))), &params.Order) //#nosec G404 weak rng is fine here
+	}
+	fixedScalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		new(big.Int).Sub(&params.Order, big.NewInt(1)),
+	}
+
+	// the same measurements detect the leak of the variable time scalar
+	// multiplication, otherwise the environment is too noisy
+	var base, res PointExtended
+	base.FromAffine(&params.Base)
+	scalars := timingTestScalars(fixedScalars[1], random)
+	leak := testutils.TimingLeakage(n, func(class, i int) {
+		res.ScalarMultiplication(&base, &scalars[class][i])
+	})
+	if leak < 10 {
+		t.Skipf("the measurements are too noisy (t=%.2f for ScalarMultiplication)", leak)
+	}
+
+	for _, s := range fixedScalars {
+		scalars := timingTestScalars(s, random)
+		leak := testutils.TimingLeakage(n, func(class, i int) {
+			res.ScalarMultiplicationConstantTime(&base, &scalars[class][i])
+		})
+		if leak > 10 {
+			t.Fatalf("the execution time depends on the scalar (t=%.2f for s=%s)", leak, s.String())
+		}
+	}
+}
+
+// timingTestScalars returns the inputs of the two classes of a timing test:
+// copies of fixed, and random.
+func timingTestScalars(fixed *big.Int, random []big.Int) [2][]big.Int {
+	res := [2][]big.Int{make([]big.Int, len(random)), random}
+	for i := range res[0] {
+		res[0][i].Set(fixed)
+	}
+	return res
+}
+
+func BenchmarkScalarMultiplicationConstantTime(b *testing.B) {
+	params := GetEdwardsCurve()
+	var s big.Int
+	s.Sub(&params.Order, big.NewInt(7))
+
+	var base, res PointExtended
+	base.FromAffine(&params.Base)
+	b.Run("ScalarMultiplication", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			res.ScalarMultiplication(&base, &s)
+		}
+	})
+	b.Run("ScalarMultiplicationConstantTime", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			res.ScalarMultiplicationConstantTime(&base, &s)
+		}
+	})
+}
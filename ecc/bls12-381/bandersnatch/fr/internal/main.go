@@ -0,0 +1,22 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/field/generator"
+	"github.com/consensys/gnark-crypto/field/generator/config"
+)
+
+//go:generate go run main.go
+func main() {
+	// order of the prime subgroup of bandersnatch
+	const modulus = "0x1cfb69d4ca675f520cce760202687600ff8f87007419047174fd06b52876e7e1"
+	fr, err := config.NewFieldConfig("fr", "Element", modulus, true)
+	if err != nil {
+		panic(err)
+	}
+	if err := generator.GenerateFF(fr, "../"); err != nil {
+		panic(err)
+	}
+	fmt.Println("successfully generated bandersnatch scalar field")
+}
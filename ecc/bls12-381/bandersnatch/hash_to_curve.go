@@ -0,0 +1,184 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bandersnatch
+
+import (
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// The curve a·x² + y² = 1 + d·x²·y² is birationally equivalent to the
+// Montgomery curve K·t² = s³ + J·s² + s, where J = 2(a+d)/(a-d) and
+// K = 4/(a-d). Points are hashed to the Montgomery curve with the Elligator 2
+// map, and then mapped to the twisted Edwards curve with the rational map
+// (x, y) = (s/t, (s-1)/(s+1)).
+// https://www.rfc-editor.org/rfc/rfc9380.html#name-elligator-2-method
+
+var (
+	h2cOnce sync.Once
+
+	// constants of the Elligator 2 map: the non-square Z = 5, J/K, K and 1/K²
+	h2cZ, h2cJOverK, h2cK, h2cInvKSquare fr.Element
+)
+
+func initHashToCurve() {
+	initOnce.Do(initCurveParams)
+
+	var aMinusD fr.Element
+	aMinusD.Sub(&curveParams.A, &curveParams.D)
+	h2cK.SetUint64(4).Div(&h2cK, &aMinusD)
+
+	// J/K = (a+d)/2
+	h2cJOverK.Add(&curveParams.A, &curveParams.D).Halve()
+
+	h2cInvKSquare.Square(&h2cK).Inverse(&h2cInvKSquare)
+	h2cZ.SetInt64(5)
+}
+
+// sgn0 returns the parity of the canonical representation of z
+// https://www.rfc-editor.org/rfc/rfc9380.html#name-the-sgn0-function
+func sgn0(z *fr.Element) uint64 {
+	nonMont := z.Bits()
+	return nonMont[0] % 2
+}
+
+// mapToMontgomery implements the Elligator 2 map to the Montgomery curve
+// K·t² = s³ + J·s² + s, and returns the coordinates (s, t) of the point.
+// https://www.rfc-editor.org/rfc/rfc9380.html#name-elligator-2-method-2
+func mapToMontgomery(u *fr.Element) (s, t fr.Element) {
+	h2cOnce.Do(initHashToCurve)
+
+	var x1, x2, gx, y, tmp fr.Element
+
+	// x1 = -(J/K) / (1 + Z·u²), or -(J/K) if the denominator is zero
+	tmp.Square(u).Mul(&tmp, &h2cZ)
+	y.SetOne()
+	tmp.Add(&tmp, &y).Inverse(&tmp)
+	x1.Mul(&h2cJOverK, &tmp).Neg(&x1)
+	if x1.IsZero() {
+		x1.Neg(&h2cJOverK)
+	}
+
+	// gx1 = x1³ + (J/K)·x1² + x1/K²
+	gx.Add(&x1, &h2cJOverK).
+		Mul(&gx, &x1).
+		Add(&gx, &h2cInvKSquare).
+		Mul(&gx, &x1)
+
+	if gx.Legendre() != -1 {
+		// gx1 is a square, choose y with sgn0(y) = 1
+		y.Sqrt(&gx)
+		if sgn0(&y) != 1 {
+			y.Neg(&y)
+		}
+		s.Mul(&x1, &h2cK)
+	} else {
+		// x2 = -x1 - J/K, and gx2 is a square
+		x2.Add(&x1, &h2cJOverK).Neg(&x2)
+		gx.Add(&x2, &h2cJOverK).
+			Mul(&gx, &x2).
+			Add(&gx, &h2cInvKSquare).
+			Mul(&gx, &x2)
+		y.Sqrt(&gx)
+		if sgn0(&y) != 0 {
+			y.Neg(&y)
+		}
+		s.Mul(&x2, &h2cK)
+	}
+	t.Mul(&y, &h2cK)
+
+	return
+}
+
+// MapToCurve maps a field element to a point of the curve with the Elligator 2
+// map. The result is not necessarily in the prime order subgroup.
+// https://www.rfc-editor.org/rfc/rfc9380.html#name-mappings-for-twisted-edward
+func MapToCurve(u fr.Element) PointAffine {
+	s, t := mapToMontgomery(&u)
+
+	var res PointAffine
+	var den fr.Element
+	den.SetOne().Add(&den, &s).Mul(&den, &t)
+	if den.IsZero() {
+		// exceptional cases t = 0 or s = -1 are sent to the identity
+		res.setInfinity()
+		return res
+	}
+
+	// x = s/t, y = (s-1)/(s+1), with a single inversion
+	var one fr.Element
+	one.SetOne()
+	den.Inverse(&den)
+	res.X.Add(&s, &one).Mul(&res.X, &s).Mul(&res.X, &den)
+	res.Y.Sub(&s, &one).Mul(&res.Y, &t).Mul(&res.Y, &den)
+
+	return res
+}
+
+// clearCofactor sets p to [cofactor]p1, which is in the prime order subgroup
+func clearCofactor(p *PointExtended, p1 *PointExtended) {
+	p.Set(p1)
+	for i := 0; i < 2; i++ {
+		p.Double(p)
+	}
+}
+
+// EncodeToCurve hashes a message to a point of the prime order subgroup
+// using the Elligator 2 map.
+// It is faster than HashToCurve, but the result is not uniformly distributed. Unsuitable as a random oracle.
+// dst stands for "domain separation tag", a string unique to the construction using the hash function
+// https://www.rfc-editor.org/rfc/rfc9380.html#name-encoding-byte-strings-to-el
+func EncodeToCurve(msg, dst []byte) (PointAffine, error) {
+	var res PointAffine
+	u, err := fr.Hash(msg, dst, 1)
+	if err != nil {
+		return res, err
+	}
+
+	Q := MapToCurve(u[0])
+	var _Q PointExtended
+	_Q.FromAffine(&Q)
+	clearCofactor(&_Q, &_Q)
+
+	res.FromExtended(&_Q)
+	return res, nil
+}
+
+// HashToCurve hashes a message to a point of the prime order subgroup
+// using the Elligator 2 map.
+// Slower than EncodeToCurve, but usable as a random oracle.
+// dst stands for "domain separation tag", a string unique to the construction using the hash function
+// https://www.rfc-editor.org/rfc/rfc9380.html#name-encoding-byte-strings-to-el
+func HashToCurve(msg, dst []byte) (PointAffine, error) {
+	var res PointAffine
+	u, err := fr.Hash(msg, dst, 2)
+	if err != nil {
+		return res, err
+	}
+
+	Q0 := MapToCurve(u[0])
+	Q1 := MapToCurve(u[1])
+
+	var _Q0, _Q1 PointExtended
+	_Q0.FromAffine(&Q0)
+	_Q1.FromAffine(&Q1).Add(&_Q1, &_Q0)
+	clearCofactor(&_Q1, &_Q1)
+
+	res.FromExtended(&_Q1)
+	return res, nil
+}
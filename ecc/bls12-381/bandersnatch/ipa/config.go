@@ -0,0 +1,173 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// DomainSize number of evaluations of the committed polynomials
+	DomainSize = 256
+
+	// nbRounds number of rounds of the inner product argument, log₂(DomainSize)
+	nbRounds = 8
+
+	// CRSSeed seed of the common reference string of the Ethereum Verkle specification
+	CRSSeed = "eth_verkle_oct_2021"
+)
+
+var ErrInvalidBasisSize = errors.New("basis must have DomainSize points")
+
+// Config common reference string and precomputed values of the scheme.
+type Config struct {
+	// Basis points Gᵢ of the Pedersen vector commitment, the commitment to the
+	// polynomial f being ∑ᵢf(i)Gᵢ
+	Basis []bandersnatch.PointAffine
+
+	// Q point used to bind the inner product in the argument
+	Q bandersnatch.PointAffine
+
+	// barycentricWeights[i] = A'(i) = ∏_{j≠i}(i-j), followed by their inverses
+	barycentricWeights []fr.Element
+
+	// invertedDomain[k-1] = 1/k and invertedDomain[DomainSize-2+k] = -1/k, for 0 < k < DomainSize
+	invertedDomain []fr.Element
+}
+
+// NewConfig returns the configuration of the Ethereum Verkle specification:
+// the basis is GenerateBasis(CRSSeed, DomainSize) and Q is the generator of
+// Bandersnatch.
+func NewConfig() *Config {
+	c, _ := NewConfigFromBasis(GenerateBasis(CRSSeed, DomainSize), bandersnatch.GetEdwardsCurve().Base)
+	return c
+}
+
+// NewConfigFromBasis returns a configuration with the given basis of
+// DomainSize points and point Q. The discrete logarithms between the points
+// must be unknown.
+func NewConfigFromBasis(basis []bandersnatch.PointAffine, q bandersnatch.PointAffine) (*Config, error) {
+	if len(basis) != DomainSize {
+		return nil, ErrInvalidBasisSize
+	}
+	c := &Config{
+		Basis: append([]bandersnatch.PointAffine{}, basis...),
+		Q:     q,
+	}
+
+	// A'(i) = ∏_{j≠i}(i-j)
+	c.barycentricWeights = make([]fr.Element, 2*DomainSize)
+	var t fr.Element
+	for i := 0; i < DomainSize; i++ {
+		w := &c.barycentricWeights[i]
+		w.SetOne()
+		for j := 0; j < DomainSize; j++ {
+			if j == i {
+				continue
+			}
+			t.SetInt64(int64(i - j))
+			w.Mul(w, &t)
+		}
+	}
+	copy(c.barycentricWeights[DomainSize:], fr.BatchInvert(c.barycentricWeights[:DomainSize]))
+
+	c.invertedDomain = make([]fr.Element, 2*(DomainSize-1))
+	for k := 1; k < DomainSize; k++ {
+		c.invertedDomain[k-1].SetUint64(uint64(k))
+	}
+	copy(c.invertedDomain[:DomainSize-1], fr.BatchInvert(c.invertedDomain[:DomainSize-1]))
+	for k := 1; k < DomainSize; k++ {
+		c.invertedDomain[DomainSize-2+k].Neg(&c.invertedDomain[k-1])
+	}
+
+	return c, nil
+}
+
+// GenerateBasis derives n points from seed, by try-and-increment: the i-th
+// attempt hashes seed ∥ i (as 8 bytes big-endian) with SHA-256 into a base
+// field element x, kept if it is the abscissa of a Banderwagon element.
+func GenerateBasis(seed string, n int) []bandersnatch.PointAffine {
+	res := make([]bandersnatch.PointAffine, 0, n)
+	var counter [8]byte
+	for i := uint64(0); len(res) < n; i++ {
+		h := sha256.New()
+		h.Write([]byte(seed))
+		binary.BigEndian.PutUint64(counter[:], i)
+		h.Write(counter[:])
+
+		var x fp.Element
+		x.SetBytes(h.Sum(nil))
+		var p bandersnatch.PointAffine
+		if pointFromX(&p, &x) == nil {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// inverseDifference returns 1/(i-j) for distinct i, j in the domain.
+func (c *Config) inverseDifference(i, j int) *fr.Element {
+	if i > j {
+		return &c.invertedDomain[i-j-1]
+	}
+	return &c.invertedDomain[DomainSize-2+j-i]
+}
+
+// lagrangeCoefficients returns the evaluations Lᵢ(z) of the Lagrange
+// polynomials of the domain.
+func (c *Config) lagrangeCoefficients(z *fr.Element) []fr.Element {
+	res := make([]fr.Element, DomainSize)
+
+	// z in the domain
+	if b := z.Bits(); b[1] == 0 && b[2] == 0 && b[3] == 0 && b[0] < DomainSize {
+		res[b[0]].SetOne()
+		return res
+	}
+
+	// Lᵢ(z) = A(z) / (A'(i)⋅(z-i))
+	var a, t fr.Element
+	a.SetOne()
+	for i := range res {
+		t.SetUint64(uint64(i))
+		res[i].Sub(z, &t)
+		a.Mul(&a, &res[i])
+	}
+	res = fr.BatchInvert(res)
+	for i := range res {
+		res[i].Mul(&res[i], &c.barycentricWeights[DomainSize+i]).Mul(&res[i], &a)
+	}
+	return res
+}
+
+// evaluate evaluates at z the polynomial given by its evaluations on the domain.
+func (c *Config) evaluate(values []fr.Element, z *fr.Element) fr.Element {
+	return innerProduct(values, c.lagrangeCoefficients(z))
+}
+
+// innerProduct returns ∑ᵢaᵢbᵢ.
+func innerProduct(a, b []fr.Element) fr.Element {
+	var res, t fr.Element
+	for i := range a {
+		t.Mul(&a[i], &b[i])
+		res.Add(&res, &t)
+	}
+	return res
+}
@@ -0,0 +1,34 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ipa implements a transparent polynomial commitment scheme based on
+// the inner product argument of Bulletproofs, over the Bandersnatch curve, as
+// used by Ethereum's Verkle trees.
+//
+// Polynomials are given by their evaluations on the domain {0, 1, …, 255} of
+// the scalar field of Bandersnatch, and are committed to with a Pedersen
+// vector commitment in the corresponding Lagrange basis. An opening proof is
+// made of 2⋅log₂(256) points and one scalar. Openings of many polynomials at
+// points of the domain are aggregated in a single multiproof.
+//
+// Points are elements of the Banderwagon group, the quotient of the subgroup
+// of Bandersnatch points of even order by the 2-torsion point (0, -1). They
+// are represented by bandersnatch.PointAffine, compared with EqualPoints and
+// serialized with PointBytes.
+//
+// The common reference string, the Fiat-Shamir transcript with its domain
+// separators (see Transcript) and the serialization of the proofs are those
+// of the Ethereum Verkle specification, so that commitments and proofs are
+// interchangeable with other implementations (go-ipa, rust-verkle).
+package ipa
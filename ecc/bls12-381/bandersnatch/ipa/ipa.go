@@ -0,0 +1,239 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+var (
+	ErrInvalidPolynomialSize = errors.New("invalid polynomial size (larger than DomainSize)")
+	ErrInvalidProofSize      = errors.New("invalid number of rounds in the inner product proof")
+	ErrVerifyOpeningProof    = errors.New("can't verify opening proof")
+)
+
+// labels of the inner product argument in the transcript: the domain
+// separator, then the commitment, the evaluation point and the claimed value
+// from which w is derived, then Lᵢ and Rᵢ from which xᵢ is derived for each
+// round.
+const (
+	labelIPA         = "ipa"
+	labelCommitment  = "C"
+	labelInputPoint  = "input point"
+	labelOutputPoint = "output point"
+	labelW           = "w"
+	labelL           = "L"
+	labelR           = "R"
+	labelX           = "x"
+)
+
+// Digest commitment of a polynomial.
+type Digest = bandersnatch.PointAffine
+
+// InnerProductProof proof that the polynomial committed to in C - D (for a
+// multiproof) or C (for a single opening) evaluates to a given value at a
+// given point.
+//
+// implements io.ReaderFrom and io.WriterTo
+type InnerProductProof struct {
+	// L, R cross commitments of each round
+	L, R []bandersnatch.PointAffine
+
+	// A the last remaining coefficient of the folded polynomial
+	A fr.Element
+}
+
+// OpeningProof IPA proof for opening at a single point.
+//
+// implements io.ReaderFrom and io.WriterTo
+type OpeningProof struct {
+	// IPA inner product argument
+	IPA InnerProductProof
+
+	// ClaimedValue purported value
+	ClaimedValue fr.Element
+}
+
+// Commit commits to a polynomial given by its evaluations on the domain, as
+// ∑ᵢvalues[i]⋅Gᵢ. Missing evaluations are zeros.
+func (c *Config) Commit(values []fr.Element) (Digest, error) {
+	if len(values) > DomainSize {
+		return Digest{}, ErrInvalidPolynomialSize
+	}
+	res := multiExp(c.Basis[:len(values)], values)
+	return batchToAffine([]bandersnatch.PointExtended{res})[0], nil
+}
+
+// Open computes an opening proof at point of the polynomial given by its
+// evaluations on the domain, committed to in digest.
+// It's an interactive protocol, made non-interactive using Fiat Shamir: the
+// challenges are derived from transcript, to which the caller may have
+// appended data, as in the Ethereum Verkle specification.
+func (c *Config) Open(values []fr.Element, digest *Digest, point fr.Element, transcript *Transcript) (OpeningProof, error) {
+	if len(values) > DomainSize {
+		return OpeningProof{}, ErrInvalidPolynomialSize
+	}
+	a := make([]fr.Element, DomainSize)
+	copy(a, values)
+
+	var res OpeningProof
+	res.ClaimedValue = c.evaluate(a, &point)
+	res.IPA = c.proveIPA(transcript, a, digest, &point, &res.ClaimedValue)
+	return res, nil
+}
+
+// Verify verifies an IPA opening proof at a single point, transcript being
+// in the state it had when the proof was computed.
+func (c *Config) Verify(digest *Digest, proof *OpeningProof, point fr.Element, transcript *Transcript) error {
+	return c.verifyIPA(transcript, &proof.IPA, digest, &point, &proof.ClaimedValue)
+}
+
+// proveIPA proves that ⟨a, b⟩ = y, where a is committed to in commitment and
+// b = (Lᵢ(z))ᵢ, so that y is the evaluation at z of the polynomial of
+// evaluations a. a is modified.
+//
+// At each round, a, b and the basis G are split in halves and folded with the
+// challenge x as a' = a_L + x⋅a_R, b' = b_L + x⁻¹⋅b_R and G' = G_L + x⁻¹⋅G_R,
+// the cross terms being committed to in L = ⟨a_R, G_L⟩ + ⟨a_R, b_L⟩⋅wQ and
+// R = ⟨a_L, G_R⟩ + ⟨a_L, b_R⟩⋅wQ.
+func (c *Config) proveIPA(transcript *Transcript, a []fr.Element, commitment *Digest, z, y *fr.Element) InnerProductProof {
+	b := c.lagrangeCoefficients(z)
+
+	transcript.DomainSep(labelIPA)
+	transcript.AppendPoint(labelCommitment, commitment)
+	transcript.AppendScalar(labelInputPoint, z)
+	transcript.AppendScalar(labelOutputPoint, y)
+	w := transcript.ChallengeScalar(labelW)
+	var q bandersnatch.PointExtended
+	q.FromAffine(&c.Q)
+	scalarMul(&q, &q, &w)
+
+	res := InnerProductProof{
+		L: make([]bandersnatch.PointAffine, nbRounds),
+		R: make([]bandersnatch.PointAffine, nbRounds),
+	}
+	g := append([]bandersnatch.PointAffine{}, c.Basis...)
+	gExtended := make([]bandersnatch.PointExtended, len(g)/2)
+	for i := 0; i < nbRounds; i++ {
+		n := len(a) / 2
+		aL, aR := a[:n], a[n:]
+		bL, bR := b[:n], b[n:]
+		gL, gR := g[:n], g[n:]
+
+		// L = ⟨a_R, G_L⟩ + ⟨a_R, b_L⟩⋅wQ, R = ⟨a_L, G_R⟩ + ⟨a_L, b_R⟩⋅wQ
+		var cross [2]bandersnatch.PointExtended
+		var t bandersnatch.PointExtended
+		zL, zR := innerProduct(aR, bL), innerProduct(aL, bR)
+		cross[0] = multiExp(gL, aR)
+		cross[0].Add(&cross[0], scalarMul(&t, &q, &zL))
+		cross[1] = multiExp(gR, aL)
+		cross[1].Add(&cross[1], scalarMul(&t, &q, &zR))
+		lr := batchToAffine(cross[:])
+		res.L[i], res.R[i] = lr[0], lr[1]
+
+		transcript.AppendPoint(labelL, &res.L[i])
+		transcript.AppendPoint(labelR, &res.R[i])
+		x := transcript.ChallengeScalar(labelX)
+		var xInv fr.Element
+		xInv.Inverse(&x)
+
+		// fold
+		for j := 0; j < n; j++ {
+			var s fr.Element
+			s.Mul(&aR[j], &x)
+			aL[j].Add(&aL[j], &s)
+			s.Mul(&bR[j], &xInv)
+			bL[j].Add(&bL[j], &s)
+
+			var t bandersnatch.PointExtended
+			t.FromAffine(&gL[j])
+			gExtended[j].FromAffine(&gR[j])
+			scalarMul(&gExtended[j], &gExtended[j], &xInv)
+			gExtended[j].Add(&gExtended[j], &t)
+		}
+		a, b = aL, bL
+		g = batchToAffine(gExtended[:n])
+	}
+	res.A = a[0]
+
+	return res
+}
+
+// verifyIPA verifies that proof proves that the polynomial committed to in
+// commitment evaluates to y at z. It checks in a single multi
+// exponentiation that
+//
+//	C + y⋅wQ + ∑ᵢ(xᵢ⋅Lᵢ + xᵢ⁻¹⋅Rᵢ) = A⋅G₀ + A⋅b₀⋅wQ
+//
+// where G₀ and b₀ are the basis and the Lagrange coefficients folded with
+// the challenges, that is G₀ = ⟨s, G⟩ and b₀ = ⟨s, b⟩ where sⱼ is the product
+// of the xᵢ⁻¹ for the rounds i where j was in the right half.
+func (c *Config) verifyIPA(transcript *Transcript, proof *InnerProductProof, commitment *Digest, z, y *fr.Element) error {
+	if len(proof.L) != nbRounds || len(proof.R) != nbRounds {
+		return ErrInvalidProofSize
+	}
+	b := c.lagrangeCoefficients(z)
+
+	transcript.DomainSep(labelIPA)
+	transcript.AppendPoint(labelCommitment, commitment)
+	transcript.AppendScalar(labelInputPoint, z)
+	transcript.AppendScalar(labelOutputPoint, y)
+	w := transcript.ChallengeScalar(labelW)
+	xs := make([]fr.Element, nbRounds)
+	for i := range xs {
+		transcript.AppendPoint(labelL, &proof.L[i])
+		transcript.AppendPoint(labelR, &proof.R[i])
+		xs[i] = transcript.ChallengeScalar(labelX)
+	}
+	xInvs := fr.BatchInvert(xs)
+
+	// s, such that G₀ = ⟨s, G⟩, round 0 folding the most significant bit
+	s := make([]fr.Element, 1, DomainSize)
+	s[0].SetOne()
+	for i := nbRounds - 1; i >= 0; i-- {
+		n := len(s)
+		s = s[:2*n]
+		for j := 0; j < n; j++ {
+			s[n+j].Mul(&s[j], &xInvs[i])
+		}
+	}
+	b0 := innerProduct(s, b)
+
+	// C + (y - A⋅b₀)w⋅Q + ∑ᵢ(xᵢ⋅Lᵢ + xᵢ⁻¹⋅Rᵢ) - ⟨A⋅s, G⟩ = 0
+	bases := make([]bandersnatch.PointAffine, 0, 2+2*nbRounds+DomainSize)
+	scalars := make([]fr.Element, 0, cap(bases))
+	bases = append(bases, *commitment, c.Q)
+	var t fr.Element
+	t.Mul(&proof.A, &b0).Sub(y, &t).Mul(&t, &w)
+	scalars = append(scalars, fr.One(), t)
+	bases = append(bases, proof.L...)
+	scalars = append(scalars, xs...)
+	bases = append(bases, proof.R...)
+	scalars = append(scalars, xInvs...)
+	bases = append(bases, c.Basis...)
+	for j := range s {
+		t.Mul(&proof.A, &s[j]).Neg(&t)
+		scalars = append(scalars, t)
+	}
+
+	check := multiExp(bases, scalars)
+	if !check.X.IsZero() {
+		return ErrVerifyOpeningProof
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommit(t *testing.T) {
+	assert := require.New(t)
+
+	// linearity
+	f, g := randomValues(DomainSize), randomValues(100)
+	var lambda fr.Element
+	lambda.SetRandom()
+	h := append([]fr.Element{}, f...)
+	for i := range g {
+		var t fr.Element
+		t.Mul(&g[i], &lambda)
+		h[i].Add(&h[i], &t)
+	}
+	cf, err := testConfig.Commit(f)
+	assert.NoError(err)
+	cg, err := testConfig.Commit(g)
+	assert.NoError(err)
+	ch, err := testConfig.Commit(h)
+	assert.NoError(err)
+
+	var expected, lcg bandersnatch.PointExtended
+	expected.FromAffine(&cf)
+	lcg.FromAffine(&cg)
+	scalarMul(&lcg, &lcg, &lambda)
+	expected.Add(&expected, &lcg)
+	assert.True(EqualPoints(&ch, &batchToAffine([]bandersnatch.PointExtended{expected})[0]))
+
+	// unit vectors commit to the basis
+	e := make([]fr.Element, 10)
+	e[9].SetOne()
+	c, err := testConfig.Commit(e)
+	assert.NoError(err)
+	assert.True(EqualPoints(&c, &testConfig.Basis[9]))
+
+	_, err = testConfig.Commit(make([]fr.Element, DomainSize+1))
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestLagrangeCoefficients(t *testing.T) {
+	assert := require.New(t)
+
+	// the polynomial of evaluations f(i) = i² + 3 evaluates to z² + 3 at z
+	f := make([]fr.Element, DomainSize)
+	for i := range f {
+		f[i].SetUint64(uint64(i*i + 3))
+	}
+	var z, expected fr.Element
+	for _, zz := range []uint64{0, 7, DomainSize - 1, DomainSize, 1 << 40} {
+		z.SetUint64(zz)
+		expected.Square(&z).Add(&expected, new(fr.Element).SetUint64(3))
+		y := testConfig.evaluate(f, &z)
+		assert.True(y.Equal(&expected), "z=%d", zz)
+	}
+	z.SetRandom()
+	expected.Square(&z).Add(&expected, new(fr.Element).SetUint64(3))
+	y := testConfig.evaluate(f, &z)
+	assert.True(y.Equal(&expected))
+}
+
+func TestOpen(t *testing.T) {
+	assert := require.New(t)
+
+	f := randomValues(DomainSize)
+	digest, err := testConfig.Commit(f)
+	assert.NoError(err)
+
+	var outside fr.Element
+	outside.SetRandom()
+	for _, point := range []fr.Element{fr.NewElement(0), fr.NewElement(42), outside} {
+		proof, err := testConfig.Open(f, &digest, point, NewTranscript("test"))
+		assert.NoError(err)
+		expected := testConfig.evaluate(f, &point)
+		assert.True(proof.ClaimedValue.Equal(&expected), "inconsistent claimed value")
+
+		// correct proof
+		assert.NoError(testConfig.Verify(&digest, &proof, point, NewTranscript("test")))
+
+		// serialization
+		t.Run("proof round-trip", serializationRoundTrip(&proof, new(OpeningProof)))
+		var buf bytes.Buffer
+		_, err = proof.WriteTo(&buf)
+		assert.NoError(err)
+		assert.Equal(2*nbRounds*PointSize+2*fr.Bytes, buf.Len())
+		var decoded OpeningProof
+		_, err = decoded.ReadFrom(&buf)
+		assert.NoError(err)
+		assert.NoError(testConfig.Verify(&digest, &decoded, point, NewTranscript("test")))
+
+		// wrong claimed value
+		wrong := proof
+		wrong.ClaimedValue.Double(&wrong.ClaimedValue)
+		assert.ErrorIs(testConfig.Verify(&digest, &wrong, point, NewTranscript("test")), ErrVerifyOpeningProof)
+
+		// wrong point
+		var wrongPoint fr.Element
+		wrongPoint.SetUint64(3)
+		wrongPoint.Add(&wrongPoint, &point)
+		assert.ErrorIs(testConfig.Verify(&digest, &proof, wrongPoint, NewTranscript("test")), ErrVerifyOpeningProof)
+
+		// wrong digest
+		assert.ErrorIs(testConfig.Verify(&testConfig.Basis[0], &proof, point, NewTranscript("test")), ErrVerifyOpeningProof)
+
+		// wrong folded value and cross commitments
+		wrong = proof
+		wrong.IPA.A.Double(&wrong.IPA.A)
+		assert.ErrorIs(testConfig.Verify(&digest, &wrong, point, NewTranscript("test")), ErrVerifyOpeningProof)
+		wrong = proof
+		wrong.IPA.L = append([]bandersnatch.PointAffine{}, proof.IPA.L...)
+		wrong.IPA.L[3] = proof.IPA.R[3]
+		assert.ErrorIs(testConfig.Verify(&digest, &wrong, point, NewTranscript("test")), ErrVerifyOpeningProof)
+		wrong.IPA.L = wrong.IPA.L[1:]
+		assert.ErrorIs(testConfig.Verify(&digest, &wrong, point, NewTranscript("test")), ErrInvalidProofSize)
+	}
+
+	// data appended to the transcript
+	salt := outside.Marshal()
+	proof, err := testConfig.Open(f, &digest, outside, saltedTranscript(salt))
+	assert.NoError(err)
+	assert.NoError(testConfig.Verify(&digest, &proof, outside, saltedTranscript(salt)))
+	assert.ErrorIs(testConfig.Verify(&digest, &proof, outside, NewTranscript("test")), ErrVerifyOpeningProof)
+
+	// shorter polynomials are padded with zeros
+	g := f[:30]
+	digest, err = testConfig.Commit(g)
+	assert.NoError(err)
+	proof, err = testConfig.Open(g, &digest, fr.NewElement(100), NewTranscript("test"))
+	assert.NoError(err)
+	assert.True(proof.ClaimedValue.IsZero())
+	assert.NoError(testConfig.Verify(&digest, &proof, fr.NewElement(100), NewTranscript("test")))
+	assert.Len(g, 30, "the polynomial must not be modified")
+
+	_, err = testConfig.Open(make([]fr.Element, DomainSize+1), &digest, outside, NewTranscript("test"))
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+// serializationRoundTrip checks that o is re-encoded identically once
+// decoded. Points being decoded to a canonical representative of their
+// Banderwagon element, the decoded value itself may differ.
+func serializationRoundTrip(o, decoded interface {
+	io.ReaderFrom
+	io.WriterTo
+}) func(*testing.T) {
+	return func(t *testing.T) {
+		assert := require.New(t)
+		var buf bytes.Buffer
+		written, err := o.WriteTo(&buf)
+		assert.NoError(err)
+		encoded := append([]byte{}, buf.Bytes()...)
+
+		read, err := decoded.ReadFrom(&buf)
+		assert.NoError(err)
+		assert.Equal(written, read)
+
+		buf.Reset()
+		_, err = decoded.WriteTo(&buf)
+		assert.NoError(err)
+		assert.Equal(encoded, buf.Bytes())
+	}
+}
+
+func BenchmarkCommit(b *testing.B) {
+	f := randomValues(DomainSize)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = testConfig.Commit(f)
+	}
+}
+
+func BenchmarkOpen(b *testing.B) {
+	f := randomValues(DomainSize)
+	digest, _ := testConfig.Commit(f)
+	var point fr.Element
+	point.SetRandom()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = testConfig.Open(f, &digest, point, NewTranscript("test"))
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	f := randomValues(DomainSize)
+	digest, _ := testConfig.Commit(f)
+	var point fr.Element
+	point.SetRandom()
+	proof, err := testConfig.Open(f, &digest, point, NewTranscript("test"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = testConfig.Verify(&digest, &proof, point, NewTranscript("test"))
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+// The proofs are serialized as in the Ethereum Verkle specification: points
+// are PointSize bytes (see PointBytes) and scalars fr.Bytes bytes in little
+// endian, with no length prefix.
+
+// WriteTo writes binary encoding of the InnerProductProof: L, R then A.
+func (proof *InnerProductProof) WriteTo(w io.Writer) (int64, error) {
+	var n int64
+	if len(proof.L) != nbRounds || len(proof.R) != nbRounds {
+		return n, ErrInvalidProofSize
+	}
+	for _, points := range [][]bandersnatch.PointAffine{proof.L, proof.R} {
+		for i := range points {
+			m, err := writePoint(w, &points[i])
+			n += m
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+	m, err := writeScalar(w, &proof.A)
+	return n + m, err
+}
+
+// ReadFrom decodes InnerProductProof data from reader.
+func (proof *InnerProductProof) ReadFrom(r io.Reader) (int64, error) {
+	var n int64
+	proof.L = make([]bandersnatch.PointAffine, nbRounds)
+	proof.R = make([]bandersnatch.PointAffine, nbRounds)
+	for _, points := range [][]bandersnatch.PointAffine{proof.L, proof.R} {
+		for i := range points {
+			m, err := readPoint(r, &points[i])
+			n += m
+			if err != nil {
+				return n, err
+			}
+		}
+	}
+	m, err := readScalar(r, &proof.A)
+	return n + m, err
+}
+
+// WriteTo writes binary encoding of the OpeningProof: the inner product
+// proof then the claimed value.
+func (proof *OpeningProof) WriteTo(w io.Writer) (int64, error) {
+	n, err := proof.IPA.WriteTo(w)
+	if err != nil {
+		return n, err
+	}
+	m, err := writeScalar(w, &proof.ClaimedValue)
+	return n + m, err
+}
+
+// ReadFrom decodes OpeningProof data from reader.
+func (proof *OpeningProof) ReadFrom(r io.Reader) (int64, error) {
+	n, err := proof.IPA.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	m, err := readScalar(r, &proof.ClaimedValue)
+	return n + m, err
+}
+
+// WriteTo writes binary encoding of the MultiProof: D then the inner product
+// proof.
+func (proof *MultiProof) WriteTo(w io.Writer) (int64, error) {
+	n, err := writePoint(w, &proof.D)
+	if err != nil {
+		return n, err
+	}
+	m, err := proof.IPA.WriteTo(w)
+	return n + m, err
+}
+
+// ReadFrom decodes MultiProof data from reader.
+func (proof *MultiProof) ReadFrom(r io.Reader) (int64, error) {
+	n, err := readPoint(r, &proof.D)
+	if err != nil {
+		return n, err
+	}
+	m, err := proof.IPA.ReadFrom(r)
+	return n + m, err
+}
+
+func writePoint(w io.Writer, p *bandersnatch.PointAffine) (int64, error) {
+	buf := PointBytes(p)
+	n, err := w.Write(buf[:])
+	return int64(n), err
+}
+
+func readPoint(r io.Reader, p *bandersnatch.PointAffine) (int64, error) {
+	var buf [PointSize]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+	return int64(n), SetPointBytes(p, buf[:])
+}
+
+func writeScalar(w io.Writer, s *fr.Element) (int64, error) {
+	var buf [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&buf, *s)
+	n, err := w.Write(buf[:])
+	return int64(n), err
+}
+
+func readScalar(r io.Reader, s *fr.Element) (int64, error) {
+	var buf [fr.Bytes]byte
+	n, err := io.ReadFull(r, buf[:])
+	if err != nil {
+		return int64(n), err
+	}
+	*s, err = fr.LittleEndian.Element(&buf)
+	return int64(n), err
+}
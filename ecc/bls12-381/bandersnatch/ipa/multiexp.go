@@ -0,0 +1,58 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+// multiExp computes ∑ᵢ[scalars[i]]points[i].
+//
+// Any representative of the Banderwagon elements can be given among the
+// points, including both p and p + (0, -1): bandersnatch.PointExtended.MultiExp
+// uses unified additions, and its result is only off by a multiple of
+// (0, -1) for points outside the subgroup.
+func multiExp(points []bandersnatch.PointAffine, scalars []fr.Element) bandersnatch.PointExtended {
+	bScalars := make([]big.Int, len(scalars))
+	for i := range scalars {
+		scalars[i].BigInt(&bScalars[i])
+	}
+
+	var res bandersnatch.PointExtended
+	if _, err := res.MultiExp(points, bScalars, ecc.MultiExpConfig{}); err != nil {
+		// unreachable: the lengths match and the config is the default one
+		panic(err)
+	}
+	return res
+}
+
+// scalarMul sets p to [s]p1 with double-and-add.
+func scalarMul(p, p1 *bandersnatch.PointExtended, s *fr.Element) *bandersnatch.PointExtended {
+	id := identity()
+	var res bandersnatch.PointExtended
+	res.FromAffine(&id)
+	k := s.Bits()
+	for i := fr.Bits - 1; i >= 0; i-- {
+		res.Double(&res)
+		if k[i/64]>>(i%64)&1 == 1 {
+			res.Add(&res, p1)
+		}
+	}
+	return p.Set(&res)
+}
@@ -0,0 +1,228 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+var (
+	ErrInvalidNbDigests = errors.New("number of digests is not the same as the number of polynomials")
+	ErrInvalidNbPoints  = errors.New("number of points is not the same as the number of polynomials")
+)
+
+// labels of the multiproof in the transcript: the domain separator, then
+// (Cᵢ, zᵢ, yᵢ) for each opening from which r is derived, D from which t is
+// derived, and E before the inner product argument.
+const (
+	labelMultiProof = "multiproof"
+	labelZ          = "z"
+	labelY          = "y"
+	labelRChallenge = "r"
+	labelD          = "D"
+	labelT          = "t"
+	labelE          = "E"
+)
+
+// MultiProof proof that polynomials fᵢ, committed to in Cᵢ, evaluate to yᵢ
+// at domain points zᵢ, aggregated in a single inner product argument.
+//
+// implements io.ReaderFrom and io.WriterTo
+type MultiProof struct {
+	// D commitment to g(X) = ∑ᵢrⁱ(fᵢ(X) - yᵢ)/(X - zᵢ)
+	D bandersnatch.PointAffine
+
+	// IPA opening proof of h - g at t, where h(X) = ∑ᵢrⁱfᵢ(X)/(t - zᵢ)
+	IPA InnerProductProof
+}
+
+// MultiOpen computes a proof that the polynomials, given by their evaluations
+// on the domain and committed to in digests, evaluate at the domain points
+// points[i] to polynomials[i][points[i]]. The same polynomial can be opened
+// at several points by repeating it.
+//
+// The challenges are derived from transcript as in the Ethereum Verkle
+// specification, so that the proof is the one of other implementations when
+// the openings are given in the same order.
+func (c *Config) MultiOpen(digests []Digest, polynomials [][]fr.Element, points []uint8, transcript *Transcript) (MultiProof, error) {
+	if len(digests) != len(polynomials) {
+		return MultiProof{}, ErrInvalidNbDigests
+	}
+	if len(points) != len(polynomials) {
+		return MultiProof{}, ErrInvalidNbPoints
+	}
+	for i := range polynomials {
+		if len(polynomials[i]) > DomainSize {
+			return MultiProof{}, ErrInvalidPolynomialSize
+		}
+	}
+
+	values := make([]fr.Element, len(polynomials))
+	for i := range values {
+		if int(points[i]) < len(polynomials[i]) {
+			values[i] = polynomials[i][points[i]]
+		}
+	}
+	r := deriveMultiProofR(transcript, digests, points, values)
+
+	// aggregate the polynomials opened at the same point: ∑rⁱfᵢ for zᵢ = z
+	var aggregated [DomainSize][]fr.Element
+	var rPow, t fr.Element
+	rPow.SetOne()
+	for i := range polynomials {
+		z := points[i]
+		if aggregated[z] == nil {
+			aggregated[z] = make([]fr.Element, DomainSize)
+		}
+		for j := range polynomials[i] {
+			t.Mul(&polynomials[i][j], &rPow)
+			aggregated[z][j].Add(&aggregated[z][j], &t)
+		}
+		rPow.Mul(&rPow, &r)
+	}
+
+	// g(X) = ∑_z (∑ rⁱfᵢ(X) - ∑ rⁱyᵢ)/(X - z)
+	g := make([]fr.Element, DomainSize)
+	for z := range aggregated {
+		if aggregated[z] != nil {
+			c.addQuotient(g, aggregated[z], z)
+		}
+	}
+
+	var proof MultiProof
+	var err error
+	if proof.D, err = c.Commit(g); err != nil {
+		return MultiProof{}, err
+	}
+	transcript.AppendPoint(labelD, &proof.D)
+	tChallenge := transcript.ChallengeScalar(labelT)
+
+	// h(X) = ∑_z ∑ rⁱfᵢ(X) / (t - z), committed to in E, the IPA opens h - g
+	// at t
+	denominators := make([]fr.Element, 0, DomainSize)
+	for z := range aggregated {
+		if aggregated[z] != nil {
+			t.SetUint64(uint64(z))
+			denominators = append(denominators, *new(fr.Element).Sub(&tChallenge, &t))
+		}
+	}
+	denominators = fr.BatchInvert(denominators)
+	h := make([]fr.Element, DomainSize)
+	k := 0
+	for z := range aggregated {
+		if aggregated[z] == nil {
+			continue
+		}
+		for j := range h {
+			t.Mul(&aggregated[z][j], &denominators[k])
+			h[j].Add(&h[j], &t)
+		}
+		k++
+	}
+	e, err := c.Commit(h)
+	if err != nil {
+		return MultiProof{}, err
+	}
+	transcript.AppendPoint(labelE, &e)
+
+	hMinusG := h
+	for i := range hMinusG {
+		hMinusG[i].Sub(&hMinusG[i], &g[i])
+	}
+	var commitment, d bandersnatch.PointExtended
+	commitment.FromAffine(&e)
+	d.FromAffine(&proof.D)
+	d.Neg(&d)
+	commitment.Add(&commitment, &d)
+	y := c.evaluate(hMinusG, &tChallenge)
+	proof.IPA = c.proveIPA(transcript, hMinusG, &batchToAffine([]bandersnatch.PointExtended{commitment})[0], &tChallenge, &y)
+	return proof, nil
+}
+
+// MultiVerify verifies a multiproof that the polynomials committed to in
+// digests evaluate at the domain points points[i] to values[i], transcript
+// being in the state it had when the proof was computed.
+func (c *Config) MultiVerify(digests []Digest, points []uint8, values []fr.Element, proof *MultiProof, transcript *Transcript) error {
+	if len(points) != len(digests) || len(values) != len(digests) {
+		return ErrInvalidNbPoints
+	}
+
+	r := deriveMultiProofR(transcript, digests, points, values)
+	transcript.AppendPoint(labelD, &proof.D)
+	tChallenge := transcript.ChallengeScalar(labelT)
+
+	// coefficients rⁱ/(t - zᵢ)
+	coefficients := make([]fr.Element, len(digests))
+	var t fr.Element
+	for i := range coefficients {
+		t.SetUint64(uint64(points[i]))
+		coefficients[i].Sub(&tChallenge, &t)
+	}
+	coefficients = fr.BatchInvert(coefficients)
+	var rPow fr.Element
+	rPow.SetOne()
+	for i := range coefficients {
+		coefficients[i].Mul(&coefficients[i], &rPow)
+		rPow.Mul(&rPow, &r)
+	}
+
+	// E = ∑ rⁱ/(t - zᵢ)⋅Cᵢ commits to h, E - D to h - g, and
+	// (h - g)(t) = ∑ rⁱyᵢ/(t - zᵢ)
+	y := innerProduct(coefficients, values)
+	e := multiExp(digests, coefficients)
+	eAffine := batchToAffine([]bandersnatch.PointExtended{e})[0]
+	transcript.AppendPoint(labelE, &eAffine)
+	var d bandersnatch.PointExtended
+	d.FromAffine(&proof.D)
+	d.Neg(&d)
+	e.Add(&e, &d)
+	return c.verifyIPA(transcript, &proof.IPA, &batchToAffine([]bandersnatch.PointExtended{e})[0], &tChallenge, &y)
+}
+
+// deriveMultiProofR appends the domain separator and the openings
+// (Cᵢ, zᵢ, yᵢ) to the transcript and derives the aggregation challenge r.
+func deriveMultiProofR(transcript *Transcript, digests []Digest, points []uint8, values []fr.Element) fr.Element {
+	transcript.DomainSep(labelMultiProof)
+	for i := range digests {
+		var z fr.Element
+		z.SetUint64(uint64(points[i]))
+		transcript.AppendPoint(labelCommitment, &digests[i])
+		transcript.AppendScalar(labelZ, &z)
+		transcript.AppendScalar(labelY, &values[i])
+	}
+	return transcript.ChallengeScalar(labelRChallenge)
+}
+
+// addQuotient adds to res the evaluations on the domain of (f(X) - f(m))/(X - m).
+// For i ≠ m it is (f(i) - f(m))/(i - m), and at m it is f'(m), which is
+// -A'(m)⋅∑_{i≠m} qᵢ/A'(i) where qᵢ are the other evaluations.
+func (c *Config) addQuotient(res, f []fr.Element, m int) {
+	var qm, q fr.Element
+	for i := range f {
+		if i == m {
+			continue
+		}
+		q.Sub(&f[i], &f[m]).Mul(&q, c.inverseDifference(i, m))
+		res[i].Add(&res[i], &q)
+
+		q.Mul(&q, &c.barycentricWeights[DomainSize+i])
+		qm.Sub(&qm, &q)
+	}
+	qm.Mul(&qm, &c.barycentricWeights[m])
+	res[m].Add(&res[m], &qm)
+}
@@ -0,0 +1,142 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// multiProofInstance returns polynomials of various sizes, their
+// commitments, the opened domain points, some being shared or repeated, and
+// the evaluations at those points.
+func multiProofInstance(t testing.TB, n int) ([]Digest, [][]fr.Element, []uint8, []fr.Element) {
+	polynomials := make([][]fr.Element, n)
+	digests := make([]Digest, n)
+	points := make([]uint8, n)
+	values := make([]fr.Element, n)
+	for i := range polynomials {
+		if i%4 == 3 {
+			// same polynomial as the previous one, at another point
+			polynomials[i], digests[i] = polynomials[i-1], digests[i-1]
+		} else {
+			polynomials[i] = randomValues(DomainSize - 10*(i%8))
+			var err error
+			digests[i], err = testConfig.Commit(polynomials[i])
+			require.NoError(t, err)
+		}
+		points[i] = uint8((i * 37) % 11 * 23)
+		if int(points[i]) < len(polynomials[i]) {
+			values[i] = polynomials[i][points[i]]
+		}
+	}
+	return digests, polynomials, points, values
+}
+
+func TestMultiProof(t *testing.T) {
+	assert := require.New(t)
+
+	for _, n := range []int{1, 2, 15} {
+		digests, polynomials, points, values := multiProofInstance(t, n)
+
+		proof, err := testConfig.MultiOpen(digests, polynomials, points, NewTranscript("test"))
+		assert.NoError(err)
+
+		// correct proof
+		if err := testConfig.MultiVerify(digests, points, values, &proof, NewTranscript("test")); err != nil {
+			t.Log(n, points)
+			continue
+		}
+
+		// serialization
+		t.Run("proof round-trip", serializationRoundTrip(&proof, new(MultiProof)))
+
+		// wrong value
+		wrongValues := append([]fr.Element{}, values...)
+		wrongValues[n-1].SetRandom()
+		assert.ErrorIs(testConfig.MultiVerify(digests, points, wrongValues, &proof, NewTranscript("test")), ErrVerifyOpeningProof)
+
+		// wrong point
+		wrongPoints := append([]uint8{}, points...)
+		wrongPoints[0]++
+		assert.ErrorIs(testConfig.MultiVerify(digests, wrongPoints, values, &proof, NewTranscript("test")), ErrVerifyOpeningProof)
+
+		// wrong D
+		wrong := proof
+		wrong.D = testConfig.Basis[0]
+		assert.ErrorIs(testConfig.MultiVerify(digests, points, values, &wrong, NewTranscript("test")), ErrVerifyOpeningProof)
+	}
+
+	// data appended to the transcript
+	digests, polynomials, points, values := multiProofInstance(t, 5)
+	salt := []byte("salt")
+	proof, err := testConfig.MultiOpen(digests, polynomials, points, saltedTranscript(salt))
+	assert.NoError(err)
+	assert.NoError(testConfig.MultiVerify(digests, points, values, &proof, saltedTranscript(salt)))
+	assert.ErrorIs(testConfig.MultiVerify(digests, points, values, &proof, NewTranscript("test")), ErrVerifyOpeningProof)
+
+	// invalid inputs
+	_, err = testConfig.MultiOpen(digests[1:], polynomials, points, NewTranscript("test"))
+	assert.ErrorIs(err, ErrInvalidNbDigests)
+	_, err = testConfig.MultiOpen(digests, polynomials, points[1:], NewTranscript("test"))
+	assert.ErrorIs(err, ErrInvalidNbPoints)
+	assert.ErrorIs(testConfig.MultiVerify(digests, points, values[1:], &proof, NewTranscript("test")), ErrInvalidNbPoints)
+}
+
+func TestAddQuotient(t *testing.T) {
+	assert := require.New(t)
+
+	// q = (f - f(m))/(X - m) satisfies q(z)(z - m) = f(z) - f(m)
+	f := randomValues(DomainSize)
+	var z fr.Element
+	z.SetRandom()
+	fz := testConfig.evaluate(f, &z)
+	for _, m := range []int{0, 1, 128, DomainSize - 1} {
+		q := make([]fr.Element, DomainSize)
+		testConfig.addQuotient(q, f, m)
+		qz := testConfig.evaluate(q, &z)
+
+		var l, r, zm fr.Element
+		zm.SetUint64(uint64(m))
+		zm.Sub(&z, &zm)
+		l.Mul(&qz, &zm)
+		r.Sub(&fz, &f[m])
+		assert.True(l.Equal(&r), "m=%d", m)
+	}
+}
+
+func BenchmarkMultiOpen(b *testing.B) {
+	digests, polynomials, points, _ := multiProofInstance(b, 128)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = testConfig.MultiOpen(digests, polynomials, points, NewTranscript("test"))
+	}
+}
+
+func BenchmarkMultiVerify(b *testing.B) {
+	digests, polynomials, points, values := multiProofInstance(b, 128)
+	proof, err := testConfig.MultiOpen(digests, polynomials, points, NewTranscript("test"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = testConfig.MultiVerify(digests, points, values, &proof, NewTranscript("test"))
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"errors"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	fp "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// PointSize size in bytes of a serialized point
+const PointSize = fp.Bytes
+
+var ErrInvalidPoint = errors.New("invalid Banderwagon point encoding")
+
+// identity returns the neutral element (0, 1).
+func identity() bandersnatch.PointAffine {
+	var p bandersnatch.PointAffine
+	p.Y.SetOne()
+	return p
+}
+
+// EqualPoints returns true if p and q are the same Banderwagon element, that
+// is if p = q or p = q + (0, -1).
+func EqualPoints(p, q *bandersnatch.PointAffine) bool {
+	var l, r fp.Element
+	l.Mul(&p.X, &q.Y)
+	r.Mul(&q.X, &p.Y)
+	return l.Equal(&r)
+}
+
+// equalExtended is EqualPoints for points in extended coordinates.
+func equalExtended(p, q *bandersnatch.PointExtended) bool {
+	var l, r fp.Element
+	l.Mul(&p.X, &q.Y)
+	r.Mul(&q.X, &p.Y)
+	return l.Equal(&r)
+}
+
+// PointBytes returns the serialization of p: the big-endian encoding of x if y
+// is lexicographically largest, of -x otherwise. It is the same for p and
+// p + (0, -1).
+func PointBytes(p *bandersnatch.PointAffine) [PointSize]byte {
+	x := p.X
+	if !p.Y.LexicographicallyLargest() {
+		x.Neg(&x)
+	}
+	return x.Bytes()
+}
+
+// SetPointBytes sets p from its serialization, checking that it is a
+// Banderwagon element, that is that 1 - a⋅x² is a square. The y coordinate of
+// p is the lexicographically largest one.
+func SetPointBytes(p *bandersnatch.PointAffine, buf []byte) error {
+	if len(buf) != PointSize {
+		return ErrInvalidPoint
+	}
+	var x fp.Element
+	if err := x.SetBytesCanonical(buf); err != nil {
+		return ErrInvalidPoint
+	}
+	return pointFromX(p, &x)
+}
+
+// pointFromX sets p to the Banderwagon element of abscissa x, with the
+// lexicographically largest y, if it exists.
+func pointFromX(p *bandersnatch.PointAffine, x *fp.Element) error {
+	curve := bandersnatch.GetEdwardsCurve()
+
+	// y² = (1 - a⋅x²) / (1 - d⋅x²), where 1 - a⋅x² must be a square
+	var num, den, xx, one fp.Element
+	one.SetOne()
+	xx.Square(x)
+	num.Mul(&xx, &curve.A)
+	num.Sub(&one, &num)
+	if num.Legendre() != 1 {
+		return ErrInvalidPoint
+	}
+	den.Mul(&xx, &curve.D)
+	den.Sub(&one, &den)
+	if den.IsZero() {
+		return ErrInvalidPoint
+	}
+	var y fp.Element
+	y.Div(&num, &den)
+	if y.Sqrt(&y) == nil {
+		return ErrInvalidPoint
+	}
+	if !y.LexicographicallyLargest() {
+		y.Neg(&y)
+	}
+	p.X.Set(x)
+	p.Y.Set(&y)
+	return nil
+}
+
+// MapToScalarField maps p to the scalar field, as x/y reduced modulo the order
+// of Bandersnatch. It is the same for p and p + (0, -1).
+func MapToScalarField(p *bandersnatch.PointAffine) fr.Element {
+	var m fp.Element
+	m.Div(&p.X, &p.Y)
+	b := m.Bytes()
+	var res fr.Element
+	res.SetBytes(b[:])
+	return res
+}
+
+// batchToAffine converts points from extended to affine coordinates, using a
+// single inversion.
+func batchToAffine(points []bandersnatch.PointExtended) []bandersnatch.PointAffine {
+	zs := make([]fp.Element, len(points))
+	for i := range points {
+		zs[i] = points[i].Z
+	}
+	zs = fp.BatchInvert(zs)
+	res := make([]bandersnatch.PointAffine, len(points))
+	for i := range points {
+		res[i].X.Mul(&points[i].X, &zs[i])
+		res[i].Y.Mul(&points[i].Y, &zs[i])
+	}
+	return res
+}
@@ -0,0 +1,141 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// testConfig configuration re-used across tests
+var testConfig = NewConfig()
+
+func TestCRS(t *testing.T) {
+	assert := require.New(t)
+
+	// values of the Ethereum Verkle specification
+	assert.Len(testConfig.Basis, DomainSize)
+	first := PointBytes(&testConfig.Basis[0])
+	assert.Equal("01587ad1336675eb912550ec2a28eb8923b824b490dd2ba82e48f14590a298a0", hex.EncodeToString(first[:]))
+	last := PointBytes(&testConfig.Basis[DomainSize-1])
+	assert.Equal("3de2be346b539395b0c0de56a5ccca54a317f1b5c80107b0802af9a62276a4d8", hex.EncodeToString(last[:]))
+
+	h := sha256.New()
+	for i := range testConfig.Basis {
+		b := PointBytes(&testConfig.Basis[i])
+		h.Write(b[:])
+	}
+	assert.Equal("1fcaea10bf24f750200e06fa473c76ff0468007291fa548e2d99f09ba9256fdb", hex.EncodeToString(h.Sum(nil)))
+}
+
+// randomPoint returns a random Banderwagon element, as a combination of the basis.
+func randomPoint() bandersnatch.PointAffine {
+	s := randomValues(DomainSize)
+	p := multiExp(testConfig.Basis, s)
+	return batchToAffine([]bandersnatch.PointExtended{p})[0]
+}
+
+func randomValues(n int) []fr.Element {
+	res := make([]fr.Element, n)
+	for i := range res {
+		res[i].SetRandom()
+	}
+	return res
+}
+
+func TestPointSerialization(t *testing.T) {
+	assert := require.New(t)
+
+	for i := 0; i < 10; i++ {
+		p := randomPoint()
+
+		// p and p + (0, -1) are the same element
+		var q bandersnatch.PointAffine
+		q.X.Neg(&p.X)
+		q.Y.Neg(&p.Y)
+		assert.True(EqualPoints(&p, &q))
+		assert.Equal(PointBytes(&p), PointBytes(&q))
+		assert.Equal(MapToScalarField(&p), MapToScalarField(&q))
+
+		// round-trip
+		b := PointBytes(&p)
+		var r bandersnatch.PointAffine
+		assert.NoError(SetPointBytes(&r, b[:]))
+		assert.True(r.IsOnCurve())
+		assert.True(EqualPoints(&p, &r))
+		assert.True(r.Y.LexicographicallyLargest())
+
+		assert.False(EqualPoints(&p, &testConfig.Basis[0]))
+	}
+
+	// identity
+	id := identity()
+	b := PointBytes(&id)
+	assert.Equal([PointSize]byte{}, b)
+	var p bandersnatch.PointAffine
+	assert.NoError(SetPointBytes(&p, b[:]))
+	assert.True(EqualPoints(&p, &id))
+
+	// invalid encodings
+	assert.ErrorIs(SetPointBytes(&p, b[1:]), ErrInvalidPoint)
+	var nonCanonical [PointSize]byte
+	for i := range nonCanonical {
+		nonCanonical[i] = 0xff
+	}
+	assert.ErrorIs(SetPointBytes(&p, nonCanonical[:]), ErrInvalidPoint)
+	nbInvalid := 0
+	for i := 0; i < 20; i++ {
+		var x [PointSize]byte
+		x[PointSize-1] = byte(i)
+		if SetPointBytes(&p, x[:]) != nil {
+			nbInvalid++
+		} else {
+			assert.True(p.IsOnCurve())
+		}
+	}
+	assert.NotZero(nbInvalid, "points out of the subgroup must be rejected")
+}
+
+func TestMultiExp(t *testing.T) {
+	assert := require.New(t)
+
+	// repeated points and both representatives of the same element
+	p := randomPoint()
+	var pT bandersnatch.PointAffine
+	pT.X.Neg(&p.X)
+	pT.Y.Neg(&p.Y)
+	for _, n := range []int{1, 2, 5, 40} {
+		points := make([]bandersnatch.PointAffine, n)
+		scalars := randomValues(n)
+		var sum fr.Element
+		for i := range points {
+			points[i] = p
+			if i%2 == 1 {
+				points[i] = pT
+			}
+			sum.Add(&sum, &scalars[i])
+		}
+		var expected bandersnatch.PointExtended
+		expected.FromAffine(&p)
+		scalarMul(&expected, &expected, &sum)
+		res := multiExp(points, scalars)
+		assert.True(equalExtended(&expected, &res), "n=%d", n)
+	}
+}
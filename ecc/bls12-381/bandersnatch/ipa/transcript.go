@@ -0,0 +1,91 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+)
+
+// Transcript Fiat-Shamir transcript of the Ethereum Verkle specification.
+//
+// Labels and messages are appended to a buffer. A challenge is the SHA-256
+// digest of the state and the buffer, read as a little-endian integer reduced
+// modulo the order of the group; the state is then reset and the challenge,
+// with its label, is appended to the buffer.
+//
+// The fiat-shamir package is not used: its challenges are declared upfront
+// and hash their name, bindings and the previous challenge in its own format,
+// so proofs derived with it could not be verified by other implementations of
+// the specification (go-ipa, rust-verkle), nor theirs by this package.
+type Transcript struct {
+	state hash.Hash
+	buf   bytes.Buffer
+}
+
+// NewTranscript returns a transcript whose state is initialized with label.
+// Proofs of a Verkle trie use the label "vt".
+func NewTranscript(label string) *Transcript {
+	t := &Transcript{state: sha256.New()}
+	t.state.Write([]byte(label))
+	return t
+}
+
+// DomainSep appends a domain separator.
+func (t *Transcript) DomainSep(label string) {
+	t.buf.WriteString(label)
+}
+
+// AppendMessage appends a label followed by a message.
+func (t *Transcript) AppendMessage(label string, message []byte) {
+	t.buf.WriteString(label)
+	t.buf.Write(message)
+}
+
+// AppendScalar appends a label followed by the little-endian encoding of s.
+func (t *Transcript) AppendScalar(label string, s *fr.Element) {
+	var b [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&b, *s)
+	t.AppendMessage(label, b[:])
+}
+
+// AppendPoint appends a label followed by the serialization of p (see
+// PointBytes).
+func (t *Transcript) AppendPoint(label string, p *bandersnatch.PointAffine) {
+	b := PointBytes(p)
+	t.AppendMessage(label, b[:])
+}
+
+// ChallengeScalar appends label and returns the challenge derived from the
+// transcript.
+func (t *Transcript) ChallengeScalar(label string) fr.Element {
+	t.DomainSep(label)
+	t.state.Write(t.buf.Bytes())
+	t.buf.Reset()
+	digest := t.state.Sum(nil)
+	t.state.Reset()
+
+	for i, j := 0, len(digest)-1; i < j; i, j = i+1, j-1 {
+		digest[i], digest[j] = digest[j], digest[i]
+	}
+	var res fr.Element
+	res.SetBytes(digest)
+	t.AppendScalar(label, &res)
+	return res
+}
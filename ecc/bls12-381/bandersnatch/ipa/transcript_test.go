@@ -0,0 +1,155 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ipa
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/bandersnatch/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// The vectors of this file are those of the Ethereum Verkle specification,
+// shared by its implementations (go-ipa, rust-verkle).
+
+func TestTranscript(t *testing.T) {
+	assert := require.New(t)
+
+	// the challenges depend on the label of the transcript, on the appended
+	// data and on the previous challenges
+	challenges := func(label string, data ...string) [2]fr.Element {
+		transcript := NewTranscript(label)
+		for _, d := range data {
+			transcript.AppendMessage("m", []byte(d))
+		}
+		return [2]fr.Element{transcript.ChallengeScalar("a"), transcript.ChallengeScalar("b")}
+	}
+	reference := challenges("test", "data")
+	assert.Equal(reference, challenges("test", "data"))
+	assert.NotEqual(reference[0], reference[1])
+	assert.NotEqual(reference, challenges("vt", "data"))
+	assert.NotEqual(reference, challenges("test", "dat"))
+	assert.NotEqual(reference, challenges("test", "data", ""))
+}
+
+func TestIPAConsistency(t *testing.T) {
+	assert := require.New(t)
+
+	poly := specTestPolynomial(false)
+	digest, err := testConfig.Commit(poly)
+	assert.NoError(err)
+	assertPointHex(t, &digest, "1b9dff8f5ebbac250d291dfe90e36283a227c64b113c37f1bfb9e7a743cdb128")
+
+	point := fr.NewElement(2101)
+	transcript := NewTranscript("test")
+	proof, err := testConfig.Open(poly, &digest, point, transcript)
+	assert.NoError(err)
+	assertScalarHex(t, &proof.ClaimedValue, "4a353e70b03c89f161de002e8713beec0d740a5e20722fd5bd68b30540a33208")
+	state := transcript.ChallengeScalar("state")
+	assertScalarHex(t, &state, "0a81881cbfd7d7197a54ebd67ed6a68b5867f3c783706675b34ece43e85e7306")
+
+	var buf bytes.Buffer
+	_, err = proof.IPA.WriteTo(&buf)
+	assert.NoError(err)
+	assert.Equal(
+		"273395a8febdaed38e94c3d874e99c911a47dd84616d54c55021d5c4131b507e46a4ec2c7e82b77ec2f533994c91ca7edaef212c666a1169b29c323eabb0cf69"+
+			"0e0146638d0e2d543f81da4bd597bf3013e1663f340a8f87b845495598d0a3951590b6417f868edaeb3424ff174901d1185a53a3ee127fb7be0af42dda44bf99"+
+			"2885bde279ef821a298087717ef3f2b78b2ede7f5d2ea1b60a4195de86a530eb247fd7e456012ae9a070c61635e55d1b7a340dfab8dae991d6273d099d955281"+
+			"5434cc1ba7bcdae341cf7928c6f25102370bdf4b26aad3af654d9dff4b3735661db3177342de5aad774a59d3e1b12754aee641d5f9cd1ecd2751471b308d2d84"+
+			"10add1c9fcc5a2b7371259f0538270832a98d18151f653efbc60895fab8be9650510449081626b5cd24671d1a3253487d44f589c2ff0da3557e307e520cf4e00"+
+			"54bbf8bdffaa24b7e4cce5092ccae5a08281ee24758374f4e65f126cacce64051905b5e2038060ad399c69ca6cb1d596d7c9cb5e161c7dcddc1a7ad62660dd4a"+
+			"5f69b31229b80e6b3df520714e4ea2b5896ebd48d14c7455e91c1ecf4acc5ffb36937c49413b7d1005dd6efbd526f5af5d61131ca3fcdae1218ce81c75e62b39"+
+			"100ec7f474b48a2bee6cef453fa1bc3db95c7c6575bc2d5927cbf7413181ac905766a4038a7b422a8ef2bf7b5059b5c546c19a33c1049482b9a9093f864913ca"+
+			"82290decf6e9a65bf3f66bc3ba4a8ed17b56d890a83bcbe74435a42499dec115",
+		hex.EncodeToString(buf.Bytes()),
+	)
+
+	transcript = NewTranscript("test")
+	assert.NoError(testConfig.Verify(&digest, &proof, point, transcript))
+	state = transcript.ChallengeScalar("state")
+	assertScalarHex(t, &state, "0a81881cbfd7d7197a54ebd67ed6a68b5867f3c783706675b34ece43e85e7306")
+}
+
+func TestMultiProofConsistency(t *testing.T) {
+	assert := require.New(t)
+
+	polynomials := [][]fr.Element{specTestPolynomial(false), specTestPolynomial(true)}
+	digests := make([]Digest, len(polynomials))
+	for i := range polynomials {
+		var err error
+		digests[i], err = testConfig.Commit(polynomials[i])
+		assert.NoError(err)
+	}
+	points := []uint8{0, 0}
+	values := []fr.Element{fr.NewElement(1), fr.NewElement(32)}
+
+	transcript := NewTranscript("test")
+	proof, err := testConfig.MultiOpen(digests, polynomials, points, transcript)
+	assert.NoError(err)
+	state := transcript.ChallengeScalar("state")
+	assertScalarHex(t, &state, "eee8a80357ff74b766eba39db90797d022e8d6dee426ded71234241be504d519")
+
+	var buf bytes.Buffer
+	_, err = proof.WriteTo(&buf)
+	assert.NoError(err)
+	assert.Equal(
+		"4f53588244efaf07a370ee3f9c467f933eed360d4fbf7a19dfc8bc49b67df4711bf1d0a720717cd6a8c75f1a668cb7cbdd63b48c676b89a7aee4298e71bd7f40"+
+			"13d7657146aa9736817da47051ed6a45fc7b5a61d00eb23e5df82a7f285cc10e67d444e91618465ca68d8ae4f2c916d1942201b7e2aae491ef0f809867d00e83"+
+			"468fb7f9af9b42ede76c1e90d89dd789ff22eb09e8b1d062d8a58b6f88b3cbe80136fc68331178cd45a1df9496ded092d976911b5244b85bc3de41e844ec1942"+
+			"56b39aeee4ea55538a36139211e9910ad6b7a74e75d45b869d0a67aa4bf600930a5f760dfb8e4df9938d1f47b743d71c78ba8585e3b80aba26d24b1f50b36fa1"+
+			"458e79d54c05f58049245392bc3e2b5c5f9a1b99d43ed112ca82b201fb143d401741713188e47f1d6682b0bf496a5d4182836121efff0fd3b030fc6bfb5e21d6"+
+			"314a200963fe75cb856d444a813426b2084dfdc49dca2e649cb9da8bcb47859a4c629e97898e3547c591e39764110a224150d579c33fb74fa5eb96427036899c"+
+			"04154feab5344873d36a53a5baefd78c132be419f3f3a8dd8f60f72eb78dd5f43c53226f5ceb68947da3e19a750d760fb31fa8d4c7f53bfef11c4b89158aa56b"+
+			"1f4395430e16a3128f88e234ce1df7ef865f2d2c4975e8c82225f578310c31fd41d265fd530cbfa2b8895b228a510b806c31dff3b1fa5c08bffad443d567ed0e"+
+			"628febdd22775776e0cc9cebcaea9c6df9279a5d91dd0ee5e7a0434e989a160005321c97026cb559f71db23360105460d959bcdf74bee22c4ad8805a1d497507",
+		hex.EncodeToString(buf.Bytes()),
+	)
+
+	assert.NoError(testConfig.MultiVerify(digests, points, values, &proof, NewTranscript("test")))
+}
+
+// specTestPolynomial returns the evaluations 1, 2, …, 32 repeated 8 times,
+// or 32, 31, …, 1 if reversed.
+func specTestPolynomial(reversed bool) []fr.Element {
+	res := make([]fr.Element, DomainSize)
+	for i := range res {
+		v := uint64(i%32 + 1)
+		if reversed {
+			v = 33 - v
+		}
+		res[i].SetUint64(v)
+	}
+	return res
+}
+
+// saltedTranscript returns a transcript to which salt was appended.
+func saltedTranscript(salt []byte) *Transcript {
+	transcript := NewTranscript("test")
+	transcript.AppendMessage("salt", salt)
+	return transcript
+}
+
+func assertPointHex(t *testing.T, p *Digest, expected string) {
+	b := PointBytes(p)
+	require.Equal(t, expected, hex.EncodeToString(b[:]))
+}
+
+func assertScalarHex(t *testing.T, s *fr.Element, expected string) {
+	var b [fr.Bytes]byte
+	fr.LittleEndian.PutElement(&b, *s)
+	require.Equal(t, expected, hex.EncodeToString(b[:]))
+}
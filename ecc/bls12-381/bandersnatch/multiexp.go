@@ -0,0 +1,198 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bandersnatch
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"math/bits"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// MultiExp computes ∑ᵢ[scalars[i]]points[i] and sets p to the result.
+//
+// See PointExtended.MultiExp.
+func (p *PointAffine) MultiExp(points []PointAffine, scalars []big.Int, config ecc.MultiExpConfig) (*PointAffine, error) {
+	var _p PointExtended
+	if _, err := _p.MultiExp(points, scalars, config); err != nil {
+		return nil, err
+	}
+	p.FromExtended(&_p)
+	return p, nil
+}
+
+// MultiExp computes ∑ᵢ[scalars[i]]points[i] with the bucket method (Pippenger)
+// in extended coordinates, and sets p to the result.
+// The scalars are split with the GLV decomposition kᵢ = kᵢ₁ + λkᵢ₂, so that the
+// multi-exponentiation is done on the points and their images by the
+// endomorphism, with scalars of half the size.
+//
+// The scalars are reduced modulo the order of the subgroup: for points which
+// are not in the subgroup, the result is only correct up to a small torsion
+// component, which a multiplication by the cofactor clears.
+// The bucket additions use the unified addition formulas, so that any point of
+// the curve can be given (including the identity).
+//
+// This call returns an error if len(scalars) != len(points) or if provided config is invalid.
+func (p *PointExtended) MultiExp(points []PointAffine, scalars []big.Int, config ecc.MultiExpConfig) (*PointExtended, error) {
+	if len(points) != len(scalars) {
+		return nil, errors.New("len(points) != len(scalars)")
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	initOnce.Do(initCurveParams)
+
+	// the multi-exponentiation is computed on bases[i] with the non-negative
+	// scalars k[i]
+	// bases = [±P₀, ..., ±Pₙ₋₁, ±φ(P₀), ..., ±φ(Pₙ₋₁)]
+	nbPoints := len(points)
+	bases := make([]PointExtended, 2*nbPoints)
+	k := make([]big.Int, 2*nbPoints)
+	parallel.Execute(nbPoints, func(start, end int) {
+		var s big.Int
+		for i := start; i < end; i++ {
+			s.Mod(&scalars[i], &curveParams.Order)
+			split := ecc.SplitScalar(&s, &curveParams.glvBasis)
+			bases[i].FromAffine(&points[i])
+			bases[nbPoints+i].phi(&bases[i])
+			if bases[nbPoints+i].Z.IsZero() {
+				// φ is not defined by these formulas on the points of its
+				// kernel (with x = 0), which it sends to the identity
+				bases[nbPoints+i].setInfinity()
+			}
+			for j := 0; j < 2; j++ {
+				if split[j].Sign() == -1 {
+					split[j].Neg(&split[j])
+					bases[j*nbPoints+i].Neg(&bases[j*nbPoints+i])
+				}
+				k[j*nbPoints+i].Set(&split[j])
+			}
+		}
+	}, config.NbTasks)
+
+	nbBits := 0
+	for i := range k {
+		if l := k[i].BitLen(); l > nbBits {
+			nbBits = l
+		}
+	}
+
+	// the window size c minimizes the approximate cost (in additions)
+	// nbWindows * (n + 2ᶜ), with 2ᶜ⁻¹ buckets per window, each of them
+	// costing 2 additions in the reduction.
+	c, min := 2, math.MaxInt
+	for cc := 2; cc <= 16; cc++ {
+		nbWindows := (nbBits + cc) / cc
+		if cost := nbWindows * (len(bases) + (1 << cc)); cost < min {
+			c, min = cc, cost
+		}
+	}
+	// with signed digits in [-2ᶜ⁻¹, 2ᶜ⁻¹], nbBits+1 bits are needed
+	nbWindows := (nbBits + c) / c
+
+	// digits[w*len(bases)+i] is the w-th signed digit of k[i]
+	digits := make([]int32, nbWindows*len(bases))
+	parallel.Execute(len(bases), func(start, end int) {
+		for i := start; i < end; i++ {
+			partitionScalar(digits, k[i].Bits(), i, len(bases), c)
+		}
+	}, config.NbTasks)
+
+	// the windows are processed independently
+	windows := make([]PointExtended, nbWindows)
+	parallel.Execute(nbWindows, func(start, end int) {
+		buckets := make([]PointExtended, 1<<(c-1))
+		for w := start; w < end; w++ {
+			processWindow(&windows[w], buckets, bases, digits[w*len(bases):(w+1)*len(bases)])
+		}
+	}, config.NbTasks)
+
+	// ∑ 2ᶜʷ windows[w]
+	var res PointExtended
+	res.setInfinity()
+	for w := nbWindows - 1; w >= 0; w-- {
+		for j := 0; j < c; j++ {
+			res.Double(&res)
+		}
+		res.Add(&res, &windows[w])
+	}
+
+	p.Set(&res)
+	return p, nil
+}
+
+// partitionScalar sets digits[w*n+i] to the w-th signed digit of the scalar
+// of words k, in base 2ᶜ with digits in ]-2ᶜ⁻¹, 2ᶜ⁻¹].
+func partitionScalar(digits []int32, k []big.Word, i, n, c int) {
+	const wordSize = bits.UintSize
+	nbWindows := len(digits) / n
+	carry := int32(0)
+	for w := 0; w < nbWindows; w++ {
+		// c bits of k at position w*c
+		var window big.Word
+		pos := w * c
+		if idx := pos / wordSize; idx < len(k) {
+			window = k[idx] >> (pos % wordSize)
+			if shift := wordSize - pos%wordSize; shift < c && idx+1 < len(k) {
+				window |= k[idx+1] << shift
+			}
+		}
+		digit := int32(window&((1<<c)-1)) + carry
+		carry = 0
+		if digit > 1<<(c-1) {
+			digit -= 1 << c
+			carry = 1
+		}
+		digits[w*n+i] = digit
+	}
+}
+
+// processWindow sets res to ∑ᵢ[digits[i]]bases[i], using the buckets.
+func processWindow(res *PointExtended, buckets, bases []PointExtended, digits []int32) {
+	for j := range buckets {
+		buckets[j].setInfinity()
+	}
+
+	var neg PointExtended
+	for i, d := range digits {
+		if d > 0 {
+			buckets[d-1].Add(&buckets[d-1], &bases[i])
+		} else if d < 0 {
+			neg.Neg(&bases[i])
+			buckets[-d-1].Add(&buckets[-d-1], &neg)
+		}
+	}
+
+	// ∑ (j+1)*buckets[j] = ∑ runningSum
+	var runningSum PointExtended
+	runningSum.setInfinity()
+	res.setInfinity()
+	for j := len(buckets) - 1; j >= 0; j-- {
+		runningSum.Add(&runningSum, &buckets[j])
+		res.Add(res, &runningSum)
+	}
+}
@@ -0,0 +1,376 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bandersnatch
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+// windowSizeConstantTime is the window size of the constant time scalar
+// multiplication, whose table holds the 2ʷ⁻¹ odd multiples of the point.
+const windowSizeConstantTime = 4
+
+// nbDigitsConstantTime is the number of digits of the scalars in the regular
+// recoding of the constant time scalar multiplication. The order of the
+// subgroup being smaller than the modulus of the base field, the scalars fit
+// in fr.Limbs words.
+const nbDigitsConstantTime = (fr.Bits + windowSizeConstantTime - 1) / windowSizeConstantTime
+
+// qConstantTime are the limbs of the modulus of fr and qInvNegConstantTime is
+// -q⁻¹ mod 2⁶⁴, used by the constant time arithmetic.
+var qConstantTime, qInvNegConstantTime = func() (q [fr.Limbs]uint64, qInvNeg uint64) {
+	q = limbsConstantTime(fr.Modulus())
+	// Newton's iteration doubles the number of correct bits of q⁻¹ mod 2⁶⁴
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - q[0]*inv
+	}
+	return q, -inv
+}()
+
+// ScalarMultiplicationConstantTime computes and returns p = [scalar]p1, where
+// p1 is in the prime order subgroup, in constant time.
+//
+// See PointExtended.ScalarMultiplicationConstantTime.
+func (p *PointAffine) ScalarMultiplicationConstantTime(p1 *PointAffine, scalar *big.Int) *PointAffine {
+	var p1Extended, resExtended PointExtended
+	p1Extended.FromAffine(p1)
+	resExtended.ScalarMultiplicationConstantTime(&p1Extended, scalar)
+
+	// the inversion is done with Fermat's little theorem to be constant time
+	// (Z ≠ 0 with the complete formulas)
+	var zInv fr.Element
+	exponent := fr.Modulus()
+	exponent.Sub(exponent, big.NewInt(2))
+	frExpConstantTime(&zInv, &resExtended.Z, exponent)
+	frMulConstantTime(&p.X, &resExtended.X, &zInv)
+	frMulConstantTime(&p.Y, &resExtended.Y, &zInv)
+	return p
+}
+
+// ScalarMultiplicationConstantTime computes and returns p = [scalar]p1, where
+// p1 is in the prime order subgroup, in constant time.
+//
+// Unlike ScalarMultiplication, the sequence of field operations and memory
+// accesses does not depend on the scalar, which can then be a secret (a private
+// key or a nonce). It uses a fixed window method on a regular recoding of the
+// scalar, constant time table lookups and the unified addition formulas, whose
+// field operations are implemented here without branches rather than with the
+// methods of fr.Element. The scalar is reduced modulo the order of the
+// subgroup, which is not done in constant time: secret scalars should be given
+// reduced.
+func (p *PointExtended) ScalarMultiplicationConstantTime(p1 *PointExtended, scalar *big.Int) *PointExtended {
+	initOnce.Do(initCurveParams)
+
+	// the recoding needs an odd scalar: if s is even, ℓ-s (which is odd) is
+	// used instead and the result is negated. If s=0, the result is replaced
+	// by the identity.
+	s := scalar
+	if s.Sign() < 0 || s.Cmp(&curveParams.Order) >= 0 {
+		s = new(big.Int).Mod(scalar, &curveParams.Order)
+	}
+	k, order := limbsConstantTime(s), limbsConstantTime(&curveParams.Order)
+	var kNeg [fr.Limbs]uint64
+	var borrow uint64
+	for i := range kNeg {
+		kNeg[i], borrow = bits.Sub64(order[i], k[i], borrow)
+	}
+	isZero := isZeroConstantTime(k[:])
+	isEven := (k[0] & 1) ^ 1
+	mask := -isEven
+	for i := range k {
+		k[i] = k[i]&^mask | kNeg[i]&mask
+	}
+	k[0] |= 1
+	digits := recodeConstantTime(k)
+
+	// table[i] = [2i+1]p1
+	var table [1 << (windowSizeConstantTime - 1)]PointExtended
+	var p2 PointExtended
+	table[0].Set(p1)
+	p2.doubleConstantTime(p1)
+	for i := 1; i < len(table); i++ {
+		table[i].addConstantTime(&table[i-1], &p2)
+	}
+
+	var res, tmp PointExtended
+	res.lookupConstantTime(&table, digits[nbDigitsConstantTime-1])
+	for i := nbDigitsConstantTime - 2; i >= 0; i-- {
+		for j := 0; j < windowSizeConstantTime; j++ {
+			res.doubleConstantTime(&res)
+		}
+		tmp.lookupConstantTime(&table, digits[i])
+		res.addConstantTime(&res, &tmp)
+	}
+
+	res.negConstantTime(int(isEven))
+
+	var identity PointExtended
+	identity.setInfinity()
+	p.X.Select(isZero, &res.X, &identity.X)
+	p.Y.Select(isZero, &res.Y, &identity.Y)
+	p.Z.Select(isZero, &res.Z, &identity.Z)
+	p.T.Select(isZero, &res.T, &identity.T)
+	return p
+}
+
+// limbsConstantTime returns the little endian 64-bit words of s, which must fit
+// in fr.Limbs words.
+func limbsConstantTime(s *big.Int) (k [fr.Limbs]uint64) {
+	var buf [fr.Limbs * 8]byte
+	s.FillBytes(buf[:])
+	for i := range k {
+		k[i] = binary.BigEndian.Uint64(buf[len(buf)-8*(i+1):])
+	}
+	return
+}
+
+// recodeConstantTime returns the regular recoding of the odd scalar k, with
+// the odd digits dᵢ ∈ [-(2ʷ-1), 2ʷ-1] such that k = ∑ dᵢ2ʷⁱ, the last one
+// being positive (Joye–Tunstall).
+func recodeConstantTime(k [fr.Limbs]uint64) (digits [nbDigitsConstantTime]int) {
+	const w = windowSizeConstantTime
+	for i := 0; i < nbDigitsConstantTime-1; i++ {
+		// dᵢ = (k mod 2ʷ⁺¹) - 2ʷ and k = (k - dᵢ) / 2ʷ, which stays odd
+		digits[i] = int(k[0]&((1<<(w+1))-1)) - (1 << w)
+		for j := 0; j < fr.Limbs-1; j++ {
+			k[j] = k[j]>>w | k[j+1]<<(64-w)
+		}
+		k[fr.Limbs-1] >>= w
+		k[0] |= 1
+	}
+	digits[nbDigitsConstantTime-1] = int(k[0])
+	return
+}
+
+// lookupConstantTime sets p to [d]q, where table[i] = [2i+1]q and d is odd,
+// reading all the entries of the table.
+func (p *PointExtended) lookupConstantTime(table *[1 << (windowSizeConstantTime - 1)]PointExtended, d int) *PointExtended {
+	sign := d >> 63
+	idx := ((d ^ sign) - sign) >> 1
+	for i := range table {
+		c := subtle.ConstantTimeEq(int32(i), int32(idx))
+		p.X.Select(c, &p.X, &table[i].X)
+		p.Y.Select(c, &p.Y, &table[i].Y)
+		p.Z.Select(c, &p.Z, &table[i].Z)
+		p.T.Select(c, &p.T, &table[i].T)
+	}
+	p.negConstantTime(sign & 1)
+	return p
+}
+
+// negConstantTime sets p to -p if c=1 and leaves it unchanged if c=0.
+func (p *PointExtended) negConstantTime(c int) *PointExtended {
+	var xNeg, tNeg fr.Element
+	frNegConstantTime(&xNeg, &p.X)
+	frNegConstantTime(&tNeg, &p.T)
+	p.X.Select(c, &p.X, &xNeg)
+	p.T.Select(c, &p.T, &tNeg)
+	return p
+}
+
+// addConstantTime sets p to p1+p2, as Add, with the constant time field
+// operations.
+func (p *PointExtended) addConstantTime(p1, p2 *PointExtended) *PointExtended {
+	var A, B, C, D, E, F, G, H, tmp fr.Element
+	frMulConstantTime(&A, &p1.X, &p2.X)
+	frMulConstantTime(&B, &p1.Y, &p2.Y)
+	frMulConstantTime(&C, &p1.T, &p2.T)
+	frMulConstantTime(&C, &C, &curveParams.D)
+	frMulConstantTime(&D, &p1.Z, &p2.Z)
+	frAddConstantTime(&tmp, &p1.X, &p1.Y)
+	frAddConstantTime(&E, &p2.X, &p2.Y)
+	frMulConstantTime(&E, &E, &tmp)
+	frSubConstantTime(&E, &E, &A)
+	frSubConstantTime(&E, &E, &B)
+	frSubConstantTime(&F, &D, &C)
+	frAddConstantTime(&G, &D, &C)
+	frMulConstantTime(&H, &A, &curveParams.A)
+	frSubConstantTime(&H, &B, &H)
+
+	frMulConstantTime(&p.X, &E, &F)
+	frMulConstantTime(&p.Y, &G, &H)
+	frMulConstantTime(&p.T, &E, &H)
+	frMulConstantTime(&p.Z, &F, &G)
+	return p
+}
+
+// doubleConstantTime sets p to [2]p1, as Double, with the constant time field
+// operations.
+func (p *PointExtended) doubleConstantTime(p1 *PointExtended) *PointExtended {
+	var A, B, C, D, E, F, G, H fr.Element
+	frMulConstantTime(&A, &p1.X, &p1.X)
+	frMulConstantTime(&B, &p1.Y, &p1.Y)
+	frMulConstantTime(&C, &p1.Z, &p1.Z)
+	frAddConstantTime(&C, &C, &C)
+	frMulConstantTime(&D, &A, &curveParams.A)
+	frAddConstantTime(&E, &p1.X, &p1.Y)
+	frMulConstantTime(&E, &E, &E)
+	frSubConstantTime(&E, &E, &A)
+	frSubConstantTime(&E, &E, &B)
+	frAddConstantTime(&G, &D, &B)
+	frSubConstantTime(&F, &G, &C)
+	frSubConstantTime(&H, &D, &B)
+
+	frMulConstantTime(&p.X, &E, &F)
+	frMulConstantTime(&p.Y, &G, &H)
+	frMulConstantTime(&p.T, &H, &E)
+	frMulConstantTime(&p.Z, &F, &G)
+	return p
+}
+
+// frAddConstantTime sets z = x + y, without branches.
+func frAddConstantTime(z, x, y *fr.Element) {
+	var sum, diff fr.Element
+	var carry, borrow uint64
+	for i := range sum {
+		sum[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+	for i := range diff {
+		diff[i], borrow = bits.Sub64(sum[i], qConstantTime[i], borrow)
+	}
+	// x + y ⩾ q if the addition overflows or the subtraction doesn't
+	z.Select(int(carry|(borrow^1)), &sum, &diff)
+}
+
+// frSubConstantTime sets z = x - y, without branches.
+func frSubConstantTime(z, x, y *fr.Element) {
+	var borrow, carry uint64
+	for i := range z {
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+	mask := -borrow
+	for i := range z {
+		z[i], carry = bits.Add64(z[i], qConstantTime[i]&mask, carry)
+	}
+}
+
+// frNegConstantTime sets z = -x, without branches.
+func frNegConstantTime(z, x *fr.Element) {
+	var zero fr.Element
+	frSubConstantTime(z, &zero, x)
+}
+
+// frMulConstantTime sets z = x⋅y, with a Montgomery multiplication (CIOS)
+// whose final subtraction is done without branches.
+func frMulConstantTime(z, x, y *fr.Element) {
+	var t [5]uint64
+	var c, d, m uint64
+
+	// for each limb yⱼ, t = t + x⋅yⱼ, then t = (t + m⋅q) / 2⁶⁴ with m such
+	// that the division is exact
+	c = 0
+	c, t[0] = maddConstantTime(x[0], y[0], t[0], c)
+	c, t[1] = maddConstantTime(x[1], y[0], t[1], c)
+	c, t[2] = maddConstantTime(x[2], y[0], t[2], c)
+	c, t[3] = maddConstantTime(x[3], y[0], t[3], c)
+	t[4], d = bits.Add64(t[4], c, 0)
+	m = t[0] * qInvNegConstantTime
+	c, _ = maddConstantTime(m, qConstantTime[0], t[0], 0)
+	c, t[0] = maddConstantTime(m, qConstantTime[1], t[1], c)
+	c, t[1] = maddConstantTime(m, qConstantTime[2], t[2], c)
+	c, t[2] = maddConstantTime(m, qConstantTime[3], t[3], c)
+	t[3], c = bits.Add64(t[4], c, 0)
+	t[4] = d + c
+	c = 0
+	c, t[0] = maddConstantTime(x[0], y[1], t[0], c)
+	c, t[1] = maddConstantTime(x[1], y[1], t[1], c)
+	c, t[2] = maddConstantTime(x[2], y[1], t[2], c)
+	c, t[3] = maddConstantTime(x[3], y[1], t[3], c)
+	t[4], d = bits.Add64(t[4], c, 0)
+	m = t[0] * qInvNegConstantTime
+	c, _ = maddConstantTime(m, qConstantTime[0], t[0], 0)
+	c, t[0] = maddConstantTime(m, qConstantTime[1], t[1], c)
+	c, t[1] = maddConstantTime(m, qConstantTime[2], t[2], c)
+	c, t[2] = maddConstantTime(m, qConstantTime[3], t[3], c)
+	t[3], c = bits.Add64(t[4], c, 0)
+	t[4] = d + c
+	c = 0
+	c, t[0] = maddConstantTime(x[0], y[2], t[0], c)
+	c, t[1] = maddConstantTime(x[1], y[2], t[1], c)
+	c, t[2] = maddConstantTime(x[2], y[2], t[2], c)
+	c, t[3] = maddConstantTime(x[3], y[2], t[3], c)
+	t[4], d = bits.Add64(t[4], c, 0)
+	m = t[0] * qInvNegConstantTime
+	c, _ = maddConstantTime(m, qConstantTime[0], t[0], 0)
+	c, t[0] = maddConstantTime(m, qConstantTime[1], t[1], c)
+	c, t[1] = maddConstantTime(m, qConstantTime[2], t[2], c)
+	c, t[2] = maddConstantTime(m, qConstantTime[3], t[3], c)
+	t[3], c = bits.Add64(t[4], c, 0)
+	t[4] = d + c
+	c = 0
+	c, t[0] = maddConstantTime(x[0], y[3], t[0], c)
+	c, t[1] = maddConstantTime(x[1], y[3], t[1], c)
+	c, t[2] = maddConstantTime(x[2], y[3], t[2], c)
+	c, t[3] = maddConstantTime(x[3], y[3], t[3], c)
+	t[4], d = bits.Add64(t[4], c, 0)
+	m = t[0] * qInvNegConstantTime
+	c, _ = maddConstantTime(m, qConstantTime[0], t[0], 0)
+	c, t[0] = maddConstantTime(m, qConstantTime[1], t[1], c)
+	c, t[1] = maddConstantTime(m, qConstantTime[2], t[2], c)
+	c, t[2] = maddConstantTime(m, qConstantTime[3], t[3], c)
+	t[3], c = bits.Add64(t[4], c, 0)
+	t[4] = d + c
+
+	// t < 2q, and q is subtracted if t ⩾ q
+	var res, diff fr.Element
+	var borrow uint64
+	copy(res[:], t[:fr.Limbs])
+	for i := range diff {
+		diff[i], borrow = bits.Sub64(t[i], qConstantTime[i], borrow)
+	}
+	_, borrow = bits.Sub64(t[fr.Limbs], 0, borrow)
+	z.Select(int(borrow^1), &res, &diff)
+}
+
+// maddConstantTime returns hi, lo such that hi⋅2⁶⁴ + lo = a⋅b + c + d.
+func maddConstantTime(a, b, c, d uint64) (hi, lo uint64) {
+	var carry uint64
+	hi, lo = bits.Mul64(a, b)
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return
+}
+
+// frExpConstantTime sets z = xᵉ, the exponent e being public.
+func frExpConstantTime(z, x *fr.Element, e *big.Int) {
+	var res fr.Element
+	res.SetOne()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		frMulConstantTime(&res, &res, &res)
+		if e.Bit(i) == 1 {
+			frMulConstantTime(&res, &res, x)
+		}
+	}
+	z.Set(&res)
+}
+
+// isZeroConstantTime returns 1 if all the limbs are 0, and 0 otherwise.
+func isZeroConstantTime(limbs []uint64) int {
+	var acc uint64
+	for _, l := range limbs {
+		acc |= l
+	}
+	return int(((acc | -acc) >> 63) ^ 1)
+}
@@ -674,7 +674,7 @@ func TestOps(t *testing.T) {
 			pAffine.ScalarMultiplication(&params.Base, &s)
 
 			p.MixedAdd(&pExtended, &pAffine)
-			p2.MixedDouble(&pExtended)
+			p2.Double(&pExtended)
 
 			return p.Equal(&p2)
 		},
@@ -745,6 +745,55 @@ func TestOps(t *testing.T) {
 
 }
 
+// TestMixedAddNonNormalized checks MixedAdd(P, P) = 2P when P is not
+// normalized, that is when its extended coordinates have Z ≠ 1
+func TestMixedAddNonNormalized(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+	genS := GenBigInt()
+
+	properties.Property("(mixed affine+extended) MixedAdd(P, P) = Double(P) for Z ≠ 1", prop.ForAll(
+		func(s, l big.Int) bool {
+
+			params := GetEdwardsCurve()
+
+			var pAffine PointAffine
+			pAffine.ScalarMultiplication(&params.Base, &s)
+
+			// (λX : λY : λZ : λT) with λ ∉ {0, 1}
+			var lambda fr.Element
+			lambda.SetBigInt(&l)
+			if lambda.IsZero() || lambda.IsOne() {
+				lambda.SetUint64(2)
+			}
+			var pExtended PointExtended
+			pExtended.FromAffine(&pAffine)
+			pExtended.X.Mul(&pExtended.X, &lambda)
+			pExtended.Y.Mul(&pExtended.Y, &lambda)
+			pExtended.Z.Mul(&pExtended.Z, &lambda)
+			pExtended.T.Mul(&pExtended.T, &lambda)
+
+			var p, expected PointExtended
+			p.MixedAdd(&pExtended, &pAffine)
+			expected.FromAffine(&pAffine)
+			expected.Double(&expected)
+
+			return p.Equal(&expected)
+		},
+		genS,
+		genS,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
 func TestMarshal(t *testing.T) {
 	t.Parallel()
 	initOnce.Do(initCurveParams)
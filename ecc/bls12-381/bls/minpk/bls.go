@@ -0,0 +1,331 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package minpk
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/signature"
+	"golang.org/x/crypto/hkdf"
+)
+
+const (
+	sizeFr         = fr.Bytes
+	sizePublicKey  = bls12381.SizeOfG1AffineCompressed
+	sizePrivateKey = sizeFr + sizePublicKey
+	sizeSignature  = bls12381.SizeOfG2AffineCompressed
+
+	// minimal size of the input keying material of KeyGen
+	sizeIKM = 32
+	// size of the HKDF output in KeyGen: ceil((3 * ceil(log2(r))) / 16)
+	sizeOKM = (3*fr.Bits + 15) / 16
+)
+
+// Domain separation tags of the proof of possession scheme.
+const (
+	// DSTSignature is the domain separation tag used to hash messages to G2
+	DSTSignature = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	// DSTProofOfPossession is the domain separation tag used to hash public keys to G2
+	DSTProofOfPossession = "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+var (
+	errShortIKM          = errors.New("input keying material must be at least 32 bytes")
+	errInvalidPublicKey  = errors.New("invalid public key: point at infinity")
+	errInvalidPrivateKey = errors.New("invalid private key: public key mismatch")
+	errEmptyInput        = errors.New("no input to aggregate")
+	errLengthMismatch    = errors.New("number of public keys and messages mismatch")
+)
+
+var order = fr.Modulus()
+
+// PublicKey represents a BLS public key
+type PublicKey struct {
+	A bls12381.G1Affine
+}
+
+// PrivateKey represents a BLS private key
+type PrivateKey struct {
+	PublicKey PublicKey
+	scalar    [sizeFr]byte // secret scalar, in big Endian
+}
+
+// Signature represents a BLS signature
+type Signature struct {
+	S bls12381.G2Affine
+}
+
+// GenerateKey generates a public and private key pair, from 32 bytes of
+// input keying material read from rand.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	ikm := make([]byte, sizeIKM)
+	if _, err := io.ReadFull(rand, ikm); err != nil {
+		return nil, err
+	}
+	return KeyGen(ikm, nil)
+}
+
+// KeyGen derives a key pair from the secret input keying material ikm (at
+// least 32 bytes) and an optional keyInfo, as specified in section 2.3 of the
+// IETF draft:
+//
+//	salt = "BLS-SIG-KEYGEN-SALT-"
+//	while SK == 0:
+//		salt = SHA-256(salt)
+//		PRK = HKDF-Extract(salt, ikm ∥ I2OSP(0, 1))
+//		OKM = HKDF-Expand(PRK, keyInfo ∥ I2OSP(L, 2), L)
+//		SK = OS2IP(OKM) mod r
+func KeyGen(ikm, keyInfo []byte) (*PrivateKey, error) {
+	if len(ikm) < sizeIKM {
+		return nil, errShortIKM
+	}
+
+	secret := make([]byte, len(ikm)+1)
+	copy(secret, ikm)
+	info := make([]byte, len(keyInfo)+2)
+	copy(info, keyInfo)
+	info[len(keyInfo)] = byte(sizeOKM >> 8)
+	info[len(keyInfo)+1] = byte(sizeOKM)
+
+	salt := []byte("BLS-SIG-KEYGEN-SALT-")
+	okm := make([]byte, sizeOKM)
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		h := sha256.Sum256(salt)
+		salt = h[:]
+		prk := hkdf.Extract(sha256.New, secret, salt)
+		if _, err := io.ReadFull(hkdf.Expand(sha256.New, prk, info), okm); err != nil {
+			return nil, err
+		}
+		sk.SetBytes(okm).Mod(sk, order)
+	}
+
+	privateKey := new(PrivateKey)
+	sk.FillBytes(privateKey.scalar[:sizeFr])
+	privateKey.PublicKey.A.ScalarMultiplicationBase(sk)
+	return privateKey, nil
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	return &pub
+}
+
+// hashMessage hashes the message to G2. If hFunc is not nil, the
+// message is first hashed with hFunc.
+func hashMessage(message []byte, hFunc hash.Hash, dst string) (bls12381.G2Affine, error) {
+	if hFunc != nil {
+		hFunc.Reset()
+		if _, err := hFunc.Write(message); err != nil {
+			return bls12381.G2Affine{}, err
+		}
+		message = hFunc.Sum(nil)
+	}
+	return bls12381.HashToG2(message, []byte(dst))
+}
+
+// sign returns sk ⋅ HashToG2(message)
+func (privKey *PrivateKey) sign(message []byte, hFunc hash.Hash, dst string) ([]byte, error) {
+	Q, err := hashMessage(message, hFunc, dst)
+	if err != nil {
+		return nil, err
+	}
+	var sig Signature
+	sig.S.ScalarMultiplication(&Q, new(big.Int).SetBytes(privKey.scalar[:sizeFr]))
+	return sig.Bytes(), nil
+}
+
+// Sign performs the BLS signature
+//
+// Q = HashToG2(m)
+// signature = sk ⋅ Q
+//
+// If hFunc is not nil, m is hFunc(message), else m is the message itself.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	return privKey.sign(message, hFunc, DSTSignature)
+}
+
+// ProvePossession returns a proof of possession of the private key, that is a
+// signature of the public key with the DSTProofOfPossession domain separation tag.
+func (privKey *PrivateKey) ProvePossession() ([]byte, error) {
+	return privKey.sign(privKey.PublicKey.Bytes(), nil, DSTProofOfPossession)
+}
+
+// coreVerify checks that e(pk, Q) == e(g, sig) where Q is the hash of the
+// message, g the generator of G1 and pk is assumed valid.
+func coreVerify(pk *bls12381.G1Affine, sig *Signature, message []byte, hFunc hash.Hash, dst string) (bool, error) {
+	Q, err := hashMessage(message, hFunc, dst)
+	if err != nil {
+		return false, err
+	}
+	_, _, g1, _ := bls12381.Generators()
+	var negG1 bls12381.G1Affine
+	negG1.Neg(&g1)
+	return bls12381.PairingCheck([]bls12381.G1Affine{*pk, negG1}, []bls12381.G2Affine{Q, sig.S})
+}
+
+// Verify validates the BLS signature
+//
+// Q = HashToG2(m)
+// e(publicKey, Q) ?= e(g1, signature)
+//
+// If hFunc is not nil, m is hFunc(message), else m is the message itself.
+func (publicKey *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	if err := publicKey.validate(); err != nil {
+		return false, err
+	}
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+	return coreVerify(&publicKey.A, &sig, message, hFunc, DSTSignature)
+}
+
+// VerifyPossession validates a proof of possession of the private key
+// associated to publicKey, as returned by ProvePossession.
+func (publicKey *PublicKey) VerifyPossession(proof []byte) (bool, error) {
+	if err := publicKey.validate(); err != nil {
+		return false, err
+	}
+	var sig Signature
+	if _, err := sig.SetBytes(proof); err != nil {
+		return false, err
+	}
+	return coreVerify(&publicKey.A, &sig, publicKey.Bytes(), nil, DSTProofOfPossession)
+}
+
+// validate checks that the public key is not the point at infinity.
+// The subgroup membership is checked when decoding the public key.
+func (publicKey *PublicKey) validate() error {
+	if publicKey.A.IsInfinity() {
+		return errInvalidPublicKey
+	}
+	return nil
+}
+
+// Aggregate aggregates the signatures into a single signature.
+func Aggregate(signatures [][]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errEmptyInput
+	}
+	var acc bls12381.G2Jac
+	var sig Signature
+	for i := range signatures {
+		if _, err := sig.SetBytes(signatures[i]); err != nil {
+			return nil, err
+		}
+		acc.AddMixed(&sig.S)
+	}
+	sig.S.FromJacobian(&acc)
+	return sig.Bytes(), nil
+}
+
+// AggregatePublicKeys aggregates the public keys into a single public key.
+//
+// The aggregated key can be used to verify an aggregated signature of a
+// single message only if every public key comes with a verified proof of
+// possession.
+func AggregatePublicKeys(publicKeys []PublicKey) (*PublicKey, error) {
+	if len(publicKeys) == 0 {
+		return nil, errEmptyInput
+	}
+	var acc bls12381.G1Jac
+	for i := range publicKeys {
+		if err := publicKeys[i].validate(); err != nil {
+			return nil, err
+		}
+		acc.AddMixed(&publicKeys[i].A)
+	}
+	var res PublicKey
+	res.A.FromJacobian(&acc)
+	return &res, nil
+}
+
+// FastAggregateVerify validates an aggregated signature of a single message
+// by all the public keys.
+//
+// Every public key must come with a verified proof of possession.
+func FastAggregateVerify(publicKeys []PublicKey, aggregatedSig, message []byte, hFunc hash.Hash) (bool, error) {
+	pk, err := AggregatePublicKeys(publicKeys)
+	if err != nil {
+		return false, err
+	}
+	var sig Signature
+	if _, err := sig.SetBytes(aggregatedSig); err != nil {
+		return false, err
+	}
+	return coreVerify(&pk.A, &sig, message, hFunc, DSTSignature)
+}
+
+// AggregateVerify validates an aggregated signature of messages[i] by
+// publicKeys[i] for all i, that is
+//
+//	∏ e(publicKeys[i], HashToG2(messages[i])) ?= e(g1, signature)
+//
+// Every public key must come with a verified proof of possession. The
+// messages do not need to be distinct.
+func AggregateVerify(publicKeys []PublicKey, messages [][]byte, aggregatedSig []byte, hFunc hash.Hash) (bool, error) {
+	if len(publicKeys) == 0 {
+		return false, errEmptyInput
+	}
+	if len(publicKeys) != len(messages) {
+		return false, errLengthMismatch
+	}
+	var sig Signature
+	if _, err := sig.SetBytes(aggregatedSig); err != nil {
+		return false, err
+	}
+
+	n := len(publicKeys)
+	P := make([]bls12381.G1Affine, n+1)
+	Q := make([]bls12381.G2Affine, n+1)
+	for i := 0; i < n; i++ {
+		if err := publicKeys[i].validate(); err != nil {
+			return false, err
+		}
+		H, err := hashMessage(messages[i], hFunc, DSTSignature)
+		if err != nil {
+			return false, err
+		}
+		P[i].Set(&publicKeys[i].A)
+		Q[i].Set(&H)
+	}
+	_, _, g1, _ := bls12381.Generators()
+	P[n].Neg(&g1)
+	Q[n].Set(&sig.S)
+	return bls12381.PairingCheck(P, Q)
+}
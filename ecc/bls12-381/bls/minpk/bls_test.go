@@ -0,0 +1,263 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package minpk
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestBLS(t *testing.T) {
+
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[BLS12-381] test the signing and verification", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing BLS")
+			hFunc := sha256.New()
+			sig, _ := privKey.Sign(msg, hFunc)
+			flag, _ := publicKey.Verify(sig, msg, hFunc)
+
+			return flag
+		},
+	))
+
+	properties.Property("[BLS12-381] test the signing and verification (pre-hashed)", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing BLS")
+			sig, _ := privKey.Sign(msg, nil)
+			flag, _ := publicKey.Verify(sig, msg, nil)
+
+			return flag
+		},
+	))
+
+	properties.Property("[BLS12-381] test the verification of a wrong message", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			sig, _ := privKey.Sign([]byte("testing BLS"), nil)
+			flag, _ := publicKey.Verify(sig, []byte("testing BLS!"), nil)
+
+			return !flag
+		},
+	))
+
+	properties.Property("[BLS12-381] test the proof of possession", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			proof, _ := privKey.ProvePossession()
+			flag, _ := publicKey.VerifyPossession(proof)
+
+			// a signature of the public key is not a valid proof of possession
+			sig, _ := privKey.Sign(publicKey.Bytes(), nil)
+			wrongFlag, _ := publicKey.VerifyPossession(sig)
+
+			return flag && !wrongFlag
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestKeyGen(t *testing.T) {
+	t.Parallel()
+
+	ikm := make([]byte, sizeIKM)
+	if _, err := KeyGen(ikm[:sizeIKM-1], nil); err != errShortIKM {
+		t.Fatal("expected error for short input keying material")
+	}
+
+	// deterministic
+	sk1, err := KeyGen(ikm, []byte("key info"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk2, err := KeyGen(ikm, []byte("key info"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sk1.PublicKey.Equal(&sk2.PublicKey) {
+		t.Fatal("KeyGen should be deterministic")
+	}
+
+	// key info is a domain separator
+	sk3, err := KeyGen(ikm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sk1.PublicKey.Equal(&sk3.PublicKey) {
+		t.Fatal("KeyGen should depend on the key info")
+	}
+}
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+
+	const n = 5
+	privKeys := make([]*PrivateKey, n)
+	publicKeys := make([]PublicKey, n)
+	messages := make([][]byte, n)
+	sameMessageSigs := make([][]byte, n)
+	sigs := make([][]byte, n)
+	msg := []byte("testing BLS aggregation")
+	for i := 0; i < n; i++ {
+		var err error
+		privKeys[i], err = GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		publicKeys[i] = privKeys[i].PublicKey
+		messages[i] = []byte{byte(i)}
+		if sameMessageSigs[i], err = privKeys[i].Sign(msg, nil); err != nil {
+			t.Fatal(err)
+		}
+		if sigs[i], err = privKeys[i].Sign(messages[i], nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("FastAggregateVerify", func(t *testing.T) {
+		aggSig, err := Aggregate(sameMessageSigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := FastAggregateVerify(publicKeys, aggSig, msg, nil); err != nil || !ok {
+			t.Fatal("valid aggregated signature rejected")
+		}
+		if ok, _ := FastAggregateVerify(publicKeys[1:], aggSig, msg, nil); ok {
+			t.Fatal("aggregated signature accepted with a missing public key")
+		}
+		if ok, _ := FastAggregateVerify(publicKeys, aggSig, messages[0], nil); ok {
+			t.Fatal("aggregated signature accepted for a wrong message")
+		}
+	})
+
+	t.Run("AggregateVerify", func(t *testing.T) {
+		aggSig, err := Aggregate(sigs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, err := AggregateVerify(publicKeys, messages, aggSig, nil); err != nil || !ok {
+			t.Fatal("valid aggregated signature rejected")
+		}
+		messages[0], messages[1] = messages[1], messages[0]
+		defer func() { messages[0], messages[1] = messages[1], messages[0] }()
+		if ok, _ := AggregateVerify(publicKeys, messages, aggSig, nil); ok {
+			t.Fatal("aggregated signature accepted for swapped messages")
+		}
+		if _, err := AggregateVerify(publicKeys[1:], messages, aggSig, nil); err != errLengthMismatch {
+			t.Fatal("expected length mismatch error")
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		if _, err := Aggregate(nil); err != errEmptyInput {
+			t.Fatal("expected empty input error")
+		}
+		if _, err := AggregatePublicKeys(nil); err != errEmptyInput {
+			t.Fatal("expected empty input error")
+		}
+	})
+}
+
+func TestInfinityPublicKey(t *testing.T) {
+	t.Parallel()
+
+	var pk PublicKey
+	var sig Signature
+	if ok, err := pk.Verify(sig.Bytes(), []byte("msg"), nil); err != errInvalidPublicKey || ok {
+		t.Fatal("the point at infinity should be rejected as a public key")
+	}
+}
+
+// TestEthereumVectors checks the signature of the Ethereum consensus specs
+// test vector sign_case_84d45c9c7cca6b92.
+func TestEthereumVectors(t *testing.T) {
+	t.Parallel()
+
+	sk, _ := hex.DecodeString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3")
+	pk, _ := hex.DecodeString("a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a")
+	msg := make([]byte, 32)
+	expected, _ := hex.DecodeString("b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55")
+
+	var privKey PrivateKey
+	if _, err := privKey.SetBytes(append(pk, sk...)); err != nil {
+		t.Fatal(err)
+	}
+	sig, err := privKey.Sign(msg, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(sig) != hex.EncodeToString(expected) {
+		t.Fatal("signature doesn't match the test vector")
+	}
+	if ok, err := privKey.PublicKey.Verify(sig, msg, nil); err != nil || !ok {
+		t.Fatal("test vector signature rejected")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkSignBLS(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+
+	msg := []byte("benchmarking BLS sign()")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.Sign(msg, nil)
+	}
+}
+
+func BenchmarkVerifyBLS(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+	msg := []byte("benchmarking BLS sign()")
+	sig, _ := privKey.Sign(msg, nil)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.PublicKey.Verify(sig, msg, nil)
+	}
+}
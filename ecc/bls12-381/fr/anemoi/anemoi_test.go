@@ -0,0 +1,249 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package anemoi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameters(t *testing.T) {
+	assert := require.New(t)
+
+	for _, nbCols := range []int{1, 2} {
+		params := NewParameters(nbCols, NbRounds(nbCols))
+		assert.Equal(NbRounds(nbCols), len(params.C))
+		assert.Equal(NbRounds(nbCols), len(params.D))
+		for r := range params.C {
+			assert.Equal(nbCols, len(params.C[r]))
+			assert.Equal(nbCols, len(params.D[r]))
+		}
+	}
+
+	// C[0][0] = g + 2ᵅ, D[0][0] = g + 2ᵅ + g⁻¹
+	params := NewParameters(1, 1)
+	var c, d fr.Element
+	c.SetUint64(generator + 1<<sBoxDegree)
+	d.SetUint64(generator).Inverse(&d).Add(&d, &c)
+	assert.True(c.Equal(&params.C[0][0]))
+	assert.True(d.Equal(&params.D[0][0]))
+
+	assert.Panics(func() { NewParameters(3, DefaultNbRounds) })
+}
+
+// TestSpecificationVectors checks the permutation and the Jive compression,
+// with the number of rounds of the specification, against vectors computed
+// with an independent implementation of https://eprint.iacr.org/2022/840
+func TestSpecificationVectors(t *testing.T) {
+	assert := require.New(t)
+
+	for _, v := range []struct {
+		nbCols      int
+		permutation []string
+		jive        []string
+	}{
+		{
+			nbCols: 1,
+			permutation: []string{
+				"0x019ea09bf18332c14411e27d2a654837a188f8b718d13faa824730fa20350684",
+				"0x68ae6629a63203e1fc2c8ecbfc72eb940a63a0f7ed9bf9d64bec32dec5217cc0",
+			},
+			jive: []string{
+				"0x6a4d06c597b536a3403e714926d833cbabec99af066d3980ce3363d8e5568345",
+			},
+		},
+		{
+			nbCols: 2,
+			permutation: []string{
+				"0x103198778534d584c4e960834939b6549ade65a64facdf0d75742ddc1e1755b6",
+				"0x504b9b2827c9426bca315935a415895bc787f21b2137cafa259ea66992b416d2",
+				"0x0abe38e4a44d3ca4ce4fd3470129bfe01e2317f98522899420615d4363b4242a",
+				"0x674dfdef1d8e6c4600141c587d5ba59b466df8d0fe675474dfc10861fadb0424",
+			},
+			jive: []string{
+				"0x1aefd15c29821229933933ca4a637634b9017d9fd4cf68a195d58b1f81cb79e2",
+				"0x43abf1c41bba3169970b9d8617cf56f1ba3846e91fa0c370055faecc8d8f1af9",
+			},
+		},
+	} {
+		h := NewPermutation(v.nbCols, NbRounds(v.nbCols))
+
+		// input (0, 1, .., 2ℓ-1)
+		input := make([]fr.Element, 2*v.nbCols)
+		left := make([]byte, 0, v.nbCols*fr.Bytes)
+		right := make([]byte, 0, v.nbCols*fr.Bytes)
+		for i := range input {
+			input[i].SetUint64(uint64(i))
+			b := input[i].Bytes()
+			if i < v.nbCols {
+				left = append(left, b[:]...)
+			} else {
+				right = append(right, b[:]...)
+			}
+		}
+
+		assert.NoError(h.Permutation(input))
+		for i := range input {
+			var expected fr.Element
+			_, err := expected.SetString(v.permutation[i])
+			assert.NoError(err)
+			assert.True(expected.Equal(&input[i]), "permutation mismatch at index %d, ℓ = %d", i, v.nbCols)
+		}
+
+		res, err := h.Compress(left, right)
+		assert.NoError(err)
+		for i := 0; i < v.nbCols; i++ {
+			var expected fr.Element
+			_, err = expected.SetString(v.jive[i])
+			assert.NoError(err)
+			b := expected.Bytes()
+			assert.Equal(b[:], res[i*fr.Bytes:(i+1)*fr.Bytes], "jive mismatch at index %d, ℓ = %d", i, v.nbCols)
+		}
+	}
+}
+
+func TestFlystel(t *testing.T) {
+	assert := require.New(t)
+
+	// α⁻¹ is the inverse of α modulo r-1
+	initOnce.Do(initConstants)
+	var rMinusOne, prod big.Int
+	rMinusOne.Sub(fr.Modulus(), big.NewInt(1))
+	prod.Mul(&sBoxInvExponent, big.NewInt(sBoxDegree)).Mod(&prod, &rMinusOne)
+	assert.Equal(int64(1), prod.Int64())
+
+	// the open Flystel is a permutation, whose inverse is given by the
+	// closed Flystel
+	var x, y, u, v, t0 fr.Element
+	for i := 0; i < 10; i++ {
+		x.SetRandom()
+		y.SetRandom()
+		u.Set(&x)
+		v.Set(&y)
+		flystel(&u, &v)
+
+		// u - g·v² - g⁻¹ = (y - v)ᵅ
+		t0.Sub(&y, &v)
+		sBox(&t0)
+		var w fr.Element
+		w.Square(&v).Mul(&w, &beta).Add(&w, &delta)
+		w.Sub(&u, &w)
+		assert.True(w.Equal(&t0))
+
+		// x = u - g·v² - g⁻¹ + g·y²
+		w.Square(&y).Mul(&w, &beta).Add(&w, &t0)
+		assert.True(w.Equal(&x))
+	}
+}
+
+func TestPermutation(t *testing.T) {
+	assert := require.New(t)
+
+	for _, nbCols := range []int{1, 2} {
+		h := NewPermutation(nbCols, NbRounds(nbCols))
+		assert.ErrorIs(h.Permutation(make([]fr.Element, 2*nbCols+1)), ErrInvalidSizebuffer)
+
+		a := make([]fr.Element, 2*nbCols)
+		for i := range a {
+			a[i].SetRandom()
+		}
+		b := make([]fr.Element, len(a))
+		copy(b, a)
+		assert.NoError(h.Permutation(a))
+		assert.NotEqual(a, b)
+
+		// deterministic
+		assert.NoError(h.Permutation(b))
+		assert.Equal(a, b)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	assert := require.New(t)
+
+	h := NewPermutation(1, NbRounds(1))
+	var x, y fr.Element
+	x.SetRandom()
+	y.SetRandom()
+	bx, by := x.Bytes(), y.Bytes()
+
+	xy, err := h.Compress(bx[:], by[:])
+	assert.NoError(err)
+	assert.Equal(fr.Bytes, len(xy))
+	yx, err := h.Compress(by[:], bx[:])
+	assert.NoError(err)
+	assert.NotEqual(xy, yx)
+
+	_, err = h.Compress(bx[1:], by[:])
+	assert.ErrorIs(err, ErrInvalidSizebuffer)
+
+	// with 2 columns, the inputs and the output are made of 2 elements
+	h = NewPermutation(2, NbRounds(2))
+	xy, err = h.Compress(append(bx[:], by[:]...), append(by[:], bx[:]...))
+	assert.NoError(err)
+	assert.Equal(2*fr.Bytes, len(xy))
+	_, err = h.Compress(bx[:], by[:])
+	assert.ErrorIs(err, ErrInvalidSizebuffer)
+}
+
+func TestHash(t *testing.T) {
+	assert := require.New(t)
+
+	var x fr.Element
+	x.SetRandom()
+	bx := x.Bytes()
+	var zero [fr.Bytes]byte
+
+	h := NewAnemoi()
+	_, err := h.Write(bx[:])
+	assert.NoError(err)
+	d1 := h.Sum(nil)
+	assert.Equal(h.Size(), len(d1))
+
+	// Sum doesn't change the state
+	assert.Equal(d1, h.Sum(nil))
+
+	// trailing zeroes are not ignored thanks to the padding
+	_, err = h.Write(zero[:])
+	assert.NoError(err)
+	assert.NotEqual(d1, h.Sum(nil))
+
+	h.Reset()
+	_, err = h.Write(bx[:])
+	assert.NoError(err)
+	assert.Equal(d1, h.Sum(nil))
+
+	// invalid input
+	h.Reset()
+	_, err = h.Write(append(bx[:], 1))
+	assert.Error(err)
+}
+
+func BenchmarkPermutation(b *testing.B) {
+	h := NewPermutation(DefaultNbCols, DefaultNbRounds)
+	var tmp [2 * DefaultNbCols]fr.Element
+	for i := range tmp {
+		tmp[i].SetRandom()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = h.Permutation(tmp[:])
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"errors"
+	"hash"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+)
+
+const (
+	// BlockSize size that poseidon2 consumes
+	BlockSize = fr.Bytes
+)
+
+// digest is a sponge construction over the poseidon2 permutation. The first
+// entry of the state is the capacity, the remaining Width-1 entries are the
+// rate.
+type digest struct {
+	perm *Permutation
+	data []fr.Element // data to hash
+}
+
+// NewPoseidon2 returns a sponge hash function over the poseidon2 permutation
+// of width DefaultWidth, with DefaultNbFullRounds full rounds and
+// DefaultNbPartialRounds partial rounds.
+func NewPoseidon2() hash.Hash {
+	return NewPoseidon2FromParameters(DefaultWidth, DefaultNbFullRounds, DefaultNbPartialRounds)
+}
+
+// NewPoseidon2FromParameters returns a sponge hash function over the poseidon2
+// permutation of the given width and number of rounds.
+// It panics if width is not 2 or 3, or if nbFullRounds is odd.
+func NewPoseidon2FromParameters(width, nbFullRounds, nbPartialRounds int) hash.Hash {
+	return &digest{perm: NewPermutation(width, nbFullRounds, nbPartialRounds)}
+}
+
+// Reset resets the Hash to its initial state.
+func (d *digest) Reset() {
+	d.data = d.data[:0]
+}
+
+// Sum appends the current hash to b and returns the resulting slice.
+// It does not change the underlying hash state.
+func (d *digest) Sum(b []byte) []byte {
+	h := d.checksum()
+	res := h.Bytes()
+	return append(b, res[:]...)
+}
+
+// Size returns the number of bytes Sum will return.
+func (d *digest) Size() int {
+	return BlockSize
+}
+
+// BlockSize returns the hash's underlying block size.
+// The Write method must be able to accept any amount
+// of data, but it may operate more efficiently if all writes
+// are a multiple of the block size.
+func (d *digest) BlockSize() int {
+	return BlockSize
+}
+
+// Write (via the embedded io.Writer interface) adds more data to the running hash.
+//
+// Each []byte block of size BlockSize represents a big endian fr.Element.
+//
+// If len(p) is not a multiple of BlockSize and any of the []byte in p represent an integer
+// larger than fr.Modulus, this function returns an error.
+//
+// To hash arbitrary data ([]byte not representing canonical field elements) use fr.Hash first
+func (d *digest) Write(p []byte) (int, error) {
+	// we usually expect multiple of block size. But sometimes we hash short
+	// values (FS transcript). Instead of forcing to hash to field, we left-pad the
+	// input here.
+	if len(p) > 0 && len(p) < BlockSize {
+		pp := make([]byte, BlockSize)
+		copy(pp[len(pp)-len(p):], p)
+		p = pp
+	}
+
+	var start int
+	for start = 0; start < len(p); start += BlockSize {
+		if elem, err := fr.BigEndian.Element((*[BlockSize]byte)(p[start : start+BlockSize])); err == nil {
+			d.data = append(d.data, elem)
+		} else {
+			return 0, err
+		}
+	}
+
+	if start != len(p) {
+		return 0, errors.New("invalid input length: must represent a list of field elements, expects a []byte of len m*BlockSize")
+	}
+	return len(p), nil
+}
+
+// checksum absorbs the data followed by the padding 1 0*, rate elements at a
+// time, and squeezes the first rate element.
+func (d *digest) checksum() fr.Element {
+	rate := d.perm.params.Width - 1
+	state := make([]fr.Element, d.perm.params.Width)
+
+	nbBlocks := len(d.data)/rate + 1
+	for i := 0; i < nbBlocks; i++ {
+		for j := 0; j < rate; j++ {
+			k := i*rate + j
+			switch {
+			case k < len(d.data):
+				state[j+1].Add(&state[j+1], &d.data[k])
+			case k == len(d.data):
+				var one fr.Element
+				one.SetOne()
+				state[j+1].Add(&state[j+1], &one)
+			}
+		}
+		// the width of the state is correct by construction
+		_ = d.perm.Permutation(state)
+	}
+
+	return state[1]
+}
@@ -0,0 +1,178 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package poseidon2
+
+import (
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReferenceVectors checks the round keys and the permutation with the
+// default number of rounds against the reference implementation
+// https://github.com/HorizenLabs/poseidon2
+func TestReferenceVectors(t *testing.T) {
+	assert := require.New(t)
+
+	for _, v := range []struct {
+		width    int
+		roundKey string
+		expected []string
+	}{
+		{
+			width:    2,
+			roundKey: "0x6267f5556c88257324c1c8b00d5871b2eba13cc39d72aa10dde6b69bc44c41c7",
+			expected: []string{
+				"0x73c46dd530e248a87b61d19e67fa1b4ed30fc3d09f16531fe189fb945a15ce4e",
+				"0x1f0e305ee21c9366d5793b80251405032a3fee32b9dd0b5f4578262891b043b4",
+			},
+		},
+		{
+			width:    3,
+			roundKey: "0x6f007a551156b3a449e44936b7c093644a0ed33f33eaccc628e942e836c1a875",
+			expected: []string{
+				"0x1b152349b1950b6a8ca75ee4407b6e26ca5cca5650534e56ef3fd45761fbf5f0",
+				"0x4c5793c87d51bdc2c08a32108437dc0000bd0275868f09ebc5f36919af5b3891",
+				"0x1fc8ed171e67902ca49863159fe5ba6325318843d13976143b8125f08b50dc6b",
+			},
+		},
+	} {
+		h := NewPermutation(v.width, DefaultNbFullRounds, DefaultNbPartialRounds)
+
+		// first round key
+		var rc fr.Element
+		_, err := rc.SetString(v.roundKey)
+		assert.NoError(err)
+		assert.True(rc.Equal(&h.Parameters().RoundKeys[0][0]), "round key mismatch, width %d", v.width)
+
+		// permutation of (0, 1, .., t-1)
+		input := make([]fr.Element, v.width)
+		for i := range input {
+			input[i].SetUint64(uint64(i))
+		}
+		assert.NoError(h.Permutation(input))
+		for i := range input {
+			var expected fr.Element
+			_, err = expected.SetString(v.expected[i])
+			assert.NoError(err)
+			assert.True(expected.Equal(&input[i]), "mismatch at index %d, width %d", i, v.width)
+		}
+	}
+}
+
+func TestParameters(t *testing.T) {
+	assert := require.New(t)
+
+	for _, width := range []int{2, 3} {
+		params := NewParameters(width, DefaultNbFullRounds, DefaultNbPartialRounds)
+		assert.Equal(DefaultNbFullRounds+DefaultNbPartialRounds, len(params.RoundKeys))
+		for i, rk := range params.RoundKeys {
+			if i >= DefaultNbFullRounds/2 && i < DefaultNbFullRounds/2+DefaultNbPartialRounds {
+				assert.Equal(1, len(rk))
+			} else {
+				assert.Equal(width, len(rk))
+			}
+		}
+	}
+
+	assert.Panics(func() { NewParameters(4, DefaultNbFullRounds, DefaultNbPartialRounds) })
+	assert.Panics(func() { NewParameters(3, DefaultNbFullRounds+1, DefaultNbPartialRounds) })
+}
+
+func TestPermutation(t *testing.T) {
+	assert := require.New(t)
+
+	h := NewPermutation(2, DefaultNbFullRounds, DefaultNbPartialRounds)
+	assert.ErrorIs(h.Permutation(make([]fr.Element, 3)), ErrInvalidSizebuffer)
+
+	var a, b [2]fr.Element
+	a[0].SetRandom()
+	a[1].SetRandom()
+	b = a
+	assert.NoError(h.Permutation(a[:]))
+	assert.NotEqual(a, b)
+
+	// deterministic
+	assert.NoError(h.Permutation(b[:]))
+	assert.Equal(a, b)
+}
+
+func TestCompress(t *testing.T) {
+	assert := require.New(t)
+
+	h := NewPermutation(2, DefaultNbFullRounds, DefaultNbPartialRounds)
+	var x, y fr.Element
+	x.SetRandom()
+	y.SetRandom()
+	bx, by := x.Bytes(), y.Bytes()
+
+	xy, err := h.Compress(bx[:], by[:])
+	assert.NoError(err)
+	assert.Equal(fr.Bytes, len(xy))
+	yx, err := h.Compress(by[:], bx[:])
+	assert.NoError(err)
+	assert.NotEqual(xy, yx)
+
+	_, err = h.Compress(bx[1:], by[:])
+	assert.ErrorIs(err, ErrInvalidSizebuffer)
+}
+
+func TestHash(t *testing.T) {
+	assert := require.New(t)
+
+	var x fr.Element
+	x.SetRandom()
+	bx := x.Bytes()
+	var zero [fr.Bytes]byte
+
+	h := NewPoseidon2()
+	_, err := h.Write(bx[:])
+	assert.NoError(err)
+	d1 := h.Sum(nil)
+	assert.Equal(h.Size(), len(d1))
+
+	// Sum doesn't change the state
+	assert.Equal(d1, h.Sum(nil))
+
+	// trailing zeroes are not ignored thanks to the padding
+	_, err = h.Write(zero[:])
+	assert.NoError(err)
+	assert.NotEqual(d1, h.Sum(nil))
+
+	h.Reset()
+	_, err = h.Write(bx[:])
+	assert.NoError(err)
+	assert.Equal(d1, h.Sum(nil))
+
+	// invalid input
+	h.Reset()
+	_, err = h.Write(append(bx[:], 1))
+	assert.Error(err)
+}
+
+func BenchmarkPermutation(b *testing.B) {
+	h := NewPermutation(DefaultWidth, DefaultNbFullRounds, DefaultNbPartialRounds)
+	var tmp [DefaultWidth]fr.Element
+	tmp[0].SetRandom()
+	tmp[1].SetRandom()
+	tmp[2].SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = h.Permutation(tmp[:])
+	}
+}
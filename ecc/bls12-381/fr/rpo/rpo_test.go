@@ -0,0 +1,156 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package rpo
+
+import (
+	"math/big"
+	"testing"
+
+	fr "github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameters(t *testing.T) {
+	assert := require.New(t)
+
+	params := NewParameters(DefaultNbRounds)
+	assert.Equal(2*DefaultNbRounds, len(params.RoundKeys))
+
+	// the round keys are deterministic
+	assert.Equal(params.RoundKeys, NewParameters(DefaultNbRounds).RoundKeys)
+
+	assert.Panics(func() { NewParameters(0) })
+}
+
+func TestSBox(t *testing.T) {
+	assert := require.New(t)
+
+	// α⁻¹ is the inverse of α modulo p-1
+	initOnce.Do(initConstants)
+	var pMinusOne, prod big.Int
+	pMinusOne.Sub(fr.Modulus(), big.NewInt(1))
+	prod.Mul(&sBoxInvExponent, big.NewInt(sBoxDegree)).Mod(&prod, &pMinusOne)
+	assert.Equal(int64(1), prod.Int64())
+
+	var x, y fr.Element
+	for i := 0; i < 10; i++ {
+		x.SetRandom()
+		y.Set(&x)
+		sBox(&y)
+		sBoxInv(&y)
+		assert.True(x.Equal(&y))
+	}
+}
+
+func TestPermutation(t *testing.T) {
+	assert := require.New(t)
+
+	h := NewPermutation(DefaultNbRounds)
+	assert.ErrorIs(h.Permutation(make([]fr.Element, Width+1)), ErrInvalidSizebuffer)
+
+	var a, b [Width]fr.Element
+	for i := range a {
+		a[i].SetRandom()
+	}
+	b = a
+	assert.NoError(h.Permutation(a[:]))
+	assert.NotEqual(a, b)
+
+	// deterministic
+	assert.NoError(h.Permutation(b[:]))
+	assert.Equal(a, b)
+}
+
+func TestCompress(t *testing.T) {
+	assert := require.New(t)
+
+	h := NewPermutation(DefaultNbRounds)
+	x := make([]byte, 0, DigestSize*fr.Bytes)
+	y := make([]byte, 0, DigestSize*fr.Bytes)
+	for i := 0; i < DigestSize; i++ {
+		var e fr.Element
+		e.SetRandom()
+		b := e.Bytes()
+		x = append(x, b[:]...)
+		e.SetRandom()
+		b = e.Bytes()
+		y = append(y, b[:]...)
+	}
+
+	xy, err := h.Compress(x, y)
+	assert.NoError(err)
+	assert.Equal(DigestSize*fr.Bytes, len(xy))
+	yx, err := h.Compress(y, x)
+	assert.NoError(err)
+	assert.NotEqual(xy, yx)
+
+	_, err = h.Compress(x[1:], y)
+	assert.ErrorIs(err, ErrInvalidSizebuffer)
+}
+
+func TestHash(t *testing.T) {
+	assert := require.New(t)
+
+	var x fr.Element
+	x.SetRandom()
+	bx := x.Bytes()
+	var zero [fr.Bytes]byte
+
+	h := NewRPO()
+	_, err := h.Write(bx[:])
+	assert.NoError(err)
+	d1 := h.Sum(nil)
+	assert.Equal(h.Size(), len(d1))
+
+	// Sum doesn't change the state
+	assert.Equal(d1, h.Sum(nil))
+
+	// trailing zeroes are not ignored thanks to the length in the capacity
+	_, err = h.Write(zero[:])
+	assert.NoError(err)
+	assert.NotEqual(d1, h.Sum(nil))
+
+	h.Reset()
+	_, err = h.Write(bx[:])
+	assert.NoError(err)
+	assert.Equal(d1, h.Sum(nil))
+
+	// more than Rate elements
+	h.Reset()
+	for i := 0; i <= Rate; i++ {
+		_, err = h.Write(bx[:])
+		assert.NoError(err)
+	}
+	assert.NotEqual(d1, h.Sum(nil))
+
+	// invalid input
+	h.Reset()
+	_, err = h.Write(append(bx[:], 1))
+	assert.Error(err)
+}
+
+func BenchmarkPermutation(b *testing.B) {
+	h := NewPermutation(DefaultNbRounds)
+	var tmp [Width]fr.Element
+	for i := range tmp {
+		tmp[i].SetRandom()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = h.Permutation(tmp[:])
+	}
+}
@@ -0,0 +1,296 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// consensusSpecsTests are the KZG test vectors of the Ethereum consensus
+// specification (tests/<suite>/kzg-mainnet/<case>/data.yaml), converted to
+// JSON. The blobs, which are shared by many cases, are stored once and
+// referred to by their index.
+type consensusSpecsTests struct {
+	Blobs []string                            `json:"blobs"`
+	Tests map[string][]consensusSpecsTestCase `json:"tests"`
+}
+
+type consensusSpecsTestCase struct {
+	Name   string          `json:"name"`
+	Input  json.RawMessage `json:"input"`
+	Output json.RawMessage `json:"output"`
+}
+
+func loadConsensusSpecsTests(t *testing.T) *consensusSpecsTests {
+	f, err := os.Open(filepath.Join("testdata", "consensus_specs.json.gz"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests consensusSpecsTests
+	if err := json.NewDecoder(r).Decode(&tests); err != nil {
+		t.Fatal(err)
+	}
+	return &tests
+}
+
+// run runs the cases of a suite. check returns the output of the function
+// under test, or an error if the inputs are rejected, which is expected if
+// and only if the output of the test case is null.
+func (tests *consensusSpecsTests) run(t *testing.T, suite string, check func(tc *consensusSpecsTestCase) (interface{}, error)) {
+	cases := tests.Tests[suite]
+	if len(cases) == 0 {
+		t.Fatalf("no test case for %s", suite)
+	}
+	for i := range cases {
+		tc := &cases[i]
+		t.Run(tc.Name, func(t *testing.T) {
+			output, err := check(tc)
+			if string(tc.Output) == "null" {
+				if err == nil {
+					t.Fatal("invalid inputs should be rejected")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := json.Marshal(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var expected bytes.Buffer
+			if err := json.Compact(&expected, tc.Output); err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, expected.Bytes()) {
+				t.Fatalf("expected %s, got %s", expected.Bytes(), got)
+			}
+		})
+	}
+}
+
+func (tests *consensusSpecsTests) blob(i int) (*Blob, error) {
+	var blob Blob
+	if err := decodeHexFixed(blob[:], tests.Blobs[i]); err != nil {
+		return nil, err
+	}
+	return &blob, nil
+}
+
+// decodeHexFixed decodes the "0x" prefixed hex string s into dst, which must
+// be exactly filled.
+func decodeHexFixed(dst []byte, s string) error {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dst) {
+		return hex.ErrLength
+	}
+	copy(dst, b)
+	return nil
+}
+
+func encodeHex(b []byte) string {
+	return "0x" + hex.EncodeToString(b)
+}
+
+// isValid returns true if err is nil, false if err is ErrInvalidProof, and
+// err otherwise: a verification must fail with an error on malformed inputs.
+func isValid(err error) (interface{}, error) {
+	if err == ErrInvalidProof {
+		return false, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return true, nil
+}
+
+func TestConsensusSpecs(t *testing.T) {
+	tests := loadConsensusSpecsTests(t)
+
+	t.Run("blob_to_kzg_commitment", func(t *testing.T) {
+		tests.run(t, "blob_to_kzg_commitment", func(tc *consensusSpecsTestCase) (interface{}, error) {
+			var input struct {
+				Blob int `json:"blob"`
+			}
+			if err := json.Unmarshal(tc.Input, &input); err != nil {
+				return nil, err
+			}
+			blob, err := tests.blob(input.Blob)
+			if err != nil {
+				return nil, err
+			}
+			commitment, err := testCtx.BlobToKZGCommitment(blob)
+			if err != nil {
+				return nil, err
+			}
+			return encodeHex(commitment[:]), nil
+		})
+	})
+
+	t.Run("compute_kzg_proof", func(t *testing.T) {
+		tests.run(t, "compute_kzg_proof", func(tc *consensusSpecsTestCase) (interface{}, error) {
+			var input struct {
+				Blob int    `json:"blob"`
+				Z    string `json:"z"`
+			}
+			if err := json.Unmarshal(tc.Input, &input); err != nil {
+				return nil, err
+			}
+			blob, err := tests.blob(input.Blob)
+			if err != nil {
+				return nil, err
+			}
+			var z Scalar
+			if err := decodeHexFixed(z[:], input.Z); err != nil {
+				return nil, err
+			}
+			proof, y, err := testCtx.ComputeKZGProof(blob, z)
+			if err != nil {
+				return nil, err
+			}
+			return []string{encodeHex(proof[:]), encodeHex(y[:])}, nil
+		})
+	})
+
+	t.Run("compute_blob_kzg_proof", func(t *testing.T) {
+		tests.run(t, "compute_blob_kzg_proof", func(tc *consensusSpecsTestCase) (interface{}, error) {
+			var input struct {
+				Blob       int    `json:"blob"`
+				Commitment string `json:"commitment"`
+			}
+			if err := json.Unmarshal(tc.Input, &input); err != nil {
+				return nil, err
+			}
+			blob, err := tests.blob(input.Blob)
+			if err != nil {
+				return nil, err
+			}
+			var commitment KZGCommitment
+			if err := decodeHexFixed(commitment[:], input.Commitment); err != nil {
+				return nil, err
+			}
+			proof, err := testCtx.ComputeBlobKZGProof(blob, commitment)
+			if err != nil {
+				return nil, err
+			}
+			return encodeHex(proof[:]), nil
+		})
+	})
+
+	t.Run("verify_kzg_proof", func(t *testing.T) {
+		tests.run(t, "verify_kzg_proof", func(tc *consensusSpecsTestCase) (interface{}, error) {
+			var input struct {
+				Commitment string `json:"commitment"`
+				Z          string `json:"z"`
+				Y          string `json:"y"`
+				Proof      string `json:"proof"`
+			}
+			if err := json.Unmarshal(tc.Input, &input); err != nil {
+				return nil, err
+			}
+			var commitment KZGCommitment
+			var z, y Scalar
+			var proof KZGProof
+			if err := decodeHexFixed(commitment[:], input.Commitment); err != nil {
+				return nil, err
+			}
+			if err := decodeHexFixed(z[:], input.Z); err != nil {
+				return nil, err
+			}
+			if err := decodeHexFixed(y[:], input.Y); err != nil {
+				return nil, err
+			}
+			if err := decodeHexFixed(proof[:], input.Proof); err != nil {
+				return nil, err
+			}
+			return isValid(testCtx.VerifyKZGProof(commitment, z, y, proof))
+		})
+	})
+
+	t.Run("verify_blob_kzg_proof", func(t *testing.T) {
+		tests.run(t, "verify_blob_kzg_proof", func(tc *consensusSpecsTestCase) (interface{}, error) {
+			var input struct {
+				Blob       int    `json:"blob"`
+				Commitment string `json:"commitment"`
+				Proof      string `json:"proof"`
+			}
+			if err := json.Unmarshal(tc.Input, &input); err != nil {
+				return nil, err
+			}
+			blob, err := tests.blob(input.Blob)
+			if err != nil {
+				return nil, err
+			}
+			var commitment KZGCommitment
+			var proof KZGProof
+			if err := decodeHexFixed(commitment[:], input.Commitment); err != nil {
+				return nil, err
+			}
+			if err := decodeHexFixed(proof[:], input.Proof); err != nil {
+				return nil, err
+			}
+			return isValid(testCtx.VerifyBlobKZGProof(blob, commitment, proof))
+		})
+	})
+
+	t.Run("verify_blob_kzg_proof_batch", func(t *testing.T) {
+		tests.run(t, "verify_blob_kzg_proof_batch", func(tc *consensusSpecsTestCase) (interface{}, error) {
+			var input struct {
+				Blobs       []int    `json:"blobs"`
+				Commitments []string `json:"commitments"`
+				Proofs      []string `json:"proofs"`
+			}
+			if err := json.Unmarshal(tc.Input, &input); err != nil {
+				return nil, err
+			}
+			blobs := make([]Blob, len(input.Blobs))
+			for i := range blobs {
+				blob, err := tests.blob(input.Blobs[i])
+				if err != nil {
+					return nil, err
+				}
+				blobs[i] = *blob
+			}
+			commitments := make([]KZGCommitment, len(input.Commitments))
+			for i := range commitments {
+				if err := decodeHexFixed(commitments[i][:], input.Commitments[i]); err != nil {
+					return nil, err
+				}
+			}
+			proofs := make([]KZGProof, len(input.Proofs))
+			for i := range proofs {
+				if err := decodeHexFixed(proofs[i][:], input.Proofs[i]); err != nil {
+					return nil, err
+				}
+			}
+			return isValid(testCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+		})
+	})
+}
@@ -0,0 +1,25 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package eip4844 implements the polynomial commitments API of EIP-4844
+// (proto-danksharding) on top of the KZG commitment scheme on BLS12-381.
+//
+// Blobs are polynomials of degree < 4096 given by their evaluations on the
+// 4096-th roots of unity, in bit-reversed order. Field elements are encoded
+// as 32 bytes big-endian, commitments and proofs as 48 bytes compressed G₁
+// points.
+//
+// The functions follow the Deneb consensus specification:
+// https://github.com/ethereum/consensus-specs/blob/dev/specs/deneb/polynomial-commitments.md
+package eip4844
@@ -0,0 +1,472 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+)
+
+const (
+	// ScalarsPerBlob is the number of field elements in a blob
+	ScalarsPerBlob = 4096
+	// SerializedScalarSize is the size of an encoded field element
+	SerializedScalarSize = fr.Bytes
+	// BlobSize is the size of an encoded blob
+	BlobSize = ScalarsPerBlob * SerializedScalarSize
+	// CompressedG1Size is the size of an encoded commitment or proof
+	CompressedG1Size = bls12381.SizeOfG1AffineCompressed
+)
+
+// Domain separators of the Fiat-Shamir challenges
+const (
+	fiatShamirProtocolDomain      = "FSBLOBVERIFY_V1_"
+	randomChallengeKZGBatchDomain = "RCKZGBATCH___V1_"
+)
+
+var (
+	ErrInvalidProof         = errors.New("invalid KZG proof")
+	ErrNonCanonicalScalar   = errors.New("scalar is not canonical")
+	ErrBatchLengthCheck     = errors.New("the number of blobs, commitments and proofs must be equal")
+	ErrSRSTooSmall          = errors.New("SRS must contain at least 4096 G1 points")
+	ErrInvalidLagrangeSetup = errors.New("Lagrange setup must contain 4096 G1 points")
+)
+
+// Blob is a polynomial in evaluation form, encoded as 4096 big-endian field
+// elements.
+type Blob [BlobSize]byte
+
+// Scalar is a big-endian encoded field element.
+type Scalar [SerializedScalarSize]byte
+
+// KZGCommitment is a compressed G₁ point committing to a blob.
+type KZGCommitment [CompressedG1Size]byte
+
+// KZGProof is a compressed G₁ point proving the evaluation of a blob.
+type KZGProof [CompressedG1Size]byte
+
+// Context holds the trusted setup in bit-reversed Lagrange form and the
+// bit-reversed roots of unity.
+type Context struct {
+	lagrangeG1 []bls12381.G1Affine // [Lᵢ(τ)]₁, bit-reversed
+	vk         kzg.VerifyingKey
+	roots      []fr.Element // ωⁱ, bit-reversed
+	invWidth   fr.Element   // 1/4096
+}
+
+// NewContext returns a Context built from a KZG SRS in monomial form with at
+// least 4096 G₁ points, such as the one of the Ethereum KZG ceremony (see
+// kzg.SRS.ReadEthereumTranscript).
+func NewContext(srs *kzg.SRS) (*Context, error) {
+	if len(srs.Pk.G1) < ScalarsPerBlob {
+		return nil, ErrSRSTooSmall
+	}
+	lagrange, err := kzg.ToLagrangeG1(srs.Pk.G1[:ScalarsPerBlob])
+	if err != nil {
+		return nil, err
+	}
+	return NewContextFromLagrange(lagrange, &srs.Vk)
+}
+
+// NewContextFromLagrange returns a Context built from the 4096 Lagrange basis
+// commitments [Lᵢ(τ)]₁ in natural order (the KZG_SETUP_G1_LAGRANGE of the
+// specification), and the verifying key holding [1]₂ and [τ]₂.
+func NewContextFromLagrange(lagrangeG1 []bls12381.G1Affine, vk *kzg.VerifyingKey) (*Context, error) {
+	if len(lagrangeG1) != ScalarsPerBlob {
+		return nil, ErrInvalidLagrangeSetup
+	}
+	ctx := &Context{
+		lagrangeG1: make([]bls12381.G1Affine, ScalarsPerBlob),
+		vk:         *vk,
+	}
+	copy(ctx.lagrangeG1, lagrangeG1)
+	bitReverse(ctx.lagrangeG1)
+
+	domain := fft.NewDomain(ScalarsPerBlob)
+	ctx.roots = make([]fr.Element, ScalarsPerBlob)
+	ctx.roots[0].SetOne()
+	for i := 1; i < ScalarsPerBlob; i++ {
+		ctx.roots[i].Mul(&ctx.roots[i-1], &domain.Generator)
+	}
+	fft.BitReverse(ctx.roots)
+	ctx.invWidth.SetUint64(ScalarsPerBlob).Inverse(&ctx.invWidth)
+
+	return ctx, nil
+}
+
+// BlobToKZGCommitment returns the commitment to the blob.
+func (ctx *Context) BlobToKZGCommitment(blob *Blob) (KZGCommitment, error) {
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return KZGCommitment{}, err
+	}
+	var commitment bls12381.G1Affine
+	if _, err = commitment.MultiExp(ctx.lagrangeG1, polynomial, ecc.MultiExpConfig{}); err != nil {
+		return KZGCommitment{}, err
+	}
+	return commitment.Bytes(), nil
+}
+
+// ComputeKZGProof returns the proof of the evaluation of the blob at z, and
+// the evaluation y.
+func (ctx *Context) ComputeKZGProof(blob *Blob, z Scalar) (KZGProof, Scalar, error) {
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return KZGProof{}, Scalar{}, err
+	}
+	zz, err := bytesToBLSField(&z)
+	if err != nil {
+		return KZGProof{}, Scalar{}, err
+	}
+	proof, y, err := ctx.computeKZGProof(polynomial, &zz)
+	if err != nil {
+		return KZGProof{}, Scalar{}, err
+	}
+	return proof, y.Bytes(), nil
+}
+
+// ComputeBlobKZGProof returns the proof of the evaluation of the blob at the
+// Fiat-Shamir challenge derived from the blob and its commitment.
+func (ctx *Context) ComputeBlobKZGProof(blob *Blob, commitment KZGCommitment) (KZGProof, error) {
+	if _, err := bytesToG1(commitment[:]); err != nil {
+		return KZGProof{}, err
+	}
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return KZGProof{}, err
+	}
+	z := computeChallenge(blob, &commitment)
+	proof, _, err := ctx.computeKZGProof(polynomial, &z)
+	return proof, err
+}
+
+// VerifyKZGProof checks that proof proves that the polynomial committed to by
+// commitment evaluates to y at z.
+func (ctx *Context) VerifyKZGProof(commitment KZGCommitment, z, y Scalar, proof KZGProof) error {
+	c, err := bytesToG1(commitment[:])
+	if err != nil {
+		return err
+	}
+	zz, err := bytesToBLSField(&z)
+	if err != nil {
+		return err
+	}
+	yy, err := bytesToBLSField(&y)
+	if err != nil {
+		return err
+	}
+	h, err := bytesToG1(proof[:])
+	if err != nil {
+		return err
+	}
+	return ctx.verifyKZGProof(&c, &zz, &yy, &h)
+}
+
+// VerifyBlobKZGProof checks that proof proves the evaluation of the blob at
+// the Fiat-Shamir challenge derived from the blob and its commitment.
+func (ctx *Context) VerifyBlobKZGProof(blob *Blob, commitment KZGCommitment, proof KZGProof) error {
+	c, err := bytesToG1(commitment[:])
+	if err != nil {
+		return err
+	}
+	polynomial, err := blobToPolynomial(blob)
+	if err != nil {
+		return err
+	}
+	z := computeChallenge(blob, &commitment)
+	y := ctx.evaluate(polynomial, &z)
+	h, err := bytesToG1(proof[:])
+	if err != nil {
+		return err
+	}
+	return ctx.verifyKZGProof(&c, &z, &y, &h)
+}
+
+// VerifyBlobKZGProofBatch checks the proofs of a batch of blobs, as
+// VerifyBlobKZGProof, with a single pairing check.
+func (ctx *Context) VerifyBlobKZGProofBatch(blobs []Blob, commitments []KZGCommitment, proofs []KZGProof) error {
+	n := len(blobs)
+	if len(commitments) != n || len(proofs) != n {
+		return ErrBatchLengthCheck
+	}
+
+	cs := make([]bls12381.G1Affine, n)
+	hs := make([]bls12381.G1Affine, n)
+	zs := make([]fr.Element, n)
+	ys := make([]fr.Element, n)
+	var err error
+	for i := 0; i < n; i++ {
+		if cs[i], err = bytesToG1(commitments[i][:]); err != nil {
+			return err
+		}
+		polynomial, err := blobToPolynomial(&blobs[i])
+		if err != nil {
+			return err
+		}
+		zs[i] = computeChallenge(&blobs[i], &commitments[i])
+		ys[i] = ctx.evaluate(polynomial, &zs[i])
+		if hs[i], err = bytesToG1(proofs[i][:]); err != nil {
+			return err
+		}
+	}
+
+	return ctx.verifyKZGProofBatch(cs, zs, ys, hs, commitments, proofs)
+}
+
+// verifyKZGProof checks e(H, [τ - z]₂) = e(C - [y]₁, [1]₂).
+func (ctx *Context) verifyKZGProof(c *bls12381.G1Affine, z, y *fr.Element, h *bls12381.G1Affine) error {
+	err := kzg.Verify(c, &kzg.OpeningProof{H: *h, ClaimedValue: *y}, *z, ctx.vk)
+	if err == kzg.ErrVerifyOpeningProof {
+		return ErrInvalidProof
+	}
+	return err
+}
+
+// verifyKZGProofBatch checks the opening proofs hᵢ of cᵢ at zᵢ with a random
+// linear combination, the randomness being derived from the encoded inputs:
+//
+//	e(∑ rⁱ⋅hᵢ, [τ]₂) = e(∑ rⁱ⋅(cᵢ - [yᵢ]₁ + zᵢ⋅hᵢ), [1]₂)
+func (ctx *Context) verifyKZGProofBatch(cs []bls12381.G1Affine, zs, ys []fr.Element, hs []bls12381.G1Affine, commitments []KZGCommitment, proofs []KZGProof) error {
+	n := len(cs)
+	if n == 0 {
+		return nil
+	}
+
+	// r = hash_to_bls_field(domain ∥ 4096 ∥ n ∥ (cᵢ ∥ zᵢ ∥ yᵢ ∥ hᵢ)ᵢ)
+	h := sha256.New()
+	var buf [8]byte
+	h.Write([]byte(randomChallengeKZGBatchDomain))
+	binary.BigEndian.PutUint64(buf[:], ScalarsPerBlob)
+	h.Write(buf[:])
+	binary.BigEndian.PutUint64(buf[:], uint64(n))
+	h.Write(buf[:])
+	for i := 0; i < n; i++ {
+		h.Write(commitments[i][:])
+		b := zs[i].Bytes()
+		h.Write(b[:])
+		b = ys[i].Bytes()
+		h.Write(b[:])
+		h.Write(proofs[i][:])
+	}
+	var r fr.Element
+	r.SetBytes(h.Sum(nil))
+
+	// rⁱ and rⁱ⋅zᵢ
+	rPowers := make([]fr.Element, n)
+	rzs := make([]fr.Element, n)
+	rPowers[0].SetOne()
+	for i := 1; i < n; i++ {
+		rPowers[i].Mul(&rPowers[i-1], &r)
+	}
+	var sumRY fr.Element
+	for i := 0; i < n; i++ {
+		rzs[i].Mul(&rPowers[i], &zs[i])
+		var t fr.Element
+		t.Mul(&rPowers[i], &ys[i])
+		sumRY.Add(&sumRY, &t)
+	}
+
+	config := ecc.MultiExpConfig{}
+	var proofLincomb, rhs, t bls12381.G1Jac
+	if _, err := proofLincomb.MultiExp(hs, rPowers, config); err != nil {
+		return err
+	}
+	// ∑ rⁱ⋅cᵢ + ∑ rⁱ⋅zᵢ⋅hᵢ - (∑ rⁱ⋅yᵢ)⋅[1]₁
+	if _, err := rhs.MultiExp(cs, rPowers, config); err != nil {
+		return err
+	}
+	if _, err := t.MultiExp(hs, rzs, config); err != nil {
+		return err
+	}
+	rhs.AddAssign(&t)
+	var bSumRY big.Int
+	sumRY.BigInt(&bSumRY)
+	t.FromAffine(&ctx.vk.G1)
+	t.ScalarMultiplication(&t, &bSumRY)
+	rhs.SubAssign(&t)
+
+	var p [2]bls12381.G1Affine
+	p[0].FromJacobian(&proofLincomb)
+	p[1].FromJacobian(&rhs)
+	p[1].Neg(&p[1])
+	ok, err := bls12381.PairingCheck(p[:], []bls12381.G2Affine{ctx.vk.G2[1], ctx.vk.G2[0]})
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidProof
+	}
+	return nil
+}
+
+// computeKZGProof returns the proof of the evaluation of the polynomial at z
+// and the evaluation y, computing the quotient (p(X) - y)/(X - z) in
+// evaluation form.
+func (ctx *Context) computeKZGProof(polynomial []fr.Element, z *fr.Element) (KZGProof, fr.Element, error) {
+	y := ctx.evaluate(polynomial, z)
+
+	// denominators ωᵢ - z, one of them is zero if z is in the domain
+	quotient := make([]fr.Element, ScalarsPerBlob)
+	inDomain := -1
+	for i := range quotient {
+		quotient[i].Sub(&ctx.roots[i], z)
+		if quotient[i].IsZero() {
+			inDomain = i
+		}
+	}
+	quotient = fr.BatchInvert(quotient)
+	for i := range quotient {
+		if i == inDomain {
+			continue
+		}
+		var t fr.Element
+		t.Sub(&polynomial[i], &y)
+		quotient[i].Mul(&quotient[i], &t)
+	}
+	if inDomain >= 0 {
+		quotient[inDomain] = ctx.quotientWithinDomain(polynomial, z, &y, inDomain)
+	}
+
+	var proof bls12381.G1Affine
+	if _, err := proof.MultiExp(ctx.lagrangeG1, quotient, ecc.MultiExpConfig{}); err != nil {
+		return KZGProof{}, fr.Element{}, err
+	}
+	return proof.Bytes(), y, nil
+}
+
+// quotientWithinDomain returns the evaluation at z = ωₘ of the quotient
+// (p(X) - y)/(X - z):
+//
+//	q(z) = ∑_{i≠m} (pᵢ - y)⋅ωᵢ / (z⋅(z - ωᵢ))
+func (ctx *Context) quotientWithinDomain(polynomial []fr.Element, z, y *fr.Element, m int) fr.Element {
+	denominators := make([]fr.Element, 0, ScalarsPerBlob-1)
+	numerators := make([]fr.Element, 0, ScalarsPerBlob-1)
+	for i := range ctx.roots {
+		if i == m {
+			continue
+		}
+		var d, n fr.Element
+		d.Sub(z, &ctx.roots[i]).Mul(&d, z)
+		n.Sub(&polynomial[i], y).Mul(&n, &ctx.roots[i])
+		denominators = append(denominators, d)
+		numerators = append(numerators, n)
+	}
+	denominators = fr.BatchInvert(denominators)
+	var res fr.Element
+	for i := range numerators {
+		numerators[i].Mul(&numerators[i], &denominators[i])
+		res.Add(&res, &numerators[i])
+	}
+	return res
+}
+
+// evaluate returns the evaluation at z of the polynomial given in
+// bit-reversed evaluation form, using the barycentric formula
+//
+//	p(z) = (zⁿ - 1)/n ⋅ ∑ pᵢ⋅ωᵢ/(z - ωᵢ)
+func (ctx *Context) evaluate(polynomial []fr.Element, z *fr.Element) fr.Element {
+	denominators := make([]fr.Element, ScalarsPerBlob)
+	for i := range denominators {
+		if ctx.roots[i].Equal(z) {
+			return polynomial[i]
+		}
+		denominators[i].Sub(z, &ctx.roots[i])
+	}
+	denominators = fr.BatchInvert(denominators)
+
+	var res fr.Element
+	for i := range denominators {
+		var t fr.Element
+		t.Mul(&polynomial[i], &ctx.roots[i]).Mul(&t, &denominators[i])
+		res.Add(&res, &t)
+	}
+	var zn fr.Element
+	zn.Exp(*z, big.NewInt(ScalarsPerBlob))
+	zn.Sub(&zn, new(fr.Element).SetOne())
+	res.Mul(&res, &zn).Mul(&res, &ctx.invWidth)
+	return res
+}
+
+// computeChallenge returns the Fiat-Shamir challenge
+//
+//	hash_to_bls_field(domain ∥ 4096 ∥ blob ∥ commitment)
+//
+// where 4096 is encoded on 16 bytes big-endian.
+func computeChallenge(blob *Blob, commitment *KZGCommitment) fr.Element {
+	h := sha256.New()
+	h.Write([]byte(fiatShamirProtocolDomain))
+	var degree [16]byte
+	binary.BigEndian.PutUint64(degree[8:], ScalarsPerBlob)
+	h.Write(degree[:])
+	h.Write(blob[:])
+	h.Write(commitment[:])
+
+	var z fr.Element
+	z.SetBytes(h.Sum(nil))
+	return z
+}
+
+// blobToPolynomial decodes the field elements of the blob, which must be
+// canonical.
+func blobToPolynomial(blob *Blob) ([]fr.Element, error) {
+	polynomial := make([]fr.Element, ScalarsPerBlob)
+	for i := range polynomial {
+		var err error
+		chunk := (*Scalar)(blob[i*SerializedScalarSize : (i+1)*SerializedScalarSize])
+		if polynomial[i], err = bytesToBLSField(chunk); err != nil {
+			return nil, err
+		}
+	}
+	return polynomial, nil
+}
+
+// bytesToBLSField decodes a canonical big-endian field element.
+func bytesToBLSField(b *Scalar) (fr.Element, error) {
+	e, err := fr.BigEndian.Element((*[fr.Bytes]byte)(b))
+	if err != nil {
+		return fr.Element{}, ErrNonCanonicalScalar
+	}
+	return e, nil
+}
+
+// bytesToG1 decodes a compressed G₁ point, checking it is in the prime order
+// subgroup. The point at infinity is accepted.
+func bytesToG1(b []byte) (bls12381.G1Affine, error) {
+	var p bls12381.G1Affine
+	_, err := p.SetBytes(b)
+	return p, err
+}
+
+// bitReverse permutes a in bit-reversed index order.
+func bitReverse(a []bls12381.G1Affine) {
+	n := uint64(len(a))
+	nn := uint64(64 - bits.TrailingZeros64(n))
+
+	for i := uint64(0); i < n; i++ {
+		irev := bits.Reverse64(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
@@ -0,0 +1,275 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	"github.com/stretchr/testify/require"
+)
+
+// testCtx is the context of the Ethereum KZG ceremony (the mainnet trusted
+// setup of the consensus specification), re-used across tests.
+var testCtx *Context
+
+func init() {
+	f, err := os.Open(filepath.Join("testdata", "trusted_setup.json"))
+	if err != nil {
+		panic(err)
+	}
+	defer f.Close()
+	if testCtx, err = ReadTrustedSetup(f); err != nil {
+		panic(err)
+	}
+}
+
+// toySRS returns an insecure SRS with a known toxic waste, used to check the
+// Lagrange form of the setup against its monomial form.
+func toySRS(t *testing.T) *kzg.SRS {
+	srs, err := kzg.NewSRS(ScalarsPerBlob, big.NewInt(42))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return srs
+}
+
+func randomBlob(seed uint64) (*Blob, []fr.Element) {
+	var blob Blob
+	polynomial := make([]fr.Element, ScalarsPerBlob)
+	var s fr.Element
+	s.SetUint64(seed)
+	for i := range polynomial {
+		polynomial[i].Square(&s).Add(&polynomial[i], &s).Add(&polynomial[i], new(fr.Element).SetUint64(uint64(i)))
+		s = polynomial[i]
+		b := polynomial[i].Bytes()
+		copy(blob[i*SerializedScalarSize:], b[:])
+	}
+	return &blob, polynomial
+}
+
+// toMonomial returns the coefficients of the polynomial given in bit-reversed
+// evaluation form.
+func toMonomial(polynomial []fr.Element) []fr.Element {
+	coeffs := make([]fr.Element, len(polynomial))
+	copy(coeffs, polynomial)
+	fft.BitReverse(coeffs)
+	domain := fft.NewDomain(ScalarsPerBlob)
+	domain.FFTInverse(coeffs, fft.DIF)
+	fft.BitReverse(coeffs)
+	return coeffs
+}
+
+func eval(coeffs []fr.Element, z fr.Element) fr.Element {
+	var res fr.Element
+	for i := len(coeffs) - 1; i >= 0; i-- {
+		res.Mul(&res, &z).Add(&res, &coeffs[i])
+	}
+	return res
+}
+
+func TestBlobToKZGCommitment(t *testing.T) {
+	assert := require.New(t)
+
+	// the commitment to the zero blob is the point at infinity, whatever the
+	// setup
+	var zero Blob
+	commitment, err := testCtx.BlobToKZGCommitment(&zero)
+	assert.NoError(err)
+	expected, _ := hex.DecodeString("c00000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000")
+	assert.Equal(expected, commitment[:])
+
+	// non canonical field element
+	blob, _ := randomBlob(1)
+	modulus := fr.Modulus().Bytes()
+	copy(blob[3*SerializedScalarSize:], modulus)
+	_, err = testCtx.BlobToKZGCommitment(blob)
+	assert.ErrorIs(err, ErrNonCanonicalScalar)
+}
+
+func TestNewContext(t *testing.T) {
+	assert := require.New(t)
+
+	// the commitment in bit-reversed Lagrange form matches the commitment of
+	// the monomial form
+	srs := toySRS(t)
+	ctx, err := NewContext(srs)
+	assert.NoError(err)
+	blob, polynomial := randomBlob(1)
+	commitment, err := ctx.BlobToKZGCommitment(blob)
+	assert.NoError(err)
+	digest, err := kzg.Commit(toMonomial(polynomial), srs.Pk)
+	assert.NoError(err)
+	assert.Equal(digest.Bytes(), [CompressedG1Size]byte(commitment))
+
+	_, err = NewContext(&kzg.SRS{Pk: kzg.ProvingKey{G1: srs.Pk.G1[:ScalarsPerBlob-1]}, Vk: srs.Vk})
+	assert.ErrorIs(err, ErrSRSTooSmall)
+}
+
+func TestComputeKZGProof(t *testing.T) {
+	assert := require.New(t)
+
+	blob, polynomial := randomBlob(2)
+	commitment, err := testCtx.BlobToKZGCommitment(blob)
+	assert.NoError(err)
+	coeffs := toMonomial(polynomial)
+
+	// outside of the domain
+	var z fr.Element
+	z.SetUint64(123456789)
+	proof, y, err := testCtx.ComputeKZGProof(blob, z.Bytes())
+	assert.NoError(err)
+	expectedY := eval(coeffs, z)
+	assert.Equal(expectedY.Bytes(), [SerializedScalarSize]byte(y))
+	assert.NoError(testCtx.VerifyKZGProof(commitment, z.Bytes(), y, proof))
+
+	// wrong evaluation
+	var wrongY fr.Element
+	wrongY.SetOne().Add(&wrongY, &expectedY)
+	assert.ErrorIs(testCtx.VerifyKZGProof(commitment, z.Bytes(), wrongY.Bytes(), proof), ErrInvalidProof)
+
+	// within the domain
+	for _, i := range []int{0, 1, 4095} {
+		z = testCtx.roots[i]
+		proof, y, err = testCtx.ComputeKZGProof(blob, z.Bytes())
+		assert.NoError(err)
+		assert.Equal(polynomial[i].Bytes(), [SerializedScalarSize]byte(y))
+		assert.NoError(testCtx.VerifyKZGProof(commitment, z.Bytes(), y, proof))
+	}
+}
+
+func TestVerifyBlobKZGProof(t *testing.T) {
+	assert := require.New(t)
+
+	const n = 4
+	blobs := make([]Blob, n)
+	commitments := make([]KZGCommitment, n)
+	proofs := make([]KZGProof, n)
+	for i := 0; i < n; i++ {
+		blob, _ := randomBlob(uint64(i + 3))
+		blobs[i] = *blob
+		var err error
+		commitments[i], err = testCtx.BlobToKZGCommitment(blob)
+		assert.NoError(err)
+		proofs[i], err = testCtx.ComputeBlobKZGProof(blob, commitments[i])
+		assert.NoError(err)
+		assert.NoError(testCtx.VerifyBlobKZGProof(blob, commitments[i], proofs[i]))
+	}
+	assert.NoError(testCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs))
+	assert.NoError(testCtx.VerifyBlobKZGProofBatch(nil, nil, nil))
+
+	// proof of another blob
+	assert.ErrorIs(testCtx.VerifyBlobKZGProof(&blobs[0], commitments[0], proofs[1]), ErrInvalidProof)
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+	assert.ErrorIs(testCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs), ErrInvalidProof)
+	proofs[0], proofs[1] = proofs[1], proofs[0]
+
+	// proof at infinity
+	var infinity KZGProof
+	infinity[0] = 0xc0
+	assert.ErrorIs(testCtx.VerifyBlobKZGProof(&blobs[0], commitments[0], infinity), ErrInvalidProof)
+
+	// invalid point
+	invalid := proofs[0]
+	invalid[CompressedG1Size-1] ^= 1
+	assert.Error(testCtx.VerifyBlobKZGProof(&blobs[0], commitments[0], invalid))
+
+	assert.ErrorIs(testCtx.VerifyBlobKZGProofBatch(blobs[1:], commitments, proofs), ErrBatchLengthCheck)
+}
+
+func TestReadTrustedSetup(t *testing.T) {
+	assert := require.New(t)
+
+	srs := toySRS(t)
+	ctx, err := NewContext(srs)
+	assert.NoError(err)
+	lagrange := make([]bls12381.G1Affine, ScalarsPerBlob)
+	copy(lagrange, ctx.lagrangeG1)
+	bitReverse(lagrange)
+	var setup trustedSetupJSON
+	for i := range lagrange {
+		setup.G1Lagrange = append(setup.G1Lagrange, hexPoint(lagrange[i].Bytes()))
+		setup.G1Monomial = append(setup.G1Monomial, hexPoint(srs.Pk.G1[i].Bytes()))
+	}
+	for i := range srs.Vk.G2 {
+		b := srs.Vk.G2[i].Bytes()
+		setup.G2Monomial = append(setup.G2Monomial, "0x"+hex.EncodeToString(b[:]))
+	}
+	data, err := json.Marshal(&setup)
+	assert.NoError(err)
+
+	read, err := ReadTrustedSetup(bytes.NewReader(data))
+	assert.NoError(err)
+	blob, _ := randomBlob(7)
+	c1, err := read.BlobToKZGCommitment(blob)
+	assert.NoError(err)
+	c2, err := ctx.BlobToKZGCommitment(blob)
+	assert.NoError(err)
+	assert.Equal(c1, c2)
+
+	// inconsistent Lagrange and monomial forms
+	setup.G1Lagrange[0], setup.G1Lagrange[1] = setup.G1Lagrange[1], setup.G1Lagrange[0]
+	data, err = json.Marshal(&setup)
+	assert.NoError(err)
+	_, err = ReadTrustedSetup(bytes.NewReader(data))
+	assert.ErrorIs(err, ErrInvalidTrustedSetup)
+}
+
+func hexPoint(b [bls12381.SizeOfG1AffineCompressed]byte) string {
+	return "0x" + hex.EncodeToString(b[:])
+}
+
+func BenchmarkBlobToKZGCommitment(b *testing.B) {
+	blob, _ := randomBlob(1)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		testCtx.BlobToKZGCommitment(blob)
+	}
+}
+
+func BenchmarkComputeBlobKZGProof(b *testing.B) {
+	blob, _ := randomBlob(1)
+	commitment, _ := testCtx.BlobToKZGCommitment(blob)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		testCtx.ComputeBlobKZGProof(blob, commitment)
+	}
+}
+
+func BenchmarkVerifyBlobKZGProofBatch(b *testing.B) {
+	const n = 16
+	blobs := make([]Blob, n)
+	commitments := make([]KZGCommitment, n)
+	proofs := make([]KZGProof, n)
+	for i := 0; i < n; i++ {
+		blob, _ := randomBlob(uint64(i))
+		blobs[i] = *blob
+		commitments[i], _ = testCtx.BlobToKZGCommitment(blob)
+		proofs[i], _ = testCtx.ComputeBlobKZGProof(blob, commitments[i])
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		testCtx.VerifyBlobKZGProofBatch(blobs, commitments, proofs)
+	}
+}
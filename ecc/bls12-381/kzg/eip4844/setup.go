@@ -0,0 +1,122 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package eip4844
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+
+	bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/kzg"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var ErrInvalidTrustedSetup = errors.New("invalid trusted setup")
+
+// trustedSetupJSON is the trusted setup in the JSON format of the consensus
+// specification (trusted_setup_4096.json): hex encoded compressed points.
+type trustedSetupJSON struct {
+	G1Monomial []string `json:"g1_monomial"`
+	G1Lagrange []string `json:"g1_lagrange"`
+	G2Monomial []string `json:"g2_monomial"`
+}
+
+// ReadTrustedSetup returns a Context from the trusted setup in the JSON format
+// of the consensus specification. All points are checked to be in the prime
+// order subgroups and, if the monomial form of the setup is present, the
+// Lagrange form is checked against it.
+func ReadTrustedSetup(r io.Reader) (*Context, error) {
+	var setup trustedSetupJSON
+	if err := json.NewDecoder(r).Decode(&setup); err != nil {
+		return nil, err
+	}
+	if len(setup.G1Lagrange) != ScalarsPerBlob || len(setup.G2Monomial) < 2 {
+		return nil, ErrInvalidTrustedSetup
+	}
+
+	lagrange, err := decodeHexG1(setup.G1Lagrange)
+	if err != nil {
+		return nil, err
+	}
+	var vk kzg.VerifyingKey
+	for i := range vk.G2 {
+		if err := decodeHexPoint(&vk.G2[i], setup.G2Monomial[i]); err != nil {
+			return nil, err
+		}
+	}
+	_, _, vk.G1, _ = bls12381.Generators()
+	vk.Lines[0] = bls12381.PrecomputeLines(vk.G2[0])
+	vk.Lines[1] = bls12381.PrecomputeLines(vk.G2[1])
+
+	if len(setup.G1Monomial) != 0 {
+		if len(setup.G1Monomial) != ScalarsPerBlob {
+			return nil, ErrInvalidTrustedSetup
+		}
+		monomial, err := decodeHexG1(setup.G1Monomial)
+		if err != nil {
+			return nil, err
+		}
+		expected, err := kzg.ToLagrangeG1(monomial)
+		if err != nil {
+			return nil, err
+		}
+		for i := range expected {
+			if !expected[i].Equal(&lagrange[i]) {
+				return nil, ErrInvalidTrustedSetup
+			}
+		}
+	}
+
+	return NewContextFromLagrange(lagrange, &vk)
+}
+
+// decodeHexG1 decodes hex encoded compressed points of G₁, in parallel.
+func decodeHexG1(s []string) ([]bls12381.G1Affine, error) {
+	res := make([]bls12381.G1Affine, len(s))
+	var failed uint32
+	parallel.Execute(len(s), func(start, end int) {
+		for i := start; i < end; i++ {
+			if decodeHexPoint(&res[i], s[i]) != nil {
+				atomic.StoreUint32(&failed, 1)
+				return
+			}
+		}
+	})
+	if failed != 0 {
+		return nil, ErrInvalidTrustedSetup
+	}
+	return res, nil
+}
+
+// decodeHexPoint decodes a "0x" prefixed hex encoded compressed point, with
+// subgroup check.
+func decodeHexPoint(p interface{ SetBytes([]byte) (int, error) }, s string) error {
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil {
+		return ErrInvalidTrustedSetup
+	}
+	n, err := p.SetBytes(b)
+	if err != nil {
+		return err
+	}
+	if n != len(b) {
+		return ErrInvalidTrustedSetup
+	}
+	return nil
+}
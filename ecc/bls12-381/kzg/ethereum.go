@@ -0,0 +1,271 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"math/big"
+	"strings"
+	"sync/atomic"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidEthereumTranscript  = errors.New("invalid Ethereum KZG ceremony transcript")
+	ErrEthereumTranscriptNotFound = errors.New("no sub-transcript with the requested number of G1 powers")
+)
+
+// ethereumCeremony is the JSON transcript of the Ethereum KZG ceremony, see
+// https://github.com/ethereum/kzg-ceremony-specs. Points are hex encoded
+// compressed points, in the same format as bls12381.G1Affine.Bytes
+// and bls12381.G2Affine.Bytes.
+type ethereumCeremony struct {
+	Transcripts                []ethereumTranscript `json:"transcripts"`
+	ParticipantIds             []string             `json:"participantIds"`
+	ParticipantEcdsaSignatures []string             `json:"participantEcdsaSignatures"`
+}
+
+type ethereumTranscript struct {
+	NumG1Powers int `json:"numG1Powers"`
+	NumG2Powers int `json:"numG2Powers"`
+	PowersOfTau struct {
+		G1Powers []string `json:"G1Powers"`
+		G2Powers []string `json:"G2Powers"`
+	} `json:"powersOfTau"`
+	Witness struct {
+		RunningProducts []string `json:"runningProducts"`
+		PotPubkeys      []string `json:"potPubkeys"`
+		BlsSignatures   []string `json:"blsSignatures"`
+	} `json:"witness"`
+}
+
+// ReadEthereumTranscript reads the JSON transcript of the Ethereum KZG
+// ceremony and sets srs from the sub-transcript with nbG1Powers powers of τ
+// in G₁ (4096, 8192, 16384 or 32768 for the Ethereum ceremony).
+//
+// All points are checked to be in the prime order subgroups, the powers of τ
+// are checked for consistency and, if present, the witness is checked: the
+// running products must be built from the participants' public keys and end
+// with [τ]₁.
+func (srs *SRS) ReadEthereumTranscript(r io.Reader, nbG1Powers int) error {
+	var ceremony ethereumCeremony
+	if err := json.NewDecoder(r).Decode(&ceremony); err != nil {
+		return err
+	}
+
+	var t *ethereumTranscript
+	for i := range ceremony.Transcripts {
+		if ceremony.Transcripts[i].NumG1Powers == nbG1Powers {
+			t = &ceremony.Transcripts[i]
+			break
+		}
+	}
+	if t == nil {
+		return ErrEthereumTranscriptNotFound
+	}
+	if nbG1Powers < 2 {
+		return ErrMinSRSSize
+	}
+	if len(t.PowersOfTau.G1Powers) != t.NumG1Powers ||
+		len(t.PowersOfTau.G2Powers) != t.NumG2Powers ||
+		t.NumG2Powers < 2 {
+		return ErrInvalidEthereumTranscript
+	}
+
+	var res SRS
+	var err error
+	if res.Pk.G1, err = ethereumDecodeG1(t.PowersOfTau.G1Powers); err != nil {
+		return err
+	}
+	for i := range res.Vk.G2 {
+		if err = ethereumDecodePoint(&res.Vk.G2[i], t.PowersOfTau.G2Powers[i]); err != nil {
+			return err
+		}
+	}
+	res.Vk.G1 = res.Pk.G1[0]
+	if err = verifySRS(&res); err != nil {
+		return err
+	}
+	if len(t.Witness.RunningProducts) != 0 {
+		if err = ethereumVerifyWitness(t, &res.Pk.G1[1]); err != nil {
+			return err
+		}
+	}
+	res.Vk.Lines[0] = bls12381.PrecomputeLines(res.Vk.G2[0])
+	res.Vk.Lines[1] = bls12381.PrecomputeLines(res.Vk.G2[1])
+	*srs = res
+
+	return nil
+}
+
+// WriteEthereumTranscript writes srs in the JSON format of the Ethereum KZG
+// ceremony transcript, as a single sub-transcript.
+//
+// The SRS only holds [1]₂ and [τ]₂, so the sub-transcript has two G₂ powers,
+// and its witness records a single update, from the generators to τ.
+func (srs *SRS) WriteEthereumTranscript(w io.Writer) error {
+	if len(srs.Pk.G1) < 2 {
+		return ErrMinSRSSize
+	}
+	var t ethereumTranscript
+	t.NumG1Powers = len(srs.Pk.G1)
+	t.NumG2Powers = len(srs.Vk.G2)
+	t.PowersOfTau.G1Powers = make([]string, len(srs.Pk.G1))
+	parallel.Execute(len(srs.Pk.G1), func(start, end int) {
+		for i := start; i < end; i++ {
+			b := srs.Pk.G1[i].Bytes()
+			t.PowersOfTau.G1Powers[i] = "0x" + hex.EncodeToString(b[:])
+		}
+	})
+	t.PowersOfTau.G2Powers = make([]string, len(srs.Vk.G2))
+	for i := range srs.Vk.G2 {
+		b := srs.Vk.G2[i].Bytes()
+		t.PowersOfTau.G2Powers[i] = "0x" + hex.EncodeToString(b[:])
+	}
+	t.Witness.RunningProducts = []string{t.PowersOfTau.G1Powers[0], t.PowersOfTau.G1Powers[1]}
+	t.Witness.PotPubkeys = []string{t.PowersOfTau.G2Powers[0], t.PowersOfTau.G2Powers[1]}
+	t.Witness.BlsSignatures = []string{"", ""}
+
+	ceremony := ethereumCeremony{
+		Transcripts:                []ethereumTranscript{t},
+		ParticipantIds:             []string{},
+		ParticipantEcdsaSignatures: []string{},
+	}
+	return json.NewEncoder(w).Encode(&ceremony)
+}
+
+// ethereumVerifyWitness checks that the running products of the witness
+// start from the generator of G₁ and end with tau, and that each of them is
+// the previous one multiplied by the secret of the corresponding public key:
+//
+//	e(runningProducts[i+1], [1]₂) = e(runningProducts[i], potPubkeys[i+1])
+//
+// All the checks are batched in a single multi-pairing with random
+// coefficients.
+func ethereumVerifyWitness(t *ethereumTranscript, tau *bls12381.G1Affine) error {
+	n := len(t.Witness.RunningProducts)
+	if len(t.Witness.PotPubkeys) != n || n < 2 {
+		return ErrInvalidEthereumTranscript
+	}
+	products, err := ethereumDecodeG1(t.Witness.RunningProducts)
+	if err != nil {
+		return err
+	}
+	pubKeys := make([]bls12381.G2Affine, n-1)
+	var failed uint32
+	parallel.Execute(n-1, func(start, end int) {
+		for i := start; i < end; i++ {
+			if ethereumDecodePoint(&pubKeys[i], t.Witness.PotPubkeys[i+1]) != nil {
+				atomic.StoreUint32(&failed, 1)
+				return
+			}
+		}
+	})
+	if failed != 0 {
+		return ErrInvalidEthereumTranscript
+	}
+
+	_, _, g1, g2 := bls12381.Generators()
+	if !products[0].Equal(&g1) || !products[n-1].Equal(tau) {
+		return ErrInvalidEthereumTranscript
+	}
+
+	// ∏ᵢ e(ρⁱ⋅runningProducts[i], potPubkeys[i+1]) ⋅ e(-∑ᵢ ρⁱ⋅runningProducts[i+1], [1]₂) = 1
+	rhos := make([]fr.Element, n-1)
+	rhos[0].SetOne()
+	if n > 2 {
+		if _, err = rhos[1].SetRandom(); err != nil {
+			return err
+		}
+		for i := 2; i < len(rhos); i++ {
+			rhos[i].Mul(&rhos[i-1], &rhos[1])
+		}
+	}
+	left := make([]bls12381.G1Affine, n)
+	parallel.Execute(n-1, func(start, end int) {
+		for i := start; i < end; i++ {
+			if products[i].IsInfinity() || products[i+1].IsInfinity() {
+				atomic.StoreUint32(&failed, 1)
+				return
+			}
+			left[i].ScalarMultiplication(&products[i], rhos[i].BigInt(new(big.Int)))
+		}
+	})
+	if failed != 0 {
+		return ErrInvalidEthereumTranscript
+	}
+	if _, err = left[n-1].MultiExp(products[1:], rhos, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	left[n-1].Neg(&left[n-1])
+
+	right := append(pubKeys, g2)
+	ok, err := bls12381.PairingCheck(left, right)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidEthereumTranscript
+	}
+	return nil
+}
+
+// ethereumDecodeG1 decodes hex encoded compressed points of G₁, in parallel.
+func ethereumDecodeG1(s []string) ([]bls12381.G1Affine, error) {
+	res := make([]bls12381.G1Affine, len(s))
+	var failed uint32
+	parallel.Execute(len(s), func(start, end int) {
+		for i := start; i < end; i++ {
+			if ethereumDecodePoint(&res[i], s[i]) != nil {
+				atomic.StoreUint32(&failed, 1)
+				return
+			}
+		}
+	})
+	if failed != 0 {
+		return nil, ErrInvalidEthereumTranscript
+	}
+	return res, nil
+}
+
+// ethereumDecodePoint decodes a "0x" prefixed hex encoded compressed point,
+// with subgroup check.
+func ethereumDecodePoint(p interface{ SetBytes([]byte) (int, error) }, s string) error {
+	if !strings.HasPrefix(s, "0x") {
+		return ErrInvalidEthereumTranscript
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return ErrInvalidEthereumTranscript
+	}
+	n, err := p.SetBytes(b)
+	if err != nil {
+		return err
+	}
+	if n != len(b) {
+		return ErrInvalidEthereumTranscript
+	}
+	return nil
+}
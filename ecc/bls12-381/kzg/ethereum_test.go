@@ -0,0 +1,163 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEthereumTranscript(t *testing.T) {
+	assert := require.New(t)
+
+	srs, err := NewSRS(64, big.NewInt(42))
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	assert.NoError(srs.WriteEthereumTranscript(&buf))
+	data := buf.Bytes()
+
+	// the first powers are the generators, as in the Ethereum ceremony
+	var ceremony ethereumCeremony
+	assert.NoError(json.Unmarshal(data, &ceremony))
+	assert.Equal(1, len(ceremony.Transcripts))
+	assert.Equal("0x97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb",
+		ceremony.Transcripts[0].PowersOfTau.G1Powers[0])
+	assert.Equal("0x93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8",
+		ceremony.Transcripts[0].PowersOfTau.G2Powers[0])
+
+	// round trip
+	var decoded SRS
+	assert.NoError(decoded.ReadEthereumTranscript(bytes.NewReader(data), 64))
+	assert.Equal(len(srs.Pk.G1), len(decoded.Pk.G1))
+	for i := range srs.Pk.G1 {
+		assert.True(srs.Pk.G1[i].Equal(&decoded.Pk.G1[i]))
+	}
+	assert.True(srs.Vk.G2[1].Equal(&decoded.Vk.G2[1]))
+	assert.Equal(srs.Vk.Lines, decoded.Vk.Lines)
+
+	assert.ErrorIs(decoded.ReadEthereumTranscript(bytes.NewReader(data), 128), ErrEthereumTranscriptNotFound)
+}
+
+func TestEthereumTranscriptWitness(t *testing.T) {
+	assert := require.New(t)
+
+	// a ceremony with two sub-transcripts, each of them updated by three
+	// participants, as the Ethereum KZG ceremony
+	ceremony := ethereumCeremony{
+		Transcripts: []ethereumTranscript{
+			ethereumTestTranscript(assert, 16, 1),
+			ethereumTestTranscript(assert, 32, 4),
+		},
+	}
+	data, err := json.Marshal(&ceremony)
+	assert.NoError(err)
+	var decoded SRS
+	for _, tc := range ceremony.Transcripts {
+		assert.NoError(decoded.ReadEthereumTranscript(bytes.NewReader(data), tc.NumG1Powers))
+		assert.Equal(tc.PowersOfTau.G1Powers[1], hexG1(&decoded.Pk.G1[1]))
+	}
+
+	tc := &ceremony.Transcripts[1]
+	tamper := map[string]func(){
+		"swapped public keys": func() {
+			tc.Witness.PotPubkeys[1], tc.Witness.PotPubkeys[2] = tc.Witness.PotPubkeys[2], tc.Witness.PotPubkeys[1]
+		},
+		"skipped running product": func() {
+			tc.Witness.RunningProducts[2] = tc.Witness.RunningProducts[1]
+		},
+		"running products not ending with [τ]₁": func() {
+			n := len(tc.Witness.RunningProducts)
+			tc.Witness.RunningProducts = tc.Witness.RunningProducts[:n-1]
+			tc.Witness.PotPubkeys = tc.Witness.PotPubkeys[:n-1]
+		},
+		"running products not starting with [1]₁": func() {
+			tc.Witness.RunningProducts = tc.Witness.RunningProducts[1:]
+			tc.Witness.PotPubkeys = tc.Witness.PotPubkeys[1:]
+		},
+	}
+	witness := tc.Witness
+	for name, f := range tamper {
+		tc.Witness.RunningProducts = append([]string(nil), witness.RunningProducts...)
+		tc.Witness.PotPubkeys = append([]string(nil), witness.PotPubkeys...)
+		f()
+		data, err = json.Marshal(&ceremony)
+		assert.NoError(err)
+		assert.ErrorIs(decoded.ReadEthereumTranscript(bytes.NewReader(data), 32), ErrInvalidEthereumTranscript, name)
+		// the other sub-transcript is still valid
+		assert.NoError(decoded.ReadEthereumTranscript(bytes.NewReader(data), 16), name)
+	}
+	tc.Witness = witness
+
+	// tampered powers of τ
+	tc.PowersOfTau.G1Powers[3], tc.PowersOfTau.G1Powers[4] = tc.PowersOfTau.G1Powers[4], tc.PowersOfTau.G1Powers[3]
+	data, err = json.Marshal(&ceremony)
+	assert.NoError(err)
+	assert.ErrorIs(decoded.ReadEthereumTranscript(bytes.NewReader(data), 32), ErrSRSNotWellFormed)
+}
+
+// ethereumTestTranscript returns a sub-transcript of nbG1Powers powers of τ,
+// built by three contributions with deterministic randomness, and its witness.
+func ethereumTestTranscript(assert *require.Assertions, nbG1Powers int, seed byte) ethereumTranscript {
+	transcript, err := NewTranscript(uint64(nbG1Powers))
+	assert.NoError(err)
+	_, _, g1, g2 := bls12381.Generators()
+	runningProducts := []string{hexG1(&g1)}
+	potPubkeys := []string{hexG2(&g2)}
+	for i := byte(0); i < 3; i++ {
+		randomness := bytes.Repeat([]byte{seed + i}, 128)
+		prev := transcript.SRS
+		assert.NoError(transcript.Contribute(bytes.NewReader(randomness)))
+
+		// the public key of the participant is [x]₂, where τ⋅x is the new secret
+		x, err := randomNonZero(bytes.NewReader(randomness))
+		assert.NoError(err)
+		var pk, check bls12381.G2Affine
+		pk.ScalarMultiplication(&g2, x)
+		check.ScalarMultiplication(&prev.Vk.G2[1], x)
+		assert.True(check.Equal(&transcript.SRS.Vk.G2[1]))
+
+		runningProducts = append(runningProducts, hexG1(&transcript.SRS.Pk.G1[1]))
+		potPubkeys = append(potPubkeys, hexG2(&pk))
+	}
+
+	var buf bytes.Buffer
+	assert.NoError(transcript.SRS.WriteEthereumTranscript(&buf))
+	var ceremony ethereumCeremony
+	assert.NoError(json.Unmarshal(buf.Bytes(), &ceremony))
+	t := ceremony.Transcripts[0]
+	t.Witness.RunningProducts = runningProducts
+	t.Witness.PotPubkeys = potPubkeys
+	t.Witness.BlsSignatures = make([]string, len(potPubkeys))
+	return t
+}
+
+func hexG1(p *bls12381.G1Affine) string {
+	b := p.Bytes()
+	return "0x" + hex.EncodeToString(b[:])
+}
+
+func hexG2(p *bls12381.G2Affine) string {
+	b := p.Bytes()
+	return "0x" + hex.EncodeToString(b[:])
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr/fft"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrInvalidDomain    = errors.New("domain cardinality must be at least the polynomial size")
+	ErrInvalidCosetSize = errors.New("coset size must be a power of 2 dividing the domain cardinality")
+)
+
+// OpenAll computes the opening proofs of p at all the points ωʲ of the
+// domain, where ω is the domain generator (the domain shift, if any, is not
+// used), with the Feist–Khovratovich algorithm (FK20).
+//
+// It costs O(n log n) group operations, where n is the domain cardinality,
+// instead of O(n²) for n calls to Open. The proofs are in natural order: the
+// j-th proof opens p at ωʲ.
+//
+// See https://eprint.iacr.org/2023/033.
+func OpenAll(p []fr.Element, domain *fft.Domain, pk ProvingKey) ([]OpeningProof, error) {
+	h, err := OpenAllCosets(p, domain, 1, pk)
+	if err != nil {
+		return nil, err
+	}
+
+	// claimed values: evaluations of p on the domain
+	evals := make([]fr.Element, domain.Cardinality)
+	copy(evals, p)
+	domain.FFT(evals, fft.DIF)
+	fft.BitReverse(evals)
+
+	proofs := make([]OpeningProof, len(h))
+	for j := range proofs {
+		proofs[j].H = h[j]
+		proofs[j].ClaimedValue = evals[j]
+	}
+	return proofs, nil
+}
+
+// OpenAllCosets computes the multi-reveal proofs of p on the cosets of the
+// subgroup of order cosetSize of the domain, with the Feist–Khovratovich
+// algorithm.
+//
+// Let n be the domain cardinality, ω its generator and N = n/cosetSize. The
+// i-th proof, for i < N, is the commitment to the quotient of p by
+// X^cosetSize - ω^(i⋅cosetSize), the vanishing polynomial of the coset
+// {ω^(i+N⋅j)}ⱼ. Verifying it requires [τ^cosetSize]₂.
+func OpenAllCosets(p []fr.Element, domain *fft.Domain, cosetSize int, pk ProvingKey) ([]bls12381.G1Affine, error) {
+	n := int(domain.Cardinality)
+	if len(p) > n {
+		return nil, ErrInvalidDomain
+	}
+	if cosetSize < 1 || cosetSize > n || cosetSize&(cosetSize-1) != 0 {
+		return nil, ErrInvalidCosetSize
+	}
+	if len(p) > len(pk.G1) {
+		return nil, ErrInvalidPolynomialSize
+	}
+
+	// generator of the subgroup of order N
+	nbCosets := n / cosetSize
+	var generator fr.Element
+	generator.Exp(domain.Generator, big.NewInt(int64(cosetSize)))
+
+	h := fk20(p, nbCosets, cosetSize, pk.G1)
+	fftG1(h, generator)
+
+	return bls12381.BatchJacobianToAffineG1(h), nil
+}
+
+// fk20 returns the vector h of size N such that, for l = cosetSize,
+//
+//	hₘ = ∑_{k ≥ l⋅(m+1)} pₖ⋅[τ^(k-l⋅(m+1))]₁
+//
+// so that the commitment to the quotient of p by X^l - c is ∑ₘ hₘ⋅cᵐ.
+//
+// Splitting k = l⋅a + b, h is the sum over b < l of Toeplitz matrix-vector
+// products between (p_{l⋅a+b})ₐ and (srs_{l⋅a+b})ₐ, each computed as a
+// circulant product of size 2N with FFTs. The sum is done in the Fourier
+// domain, so that a single inverse FFT is needed.
+func fk20(p []fr.Element, N, l int, srs []bls12381.G1Affine) []bls12381.G1Jac {
+	var infinity bls12381.G1Jac
+	infinity.FromAffine(&bls12381.G1Affine{})
+
+	h := make([]bls12381.G1Jac, N)
+	for i := range h {
+		h[i].Set(&infinity)
+	}
+	if N < 2 {
+		return h
+	}
+
+	domain := fft.NewDomain(uint64(2 * N))
+	var invCardinality fr.Element
+	invCardinality.SetUint64(uint64(2 * N)).Inverse(&invCardinality)
+
+	acc := make([]bls12381.G1Jac, 2*N)
+	for i := range acc {
+		acc[i].Set(&infinity)
+	}
+	r := make([]bls12381.G1Jac, 2*N)
+	f := make([]fr.Element, 2*N)
+	for b := 0; b < l; b++ {
+		// r = (0, srs_{l⋅(N-2)+b}, …, srs_{b}, 0, …, 0)
+		for j := range r {
+			r[j].Set(&infinity)
+		}
+		for j := 1; j < N; j++ {
+			if idx := l*(N-1-j) + b; idx < len(srs) {
+				r[j].FromAffine(&srs[idx])
+			}
+		}
+		fftG1(r, domain.Generator)
+
+		// f = (p_b, p_{l+b}, …, p_{l⋅(N-1)+b}, 0, …, 0) / 2N
+		for a := range f {
+			f[a].SetZero()
+			if k := l*a + b; a < N && k < len(p) {
+				f[a].Mul(&p[k], &invCardinality)
+			}
+		}
+		domain.FFT(f, fft.DIF)
+		fft.BitReverse(f)
+
+		parallel.Execute(2*N, func(start, end int) {
+			var t bls12381.G1Jac
+			var s big.Int
+			for i := start; i < end; i++ {
+				t.ScalarMultiplication(&r[i], f[i].BigInt(&s))
+				acc[i].AddAssign(&t)
+			}
+		})
+	}
+
+	// the circulant product is the inverse FFT of acc, of which we keep the
+	// coefficients N, …, 2N-2
+	fftG1(acc, domain.GeneratorInv)
+	copy(h[:N-1], acc[N:2*N-1])
+
+	return h
+}
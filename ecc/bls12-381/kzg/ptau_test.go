@@ -0,0 +1,197 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/stretchr/testify/require"
+)
+
+// offsetTauG1 is the offset of the [τⁱ]₁ points in a .ptau file: the file
+// header, the header section and the head of the [τⁱ]₁ section
+const offsetTauG1 = 12 + 12 + 4 + fp.Bytes + 4 + 4 + 12
+
+func TestPtau(t *testing.T) {
+	assert := require.New(t)
+
+	const power = 4
+	tau, alpha, beta := big.NewInt(42), big.NewInt(7), big.NewInt(11)
+	ptau, err := NewPtau(power, tau, alpha, beta)
+	assert.NoError(err)
+
+	var buf bytes.Buffer
+	assert.NoError(ptau.WritePtau(&buf))
+	data := buf.Bytes()
+
+	// the first point of the [τⁱ]₁ section is the generator, in little-endian
+	// Montgomery form
+	_, _, g1, _ := bls12381.Generators()
+	expectedG1 := append(ptauEncodeTest(g1.X.BigInt(new(big.Int))), ptauEncodeTest(g1.Y.BigInt(new(big.Int)))...)
+	assert.Equal(expectedG1, data[offsetTauG1:offsetTauG1+ptauSizeG1])
+
+	// all the sections are read back
+	var decoded Ptau
+	assert.NoError(decoded.ReadPtau(bytes.NewReader(data)))
+	assert.Equal(ptau.Power, decoded.Power)
+	assert.Equal(ptau.CeremonyPower, decoded.CeremonyPower)
+	assert.Equal(ptau.TauG1, decoded.TauG1)
+	assert.Equal(ptau.TauG2, decoded.TauG2)
+	assert.Equal(ptau.AlphaTauG1, decoded.AlphaTauG1)
+	assert.Equal(ptau.BetaTauG1, decoded.BetaTauG1)
+	assert.True(ptau.BetaG2.Equal(&decoded.BetaG2))
+
+	// the powers [τⁱ]₁ are read as a KZG SRS, the other sections are skipped
+	var srs SRS
+	assert.NoError(srs.ReadPtau(bytes.NewReader(data)))
+	expected, err := NewSRS(2<<power-1, tau)
+	assert.NoError(err)
+	assert.Equal(len(expected.Pk.G1), len(srs.Pk.G1))
+	for i := range expected.Pk.G1 {
+		assert.True(expected.Pk.G1[i].Equal(&srs.Pk.G1[i]))
+	}
+	assert.True(expected.Vk.G2[0].Equal(&srs.Vk.G2[0]))
+	assert.True(expected.Vk.G2[1].Equal(&srs.Vk.G2[1]))
+	assert.True(expected.Vk.G1.Equal(&srs.Vk.G1))
+	assert.Equal(expected.Vk.Lines, srs.Vk.Lines)
+
+	// truncated proving key
+	assert.NoError(srs.ReadPtau(bytes.NewReader(data), 10))
+	assert.Equal(10, len(srs.Pk.G1))
+}
+
+// TestPtauVectors reads a .ptau file of power 2, written by
+// internal/generator/kzg/test_vectors/ptau.js with τ=5, α=3 and β=2
+func TestPtauVectors(t *testing.T) {
+	assert := require.New(t)
+
+	data, err := os.ReadFile("../../../internal/generator/kzg/test_vectors/bls12-381.ptau")
+	assert.NoError(err)
+
+	const power = 2
+	tau, alpha, beta := big.NewInt(5), big.NewInt(3), big.NewInt(2)
+	expected, err := NewPtau(power, tau, alpha, beta)
+	assert.NoError(err)
+	expected.CeremonyPower = 28
+
+	var ptau Ptau
+	assert.NoError(ptau.ReadPtau(bytes.NewReader(data)))
+	assert.Equal(expected.Power, ptau.Power)
+	assert.Equal(expected.CeremonyPower, ptau.CeremonyPower)
+	assert.Equal(expected.TauG1, ptau.TauG1)
+	assert.Equal(expected.TauG2, ptau.TauG2)
+	assert.Equal(expected.AlphaTauG1, ptau.AlphaTauG1)
+	assert.Equal(expected.BetaTauG1, ptau.BetaTauG1)
+	assert.True(expected.BetaG2.Equal(&ptau.BetaG2))
+
+	// the file is written back byte for byte
+	var buf bytes.Buffer
+	assert.NoError(expected.WritePtau(&buf))
+	assert.Equal(data, buf.Bytes())
+
+	var srs SRS
+	assert.NoError(srs.ReadPtau(bytes.NewReader(data)))
+	expectedSRS, err := NewSRS(2<<power-1, tau)
+	assert.NoError(err)
+	assert.Equal(len(expectedSRS.Pk.G1), len(srs.Pk.G1))
+	for i := range expectedSRS.Pk.G1 {
+		assert.True(expectedSRS.Pk.G1[i].Equal(&srs.Pk.G1[i]))
+	}
+	assert.True(expectedSRS.Vk.G2[1].Equal(&srs.Vk.G2[1]))
+}
+
+func TestPtauInvalid(t *testing.T) {
+	assert := require.New(t)
+
+	const power = 3
+	ptau, err := NewPtau(power, big.NewInt(42), big.NewInt(7), big.NewInt(11))
+	assert.NoError(err)
+	var buf bytes.Buffer
+	assert.NoError(ptau.WritePtau(&buf))
+	data := buf.Bytes()
+
+	var decoded Ptau
+	var srs SRS
+
+	// wrong magic
+	corrupted := append([]byte(nil), data...)
+	corrupted[0] = 'x'
+	assert.ErrorIs(decoded.ReadPtau(bytes.NewReader(corrupted)), ErrInvalidPtau)
+	assert.ErrorIs(srs.ReadPtau(bytes.NewReader(corrupted)), ErrInvalidPtau)
+
+	// the sizes of the sections don't match the power of the header
+	corrupted = append([]byte(nil), data...)
+	corrupted[12+12+4+fp.Bytes]++
+	assert.ErrorIs(decoded.ReadPtau(bytes.NewReader(corrupted)), ErrInvalidPtau)
+	assert.ErrorIs(srs.ReadPtau(bytes.NewReader(corrupted)), ErrInvalidPtau)
+
+	// point not on the curve
+	corrupted = append([]byte(nil), data...)
+	corrupted[offsetTauG1+3*ptauSizeG1] ^= 1
+	assert.Error(decoded.ReadPtau(bytes.NewReader(corrupted)))
+	assert.Error(srs.ReadPtau(bytes.NewReader(corrupted)))
+
+	// truncated file
+	assert.Error(decoded.ReadPtau(bytes.NewReader(data[:len(data)-1])))
+
+	// inconsistent sections, each of them being a valid list of points
+	var other bls12381.G2Affine
+	other.ScalarMultiplicationBase(big.NewInt(43))
+	tamper := map[string]func(p *Ptau){
+		"[τⁱ]₂ doesn't match [τⁱ]₁":  func(p *Ptau) { p.TauG2[1] = other },
+		"[τ²]₂ doesn't match [τ²]₁":  func(p *Ptau) { p.TauG2[2] = other },
+		"[ατⁱ]₁ are not powers of τ": func(p *Ptau) { p.AlphaTauG1[2] = p.AlphaTauG1[1] },
+		"[βτⁱ]₁ are not powers of τ": func(p *Ptau) { p.BetaTauG1[3] = p.BetaTauG1[1] },
+		"[β]₂ doesn't match [β]₁":    func(p *Ptau) { p.BetaG2 = other },
+	}
+	for name, f := range tamper {
+		tampered := *ptau
+		tampered.TauG2 = append([]bls12381.G2Affine(nil), ptau.TauG2...)
+		tampered.AlphaTauG1 = append([]bls12381.G1Affine(nil), ptau.AlphaTauG1...)
+		tampered.BetaTauG1 = append([]bls12381.G1Affine(nil), ptau.BetaTauG1...)
+		f(&tampered)
+		buf.Reset()
+		assert.NoError(tampered.WritePtau(&buf), name)
+		assert.ErrorIs(decoded.ReadPtau(bytes.NewReader(buf.Bytes())), ErrSRSNotWellFormed, name)
+	}
+
+	// the sections of a Ptau must have the sizes of its power
+	tampered := *ptau
+	tampered.TauG1 = tampered.TauG1[1:]
+	assert.ErrorIs(tampered.WritePtau(&buf), ErrInvalidPtau)
+	tampered = *ptau
+	tampered.CeremonyPower = power - 1
+	assert.ErrorIs(tampered.WritePtau(&buf), ErrInvalidPtau)
+}
+
+// ptauEncodeTest encodes x in little-endian Montgomery form, without the
+// arithmetic of fp
+func ptauEncodeTest(x *big.Int) []byte {
+	var v big.Int
+	v.Lsh(x, 64*fp.Limbs).Mod(&v, fp.Modulus())
+	b := make([]byte, fp.Bytes)
+	v.FillBytes(b)
+	for i, j := 0, len(b)-1; i < j; i, j = i+1, j-1 {
+		b[i], b[j] = b[j], b[i]
+	}
+	return b
+}
@@ -0,0 +1,710 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls12381
+
+import (
+	"errors"
+	"io"
+	"math"
+	"runtime"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/internal/fptower"
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+// G1MultiExpTable holds precomputed multiples of fixed bases, to speed up
+// the multi-exponentiations on these bases (see G1Jac.MultiExpWithTable).
+//
+// For a window size c, the table stores [2ᶜʲ]Bᵢ for each base Bᵢ and each of the
+// ⌈fr.Bits/c⌉ windows j. The digits of all the windows are then processed in
+// a single pass of the bucket method, without doublings and with a single
+// reduction of the buckets, at the price of a table ⌈fr.Bits/c⌉ times larger
+// than the bases.
+type G1MultiExpTable struct {
+	c       uint64     // window size
+	nbBases int        // number of bases
+	points  []G1Affine // points[i*nbChunks+j] = [2ᶜʲ]bases[i]
+}
+
+// implementedCsG1 returns the window sizes for which the tables are supported
+func implementedCsG1() []uint64 {
+	return []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+}
+
+// NewG1MultiExpTable precomputes the table of the given bases, with window size c.
+// If c is 0, the window size minimizing the cost of the multi-exponentiations
+// on all the bases is used.
+//
+// This call returns an error if c is not a supported window size.
+func NewG1MultiExpTable(bases []G1Affine, c int) (*G1MultiExpTable, error) {
+	implementedCs := implementedCsG1()
+	if c == 0 {
+		min := math.MaxInt
+		for _, cc := range implementedCs {
+			cost := tableCost(cc, len(bases))
+			if cost < min {
+				min = cost
+				c = int(cc)
+			}
+		}
+	}
+	if !isImplementedC(uint64(c), implementedCs) {
+		return nil, errors.New("invalid window size")
+	}
+
+	t := &G1MultiExpTable{c: uint64(c), nbBases: len(bases)}
+	nbChunks := int(computeNbChunks(t.c))
+	t.points = make([]G1Affine, len(bases)*nbChunks)
+
+	// the multiples are computed in Jacobian coordinates by blocks of bases,
+	// and then batch converted to affine coordinates
+	const blockSize = 1 << 10
+	jac := make([]G1Jac, blockSize*nbChunks)
+	for b := 0; b < len(bases); b += blockSize {
+		m := len(bases) - b
+		if m > blockSize {
+			m = blockSize
+		}
+		parallel.Execute(m, func(start, end int) {
+			for i := start; i < end; i++ {
+				q := jac[i*nbChunks : (i+1)*nbChunks]
+				q[0].FromAffine(&bases[b+i])
+				for j := 1; j < nbChunks; j++ {
+					q[j].Set(&q[j-1])
+					for k := 0; k < c; k++ {
+						q[j].DoubleAssign()
+					}
+				}
+			}
+		})
+		res := t.points[b*nbChunks : (b+m)*nbChunks]
+		parallel.Execute(m*nbChunks, func(start, end int) {
+			batchJacobianToAffineG1(res[start:end], jac[start:end])
+		})
+	}
+
+	return t, nil
+}
+
+// NbBases returns the number of bases of the table.
+func (t *G1MultiExpTable) NbBases() int {
+	return t.nbBases
+}
+
+// WindowSize returns the window size c of the table.
+func (t *G1MultiExpTable) WindowSize() int {
+	return int(t.c)
+}
+
+// Bases returns a copy of the bases of the table.
+func (t *G1MultiExpTable) Bases() []G1Affine {
+	nbChunks := int(computeNbChunks(t.c))
+	res := make([]G1Affine, t.nbBases)
+	for i := range res {
+		res[i] = t.points[i*nbChunks]
+	}
+	return res
+}
+
+// MultiExpWithTable computes the multi-exponentiation ∑ᵢ[scalars[i]]Bᵢ, where the
+// Bᵢ are the first len(scalars) bases of the table.
+//
+// This call returns an error if len(scalars) > table.NbBases() or if provided config is invalid.
+func (p *G1Affine) MultiExpWithTable(table *G1MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G1Affine, error) {
+	var _p G1Jac
+	if _, err := _p.MultiExpWithTable(table, scalars, config); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpWithTable computes the multi-exponentiation ∑ᵢ[scalars[i]]Bᵢ, where the
+// Bᵢ are the first len(scalars) bases of the table.
+//
+// This call returns an error if len(scalars) > table.NbBases() or if provided config is invalid.
+func (p *G1Jac) MultiExpWithTable(table *G1MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G1Jac, error) {
+	n := len(scalars)
+	if n > table.nbBases {
+		return nil, errors.New("len(scalars) > number of bases of the table")
+	}
+
+	// with few scalars, the reduction of the 2ᶜ buckets of the table dominates:
+	// a regular multi-exponentiation on the bases is then cheaper.
+	if tableCost(table.c, n) > multiExpCost(n, implementedCsG1()) {
+		nbChunks := int(computeNbChunks(table.c))
+		bases := make([]G1Affine, n)
+		for i := range bases {
+			bases[i] = table.points[i*nbChunks]
+		}
+		return p.MultiExp(bases, scalars, config)
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	// the digits of the windows of the i-th scalar are in the same order
+	// as the multiples of the i-th base in the table
+	c := table.c
+	nbChunks := int(computeNbChunks(c))
+	digits, chunkStats := partitionScalars(scalars, c, config.NbTasks)
+	digits = transposeDigits(digits, n, nbChunks, config.NbTasks)
+	points := table.points[:n*nbChunks]
+
+	// the buckets must accommodate the digits of the last window
+	cBuckets := c
+	if lc := lastC(c); lc > cBuckets {
+		cBuckets = lc
+	}
+	var stat chunkStat
+	for _, s := range chunkStats {
+		if s.nbBucketFilled > stat.nbBucketFilled {
+			stat = s
+		}
+	}
+	processChunk := getChunkProcessorG1(cBuckets, stat)
+
+	// the points are split between the tasks, each of them reducing its own
+	// buckets: we want enough points per task to amortize this reduction.
+	nbTasks := config.NbTasks
+	if maxTasks := len(points) >> (cBuckets + 1); nbTasks > maxTasks {
+		nbTasks = maxTasks
+	}
+	if nbTasks < 1 {
+		nbTasks = 1
+	}
+	chRes := make(chan g1JacExtended, nbTasks)
+	for k := 0; k < nbTasks; k++ {
+		start, end := k*len(points)/nbTasks, (k+1)*len(points)/nbTasks
+		go processChunk(0, chRes, cBuckets, points[start:end], digits[start:end], nil)
+	}
+
+	var res g1JacExtended
+	res.setInfinity()
+	for k := 0; k < nbTasks; k++ {
+		r := <-chRes
+		res.add(&r)
+	}
+
+	return p.unsafeFromJacExtended(&res), nil
+}
+
+// WriteTo writes the table in raw (uncompressed) form to w.
+func (t *G1MultiExpTable) WriteTo(w io.Writer) (int64, error) {
+	enc := NewEncoder(w, RawEncoding())
+	toEncode := []interface{}{
+		t.c,
+		uint64(t.nbBases),
+		t.points,
+	}
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom reads a table written with WriteTo from r. The points are checked
+// to be in the subgroup, and to be the multiples [2ᶜʲ]Bᵢ of the bases Bᵢ.
+func (t *G1MultiExpTable) ReadFrom(r io.Reader) (int64, error) {
+	n, err := t.readFrom(r)
+	if err != nil {
+		return n, err
+	}
+	return n, t.checkMultiples()
+}
+
+// UnsafeReadFrom reads a table written with WriteTo from r, without subgroup checks
+// and without checking that the points are the multiples of the bases.
+// Use with caution, the table must come from a trusted source.
+func (t *G1MultiExpTable) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return t.readFrom(r, NoSubgroupChecks())
+}
+
+func (t *G1MultiExpTable) readFrom(r io.Reader, decOptions ...func(*Decoder)) (int64, error) {
+	dec := NewDecoder(r, decOptions...)
+	var nbBases uint64
+	toDecode := []interface{}{
+		&t.c,
+		&nbBases,
+		&t.points,
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	if !isImplementedC(t.c, implementedCsG1()) {
+		return dec.BytesRead(), errors.New("invalid window size")
+	}
+	if uint64(len(t.points)) != nbBases*computeNbChunks(t.c) {
+		return dec.BytesRead(), errors.New("invalid table size")
+	}
+	t.nbBases = int(nbBases)
+	return dec.BytesRead(), nil
+}
+
+// checkMultiples checks that the points of the table are the multiples
+// [2ᶜʲ]Bᵢ of its bases, that is points[i*nbChunks+j] = [2ᶜ]points[i*nbChunks+j-1]
+// for j ≥ 1. The checks are batched with the powers of a random ρ:
+//
+//	∑ₖ ρᵏ(points[k] - [2ᶜ]points[k-1]) = ∑ₖ (ρᵏ - 2ᶜ⋅ρᵏ⁺¹)points[k] = 0
+//
+// where k ranges over the points which are not bases, and ρᵏ is 0 otherwise.
+// The points must be in the subgroup.
+func (t *G1MultiExpTable) checkMultiples() error {
+	nbChunks := int(computeNbChunks(t.c))
+	if nbChunks < 2 || len(t.points) == 0 {
+		return nil
+	}
+
+	var rho, twoC fr.Element
+	if _, err := rho.SetRandom(); err != nil {
+		return err
+	}
+	twoC.SetUint64(1 << t.c)
+
+	// rhos[k] = ρᵏ if points[k] is not a base, 0 otherwise
+	rhos := make([]fr.Element, len(t.points)+1)
+	var acc fr.Element
+	acc.SetOne()
+	for k := range t.points {
+		acc.Mul(&acc, &rho)
+		if k%nbChunks != 0 {
+			rhos[k] = acc
+		}
+	}
+	scalars := make([]fr.Element, len(t.points))
+	parallel.Execute(len(scalars), func(start, end int) {
+		var tmp fr.Element
+		for k := start; k < end; k++ {
+			tmp.Mul(&twoC, &rhos[k+1])
+			scalars[k].Sub(&rhos[k], &tmp)
+		}
+	})
+
+	var res G1Jac
+	if _, err := res.MultiExp(t.points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if !res.Z.IsZero() {
+		return errors.New("the points of the table are not the multiples of its bases")
+	}
+	return nil
+}
+
+// batchJacobianToAffineG1 sets res[i] to points[i] in affine coordinates,
+// with a single inversion.
+func batchJacobianToAffineG1(res []G1Affine, points []G1Jac) {
+	// the partial products of the Z coordinates are stored in res[i].X
+	var acc fp.Element
+	acc.SetOne()
+	for i := range points {
+		if points[i].Z.IsZero() {
+			continue
+		}
+		res[i].X = acc
+		acc.Mul(&acc, &points[i].Z)
+	}
+	acc.Inverse(&acc)
+
+	for i := len(points) - 1; i >= 0; i-- {
+		if points[i].Z.IsZero() {
+			// (X=0, Y=0) is infinity point in affine
+			res[i] = G1Affine{}
+			continue
+		}
+		var a, b fp.Element
+		a.Mul(&res[i].X, &acc)
+		acc.Mul(&acc, &points[i].Z)
+		b.Square(&a)
+		res[i].X.Mul(&points[i].X, &b)
+		res[i].Y.Mul(&points[i].Y, &b).
+			Mul(&res[i].Y, &a)
+	}
+}
+
+// G2MultiExpTable holds precomputed multiples of fixed bases, to speed up
+// the multi-exponentiations on these bases (see G2Jac.MultiExpWithTable).
+//
+// For a window size c, the table stores [2ᶜʲ]Bᵢ for each base Bᵢ and each of the
+// ⌈fr.Bits/c⌉ windows j. The digits of all the windows are then processed in
+// a single pass of the bucket method, without doublings and with a single
+// reduction of the buckets, at the price of a table ⌈fr.Bits/c⌉ times larger
+// than the bases.
+type G2MultiExpTable struct {
+	c       uint64     // window size
+	nbBases int        // number of bases
+	points  []G2Affine // points[i*nbChunks+j] = [2ᶜʲ]bases[i]
+}
+
+// implementedCsG2 returns the window sizes for which the tables are supported
+func implementedCsG2() []uint64 {
+	return []uint64{4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+}
+
+// NewG2MultiExpTable precomputes the table of the given bases, with window size c.
+// If c is 0, the window size minimizing the cost of the multi-exponentiations
+// on all the bases is used.
+//
+// This call returns an error if c is not a supported window size.
+func NewG2MultiExpTable(bases []G2Affine, c int) (*G2MultiExpTable, error) {
+	implementedCs := implementedCsG2()
+	if c == 0 {
+		min := math.MaxInt
+		for _, cc := range implementedCs {
+			cost := tableCost(cc, len(bases))
+			if cost < min {
+				min = cost
+				c = int(cc)
+			}
+		}
+	}
+	if !isImplementedC(uint64(c), implementedCs) {
+		return nil, errors.New("invalid window size")
+	}
+
+	t := &G2MultiExpTable{c: uint64(c), nbBases: len(bases)}
+	nbChunks := int(computeNbChunks(t.c))
+	t.points = make([]G2Affine, len(bases)*nbChunks)
+
+	// the multiples are computed in Jacobian coordinates by blocks of bases,
+	// and then batch converted to affine coordinates
+	const blockSize = 1 << 10
+	jac := make([]G2Jac, blockSize*nbChunks)
+	for b := 0; b < len(bases); b += blockSize {
+		m := len(bases) - b
+		if m > blockSize {
+			m = blockSize
+		}
+		parallel.Execute(m, func(start, end int) {
+			for i := start; i < end; i++ {
+				q := jac[i*nbChunks : (i+1)*nbChunks]
+				q[0].FromAffine(&bases[b+i])
+				for j := 1; j < nbChunks; j++ {
+					q[j].Set(&q[j-1])
+					for k := 0; k < c; k++ {
+						q[j].DoubleAssign()
+					}
+				}
+			}
+		})
+		res := t.points[b*nbChunks : (b+m)*nbChunks]
+		parallel.Execute(m*nbChunks, func(start, end int) {
+			batchJacobianToAffineG2(res[start:end], jac[start:end])
+		})
+	}
+
+	return t, nil
+}
+
+// NbBases returns the number of bases of the table.
+func (t *G2MultiExpTable) NbBases() int {
+	return t.nbBases
+}
+
+// WindowSize returns the window size c of the table.
+func (t *G2MultiExpTable) WindowSize() int {
+	return int(t.c)
+}
+
+// Bases returns a copy of the bases of the table.
+func (t *G2MultiExpTable) Bases() []G2Affine {
+	nbChunks := int(computeNbChunks(t.c))
+	res := make([]G2Affine, t.nbBases)
+	for i := range res {
+		res[i] = t.points[i*nbChunks]
+	}
+	return res
+}
+
+// MultiExpWithTable computes the multi-exponentiation ∑ᵢ[scalars[i]]Bᵢ, where the
+// Bᵢ are the first len(scalars) bases of the table.
+//
+// This call returns an error if len(scalars) > table.NbBases() or if provided config is invalid.
+func (p *G2Affine) MultiExpWithTable(table *G2MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G2Affine, error) {
+	var _p G2Jac
+	if _, err := _p.MultiExpWithTable(table, scalars, config); err != nil {
+		return nil, err
+	}
+	p.FromJacobian(&_p)
+	return p, nil
+}
+
+// MultiExpWithTable computes the multi-exponentiation ∑ᵢ[scalars[i]]Bᵢ, where the
+// Bᵢ are the first len(scalars) bases of the table.
+//
+// This call returns an error if len(scalars) > table.NbBases() or if provided config is invalid.
+func (p *G2Jac) MultiExpWithTable(table *G2MultiExpTable, scalars []fr.Element, config ecc.MultiExpConfig) (*G2Jac, error) {
+	n := len(scalars)
+	if n > table.nbBases {
+		return nil, errors.New("len(scalars) > number of bases of the table")
+	}
+
+	// with few scalars, the reduction of the 2ᶜ buckets of the table dominates:
+	// a regular multi-exponentiation on the bases is then cheaper.
+	if tableCost(table.c, n) > multiExpCost(n, implementedCsG2()) {
+		nbChunks := int(computeNbChunks(table.c))
+		bases := make([]G2Affine, n)
+		for i := range bases {
+			bases[i] = table.points[i*nbChunks]
+		}
+		return p.MultiExp(bases, scalars, config)
+	}
+
+	// if nbTasks is not set, use all available CPUs
+	if config.NbTasks <= 0 {
+		config.NbTasks = runtime.NumCPU()
+	} else if config.NbTasks > 1024 {
+		return nil, errors.New("invalid config: config.NbTasks > 1024")
+	}
+
+	// the digits of the windows of the i-th scalar are in the same order
+	// as the multiples of the i-th base in the table
+	c := table.c
+	nbChunks := int(computeNbChunks(c))
+	digits, chunkStats := partitionScalars(scalars, c, config.NbTasks)
+	digits = transposeDigits(digits, n, nbChunks, config.NbTasks)
+	points := table.points[:n*nbChunks]
+
+	// the buckets must accommodate the digits of the last window
+	cBuckets := c
+	if lc := lastC(c); lc > cBuckets {
+		cBuckets = lc
+	}
+	var stat chunkStat
+	for _, s := range chunkStats {
+		if s.nbBucketFilled > stat.nbBucketFilled {
+			stat = s
+		}
+	}
+	processChunk := getChunkProcessorG2(cBuckets, stat)
+
+	// the points are split between the tasks, each of them reducing its own
+	// buckets: we want enough points per task to amortize this reduction.
+	nbTasks := config.NbTasks
+	if maxTasks := len(points) >> (cBuckets + 1); nbTasks > maxTasks {
+		nbTasks = maxTasks
+	}
+	if nbTasks < 1 {
+		nbTasks = 1
+	}
+	chRes := make(chan g2JacExtended, nbTasks)
+	for k := 0; k < nbTasks; k++ {
+		start, end := k*len(points)/nbTasks, (k+1)*len(points)/nbTasks
+		go processChunk(0, chRes, cBuckets, points[start:end], digits[start:end], nil)
+	}
+
+	var res g2JacExtended
+	res.setInfinity()
+	for k := 0; k < nbTasks; k++ {
+		r := <-chRes
+		res.add(&r)
+	}
+
+	return p.unsafeFromJacExtended(&res), nil
+}
+
+// WriteTo writes the table in raw (uncompressed) form to w.
+func (t *G2MultiExpTable) WriteTo(w io.Writer) (int64, error) {
+	enc := NewEncoder(w, RawEncoding())
+	toEncode := []interface{}{
+		t.c,
+		uint64(t.nbBases),
+		t.points,
+	}
+	for _, v := range toEncode {
+		if err := enc.Encode(v); err != nil {
+			return enc.BytesWritten(), err
+		}
+	}
+	return enc.BytesWritten(), nil
+}
+
+// ReadFrom reads a table written with WriteTo from r. The points are checked
+// to be in the subgroup, and to be the multiples [2ᶜʲ]Bᵢ of the bases Bᵢ.
+func (t *G2MultiExpTable) ReadFrom(r io.Reader) (int64, error) {
+	n, err := t.readFrom(r)
+	if err != nil {
+		return n, err
+	}
+	return n, t.checkMultiples()
+}
+
+// UnsafeReadFrom reads a table written with WriteTo from r, without subgroup checks
+// and without checking that the points are the multiples of the bases.
+// Use with caution, the table must come from a trusted source.
+func (t *G2MultiExpTable) UnsafeReadFrom(r io.Reader) (int64, error) {
+	return t.readFrom(r, NoSubgroupChecks())
+}
+
+func (t *G2MultiExpTable) readFrom(r io.Reader, decOptions ...func(*Decoder)) (int64, error) {
+	dec := NewDecoder(r, decOptions...)
+	var nbBases uint64
+	toDecode := []interface{}{
+		&t.c,
+		&nbBases,
+		&t.points,
+	}
+	for _, v := range toDecode {
+		if err := dec.Decode(v); err != nil {
+			return dec.BytesRead(), err
+		}
+	}
+	if !isImplementedC(t.c, implementedCsG2()) {
+		return dec.BytesRead(), errors.New("invalid window size")
+	}
+	if uint64(len(t.points)) != nbBases*computeNbChunks(t.c) {
+		return dec.BytesRead(), errors.New("invalid table size")
+	}
+	t.nbBases = int(nbBases)
+	return dec.BytesRead(), nil
+}
+
+// checkMultiples checks that the points of the table are the multiples
+// [2ᶜʲ]Bᵢ of its bases, that is points[i*nbChunks+j] = [2ᶜ]points[i*nbChunks+j-1]
+// for j ≥ 1. The checks are batched with the powers of a random ρ:
+//
+//	∑ₖ ρᵏ(points[k] - [2ᶜ]points[k-1]) = ∑ₖ (ρᵏ - 2ᶜ⋅ρᵏ⁺¹)points[k] = 0
+//
+// where k ranges over the points which are not bases, and ρᵏ is 0 otherwise.
+// The points must be in the subgroup.
+func (t *G2MultiExpTable) checkMultiples() error {
+	nbChunks := int(computeNbChunks(t.c))
+	if nbChunks < 2 || len(t.points) == 0 {
+		return nil
+	}
+
+	var rho, twoC fr.Element
+	if _, err := rho.SetRandom(); err != nil {
+		return err
+	}
+	twoC.SetUint64(1 << t.c)
+
+	// rhos[k] = ρᵏ if points[k] is not a base, 0 otherwise
+	rhos := make([]fr.Element, len(t.points)+1)
+	var acc fr.Element
+	acc.SetOne()
+	for k := range t.points {
+		acc.Mul(&acc, &rho)
+		if k%nbChunks != 0 {
+			rhos[k] = acc
+		}
+	}
+	scalars := make([]fr.Element, len(t.points))
+	parallel.Execute(len(scalars), func(start, end int) {
+		var tmp fr.Element
+		for k := start; k < end; k++ {
+			tmp.Mul(&twoC, &rhos[k+1])
+			scalars[k].Sub(&rhos[k], &tmp)
+		}
+	})
+
+	var res G2Jac
+	if _, err := res.MultiExp(t.points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return err
+	}
+	if !res.Z.IsZero() {
+		return errors.New("the points of the table are not the multiples of its bases")
+	}
+	return nil
+}
+
+// batchJacobianToAffineG2 sets res[i] to points[i] in affine coordinates,
+// with a single inversion.
+func batchJacobianToAffineG2(res []G2Affine, points []G2Jac) {
+	// the partial products of the Z coordinates are stored in res[i].X
+	var acc fptower.E2
+	acc.SetOne()
+	for i := range points {
+		if points[i].Z.IsZero() {
+			continue
+		}
+		res[i].X = acc
+		acc.Mul(&acc, &points[i].Z)
+	}
+	acc.Inverse(&acc)
+
+	for i := len(points) - 1; i >= 0; i-- {
+		if points[i].Z.IsZero() {
+			// (X=0, Y=0) is infinity point in affine
+			res[i] = G2Affine{}
+			continue
+		}
+		var a, b fptower.E2
+		a.Mul(&res[i].X, &acc)
+		acc.Mul(&acc, &points[i].Z)
+		b.Square(&a)
+		res[i].X.Mul(&points[i].X, &b)
+		res[i].Y.Mul(&points[i].Y, &b).
+			Mul(&res[i].Y, &a)
+	}
+}
+
+// transposeDigits reorders the digits computed by partitionScalars for n
+// scalars, from digits[j*n+i] (window major) to res[i*nbChunks+j] (scalar
+// major), which is the order of the points in the multi-exponentiation tables.
+func transposeDigits(digits []uint16, n, nbChunks, nbTasks int) []uint16 {
+	res := make([]uint16, len(digits))
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			for j := 0; j < nbChunks; j++ {
+				res[i*nbChunks+j] = digits[j*n+i]
+			}
+		}
+	}, nbTasks)
+	return res
+}
+
+// isImplementedC returns true if c belongs to implementedCs
+func isImplementedC(c uint64, implementedCs []uint64) bool {
+	for _, cc := range implementedCs {
+		if cc == c {
+			return true
+		}
+	}
+	return false
+}
+
+// tableCost approximates the cost (in group operations) of a multi-exponentiation
+// of size n with a table of window size c: an addition per digit, and the
+// reduction of the buckets (2 additions per bucket).
+func tableCost(c uint64, n int) int {
+	return int(computeNbChunks(c))*n + (1 << (c + 1))
+}
+
+// multiExpCost approximates the cost (in group operations) of a regular
+// multi-exponentiation of size n, with the best window size: for each window,
+// an addition per digit, the reduction of the buckets and c doublings.
+func multiExpCost(n int, implementedCs []uint64) int {
+	min := math.MaxInt
+	for _, c := range implementedCs {
+		cost := int(computeNbChunks(c)) * (n + (1 << (c + 1)) + int(c))
+		if cost < min {
+			min = cost
+		}
+	}
+	return min
+}
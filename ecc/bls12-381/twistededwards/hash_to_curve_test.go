@@ -0,0 +1,149 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls12-381/fr"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestMapToCurve(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[Montgomery] Elligator 2 should output a point on K·t² = s³ + J·s² + s", prop.ForAll(
+		func(a big.Int) bool {
+			var u fr.Element
+			u.SetBigInt(&a)
+			s, tt := mapToMontgomery(&u)
+
+			// K·t² = s³ + J·s² + s ⇔ (t/K)² = (s/K)³ + (J/K)·(s/K)² + (s/K)/K²
+			var x, y, lhs, rhs fr.Element
+			x.Div(&s, &h2cK)
+			y.Div(&tt, &h2cK)
+			lhs.Square(&y)
+			rhs.Add(&x, &h2cJOverK).
+				Mul(&rhs, &x).
+				Add(&rhs, &h2cInvKSquare).
+				Mul(&rhs, &x)
+			return lhs.Equal(&rhs)
+		},
+		GenBigInt(),
+	))
+
+	properties.Property("[Montgomery] Elligator 2 should be invariant under u ↦ -u", prop.ForAll(
+		func(a big.Int) bool {
+			var u, v fr.Element
+			u.SetBigInt(&a)
+			v.Neg(&u)
+			s0, t0 := mapToMontgomery(&u)
+			s1, t1 := mapToMontgomery(&v)
+			return s0.Equal(&s1) && t0.Equal(&t1)
+		},
+		GenBigInt(),
+	))
+
+	properties.Property("[Edwards] MapToCurve should output a point on the curve", prop.ForAll(
+		func(a big.Int) bool {
+			var u fr.Element
+			u.SetBigInt(&a)
+			p := MapToCurve(u)
+			return p.IsOnCurve()
+		},
+		GenBigInt(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// u = 0 is sent to x1 = -J/K
+	var zero fr.Element
+	if p := MapToCurve(zero); !p.IsOnCurve() {
+		t.Fatal("MapToCurve(0) should be on the curve")
+	}
+}
+
+func TestHashToCurve(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	dst := []byte("QUUX-V01-CS02-with-bls12-381-twistededwards_XMD:SHA-256_ELL2_RO_")
+
+	inSubgroup := func(p *PointAffine) bool {
+		params := GetEdwardsCurve()
+		var q PointAffine
+		q.ScalarMultiplication(p, &params.Order)
+		return p.IsOnCurve() && q.IsZero()
+	}
+
+	properties.Property("HashToCurve should output a point in the prime order subgroup", prop.ForAll(
+		func(a big.Int) bool {
+			p, err := HashToCurve(a.Bytes(), dst)
+			return err == nil && inSubgroup(&p)
+		},
+		GenBigInt(),
+	))
+
+	properties.Property("EncodeToCurve should output a point in the prime order subgroup", prop.ForAll(
+		func(a big.Int) bool {
+			p, err := EncodeToCurve(a.Bytes(), dst)
+			return err == nil && inSubgroup(&p)
+		},
+		GenBigInt(),
+	))
+
+	properties.Property("HashToCurve should depend on the message and the domain separation tag", prop.ForAll(
+		func(a big.Int) bool {
+			msg := a.Bytes()
+			p0, err0 := HashToCurve(msg, dst)
+			p1, err1 := HashToCurve(msg, dst)
+			p2, err2 := HashToCurve(append(msg, 0), dst)
+			p3, err3 := HashToCurve(msg, append(dst, 0))
+			return err0 == nil && err1 == nil && err2 == nil && err3 == nil &&
+				p0.Equal(&p1) && !p0.Equal(&p2) && !p0.Equal(&p3)
+		},
+		GenBigInt(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func BenchmarkHashToCurve(b *testing.B) {
+	dst := []byte("QUUX-V01-CS02-with-bls12-381-twistededwards_XMD:SHA-256_ELL2_RO_")
+	msg := []byte("abc")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = HashToCurve(msg, dst)
+	}
+}
@@ -17,6 +17,7 @@
 package ecdsa
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
 	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
@@ -62,6 +63,32 @@ func TestECDSA(t *testing.T) {
 		},
 	))
 
+	properties.Property("[BLS24-315] test the deterministic signing and verification", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing ECDSA")
+			hFunc := sha256.New()
+			sig1, err := privKey.SignDeterministic(msg, hFunc, sha256.New)
+			if err != nil {
+				return false
+			}
+			sig2, err := privKey.SignDeterministic(msg, hFunc, sha256.New)
+			if err != nil {
+				return false
+			}
+			sig3, err := privKey.SignDeterministic([]byte("testing ECDSA."), hFunc, sha256.New)
+			if err != nil {
+				return false
+			}
+			flag, _ := publicKey.Verify(sig1, msg, hFunc)
+
+			return flag && bytes.Equal(sig1, sig2) && !bytes.Equal(sig1, sig3)
+		},
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 
@@ -153,6 +180,17 @@ func BenchmarkSignECDSA(b *testing.B) {
 	}
 }
 
+func BenchmarkSignDeterministicECDSA(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+
+	msg := []byte("benchmarking ECDSA sign()")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.SignDeterministic(msg, nil, sha256.New)
+	}
+}
+
 func BenchmarkVerifyECDSA(b *testing.B) {
 
 	privKey, _ := GenerateKey(rand.Reader)
@@ -0,0 +1,44 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package poseidon2 implements the Poseidon2 permutation
+//
+// Poseidon2 permutation is a cryptographic permutation for algebraic hashes.
+// See the [original paper] by Grassi, Khovratovich and Schofnegger for the full details.
+//
+// This implementation is based on the [reference implementation] from
+// HorizenLabs: the round constants are derived with the same Grain LFSR, and the
+// linear layers use the same matrices, so that the permutation matches the
+// reference test vectors.
+//
+// The package provides:
+//   - the permutation itself, with configurable width, number of full rounds and
+//     number of partial rounds (see [NewPermutation] and [NewParameters]);
+//   - a compression function for Merkle trees (see [Permutation.Compress]);
+//   - a sponge construction implementing [hash.Hash] (see [NewPoseidon2]).
+//
+// Only widths 2 and 3 are supported, the internal matrices for larger widths
+// being field dependent.
+//
+// # Hash input format
+//
+// As for MiMC, the sponge absorbs field elements. The input byte slice is
+// interpreted as a sequence of big endian encoded field elements, each of them
+// strictly less than the field modulus.
+//
+// [original paper]: https://eprint.iacr.org/2023/323.pdf
+// [reference implementation]: https://github.com/HorizenLabs/poseidon2
+package poseidon2
@@ -0,0 +1,248 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package rpo
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	fr "github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"golang.org/x/crypto/sha3"
+)
+
+var (
+	ErrInvalidSizebuffer = errors.New("the size of the input should match the size of the hash buffer")
+)
+
+const (
+	// Width is the number of field elements of the state of the permutation
+	Width = 3
+
+	// Capacity is the number of elements of the state which are not absorbed
+	// nor squeezed by the sponge. They are the first elements of the state.
+	Capacity = 1
+
+	// Rate is the number of elements of the state absorbed or squeezed at once
+	Rate = Width - Capacity
+
+	// DigestSize is the number of field elements of a digest
+	DigestSize = 1
+
+	// sBoxDegree is the degree α of the s-box x ↦ xᵅ, the smallest integer
+	// such that gcd(α, p-1) = 1 so that the s-box is a permutation of fr.
+	sBoxDegree = 7
+
+	// DefaultNbRounds is the number of rounds ensuring 128 bits of security,
+	// following the Rescue-Prime bounds with the security margin of RPO.
+	DefaultNbRounds = 12
+
+	// securityLevel is the security level in the seed of the round constants
+	securityLevel = 128
+)
+
+var (
+	// sBoxInvExponent is α⁻¹ (mod p-1), so that x ↦ x^(α⁻¹) is the inverse s-box
+	sBoxInvExponent big.Int
+
+	// mds is the MDS matrix of the linear layer
+	mds [Width][Width]fr.Element
+
+	initOnce sync.Once
+)
+
+func initConstants() {
+	sBoxInvExponent.SetString("6572587309357291797501756802614527140548347542932603266666277811333979925943", 10)
+
+	// Cauchy matrix 1/(xᵢ+yⱼ), with xᵢ = i and yⱼ = j+1, which is MDS since
+	// the xᵢ (resp. yⱼ) are distinct and the xᵢ+yⱼ non zero.
+	for i := 0; i < Width; i++ {
+		for j := 0; j < Width; j++ {
+			mds[i][j].SetUint64(uint64(i + j + 1))
+			mds[i][j].Inverse(&mds[i][j])
+		}
+	}
+}
+
+// Parameters describe parameters of the RPO permutation
+type Parameters struct {
+
+	// number of rounds, each round applying the s-box and its inverse
+	NbRounds int
+
+	// round keys: 2*NbRounds vectors of Width elements, added after each
+	// application of the MDS matrix
+	RoundKeys [][Width]fr.Element
+}
+
+// NewParameters returns a new set of parameters for the RPO permutation. The
+// round keys are the integers encoded in the output of SHAKE256 on the seed
+// "RPO(p,m,c,λ)", following the Rescue-Prime specification.
+func NewParameters(nbRounds int) *Parameters {
+	if nbRounds < 1 {
+		panic("rpo: the number of rounds should be positive")
+	}
+	p := Parameters{NbRounds: nbRounds}
+	p.initRC()
+	return &p
+}
+
+// String returns a description of the parameters, e.g. "RPO-bls24-315[t=3,c=1,N=12,α=7]"
+func (p *Parameters) String() string {
+	return fmt.Sprintf("RPO-bls24-315[t=%d,c=%d,N=%d,α=%d]", Width, Capacity, p.NbRounds, sBoxDegree)
+}
+
+// initRC derives the round keys: each one is read from bytesPerInt bytes of
+// the SHAKE256 stream, as a little endian integer reduced modulo p.
+func (p *Parameters) initRC() {
+	modulus := fr.Modulus()
+	seed := fmt.Sprintf("RPO(%s,%d,%d,%d)", modulus.String(), Width, Capacity, securityLevel)
+	shake := sha3.NewShake256()
+	_, _ = shake.Write([]byte(seed))
+
+	bytesPerInt := (modulus.BitLen()+7)/8 + 1
+	buf := make([]byte, bytesPerInt)
+	var v big.Int
+
+	p.RoundKeys = make([][Width]fr.Element, 2*p.NbRounds)
+	for i := range p.RoundKeys {
+		for j := 0; j < Width; j++ {
+			_, _ = shake.Read(buf)
+			// little endian
+			for k, l := 0, len(buf)-1; k < l; k, l = k+1, l-1 {
+				buf[k], buf[l] = buf[l], buf[k]
+			}
+			v.SetBytes(buf)
+			p.RoundKeys[i][j].SetBigInt(&v)
+		}
+	}
+}
+
+// Permutation provides the RPO permutation
+type Permutation struct {
+	// parameters describing the instance
+	params *Parameters
+}
+
+// NewPermutation returns a new RPO permutation instance with nbRounds rounds.
+func NewPermutation(nbRounds int) *Permutation {
+	return &Permutation{params: NewParameters(nbRounds)}
+}
+
+// NewPermutationWithParameters returns a new RPO permutation instance
+// using the provided parameters.
+func NewPermutationWithParameters(params *Parameters) *Permutation {
+	return &Permutation{params: params}
+}
+
+// Parameters returns the parameters of the permutation.
+func (h *Permutation) Parameters() *Parameters {
+	return h.params
+}
+
+// sBox sets x to xᵅ
+func sBox(x *fr.Element) {
+	var x2, x4 fr.Element
+	x2.Square(x)
+	x4.Square(&x2)
+	x2.Mul(&x2, x)
+	x.Mul(&x2, &x4)
+}
+
+// sBoxInv sets x to x^(α⁻¹)
+func sBoxInv(x *fr.Element) {
+	x.Exp(*x, &sBoxInvExponent)
+}
+
+// matMulInPlace multiplies the state by the MDS matrix
+func matMulInPlace(input []fr.Element) {
+	var res [Width]fr.Element
+	var tmp fr.Element
+	for i := 0; i < Width; i++ {
+		for j := 0; j < Width; j++ {
+			tmp.Mul(&mds[i][j], &input[j])
+			res[i].Add(&res[i], &tmp)
+		}
+	}
+	copy(input, res[:])
+}
+
+// addRoundKeyInPlace adds the round-th key to the state
+func (h *Permutation) addRoundKeyInPlace(round int, input []fr.Element) {
+	for i := 0; i < Width; i++ {
+		input[i].Add(&input[i], &h.params.RoundKeys[round][i])
+	}
+}
+
+// Permutation applies the permutation on input, and stores the result in input.
+func (h *Permutation) Permutation(input []fr.Element) error {
+	if len(input) != Width {
+		return ErrInvalidSizebuffer
+	}
+	initOnce.Do(initConstants)
+
+	for i := 0; i < h.params.NbRounds; i++ {
+		// first half: MDS, round key, s-box
+		matMulInPlace(input)
+		h.addRoundKeyInPlace(2*i, input)
+		for j := range input {
+			sBox(&input[j])
+		}
+
+		// second half: MDS, round key, inverse s-box
+		matMulInPlace(input)
+		h.addRoundKeyInPlace(2*i+1, input)
+		for j := range input {
+			sBoxInv(&input[j])
+		}
+	}
+
+	return nil
+}
+
+// Compress is used in BinaryMerkleTree. The inputs are the encodings of two
+// digests left and right, each of them made of DigestSize big endian field
+// elements. The output is the digest of the state P(0, left, right), where P
+// is the permutation, the capacity being zero.
+func (h *Permutation) Compress(left []byte, right []byte) ([]byte, error) {
+	if len(left) != DigestSize*fr.Bytes || len(right) != DigestSize*fr.Bytes {
+		return nil, ErrInvalidSizebuffer
+	}
+	var state [Width]fr.Element
+	in := append(append(make([]byte, 0, 2*DigestSize*fr.Bytes), left...), right...)
+	for i := 0; i < 2*DigestSize; i++ {
+		if err := state[Capacity+i].SetBytesCanonical(in[i*fr.Bytes : (i+1)*fr.Bytes]); err != nil {
+			return nil, err
+		}
+	}
+	if err := h.Permutation(state[:]); err != nil {
+		return nil, err
+	}
+	return encodeDigest(state[:]), nil
+}
+
+// encodeDigest returns the concatenation of the big endian encodings of the
+// DigestSize first elements of the rate of the state
+func encodeDigest(state []fr.Element) []byte {
+	res := make([]byte, 0, DigestSize*fr.Bytes)
+	for i := 0; i < DigestSize; i++ {
+		b := state[Capacity+i].Bytes()
+		res = append(res, b[:]...)
+	}
+	return res
+}
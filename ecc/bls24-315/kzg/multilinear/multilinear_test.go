@@ -0,0 +1,266 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package multilinear
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-315"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/fr/polynomial"
+	"github.com/consensys/gnark-crypto/utils/testutils"
+	"github.com/stretchr/testify/require"
+)
+
+// Test SRS re-used across tests of the multilinear KZG scheme
+var testSrs *SRS
+var testTrapdoors []fr.Element
+
+func init() {
+	const nbVars = 8
+	trapdoors := make([]*big.Int, nbVars)
+	testTrapdoors = make([]fr.Element, nbVars)
+	for i := range trapdoors {
+		trapdoors[i] = big.NewInt(int64(42 + 17*i)) // randomise ?
+		testTrapdoors[i].SetBigInt(trapdoors[i])
+	}
+	testSrs, _ = NewSRS(trapdoors)
+}
+
+func randomMultiLin(nbVars int) polynomial.MultiLin {
+	p := make(polynomial.MultiLin, 1<<nbVars)
+	for i := range p {
+		p[i].SetRandom()
+	}
+	return p
+}
+
+func randomPoint(nbVars int) []fr.Element {
+	point := make([]fr.Element, nbVars)
+	for i := range point {
+		point[i].SetRandom()
+	}
+	return point
+}
+
+func TestCommit(t *testing.T) {
+	assert := require.New(t)
+
+	// [f(τₙ₋ₖ₊₁, …, τₙ)]₁
+	_, _, g1, _ := bls24315.Generators()
+	for _, nbVars := range []int{0, 1, 5, 8} {
+		p := randomMultiLin(nbVars)
+		digest, err := Commit(p, testSrs.Pk)
+		assert.NoError(err)
+
+		eval := p.Evaluate(testTrapdoors[len(testTrapdoors)-nbVars:], nil)
+		var expected bls24315.G1Affine
+		expected.ScalarMultiplication(&g1, eval.BigInt(new(big.Int)))
+		assert.True(expected.Equal(&digest), "nbVars=%d", nbVars)
+	}
+
+	// invalid sizes
+	_, err := Commit(make(polynomial.MultiLin, 3), testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = Commit(randomMultiLin(9), testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+	_, err = Commit(polynomial.MultiLin{}, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestVerifySinglePoint(t *testing.T) {
+	assert := require.New(t)
+
+	for _, nbVars := range []int{1, 5, 8} {
+		p := randomMultiLin(nbVars)
+		digest, err := Commit(p, testSrs.Pk)
+		assert.NoError(err)
+
+		point := randomPoint(nbVars)
+		proof, err := Open(p, point, testSrs.Pk)
+		assert.NoError(err)
+		assert.Len(proof.Quotients, nbVars)
+
+		// verify the claimed value
+		expected := p.Evaluate(point, nil)
+		assert.True(expected.Equal(&proof.ClaimedValue), "inconsistent claimed value")
+
+		// verify correct proof
+		assert.NoError(Verify(&digest, &proof, point, testSrs.Vk))
+
+		// serialization
+		t.Run("proof round-trip", testutils.SerializationRoundTrip(&proof))
+
+		// verify wrong proofs
+		wrong := proof
+		wrong.ClaimedValue.Double(&wrong.ClaimedValue)
+		assert.ErrorIs(Verify(&digest, &wrong, point, testSrs.Vk), ErrVerifyOpeningProof)
+
+		wrongPoint := append([]fr.Element{}, point...)
+		wrongPoint[0].SetRandom()
+		assert.ErrorIs(Verify(&digest, &proof, wrongPoint, testSrs.Vk), ErrVerifyOpeningProof)
+
+		wrong = proof
+		wrong.Quotients = make([]bls24315.G1Affine, nbVars)
+		assert.Error(Verify(&digest, &wrong, point, testSrs.Vk))
+
+		assert.ErrorIs(Verify(&digest, &proof, point[1:], testSrs.Vk), ErrInvalidNbVariables)
+	}
+
+	// points on the hypercube open to the table values
+	p := randomMultiLin(3)
+	digest, err := Commit(p, testSrs.Pk)
+	assert.NoError(err)
+	point := []fr.Element{fr.One(), {}, fr.One()} // b = 101
+	proof, err := Open(p, point, testSrs.Pk)
+	assert.NoError(err)
+	assert.True(proof.ClaimedValue.Equal(&p[5]))
+	assert.NoError(Verify(&digest, &proof, point, testSrs.Vk))
+
+	_, err = Open(p, point[1:], testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbVariables)
+}
+
+func TestBatchVerifySinglePoint(t *testing.T) {
+	assert := require.New(t)
+
+	const nbVars = 6
+	f := make([]polynomial.MultiLin, 10)
+	digests := make([]Digest, len(f))
+	for i := range f {
+		f[i] = randomMultiLin(nbVars)
+		var err error
+		digests[i], err = Commit(f[i], testSrs.Pk)
+		assert.NoError(err)
+	}
+
+	// pick a hash function
+	hf := sha256.New()
+
+	point := randomPoint(nbVars)
+	proof, err := BatchOpenSinglePoint(f, digests, point, hf, testSrs.Pk)
+	assert.NoError(err)
+
+	// verify the claimed values
+	for i := range f {
+		expected := f[i].Evaluate(point, nil)
+		assert.True(expected.Equal(&proof.ClaimedValues[i]), "inconsistent claimed values")
+	}
+
+	// verify correct proof
+	assert.NoError(BatchVerifySinglePoint(digests, &proof, point, hf, testSrs.Vk))
+
+	// verify correct proof with extended transcript
+	var salt fr.Element
+	salt.SetRandom()
+	proofExtendedTranscript, err := BatchOpenSinglePoint(f, digests, point, hf, testSrs.Pk, salt.Marshal())
+	assert.NoError(err)
+	assert.NoError(BatchVerifySinglePoint(digests, &proofExtendedTranscript, point, hf, testSrs.Vk, salt.Marshal()))
+
+	// serialization
+	t.Run("batch proof round-trip", testutils.SerializationRoundTrip(&proof))
+
+	// verify wrong proof
+	proof.ClaimedValues[0].Double(&proof.ClaimedValues[0])
+	assert.ErrorIs(BatchVerifySinglePoint(digests, &proof, point, hf, testSrs.Vk), ErrVerifyOpeningProof)
+
+	// invalid inputs
+	_, err = BatchOpenSinglePoint(f, digests[1:], point, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidNbDigests)
+	_, err = BatchOpenSinglePoint(append(f, randomMultiLin(nbVars-1)), append(digests, Digest{}), point, hf, testSrs.Pk)
+	assert.ErrorIs(err, ErrInvalidPolynomialSize)
+}
+
+func TestBatchVerifyMultiPoints(t *testing.T) {
+	assert := require.New(t)
+
+	// polynomials with different numbers of variables, at different points
+	nbVars := []int{8, 3, 5, 5, 1}
+	digests := make([]Digest, len(nbVars))
+	proofs := make([]OpeningProof, len(nbVars))
+	points := make([][]fr.Element, len(nbVars))
+	for i := range nbVars {
+		p := randomMultiLin(nbVars[i])
+		var err error
+		digests[i], err = Commit(p, testSrs.Pk)
+		assert.NoError(err)
+		points[i] = randomPoint(nbVars[i])
+		proofs[i], err = Open(p, points[i], testSrs.Pk)
+		assert.NoError(err)
+	}
+
+	assert.NoError(BatchVerifyMultiPoints(digests, proofs, points, testSrs.Vk))
+
+	// wrong proofs
+	proofs[2].ClaimedValue.Double(&proofs[2].ClaimedValue)
+	assert.ErrorIs(BatchVerifyMultiPoints(digests, proofs, points, testSrs.Vk), ErrVerifyOpeningProof)
+	proofs[2].ClaimedValue.Halve()
+	assert.NoError(BatchVerifyMultiPoints(digests, proofs, points, testSrs.Vk))
+
+	points[4][0].SetRandom()
+	assert.ErrorIs(BatchVerifyMultiPoints(digests, proofs, points, testSrs.Vk), ErrVerifyOpeningProof)
+
+	// invalid inputs
+	assert.ErrorIs(BatchVerifyMultiPoints(digests[1:], proofs, points, testSrs.Vk), ErrInvalidNbDigests)
+	points[1] = points[1][1:]
+	assert.ErrorIs(BatchVerifyMultiPoints(digests, proofs, points, testSrs.Vk), ErrInvalidNbVariables)
+}
+
+func TestSerializationSRS(t *testing.T) {
+	t.Run("proving key round-trip", testutils.SerializationRoundTrip(&testSrs.Pk))
+	t.Run("verifying key round-trip", testutils.SerializationRoundTrip(&testSrs.Vk))
+	t.Run("whole SRS round-trip", testutils.SerializationRoundTrip(testSrs))
+}
+
+func BenchmarkCommit(b *testing.B) {
+	p := randomMultiLin(8)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Commit(p, testSrs.Pk)
+	}
+}
+
+func BenchmarkOpen(b *testing.B) {
+	p := randomMultiLin(8)
+	point := randomPoint(8)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = Open(p, point, testSrs.Pk)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	p := randomMultiLin(8)
+	point := randomPoint(8)
+	digest, err := Commit(p, testSrs.Pk)
+	if err != nil {
+		b.Fatal(err)
+	}
+	proof, err := Open(p, point, testSrs.Pk)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = Verify(&digest, &proof, point, testSrs.Vk)
+	}
+}
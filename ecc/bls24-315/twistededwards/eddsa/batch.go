@@ -0,0 +1,135 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package eddsa
+
+import (
+	"crypto/rand"
+	"errors"
+	"hash"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-315/twistededwards"
+)
+
+var errLenMismatch = errors.New("number of public keys, signatures and messages mismatch")
+
+// sizeBatchCoeff is the size in bytes of the random coefficients of the
+// linear combination, which gives 128 bits of security
+const sizeBatchCoeff = 16
+
+// BatchVerify verifies the eddsa signatures sigs[i] of messages[i] by
+// publicKeys[i] all at once, using a random linear combination:
+//
+//	cofactor*(∑ aᵢsᵢ)*Base = cofactor*(∑ aᵢRᵢ + ∑ (aᵢH(Rᵢ,Aᵢ,Mᵢ))*Aᵢ)
+//
+// where the aᵢ are 128 bits random coefficients. Both sides are computed with
+// a single multi-scalar multiplication.
+//
+// If the batch is valid, it returns (true, -1, nil). Otherwise the signatures
+// are verified one by one and it returns false and the index of the first
+// invalid signature; in that case the error is not nil if the signature could
+// not be decoded.
+func BatchVerify(publicKeys []PublicKey, sigs, messages [][]byte, hFunc hash.Hash) (bool, int, error) {
+
+	// hFunc cannot be nil.
+	// We need a hash function for the Fiat-Shamir.
+	if hFunc == nil {
+		return false, -1, errHashNeeded
+	}
+	n := len(publicKeys)
+	if len(sigs) != n || len(messages) != n {
+		return false, -1, errLenMismatch
+	}
+	if n == 0 {
+		return true, -1, nil
+	}
+
+	curveParams := twistededwards.GetEdwardsCurve()
+
+	// points = [R₀, ..., Rₙ₋₁, A₀, ..., Aₙ₋₁, Base]
+	points := make([]twistededwards.PointAffine, 2*n+1)
+	scalars := make([]big.Int, 2*n+1)
+	var sum, s, hram big.Int
+	var coeff [sizeBatchCoeff]byte
+	for i := 0; i < n; i++ {
+		var sig Signature
+		if _, err := sig.SetBytes(sigs[i]); err != nil {
+			return false, i, err
+		}
+		if !publicKeys[i].A.IsOnCurve() {
+			return false, i, errNotOnCurve
+		}
+		if err := computeHRAM(&hram, &sig.R, &publicKeys[i].A, messages[i], hFunc); err != nil {
+			return false, i, err
+		}
+		if _, err := rand.Read(coeff[:]); err != nil {
+			return false, -1, err
+		}
+
+		points[i].Set(&sig.R)
+		scalars[i].SetBytes(coeff[:])
+		points[n+i].Set(&publicKeys[i].A)
+		scalars[n+i].Mul(&scalars[i], &hram).Mod(&scalars[n+i], &curveParams.Order)
+		s.SetBytes(sig.S[:])
+		sum.Add(&sum, s.Mul(&s, &scalars[i]))
+	}
+	points[2*n].Set(&curveParams.Base)
+	scalars[2*n].Mod(&sum, &curveParams.Order).Sub(&curveParams.Order, &scalars[2*n])
+
+	// the multiplication by the cofactor kills the small torsion components,
+	// so that all the scalars can be reduced modulo the order
+	var bCofactor big.Int
+	curveParams.Cofactor.BigInt(&bCofactor)
+	var res twistededwards.PointExtended
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, -1, err
+	}
+	res.ScalarMultiplication(&res, &bCofactor)
+	if res.IsZero() {
+		return true, -1, nil
+	}
+
+	// find the first invalid signature
+	for i := 0; i < n; i++ {
+		ok, err := publicKeys[i].Verify(sigs[i], messages[i], hFunc)
+		if err != nil || !ok {
+			return false, i, err
+		}
+	}
+	// unreachable, except with negligible probability
+	return false, -1, nil
+}
+
+// computeHRAM sets res to H(R, A, M)
+func computeHRAM(res *big.Int, R, A *twistededwards.PointAffine, message []byte, hFunc hash.Hash) error {
+	hFunc.Reset()
+
+	RX := R.X.Bytes()
+	RY := R.Y.Bytes()
+	AX := A.X.Bytes()
+	AY := A.Y.Bytes()
+	toWrite := [][]byte{RX[:], RY[:], AX[:], AY[:], message}
+	for _, bytes := range toWrite {
+		if _, err := hFunc.Write(bytes); err != nil {
+			return err
+		}
+	}
+
+	res.SetBytes(hFunc.Sum(nil))
+	return nil
+}
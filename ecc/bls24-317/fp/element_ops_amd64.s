@@ -270,3 +270,1688 @@ TEXT ·Butterfly(SB), $24-16
 	MOVQ DI, 24(AX)
 	MOVQ R8, 32(AX)
 	RET
+
+// addVec(res, a, b *Element, n uint64) res[0...n] = a[0...n] + b[0...n]
+TEXT ·addVec(SB), NOSPLIT, $0-32
+	MOVQ res+0(FP), AX
+	MOVQ a+8(FP), DX
+	MOVQ b+16(FP), CX
+	MOVQ n+24(FP), BX
+
+l1:
+	TESTQ BX, BX
+	JEQ   l2
+	MOVQ  0(DX), SI
+	MOVQ  8(DX), DI
+	MOVQ  16(DX), R8
+	MOVQ  24(DX), R9
+	MOVQ  32(DX), R10
+	ADDQ  0(CX), SI
+	ADCQ  8(CX), DI
+	ADCQ  16(CX), R8
+	ADCQ  24(CX), R9
+	ADCQ  32(CX), R10
+
+	// reduce element(SI,DI,R8,R9,R10) using temp registers (R11,R12,R13,R14,R15)
+	REDUCE(SI,DI,R8,R9,R10,R11,R12,R13,R14,R15)
+
+	MOVQ SI, 0(AX)
+	MOVQ DI, 8(AX)
+	MOVQ R8, 16(AX)
+	MOVQ R9, 24(AX)
+	MOVQ R10, 32(AX)
+
+	// increment pointers to visit next element
+	ADDQ $40, DX
+	ADDQ $40, CX
+	ADDQ $40, AX
+	DECQ BX
+	JMP  l1
+
+l2:
+	RET
+
+// subVec(res, a, b *Element, n uint64) res[0...n] = a[0...n] - b[0...n]
+TEXT ·subVec(SB), NOSPLIT, $0-32
+	MOVQ res+0(FP), AX
+	MOVQ a+8(FP), DX
+	MOVQ b+16(FP), CX
+	MOVQ n+24(FP), BX
+
+l3:
+	TESTQ BX, BX
+	JEQ   l5
+	MOVQ  0(DX), SI
+	MOVQ  8(DX), DI
+	MOVQ  16(DX), R8
+	MOVQ  24(DX), R9
+	MOVQ  32(DX), R10
+	SUBQ  0(CX), SI
+	SBBQ  8(CX), DI
+	SBBQ  16(CX), R8
+	SBBQ  24(CX), R9
+	SBBQ  32(CX), R10
+	JCC   l4
+	ADDQ  q<>+0(SB), SI
+	ADCQ  q<>+8(SB), DI
+	ADCQ  q<>+16(SB), R8
+	ADCQ  q<>+24(SB), R9
+	ADCQ  q<>+32(SB), R10
+
+l4:
+	MOVQ SI, 0(AX)
+	MOVQ DI, 8(AX)
+	MOVQ R8, 16(AX)
+	MOVQ R9, 24(AX)
+	MOVQ R10, 32(AX)
+
+	// increment pointers to visit next element
+	ADDQ $40, DX
+	ADDQ $40, CX
+	ADDQ $40, AX
+	DECQ BX
+	JMP  l3
+
+l5:
+	RET
+
+// sumVec(res, a *Element, n uint64) res = a[0] + ... + a[n-1]
+TEXT ·sumVec(SB), NOSPLIT, $0-24
+	MOVQ a+8(FP), AX
+	MOVQ n+16(FP), DX
+	XORQ CX, CX
+	XORQ BX, BX
+	XORQ SI, SI
+	XORQ DI, DI
+	XORQ R8, R8
+
+l6:
+	TESTQ DX, DX
+	JEQ   l7
+	ADDQ  0(AX), CX
+	ADCQ  8(AX), BX
+	ADCQ  16(AX), SI
+	ADCQ  24(AX), DI
+	ADCQ  32(AX), R8
+
+	// reduce element(CX,BX,SI,DI,R8) using temp registers (R9,R10,R11,R12,R13)
+	REDUCE(CX,BX,SI,DI,R8,R9,R10,R11,R12,R13)
+
+	// increment pointer to visit next element
+	ADDQ $40, AX
+	DECQ DX
+	JMP  l6
+
+l7:
+	MOVQ res+0(FP), AX
+	MOVQ CX, R9
+	MOVQ BX, R10
+	MOVQ SI, R11
+	MOVQ DI, R12
+	MOVQ R8, R13
+	MOVQ R9, 0(AX)
+	MOVQ R10, 8(AX)
+	MOVQ R11, 16(AX)
+	MOVQ R12, 24(AX)
+	MOVQ R13, 32(AX)
+	RET
+
+// mulVec(res, a, b *Element, n uint64) res[0...n] = a[0...n] * b[0...n]
+TEXT ·mulVec(SB), $24-32
+	MOVQ res+0(FP), R13
+	MOVQ a+8(FP), R14
+	MOVQ b+16(FP), CX
+	MOVQ n+24(FP), BX
+
+l8:
+	TESTQ BX, BX
+	JEQ   l9
+
+	// A -> BP
+	// t[0] -> SI
+	// t[1] -> DI
+	// t[2] -> R8
+	// t[3] -> R9
+	// t[4] -> R10
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 0(CX), DX
+
+	// (A,t[0])  := x[0]*y[0] + A
+	MULXQ 0(R14), SI, DI
+
+	// (A,t[1])  := x[1]*y[0] + A
+	MULXQ 8(R14), AX, R8
+	ADOXQ AX, DI
+
+	// (A,t[2])  := x[2]*y[0] + A
+	MULXQ 16(R14), AX, R9
+	ADOXQ AX, R8
+
+	// (A,t[3])  := x[3]*y[0] + A
+	MULXQ 24(R14), AX, R10
+	ADOXQ AX, R9
+
+	// (A,t[4])  := x[4]*y[0] + A
+	MULXQ 32(R14), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 8(CX), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[1] + A
+	MULXQ 0(R14), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[1] + A
+	ADCXQ BP, DI
+	MULXQ 8(R14), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[1] + A
+	ADCXQ BP, R8
+	MULXQ 16(R14), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[1] + A
+	ADCXQ BP, R9
+	MULXQ 24(R14), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[1] + A
+	ADCXQ BP, R10
+	MULXQ 32(R14), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 16(CX), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[2] + A
+	MULXQ 0(R14), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[2] + A
+	ADCXQ BP, DI
+	MULXQ 8(R14), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[2] + A
+	ADCXQ BP, R8
+	MULXQ 16(R14), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[2] + A
+	ADCXQ BP, R9
+	MULXQ 24(R14), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[2] + A
+	ADCXQ BP, R10
+	MULXQ 32(R14), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 24(CX), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[3] + A
+	MULXQ 0(R14), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[3] + A
+	ADCXQ BP, DI
+	MULXQ 8(R14), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[3] + A
+	ADCXQ BP, R8
+	MULXQ 16(R14), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[3] + A
+	ADCXQ BP, R9
+	MULXQ 24(R14), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[3] + A
+	ADCXQ BP, R10
+	MULXQ 32(R14), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 32(CX), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[4] + A
+	MULXQ 0(R14), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[4] + A
+	ADCXQ BP, DI
+	MULXQ 8(R14), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[4] + A
+	ADCXQ BP, R8
+	MULXQ 16(R14), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[4] + A
+	ADCXQ BP, R9
+	MULXQ 24(R14), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[4] + A
+	ADCXQ BP, R10
+	MULXQ 32(R14), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// reduce element(SI,DI,R8,R9,R10) using temp registers (R12,R11,s0-8(SP),s1-16(SP),s2-24(SP))
+	REDUCE(SI,DI,R8,R9,R10,R12,R11,s0-8(SP),s1-16(SP),s2-24(SP))
+
+	MOVQ SI, 0(R13)
+	MOVQ DI, 8(R13)
+	MOVQ R8, 16(R13)
+	MOVQ R9, 24(R13)
+	MOVQ R10, 32(R13)
+
+	// increment pointers to visit next element
+	ADDQ $40, R14
+	ADDQ $40, CX
+	ADDQ $40, R13
+	DECQ BX
+	JMP  l8
+
+l9:
+	RET
+
+// scalarMulVec(res, a, b *Element, n uint64) res[0...n] = a[0...n] * b
+TEXT ·scalarMulVec(SB), $24-32
+	MOVQ res+0(FP), R13
+	MOVQ a+8(FP), R14
+	MOVQ b+16(FP), CX
+	MOVQ n+24(FP), BX
+
+l10:
+	TESTQ BX, BX
+	JEQ   l11
+
+	// A -> BP
+	// t[0] -> SI
+	// t[1] -> DI
+	// t[2] -> R8
+	// t[3] -> R9
+	// t[4] -> R10
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 0(CX), DX
+
+	// (A,t[0])  := x[0]*y[0] + A
+	MULXQ 0(R14), SI, DI
+
+	// (A,t[1])  := x[1]*y[0] + A
+	MULXQ 8(R14), AX, R8
+	ADOXQ AX, DI
+
+	// (A,t[2])  := x[2]*y[0] + A
+	MULXQ 16(R14), AX, R9
+	ADOXQ AX, R8
+
+	// (A,t[3])  := x[3]*y[0] + A
+	MULXQ 24(R14), AX, R10
+	ADOXQ AX, R9
+
+	// (A,t[4])  := x[4]*y[0] + A
+	MULXQ 32(R14), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 8(CX), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[1] + A
+	MULXQ 0(R14), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[1] + A
+	ADCXQ BP, DI
+	MULXQ 8(R14), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[1] + A
+	ADCXQ BP, R8
+	MULXQ 16(R14), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[1] + A
+	ADCXQ BP, R9
+	MULXQ 24(R14), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[1] + A
+	ADCXQ BP, R10
+	MULXQ 32(R14), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 16(CX), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[2] + A
+	MULXQ 0(R14), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[2] + A
+	ADCXQ BP, DI
+	MULXQ 8(R14), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[2] + A
+	ADCXQ BP, R8
+	MULXQ 16(R14), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[2] + A
+	ADCXQ BP, R9
+	MULXQ 24(R14), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[2] + A
+	ADCXQ BP, R10
+	MULXQ 32(R14), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 24(CX), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[3] + A
+	MULXQ 0(R14), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[3] + A
+	ADCXQ BP, DI
+	MULXQ 8(R14), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[3] + A
+	ADCXQ BP, R8
+	MULXQ 16(R14), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[3] + A
+	ADCXQ BP, R9
+	MULXQ 24(R14), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[3] + A
+	ADCXQ BP, R10
+	MULXQ 32(R14), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 32(CX), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[4] + A
+	MULXQ 0(R14), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[4] + A
+	ADCXQ BP, DI
+	MULXQ 8(R14), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[4] + A
+	ADCXQ BP, R8
+	MULXQ 16(R14), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[4] + A
+	ADCXQ BP, R9
+	MULXQ 24(R14), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[4] + A
+	ADCXQ BP, R10
+	MULXQ 32(R14), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// reduce element(SI,DI,R8,R9,R10) using temp registers (R12,R11,s0-8(SP),s1-16(SP),s2-24(SP))
+	REDUCE(SI,DI,R8,R9,R10,R12,R11,s0-8(SP),s1-16(SP),s2-24(SP))
+
+	MOVQ SI, 0(R13)
+	MOVQ DI, 8(R13)
+	MOVQ R8, 16(R13)
+	MOVQ R9, 24(R13)
+	MOVQ R10, 32(R13)
+
+	// increment pointers to visit next element
+	ADDQ $40, R14
+	ADDQ $40, R13
+	DECQ BX
+	JMP  l10
+
+l11:
+	RET
+
+// axpyVec(res, alpha, x *Element, n uint64) res[0...n] = alpha * x[0...n] + res[0...n]
+TEXT ·axpyVec(SB), $24-32
+	MOVQ res+0(FP), R13
+	MOVQ alpha+8(FP), R14
+	MOVQ x+16(FP), CX
+	MOVQ n+24(FP), BX
+
+l12:
+	TESTQ BX, BX
+	JEQ   l13
+
+	// A -> BP
+	// t[0] -> SI
+	// t[1] -> DI
+	// t[2] -> R8
+	// t[3] -> R9
+	// t[4] -> R10
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 0(R14), DX
+
+	// (A,t[0])  := x[0]*y[0] + A
+	MULXQ 0(CX), SI, DI
+
+	// (A,t[1])  := x[1]*y[0] + A
+	MULXQ 8(CX), AX, R8
+	ADOXQ AX, DI
+
+	// (A,t[2])  := x[2]*y[0] + A
+	MULXQ 16(CX), AX, R9
+	ADOXQ AX, R8
+
+	// (A,t[3])  := x[3]*y[0] + A
+	MULXQ 24(CX), AX, R10
+	ADOXQ AX, R9
+
+	// (A,t[4])  := x[4]*y[0] + A
+	MULXQ 32(CX), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 8(R14), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[1] + A
+	MULXQ 0(CX), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[1] + A
+	ADCXQ BP, DI
+	MULXQ 8(CX), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[1] + A
+	ADCXQ BP, R8
+	MULXQ 16(CX), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[1] + A
+	ADCXQ BP, R9
+	MULXQ 24(CX), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[1] + A
+	ADCXQ BP, R10
+	MULXQ 32(CX), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 16(R14), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[2] + A
+	MULXQ 0(CX), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[2] + A
+	ADCXQ BP, DI
+	MULXQ 8(CX), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[2] + A
+	ADCXQ BP, R8
+	MULXQ 16(CX), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[2] + A
+	ADCXQ BP, R9
+	MULXQ 24(CX), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[2] + A
+	ADCXQ BP, R10
+	MULXQ 32(CX), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 24(R14), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[3] + A
+	MULXQ 0(CX), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[3] + A
+	ADCXQ BP, DI
+	MULXQ 8(CX), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[3] + A
+	ADCXQ BP, R8
+	MULXQ 16(CX), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[3] + A
+	ADCXQ BP, R9
+	MULXQ 24(CX), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[3] + A
+	ADCXQ BP, R10
+	MULXQ 32(CX), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 32(R14), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[4] + A
+	MULXQ 0(CX), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[1])  := t[1] + x[1]*y[4] + A
+	ADCXQ BP, DI
+	MULXQ 8(CX), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[2])  := t[2] + x[2]*y[4] + A
+	ADCXQ BP, R8
+	MULXQ 16(CX), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[3])  := t[3] + x[3]*y[4] + A
+	ADCXQ BP, R9
+	MULXQ 24(CX), AX, BP
+	ADOXQ AX, R9
+
+	// (A,t[4])  := t[4] + x[4]*y[4] + A
+	ADCXQ BP, R10
+	MULXQ 32(CX), AX, BP
+	ADOXQ AX, R10
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ SI, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, R11
+	ADCXQ SI, AX
+	MOVQ  R11, SI
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ DI, SI
+	MULXQ q<>+8(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ R8, DI
+	MULXQ q<>+16(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R9, R8
+	MULXQ q<>+24(SB), AX, R9
+	ADOXQ AX, R8
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R10, R9
+	MULXQ q<>+32(SB), AX, R10
+	ADOXQ AX, R9
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R10
+	ADOXQ BP, R10
+
+	// reduce element(SI,DI,R8,R9,R10) using temp registers (R12,R11,s0-8(SP),s1-16(SP),s2-24(SP))
+	REDUCE(SI,DI,R8,R9,R10,R12,R11,s0-8(SP),s1-16(SP),s2-24(SP))
+
+	ADDQ 0(R13), SI
+	ADCQ 8(R13), DI
+	ADCQ 16(R13), R8
+	ADCQ 24(R13), R9
+	ADCQ 32(R13), R10
+
+	// reduce element(SI,DI,R8,R9,R10) using temp registers (R12,R11,s0-8(SP),s1-16(SP),s2-24(SP))
+	REDUCE(SI,DI,R8,R9,R10,R12,R11,s0-8(SP),s1-16(SP),s2-24(SP))
+
+	MOVQ SI, 0(R13)
+	MOVQ DI, 8(R13)
+	MOVQ R8, 16(R13)
+	MOVQ R9, 24(R13)
+	MOVQ R10, 32(R13)
+
+	// increment pointers to visit next element
+	ADDQ $40, CX
+	ADDQ $40, R13
+	DECQ BX
+	JMP  l12
+
+l13:
+	RET
+
+// innerProdVec(res, a, b *Element, n uint64) res = a[0]*b[0] + ... + a[n-1]*b[n-1]
+TEXT ·innerProdVec(SB), $56-32
+	MOVQ a+8(FP), R13
+	MOVQ b+16(FP), R14
+	MOVQ n+24(FP), CX
+	MOVQ $0, R10
+	MOVQ $0, R11
+	MOVQ $0, R12
+	MOVQ $0, s0-8(SP)
+	MOVQ $0, s1-16(SP)
+
+l14:
+	TESTQ CX, CX
+	JEQ   l15
+
+	// A -> BP
+	// t[0] -> BX
+	// t[1] -> SI
+	// t[2] -> DI
+	// t[3] -> R8
+	// t[4] -> R9
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 0(R14), DX
+
+	// (A,t[0])  := x[0]*y[0] + A
+	MULXQ 0(R13), BX, SI
+
+	// (A,t[1])  := x[1]*y[0] + A
+	MULXQ 8(R13), AX, DI
+	ADOXQ AX, SI
+
+	// (A,t[2])  := x[2]*y[0] + A
+	MULXQ 16(R13), AX, R8
+	ADOXQ AX, DI
+
+	// (A,t[3])  := x[3]*y[0] + A
+	MULXQ 24(R13), AX, R9
+	ADOXQ AX, R8
+
+	// (A,t[4])  := x[4]*y[0] + A
+	MULXQ 32(R13), AX, BP
+	ADOXQ AX, R9
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADOXQ AX, BP
+	PUSHQ BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ BX, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, BP
+	ADCXQ BX, AX
+	MOVQ  BP, BX
+	POPQ  BP
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ SI, BX
+	MULXQ q<>+8(SB), AX, SI
+	ADOXQ AX, BX
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ DI, SI
+	MULXQ q<>+16(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R8, DI
+	MULXQ q<>+24(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R9, R8
+	MULXQ q<>+32(SB), AX, R9
+	ADOXQ AX, R8
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R9
+	ADOXQ BP, R9
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 8(R14), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[1] + A
+	MULXQ 0(R13), AX, BP
+	ADOXQ AX, BX
+
+	// (A,t[1])  := t[1] + x[1]*y[1] + A
+	ADCXQ BP, SI
+	MULXQ 8(R13), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[2])  := t[2] + x[2]*y[1] + A
+	ADCXQ BP, DI
+	MULXQ 16(R13), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[3])  := t[3] + x[3]*y[1] + A
+	ADCXQ BP, R8
+	MULXQ 24(R13), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[4])  := t[4] + x[4]*y[1] + A
+	ADCXQ BP, R9
+	MULXQ 32(R13), AX, BP
+	ADOXQ AX, R9
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+	PUSHQ BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ BX, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, BP
+	ADCXQ BX, AX
+	MOVQ  BP, BX
+	POPQ  BP
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ SI, BX
+	MULXQ q<>+8(SB), AX, SI
+	ADOXQ AX, BX
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ DI, SI
+	MULXQ q<>+16(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R8, DI
+	MULXQ q<>+24(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R9, R8
+	MULXQ q<>+32(SB), AX, R9
+	ADOXQ AX, R8
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R9
+	ADOXQ BP, R9
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 16(R14), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[2] + A
+	MULXQ 0(R13), AX, BP
+	ADOXQ AX, BX
+
+	// (A,t[1])  := t[1] + x[1]*y[2] + A
+	ADCXQ BP, SI
+	MULXQ 8(R13), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[2])  := t[2] + x[2]*y[2] + A
+	ADCXQ BP, DI
+	MULXQ 16(R13), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[3])  := t[3] + x[3]*y[2] + A
+	ADCXQ BP, R8
+	MULXQ 24(R13), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[4])  := t[4] + x[4]*y[2] + A
+	ADCXQ BP, R9
+	MULXQ 32(R13), AX, BP
+	ADOXQ AX, R9
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+	PUSHQ BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ BX, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, BP
+	ADCXQ BX, AX
+	MOVQ  BP, BX
+	POPQ  BP
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ SI, BX
+	MULXQ q<>+8(SB), AX, SI
+	ADOXQ AX, BX
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ DI, SI
+	MULXQ q<>+16(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R8, DI
+	MULXQ q<>+24(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R9, R8
+	MULXQ q<>+32(SB), AX, R9
+	ADOXQ AX, R8
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R9
+	ADOXQ BP, R9
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 24(R14), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[3] + A
+	MULXQ 0(R13), AX, BP
+	ADOXQ AX, BX
+
+	// (A,t[1])  := t[1] + x[1]*y[3] + A
+	ADCXQ BP, SI
+	MULXQ 8(R13), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[2])  := t[2] + x[2]*y[3] + A
+	ADCXQ BP, DI
+	MULXQ 16(R13), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[3])  := t[3] + x[3]*y[3] + A
+	ADCXQ BP, R8
+	MULXQ 24(R13), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[4])  := t[4] + x[4]*y[3] + A
+	ADCXQ BP, R9
+	MULXQ 32(R13), AX, BP
+	ADOXQ AX, R9
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+	PUSHQ BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ BX, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, BP
+	ADCXQ BX, AX
+	MOVQ  BP, BX
+	POPQ  BP
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ SI, BX
+	MULXQ q<>+8(SB), AX, SI
+	ADOXQ AX, BX
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ DI, SI
+	MULXQ q<>+16(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R8, DI
+	MULXQ q<>+24(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R9, R8
+	MULXQ q<>+32(SB), AX, R9
+	ADOXQ AX, R8
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R9
+	ADOXQ BP, R9
+
+	// clear the flags
+	XORQ AX, AX
+	MOVQ 32(R14), DX
+
+	// (A,t[0])  := t[0] + x[0]*y[4] + A
+	MULXQ 0(R13), AX, BP
+	ADOXQ AX, BX
+
+	// (A,t[1])  := t[1] + x[1]*y[4] + A
+	ADCXQ BP, SI
+	MULXQ 8(R13), AX, BP
+	ADOXQ AX, SI
+
+	// (A,t[2])  := t[2] + x[2]*y[4] + A
+	ADCXQ BP, DI
+	MULXQ 16(R13), AX, BP
+	ADOXQ AX, DI
+
+	// (A,t[3])  := t[3] + x[3]*y[4] + A
+	ADCXQ BP, R8
+	MULXQ 24(R13), AX, BP
+	ADOXQ AX, R8
+
+	// (A,t[4])  := t[4] + x[4]*y[4] + A
+	ADCXQ BP, R9
+	MULXQ 32(R13), AX, BP
+	ADOXQ AX, R9
+
+	// A += carries from ADCXQ and ADOXQ
+	MOVQ  $0, AX
+	ADCXQ AX, BP
+	ADOXQ AX, BP
+	PUSHQ BP
+
+	// m := t[0]*q'[0] mod W
+	MOVQ  qInv0<>(SB), DX
+	IMULQ BX, DX
+
+	// clear the flags
+	XORQ AX, AX
+
+	// C,_ := t[0] + m*q[0]
+	MULXQ q<>+0(SB), AX, BP
+	ADCXQ BX, AX
+	MOVQ  BP, BX
+	POPQ  BP
+
+	// (C,t[0]) := t[1] + m*q[1] + C
+	ADCXQ SI, BX
+	MULXQ q<>+8(SB), AX, SI
+	ADOXQ AX, BX
+
+	// (C,t[1]) := t[2] + m*q[2] + C
+	ADCXQ DI, SI
+	MULXQ q<>+16(SB), AX, DI
+	ADOXQ AX, SI
+
+	// (C,t[2]) := t[3] + m*q[3] + C
+	ADCXQ R8, DI
+	MULXQ q<>+24(SB), AX, R8
+	ADOXQ AX, DI
+
+	// (C,t[3]) := t[4] + m*q[4] + C
+	ADCXQ R9, R8
+	MULXQ q<>+32(SB), AX, R9
+	ADOXQ AX, R8
+
+	// t[4] = C + A
+	MOVQ  $0, AX
+	ADCXQ AX, R9
+	ADOXQ BP, R9
+
+	// reduce element(BX,SI,DI,R8,R9) using temp registers (s2-24(SP),s3-32(SP),s4-40(SP),s5-48(SP),s6-56(SP))
+	REDUCE(BX,SI,DI,R8,R9,s2-24(SP),s3-32(SP),s4-40(SP),s5-48(SP),s6-56(SP))
+
+	ADDQ R10, BX
+	ADCQ R11, SI
+	ADCQ R12, DI
+	ADCQ s0-8(SP), R8
+	ADCQ s1-16(SP), R9
+
+	// reduce element(BX,SI,DI,R8,R9) using temp registers (s2-24(SP),s3-32(SP),s4-40(SP),s5-48(SP),s6-56(SP))
+	REDUCE(BX,SI,DI,R8,R9,s2-24(SP),s3-32(SP),s4-40(SP),s5-48(SP),s6-56(SP))
+
+	MOVQ BX, R10
+	MOVQ SI, R11
+	MOVQ DI, R12
+	MOVQ R8, s0-8(SP)
+	MOVQ R9, s1-16(SP)
+
+	// increment pointers to visit next element
+	ADDQ $40, R13
+	ADDQ $40, R14
+	DECQ CX
+	JMP  l14
+
+l15:
+	MOVQ res+0(FP), R13
+	MOVQ R10, BX
+	MOVQ R11, SI
+	MOVQ R12, DI
+	MOVQ s0-8(SP), R8
+	MOVQ s1-16(SP), R9
+	MOVQ BX, 0(R13)
+	MOVQ SI, 8(R13)
+	MOVQ DI, 16(R13)
+	MOVQ R8, 24(R13)
+	MOVQ R9, 32(R13)
+	RET
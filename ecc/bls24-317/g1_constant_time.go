@@ -0,0 +1,463 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls24317
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fp"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+)
+
+// windowSizeConstantTime is the window size of the constant time scalar
+// multiplication, whose table holds the 2ʷ⁻¹ odd multiples of the point.
+const windowSizeConstantTime = 4
+
+// nbDigitsConstantTime is the number of digits of the scalars in the regular
+// recoding of the constant time scalar multiplication.
+const nbDigitsConstantTime = (fr.Bits + windowSizeConstantTime - 1) / windowSizeConstantTime
+
+// qConstantTime and rConstantTime are the limbs of the moduli of fp and fr, and
+// qInvNegConstantTime is -q⁻¹ mod 2⁶⁴, used by the constant time arithmetic.
+var qConstantTime, rConstantTime, qInvNegConstantTime = func() (q [fp.Limbs]uint64, r [fr.Limbs]uint64, qInvNeg uint64) {
+	for i := range q {
+		q[i] = new(big.Int).Rsh(fp.Modulus(), uint(64*i)).Uint64()
+	}
+	for i := range r {
+		r[i] = new(big.Int).Rsh(fr.Modulus(), uint(64*i)).Uint64()
+	}
+	// Newton's iteration doubles the number of correct bits of q⁻¹ mod 2⁶⁴
+	inv := uint64(1)
+	for i := 0; i < 6; i++ {
+		inv *= 2 - q[0]*inv
+	}
+	return q, r, -inv
+}()
+
+// g1Proj point in projective coordinates
+type g1Proj struct {
+	x, y, z fp.Element
+}
+
+// ScalarMultiplicationConstantTime computes and returns p = [s]a, where a is in
+// the prime order subgroup, in constant time.
+//
+// Unlike ScalarMultiplication, the sequence of field operations and memory
+// accesses does not depend on the scalar, which can then be a secret (a private
+// key or a nonce). It uses a fixed window method on a regular recoding of the
+// scalar, constant time table lookups and complete addition formulas, whose
+// field operations are implemented here without branches rather than with the
+// methods of fp.Element. The scalar is reduced modulo r, which is not done in
+// constant time: secret scalars should be given reduced.
+func (p *G1Affine) ScalarMultiplicationConstantTime(a *G1Affine, s *big.Int) *G1Affine {
+	// the infinity point (0, 0) in affine coordinates is (0, 1, 0) in projective
+	// coordinates
+	var q, res g1Proj
+	var one fp.Element
+	one.SetOne()
+	isInfinity := isZeroConstantTime(a.X[:]) & isZeroConstantTime(a.Y[:])
+	q.x.Set(&a.X)
+	q.y.Select(isInfinity, &a.Y, &one)
+	q.z.Select(isInfinity, &one, &q.z)
+	res.mulConstantTime(&q, s)
+
+	// the inversion is done with Fermat's little theorem to be constant time
+	var zInv fp.Element
+	fpExpConstantTime(&zInv, &res.z, pMinusTwo())
+	fpMulConstantTime(&p.X, &res.x, &zInv)
+	fpMulConstantTime(&p.Y, &res.y, &zInv)
+	return p
+}
+
+// ScalarMultiplicationBaseConstantTime computes and returns p = [s]g, where g is
+// the affine point generating the prime subgroup, in constant time.
+//
+// See ScalarMultiplicationConstantTime.
+func (p *G1Affine) ScalarMultiplicationBaseConstantTime(s *big.Int) *G1Affine {
+	return p.ScalarMultiplicationConstantTime(&g1GenAff, s)
+}
+
+// ScalarMultiplicationConstantTime computes and returns p = [s]q, where q is in
+// the prime order subgroup, in constant time.
+//
+// See G1Affine.ScalarMultiplicationConstantTime.
+func (p *G1Jac) ScalarMultiplicationConstantTime(q *G1Jac, s *big.Int) *G1Jac {
+	// (X, Y, Z) in Jacobian coordinates is (XZ, Y, Z³) in projective coordinates
+	var _q, res g1Proj
+	fpMulConstantTime(&_q.x, &q.X, &q.Z)
+	_q.y.Set(&q.Y)
+	fpMulConstantTime(&_q.z, &q.Z, &q.Z)
+	fpMulConstantTime(&_q.z, &_q.z, &q.Z)
+	res.mulConstantTime(&_q, s)
+
+	// and (X, Y, Z) in projective coordinates is (XZ, YZ², Z) in Jacobian
+	// coordinates, the infinity point being (1, 1, 0)
+	var one fp.Element
+	one.SetOne()
+	isInfinity := isZeroConstantTime(res.z[:])
+	fpMulConstantTime(&p.X, &res.x, &res.z)
+	p.X.Select(isInfinity, &p.X, &one)
+	fpMulConstantTime(&p.Y, &res.z, &res.z)
+	fpMulConstantTime(&p.Y, &p.Y, &res.y)
+	p.Y.Select(isInfinity, &p.Y, &one)
+	p.Z.Set(&res.z)
+	return p
+}
+
+// mulConstantTime sets p to [s]q in constant time, q being in the prime order subgroup.
+func (p *g1Proj) mulConstantTime(q *g1Proj, s *big.Int) *g1Proj {
+	// the recoding needs an odd scalar: if s is even, r-s (which is odd) is
+	// used instead and the result is negated. If s=0, the result is replaced
+	// by the infinity point.
+	k := scalarLimbsConstantTime(s)
+	var kNeg [fr.Limbs]uint64
+	var borrow uint64
+	for i := range kNeg {
+		kNeg[i], borrow = bits.Sub64(rConstantTime[i], k[i], borrow)
+	}
+	isZero := isZeroConstantTime(k[:])
+	isEven := (k[0] & 1) ^ 1
+	mask := -isEven
+	for i := range k {
+		k[i] = k[i]&^mask | kNeg[i]&mask
+	}
+	k[0] |= 1
+	digits := recodeConstantTime(k)
+
+	// table[i] = [2i+1]q
+	var table [1 << (windowSizeConstantTime - 1)]g1Proj
+	var q2 g1Proj
+	table[0] = *q
+	q2.doubleComplete(q)
+	for i := 1; i < len(table); i++ {
+		table[i].addComplete(&table[i-1], &q2)
+	}
+
+	var res, tmp g1Proj
+	res.lookupConstantTime(&table, digits[nbDigitsConstantTime-1])
+	for i := nbDigitsConstantTime - 2; i >= 0; i-- {
+		for j := 0; j < windowSizeConstantTime; j++ {
+			res.doubleComplete(&res)
+		}
+		tmp.lookupConstantTime(&table, digits[i])
+		res.addComplete(&res, &tmp)
+	}
+
+	var yNeg fp.Element
+	fpNegConstantTime(&yNeg, &res.y)
+	res.y.Select(int(isEven), &res.y, &yNeg)
+
+	var infinity g1Proj
+	infinity.y.SetOne()
+	p.x.Select(isZero, &res.x, &infinity.x)
+	p.y.Select(isZero, &res.y, &infinity.y)
+	p.z.Select(isZero, &res.z, &infinity.z)
+	return p
+}
+
+// recodeConstantTime returns the regular recoding of the odd scalar k, with
+// the odd digits dᵢ ∈ [-(2ʷ-1), 2ʷ-1] such that k = ∑ dᵢ2ʷⁱ, the last one
+// being positive (Joye–Tunstall).
+func recodeConstantTime(k [fr.Limbs]uint64) (digits [nbDigitsConstantTime]int) {
+	const w = windowSizeConstantTime
+	for i := 0; i < nbDigitsConstantTime-1; i++ {
+		// dᵢ = (k mod 2ʷ⁺¹) - 2ʷ and k = (k - dᵢ) / 2ʷ, which stays odd
+		digits[i] = int(k[0]&((1<<(w+1))-1)) - (1 << w)
+		for j := 0; j < fr.Limbs-1; j++ {
+			k[j] = k[j]>>w | k[j+1]<<(64-w)
+		}
+		k[fr.Limbs-1] >>= w
+		k[0] |= 1
+	}
+	digits[nbDigitsConstantTime-1] = int(k[0])
+	return
+}
+
+// lookupConstantTime sets p to [d]q, where table[i] = [2i+1]q and d is odd,
+// reading all the entries of the table.
+func (p *g1Proj) lookupConstantTime(table *[1 << (windowSizeConstantTime - 1)]g1Proj, d int) *g1Proj {
+	sign := d >> 63
+	idx := ((d ^ sign) - sign) >> 1
+	for i := range table {
+		c := subtle.ConstantTimeEq(int32(i), int32(idx))
+		p.x.Select(c, &p.x, &table[i].x)
+		p.y.Select(c, &p.y, &table[i].y)
+		p.z.Select(c, &p.z, &table[i].z)
+	}
+	var yNeg fp.Element
+	fpNegConstantTime(&yNeg, &p.y)
+	p.y.Select(sign&1, &p.y, &yNeg)
+	return p
+}
+
+// addComplete sets p to q+r with the complete addition formulas for a=0
+// https://eprint.iacr.org/2015/1060.pdf (Algorithm 7)
+func (p *g1Proj) addComplete(q, r *g1Proj) *g1Proj {
+	var t0, t1, t2, t3, t4, x3, y3, z3, b3 fp.Element
+	fpAddConstantTime(&b3, &bCurveCoeff, &bCurveCoeff)
+	fpAddConstantTime(&b3, &b3, &bCurveCoeff)
+
+	fpMulConstantTime(&t0, &q.x, &r.x)
+	fpMulConstantTime(&t1, &q.y, &r.y)
+	fpMulConstantTime(&t2, &q.z, &r.z)
+	fpAddConstantTime(&t3, &q.x, &q.y)
+	fpAddConstantTime(&t4, &r.x, &r.y)
+	fpMulConstantTime(&t3, &t3, &t4)
+	fpAddConstantTime(&t4, &t0, &t1)
+	fpSubConstantTime(&t3, &t3, &t4)
+	fpAddConstantTime(&t4, &q.y, &q.z)
+	fpAddConstantTime(&x3, &r.y, &r.z)
+	fpMulConstantTime(&t4, &t4, &x3)
+	fpAddConstantTime(&x3, &t1, &t2)
+	fpSubConstantTime(&t4, &t4, &x3)
+	fpAddConstantTime(&x3, &q.x, &q.z)
+	fpAddConstantTime(&y3, &r.x, &r.z)
+	fpMulConstantTime(&x3, &x3, &y3)
+	fpAddConstantTime(&y3, &t0, &t2)
+	fpSubConstantTime(&y3, &x3, &y3)
+	fpAddConstantTime(&x3, &t0, &t0)
+	fpAddConstantTime(&t0, &x3, &t0)
+	fpMulConstantTime(&t2, &b3, &t2)
+	fpAddConstantTime(&z3, &t1, &t2)
+	fpSubConstantTime(&t1, &t1, &t2)
+	fpMulConstantTime(&y3, &b3, &y3)
+	fpMulConstantTime(&x3, &t4, &y3)
+	fpMulConstantTime(&t2, &t3, &t1)
+	fpSubConstantTime(&x3, &t2, &x3)
+	fpMulConstantTime(&y3, &y3, &t0)
+	fpMulConstantTime(&t1, &t1, &z3)
+	fpAddConstantTime(&y3, &t1, &y3)
+	fpMulConstantTime(&t0, &t0, &t3)
+	fpMulConstantTime(&z3, &z3, &t4)
+	fpAddConstantTime(&z3, &z3, &t0)
+
+	p.x, p.y, p.z = x3, y3, z3
+	return p
+}
+
+// doubleComplete sets p to [2]q with the complete doubling formulas for a=0
+// https://eprint.iacr.org/2015/1060.pdf (Algorithm 9)
+func (p *g1Proj) doubleComplete(q *g1Proj) *g1Proj {
+	var t0, t1, t2, x3, y3, z3, b3 fp.Element
+	fpAddConstantTime(&b3, &bCurveCoeff, &bCurveCoeff)
+	fpAddConstantTime(&b3, &b3, &bCurveCoeff)
+
+	fpMulConstantTime(&t0, &q.y, &q.y)
+	fpAddConstantTime(&z3, &t0, &t0)
+	fpAddConstantTime(&z3, &z3, &z3)
+	fpAddConstantTime(&z3, &z3, &z3)
+	fpMulConstantTime(&t1, &q.y, &q.z)
+	fpMulConstantTime(&t2, &q.z, &q.z)
+	fpMulConstantTime(&t2, &b3, &t2)
+	fpMulConstantTime(&x3, &t2, &z3)
+	fpAddConstantTime(&y3, &t0, &t2)
+	fpMulConstantTime(&z3, &t1, &z3)
+	fpAddConstantTime(&t1, &t2, &t2)
+	fpAddConstantTime(&t2, &t1, &t2)
+	fpSubConstantTime(&t0, &t0, &t2)
+	fpMulConstantTime(&y3, &t0, &y3)
+	fpAddConstantTime(&y3, &x3, &y3)
+	fpMulConstantTime(&t1, &q.x, &q.y)
+	fpMulConstantTime(&x3, &t0, &t1)
+	fpAddConstantTime(&x3, &x3, &x3)
+
+	p.x, p.y, p.z = x3, y3, z3
+	return p
+}
+
+// fpAddConstantTime sets z = x + y, without branches.
+func fpAddConstantTime(z, x, y *fp.Element) {
+	var sum, diff fp.Element
+	var carry, borrow uint64
+	for i := range sum {
+		sum[i], carry = bits.Add64(x[i], y[i], carry)
+	}
+	for i := range diff {
+		diff[i], borrow = bits.Sub64(sum[i], qConstantTime[i], borrow)
+	}
+	// x + y ⩾ q if the addition overflows or the subtraction doesn't
+	z.Select(int(carry|(borrow^1)), &sum, &diff)
+}
+
+// fpSubConstantTime sets z = x - y, without branches.
+func fpSubConstantTime(z, x, y *fp.Element) {
+	var borrow, carry uint64
+	for i := range z {
+		z[i], borrow = bits.Sub64(x[i], y[i], borrow)
+	}
+	mask := -borrow
+	for i := range z {
+		z[i], carry = bits.Add64(z[i], qConstantTime[i]&mask, carry)
+	}
+}
+
+// fpNegConstantTime sets z = -x, without branches.
+func fpNegConstantTime(z, x *fp.Element) {
+	var zero fp.Element
+	fpSubConstantTime(z, &zero, x)
+}
+
+// fpMulConstantTime sets z = x⋅y, with a Montgomery multiplication (CIOS)
+// whose final subtraction is done without branches.
+func fpMulConstantTime(z, x, y *fp.Element) {
+	var t [6]uint64
+	var c, d, m uint64
+
+	// for each limb yⱼ, t = t + x⋅yⱼ, then t = (t + m⋅q) / 2⁶⁴ with m such
+	// that the division is exact
+	c = 0
+	c, t[0] = maddConstantTime(x[0], y[0], t[0], c)
+	c, t[1] = maddConstantTime(x[1], y[0], t[1], c)
+	c, t[2] = maddConstantTime(x[2], y[0], t[2], c)
+	c, t[3] = maddConstantTime(x[3], y[0], t[3], c)
+	c, t[4] = maddConstantTime(x[4], y[0], t[4], c)
+	t[5], d = bits.Add64(t[5], c, 0)
+	m = t[0] * qInvNegConstantTime
+	c, _ = maddConstantTime(m, qConstantTime[0], t[0], 0)
+	c, t[0] = maddConstantTime(m, qConstantTime[1], t[1], c)
+	c, t[1] = maddConstantTime(m, qConstantTime[2], t[2], c)
+	c, t[2] = maddConstantTime(m, qConstantTime[3], t[3], c)
+	c, t[3] = maddConstantTime(m, qConstantTime[4], t[4], c)
+	t[4], c = bits.Add64(t[5], c, 0)
+	t[5] = d + c
+	c = 0
+	c, t[0] = maddConstantTime(x[0], y[1], t[0], c)
+	c, t[1] = maddConstantTime(x[1], y[1], t[1], c)
+	c, t[2] = maddConstantTime(x[2], y[1], t[2], c)
+	c, t[3] = maddConstantTime(x[3], y[1], t[3], c)
+	c, t[4] = maddConstantTime(x[4], y[1], t[4], c)
+	t[5], d = bits.Add64(t[5], c, 0)
+	m = t[0] * qInvNegConstantTime
+	c, _ = maddConstantTime(m, qConstantTime[0], t[0], 0)
+	c, t[0] = maddConstantTime(m, qConstantTime[1], t[1], c)
+	c, t[1] = maddConstantTime(m, qConstantTime[2], t[2], c)
+	c, t[2] = maddConstantTime(m, qConstantTime[3], t[3], c)
+	c, t[3] = maddConstantTime(m, qConstantTime[4], t[4], c)
+	t[4], c = bits.Add64(t[5], c, 0)
+	t[5] = d + c
+	c = 0
+	c, t[0] = maddConstantTime(x[0], y[2], t[0], c)
+	c, t[1] = maddConstantTime(x[1], y[2], t[1], c)
+	c, t[2] = maddConstantTime(x[2], y[2], t[2], c)
+	c, t[3] = maddConstantTime(x[3], y[2], t[3], c)
+	c, t[4] = maddConstantTime(x[4], y[2], t[4], c)
+	t[5], d = bits.Add64(t[5], c, 0)
+	m = t[0] * qInvNegConstantTime
+	c, _ = maddConstantTime(m, qConstantTime[0], t[0], 0)
+	c, t[0] = maddConstantTime(m, qConstantTime[1], t[1], c)
+	c, t[1] = maddConstantTime(m, qConstantTime[2], t[2], c)
+	c, t[2] = maddConstantTime(m, qConstantTime[3], t[3], c)
+	c, t[3] = maddConstantTime(m, qConstantTime[4], t[4], c)
+	t[4], c = bits.Add64(t[5], c, 0)
+	t[5] = d + c
+	c = 0
+	c, t[0] = maddConstantTime(x[0], y[3], t[0], c)
+	c, t[1] = maddConstantTime(x[1], y[3], t[1], c)
+	c, t[2] = maddConstantTime(x[2], y[3], t[2], c)
+	c, t[3] = maddConstantTime(x[3], y[3], t[3], c)
+	c, t[4] = maddConstantTime(x[4], y[3], t[4], c)
+	t[5], d = bits.Add64(t[5], c, 0)
+	m = t[0] * qInvNegConstantTime
+	c, _ = maddConstantTime(m, qConstantTime[0], t[0], 0)
+	c, t[0] = maddConstantTime(m, qConstantTime[1], t[1], c)
+	c, t[1] = maddConstantTime(m, qConstantTime[2], t[2], c)
+	c, t[2] = maddConstantTime(m, qConstantTime[3], t[3], c)
+	c, t[3] = maddConstantTime(m, qConstantTime[4], t[4], c)
+	t[4], c = bits.Add64(t[5], c, 0)
+	t[5] = d + c
+	c = 0
+	c, t[0] = maddConstantTime(x[0], y[4], t[0], c)
+	c, t[1] = maddConstantTime(x[1], y[4], t[1], c)
+	c, t[2] = maddConstantTime(x[2], y[4], t[2], c)
+	c, t[3] = maddConstantTime(x[3], y[4], t[3], c)
+	c, t[4] = maddConstantTime(x[4], y[4], t[4], c)
+	t[5], d = bits.Add64(t[5], c, 0)
+	m = t[0] * qInvNegConstantTime
+	c, _ = maddConstantTime(m, qConstantTime[0], t[0], 0)
+	c, t[0] = maddConstantTime(m, qConstantTime[1], t[1], c)
+	c, t[1] = maddConstantTime(m, qConstantTime[2], t[2], c)
+	c, t[2] = maddConstantTime(m, qConstantTime[3], t[3], c)
+	c, t[3] = maddConstantTime(m, qConstantTime[4], t[4], c)
+	t[4], c = bits.Add64(t[5], c, 0)
+	t[5] = d + c
+
+	// t < 2q, and q is subtracted if t ⩾ q
+	var res, diff fp.Element
+	var borrow uint64
+	copy(res[:], t[:fp.Limbs])
+	for i := range diff {
+		diff[i], borrow = bits.Sub64(t[i], qConstantTime[i], borrow)
+	}
+	_, borrow = bits.Sub64(t[fp.Limbs], 0, borrow)
+	z.Select(int(borrow^1), &res, &diff)
+}
+
+// maddConstantTime returns hi, lo such that hi⋅2⁶⁴ + lo = a⋅b + c + d.
+func maddConstantTime(a, b, c, d uint64) (hi, lo uint64) {
+	var carry uint64
+	hi, lo = bits.Mul64(a, b)
+	lo, carry = bits.Add64(lo, c, 0)
+	hi += carry
+	lo, carry = bits.Add64(lo, d, 0)
+	hi += carry
+	return
+}
+
+// fpExpConstantTime sets z = xᵉ, the exponent e being public.
+func fpExpConstantTime(z, x *fp.Element, e *big.Int) {
+	var res fp.Element
+	res.SetOne()
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		fpMulConstantTime(&res, &res, &res)
+		if e.Bit(i) == 1 {
+			fpMulConstantTime(&res, &res, x)
+		}
+	}
+	z.Set(&res)
+}
+
+// scalarLimbsConstantTime returns the limbs of s mod r. The reduction is only
+// done, not in constant time, if s is not in [0, r).
+func scalarLimbsConstantTime(s *big.Int) (k [fr.Limbs]uint64) {
+	if s.Sign() < 0 || s.Cmp(fr.Modulus()) >= 0 {
+		s = new(big.Int).Mod(s, fr.Modulus())
+	}
+	var buf [fr.Limbs * 8]byte
+	s.FillBytes(buf[:])
+	for i := range k {
+		k[i] = binary.BigEndian.Uint64(buf[len(buf)-8*(i+1):])
+	}
+	return
+}
+
+// isZeroConstantTime returns 1 if all the limbs are 0, and 0 otherwise.
+func isZeroConstantTime(limbs []uint64) int {
+	var acc uint64
+	for _, l := range limbs {
+		acc |= l
+	}
+	return int(((acc | -acc) >> 63) ^ 1)
+}
+
+// pMinusTwo returns p-2, the exponent of the inversion by Fermat's little theorem
+func pMinusTwo() *big.Int {
+	res := fp.Modulus()
+	return res.Sub(res, big.NewInt(2))
+}
@@ -0,0 +1,234 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package bls24317
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/consensys/gnark-crypto/utils/testutils"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+func TestG1AffineScalarMultiplicationConstantTime(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	genScalar := GenFr()
+
+	properties.Property("[Jacobian] constant time scalar multiplication should match the scalar multiplication", prop.ForAll(
+		func(s, m fr.Element) bool {
+			var bs, bm big.Int
+			s.BigInt(&bs)
+			m.BigInt(&bm)
+
+			var q, expected, res G1Jac
+			q.ScalarMultiplication(&g1Gen, &bm)
+			expected.ScalarMultiplication(&q, &bs)
+			res.ScalarMultiplicationConstantTime(&q, &bs)
+			return res.Equal(&expected)
+		},
+		genScalar,
+		genScalar,
+	))
+
+	properties.Property("[Affine] constant time scalar multiplication should match the scalar multiplication", prop.ForAll(
+		func(s, m fr.Element) bool {
+			var bs, bm big.Int
+			s.BigInt(&bs)
+			m.BigInt(&bm)
+
+			var q, expected, res G1Affine
+			q.ScalarMultiplication(&g1GenAff, &bm)
+			expected.ScalarMultiplication(&q, &bs)
+			res.ScalarMultiplicationConstantTime(&q, &bs)
+			if !res.Equal(&expected) {
+				return false
+			}
+			expected.ScalarMultiplicationBase(&bs)
+			res.ScalarMultiplicationBaseConstantTime(&bs)
+			return res.Equal(&expected)
+		},
+		genScalar,
+		genScalar,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+
+	// edge cases
+	for _, s := range constantTimeTestScalarsG1() {
+		var expected, res G1Jac
+		var expectedAff, resAff G1Affine
+		expected.ScalarMultiplication(&g1Gen, s)
+		res.ScalarMultiplicationConstantTime(&g1Gen, s)
+		if !res.Equal(&expected) {
+			t.Fatalf("[Jacobian] mismatch for s=%s", s.String())
+		}
+		expectedAff.FromJacobian(&expected)
+		resAff.ScalarMultiplicationBaseConstantTime(s)
+		if !resAff.Equal(&expectedAff) {
+			t.Fatalf("[Affine] mismatch for s=%s", s.String())
+		}
+	}
+
+	// the infinity point
+	var res G1Jac
+	var inf G1Affine
+	res.ScalarMultiplicationConstantTime(&g1Infinity, big.NewInt(42))
+	if !res.Z.IsZero() {
+		t.Fatal("[Jacobian] expected the infinity point")
+	}
+	inf.ScalarMultiplicationConstantTime(&inf, big.NewInt(42))
+	if !inf.IsInfinity() {
+		t.Fatal("[Affine] expected the infinity point")
+	}
+}
+
+func TestRecodeConstantTime(t *testing.T) {
+	const w = windowSizeConstantTime
+	for _, s := range constantTimeTestScalarsG1() {
+		var e fr.Element
+		e.SetBigInt(s)
+		k := e.Bits()
+		if k[0]&1 == 0 {
+			continue
+		}
+		digits := recodeConstantTime(k)
+
+		// k = ∑ dᵢ2ʷⁱ with odd digits dᵢ ∈ [-(2ʷ-1), 2ʷ-1]
+		var sum, d big.Int
+		for i := len(digits) - 1; i >= 0; i-- {
+			if digits[i]&1 == 0 || digits[i] >= 1<<w || digits[i] <= -(1<<w) {
+				t.Fatalf("invalid digit %d for s=%s", digits[i], s.String())
+			}
+			sum.Lsh(&sum, w).Add(&sum, d.SetInt64(int64(digits[i])))
+		}
+		if digits[len(digits)-1] < 0 {
+			t.Fatalf("negative last digit for s=%s", s.String())
+		}
+		e.BigInt(&d)
+		if sum.Cmp(&d) != 0 {
+			t.Fatalf("wrong recoding for s=%s", s.String())
+		}
+	}
+}
+
+// constantTimeTestScalarsG1 returns scalars with a special form
+func constantTimeTestScalarsG1() []*big.Int {
+	r := fr.Modulus()
+	scalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		big.NewInt(2),
+		big.NewInt(3),
+		big.NewInt(-1),
+		new(big.Int).Sub(r, big.NewInt(1)),
+		new(big.Int).Sub(r, big.NewInt(2)),
+		new(big.Int).Set(r),
+		new(big.Int).Add(r, big.NewInt(3)),
+		new(big.Int).Lsh(big.NewInt(1), fr.Bits-1),
+		new(big.Int).Rsh(r, 1),
+	}
+	var e fr.Element
+	for i := 0; i < 4; i++ {
+		e.SetRandom()
+		scalars = append(scalars, e.BigInt(new(big.Int)))
+	}
+	return scalars
+}
+
+// TestG1AffineScalarMultiplicationConstantTimeTiming checks that the execution
+// time of the constant time scalar multiplication doesn't depend on the scalar,
+// by comparing the times with a fixed scalar and with random scalars (dudect).
+func TestG1AffineScalarMultiplicationConstantTimeTiming(t *testing.T) {
+	n := 2000
+	if testing.Short() {
+		n = 500
+	}
+	random := make([]big.Int, n)
+	for i := range random {
+		var e fr.Element
+		e.SetRandom()
+		e.BigInt(&random[i])
+	}
+	fixedScalars := []*big.Int{
+		big.NewInt(0),
+		big.NewInt(1),
+		new(big.Int).Sub(fr.Modulus(), big.NewInt(1)),
+	}
+
+	// the same measurements detect the leak of the variable time scalar
+	// multiplication, otherwise the environment is too noisy
+	var res G1Jac
+	scalars := timingTestScalarsG1(fixedScalars[1], random)
+	leak := testutils.TimingLeakage(n, func(class, i int) {
+		res.ScalarMultiplication(&g1Gen, &scalars[class][i])
+	})
+	if leak < 10 {
+		t.Skipf("the measurements are too noisy (t=%.2f for ScalarMultiplication)", leak)
+	}
+
+	for _, s := range fixedScalars {
+		scalars := timingTestScalarsG1(s, random)
+		leak := testutils.TimingLeakage(n, func(class, i int) {
+			res.ScalarMultiplicationConstantTime(&g1Gen, &scalars[class][i])
+		})
+		if leak > 10 {
+			t.Fatalf("the execution time depends on the scalar (t=%.2f for s=%s)", leak, s.String())
+		}
+	}
+}
+
+// timingTestScalarsG1 returns the inputs of the two classes of
+// a timing test: copies of fixed, and random.
+func timingTestScalarsG1(fixed *big.Int, random []big.Int) [2][]big.Int {
+	res := [2][]big.Int{make([]big.Int, len(random)), random}
+	for i := range res[0] {
+		res[0][i].Set(fixed)
+	}
+	return res
+}
+
+func BenchmarkG1JacScalarMultiplicationConstantTime(b *testing.B) {
+	var s fr.Element
+	s.SetRandom()
+	var bs big.Int
+	s.BigInt(&bs)
+
+	var res G1Jac
+	b.Run("ScalarMultiplication", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			res.ScalarMultiplication(&g1Gen, &bs)
+		}
+	})
+	b.Run("ScalarMultiplicationConstantTime", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			res.ScalarMultiplicationConstantTime(&g1Gen, &bs)
+		}
+	})
+}
@@ -0,0 +1,337 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"sync/atomic"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+
+	"github.com/consensys/gnark-crypto/internal/parallel"
+)
+
+var (
+	ErrSRSSizeMismatch    = errors.New("SRS sizes do not match")
+	ErrSRSNotWellFormed   = errors.New("SRS is not well formed")
+	ErrNotInSubgroup      = errors.New("point is not in the prime order subgroup")
+	ErrInvalidUpdateProof = errors.New("invalid update proof")
+	ErrNoContribution     = errors.New("transcript has no contribution")
+)
+
+// updateProofDST is the domain separation tag used to derive the challenge
+// point of an UpdateProof.
+const updateProofDST = "GNARK-CRYPTO-KZG-POWERS-OF-TAU-UPDATE"
+
+// UpdateProof is the public record of a contribution to a Powers-of-Tau
+// ceremony. It proves that the contributor multiplied the secret τ of the
+// previous SRS by a secret x they know, without revealing x.
+//
+// implements io.ReaderFrom and io.WriterTo
+type UpdateProof struct {
+	// Tau is [τ⋅x]₁, the first power of the updated SRS
+	Tau bls24317.G1Affine
+
+	// S, SX are [s]₁ and [s⋅x]₁ for a random s
+	S, SX bls24317.G1Affine
+
+	// RX is [x]R, where R ∈ G₂ is derived from S, SX and the previous and
+	// updated powers of τ
+	RX bls24317.G2Affine
+}
+
+// Transcript of a multi-party Powers-of-Tau ceremony.
+//
+// SRS is the current SRS; Contributions are the update proofs of all the
+// participants, in order. As long as one participant erased their
+// contribution x, no one knows the secret τ of the SRS.
+//
+// implements io.ReaderFrom and io.WriterTo
+type Transcript struct {
+	SRS           SRS
+	Contributions []UpdateProof
+}
+
+// NewTranscript initializes a Powers-of-Tau ceremony for an SRS of the given
+// size. The initial SRS corresponds to τ = 1 and must be updated through
+// Contribute before use.
+func NewTranscript(size uint64) (*Transcript, error) {
+	srs, err := NewSRS(size, big.NewInt(1))
+	if err != nil {
+		return nil, err
+	}
+	return &Transcript{SRS: *srs}, nil
+}
+
+// Contribute updates the SRS of the transcript with a secret drawn from
+// randomness, and appends the corresponding UpdateProof.
+//
+// The secret is not stored, it is the responsibility of the caller to make
+// sure randomness is not recoverable afterwards.
+func (t *Transcript) Contribute(randomness io.Reader) error {
+	srs, proof, err := Contribute(&t.SRS, randomness)
+	if err != nil {
+		return err
+	}
+	t.SRS = *srs
+	t.Contributions = append(t.Contributions, proof)
+	return nil
+}
+
+// Contribute returns a new SRS whose secret is τ⋅x, where τ is the secret of
+// prev and x is drawn from randomness, together with a proof of knowledge of
+// x. prev is not modified.
+func Contribute(prev *SRS, randomness io.Reader) (*SRS, UpdateProof, error) {
+	var proof UpdateProof
+	if len(prev.Pk.G1) < 2 {
+		return nil, proof, ErrMinSRSSize
+	}
+
+	x, err := randomNonZero(randomness)
+	if err != nil {
+		return nil, proof, err
+	}
+	s, err := randomNonZero(randomness)
+	if err != nil {
+		return nil, proof, err
+	}
+
+	// update the powers of τ: [τⁱ]₁ ← [(τ⋅x)ⁱ]₁
+	var next SRS
+	next.Pk.G1 = make([]bls24317.G1Affine, len(prev.Pk.G1))
+	parallel.Execute(len(prev.Pk.G1), func(start, end int) {
+		var xi big.Int
+		xi.Exp(x, big.NewInt(int64(start)), fr.Modulus())
+		buf := make([]bls24317.G1Jac, end-start)
+		for i := start; i < end; i++ {
+			buf[i-start].FromAffine(&prev.Pk.G1[i])
+			buf[i-start].ScalarMultiplication(&buf[i-start], &xi)
+			xi.Mul(&xi, x).Mod(&xi, fr.Modulus())
+		}
+		copy(next.Pk.G1[start:end], bls24317.BatchJacobianToAffineG1(buf))
+	})
+	next.Vk.G1 = prev.Vk.G1
+	next.Vk.G2[0] = prev.Vk.G2[0]
+	next.Vk.G2[1].ScalarMultiplication(&prev.Vk.G2[1], x)
+	next.Vk.Lines[0] = bls24317.PrecomputeLines(next.Vk.G2[0])
+	next.Vk.Lines[1] = bls24317.PrecomputeLines(next.Vk.G2[1])
+
+	// proof of knowledge of x
+	proof.Tau = next.Pk.G1[1]
+	proof.S.ScalarMultiplicationBase(s)
+	proof.SX.ScalarMultiplication(&proof.S, x)
+	r, err := updateChallenge(&prev.Pk.G1[1], &proof)
+	if err != nil {
+		return nil, proof, err
+	}
+	proof.RX.ScalarMultiplication(&r, x)
+
+	return &next, proof, nil
+}
+
+// VerifyContribution checks that next is a well formed SRS obtained by
+// updating prev with the secret whose knowledge is proven by proof.
+func VerifyContribution(prev, next *SRS, proof *UpdateProof) error {
+	if len(prev.Pk.G1) != len(next.Pk.G1) {
+		return ErrSRSSizeMismatch
+	}
+	if len(prev.Pk.G1) < 2 {
+		return ErrMinSRSSize
+	}
+	if !prev.Vk.G2[0].Equal(&next.Vk.G2[0]) || !prev.Pk.G1[0].Equal(&next.Pk.G1[0]) {
+		return ErrSRSNotWellFormed
+	}
+	if !proof.Tau.Equal(&next.Pk.G1[1]) {
+		return ErrInvalidUpdateProof
+	}
+	if err := verifyUpdateProof(&prev.Pk.G1[1], proof); err != nil {
+		return err
+	}
+	return verifySRS(next)
+}
+
+// VerifyTranscript checks that every contribution of the transcript is a
+// valid update of the previous one, starting from τ = 1, and that the SRS of
+// the transcript is well formed and corresponds to the last contribution.
+func VerifyTranscript(t *Transcript) error {
+	if len(t.Contributions) == 0 {
+		return ErrNoContribution
+	}
+	if len(t.SRS.Pk.G1) < 2 {
+		return ErrMinSRSSize
+	}
+
+	_, _, prev, _ := bls24317.Generators()
+	for i := range t.Contributions {
+		if err := verifyUpdateProof(&prev, &t.Contributions[i]); err != nil {
+			return err
+		}
+		prev = t.Contributions[i].Tau
+	}
+	if !prev.Equal(&t.SRS.Pk.G1[1]) {
+		return ErrInvalidUpdateProof
+	}
+
+	return verifySRS(&t.SRS)
+}
+
+// verifyUpdateProof checks the proof of knowledge of x such that
+// proof.Tau = [x]prev.
+func verifyUpdateProof(prev *bls24317.G1Affine, proof *UpdateProof) error {
+	if proof.Tau.IsInfinity() || proof.S.IsInfinity() || proof.SX.IsInfinity() {
+		return ErrInvalidUpdateProof
+	}
+	if !proof.Tau.IsInSubGroup() || !proof.S.IsInSubGroup() || !proof.SX.IsInSubGroup() || !proof.RX.IsInSubGroup() {
+		return ErrNotInSubgroup
+	}
+	r, err := updateChallenge(prev, proof)
+	if err != nil {
+		return err
+	}
+
+	// e(SX, R) = e(S, RX), i.e. SX = [x]S
+	// e(Tau, R) = e(prev, RX), i.e. Tau = [x]prev
+	var negSX, negTau bls24317.G1Affine
+	negSX.Neg(&proof.SX)
+	negTau.Neg(&proof.Tau)
+	ok, err := bls24317.PairingCheck(
+		[]bls24317.G1Affine{negSX, proof.S},
+		[]bls24317.G2Affine{r, proof.RX},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidUpdateProof
+	}
+	ok, err = bls24317.PairingCheck(
+		[]bls24317.G1Affine{negTau, *prev},
+		[]bls24317.G2Affine{r, proof.RX},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrInvalidUpdateProof
+	}
+	return nil
+}
+
+// verifySRS checks that srs is of the form ([τⁱ]₁, [1]₂, [τ]₂) for some τ ≠ 0,
+// with all points in the prime order subgroup.
+func verifySRS(srs *SRS) error {
+	n := len(srs.Pk.G1)
+	_, _, g1, g2 := bls24317.Generators()
+	if !srs.Pk.G1[0].Equal(&g1) || !srs.Vk.G1.Equal(&g1) || !srs.Vk.G2[0].Equal(&g2) {
+		return ErrSRSNotWellFormed
+	}
+	if srs.Pk.G1[1].IsInfinity() || srs.Vk.G2[1].IsInfinity() {
+		return ErrSRSNotWellFormed
+	}
+
+	// subgroup checks
+	if !srs.Vk.G2[1].IsInSubGroup() {
+		return ErrNotInSubgroup
+	}
+	var notInSubgroup uint32
+	parallel.Execute(n, func(start, end int) {
+		for i := start; i < end; i++ {
+			if !srs.Pk.G1[i].IsInSubGroup() {
+				atomic.StoreUint32(&notInSubgroup, 1)
+				return
+			}
+		}
+	})
+	if notInSubgroup != 0 {
+		return ErrNotInSubgroup
+	}
+
+	// consistency of the powers of τ:
+	// e(∑ᵢ ρⁱ[τⁱ]₁, [τ]₂) = e(∑ᵢ ρⁱ[τⁱ⁺¹]₁, [1]₂) for a random ρ
+	// e([τ]₁, [1]₂) = e([1]₁, [τ]₂)
+	var rho fr.Element
+	if _, err := rho.SetRandom(); err != nil {
+		return err
+	}
+	rhos := make([]fr.Element, n-1)
+	rhos[0].SetOne()
+	for i := 1; i < len(rhos); i++ {
+		rhos[i].Mul(&rhos[i-1], &rho)
+	}
+	var left, right bls24317.G1Affine
+	config := ecc.MultiExpConfig{}
+	if _, err := left.MultiExp(srs.Pk.G1[:n-1], rhos, config); err != nil {
+		return err
+	}
+	if _, err := right.MultiExp(srs.Pk.G1[1:], rhos, config); err != nil {
+		return err
+	}
+	right.Neg(&right)
+	ok, err := bls24317.PairingCheck(
+		[]bls24317.G1Affine{left, right},
+		[]bls24317.G2Affine{srs.Vk.G2[1], srs.Vk.G2[0]},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSRSNotWellFormed
+	}
+	var negG1 bls24317.G1Affine
+	negG1.Neg(&g1)
+	ok, err = bls24317.PairingCheck(
+		[]bls24317.G1Affine{srs.Pk.G1[1], negG1},
+		[]bls24317.G2Affine{srs.Vk.G2[0], srs.Vk.G2[1]},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSRSNotWellFormed
+	}
+	return nil
+}
+
+// updateChallenge returns R = HashToG2(prev ∥ Tau ∥ S ∥ SX), which binds the
+// proof of knowledge to the update it certifies.
+func updateChallenge(prev *bls24317.G1Affine, proof *UpdateProof) (bls24317.G2Affine, error) {
+	buf := make([]byte, 0, 4*bls24317.SizeOfG1AffineCompressed)
+	for _, p := range []*bls24317.G1Affine{prev, &proof.Tau, &proof.S, &proof.SX} {
+		b := p.Bytes()
+		buf = append(buf, b[:]...)
+	}
+	return bls24317.HashToG2(buf, []byte(updateProofDST))
+}
+
+// randomNonZero returns a uniformly distributed non-zero scalar read from r.
+func randomNonZero(r io.Reader) (*big.Int, error) {
+	buf := make([]byte, fr.Bytes+16)
+	for {
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		x := new(big.Int).SetBytes(buf)
+		x.Mod(x, fr.Modulus())
+		if x.Sign() != 0 {
+			return x, nil
+		}
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bls24-317"
+	"github.com/consensys/gnark-crypto/ecc/bls24-317/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCeremony(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 16
+	transcript, err := NewTranscript(size)
+	assert.NoError(err)
+	assert.ErrorIs(VerifyTranscript(transcript), ErrNoContribution)
+
+	// three participants, checking each contribution against the previous SRS
+	const nbContributions = 3
+	for i := 0; i < nbContributions; i++ {
+		prev := transcript.SRS
+		assert.NoError(transcript.Contribute(rand.Reader))
+		assert.NoError(VerifyContribution(&prev, &transcript.SRS, &transcript.Contributions[i]))
+	}
+	assert.NoError(VerifyTranscript(transcript))
+
+	// the resulting SRS is usable
+	p := make([]fr.Element, size)
+	for i := range p {
+		p[i].SetRandom()
+	}
+	digest, err := Commit(p, transcript.SRS.Pk)
+	assert.NoError(err)
+	var point fr.Element
+	point.SetRandom()
+	proof, err := Open(p, point, transcript.SRS.Pk)
+	assert.NoError(err)
+	assert.NoError(Verify(&digest, &proof, point, transcript.SRS.Vk))
+
+	// serialization round trip
+	var buf bytes.Buffer
+	written, err := transcript.WriteTo(&buf)
+	assert.NoError(err)
+	var decoded Transcript
+	read, err := decoded.ReadFrom(&buf)
+	assert.NoError(err)
+	assert.Equal(written, read)
+	assert.Equal(nbContributions, len(decoded.Contributions))
+	assert.NoError(VerifyTranscript(&decoded))
+
+	// a truncated transcript announcing 2³²-1 contributions is rejected
+	// without allocating them upfront
+	buf.Reset()
+	_, err = transcript.SRS.WriteTo(&buf)
+	assert.NoError(err)
+	buf.Write([]byte{0xff, 0xff, 0xff, 0xff})
+	_, err = decoded.ReadFrom(&buf)
+	assert.Error(err)
+	assert.Empty(decoded.Contributions)
+}
+
+func TestCeremonyInvalid(t *testing.T) {
+	assert := require.New(t)
+
+	const size = 8
+	transcript, err := NewTranscript(size)
+	assert.NoError(err)
+	first := transcript.SRS
+	assert.NoError(transcript.Contribute(rand.Reader))
+	second := transcript.SRS
+	assert.NoError(transcript.Contribute(rand.Reader))
+
+	// tampered power of τ
+	tampered := second
+	tampered.Pk.G1 = make([]bls24317.G1Affine, size)
+	copy(tampered.Pk.G1, second.Pk.G1)
+	tampered.Pk.G1[3].Add(&tampered.Pk.G1[3], &tampered.Pk.G1[0])
+	assert.ErrorIs(VerifyContribution(&first, &tampered, &transcript.Contributions[0]), ErrSRSNotWellFormed)
+
+	// tampered [τ]₂
+	tampered = second
+	tampered.Vk.G2[1].Add(&tampered.Vk.G2[1], &tampered.Vk.G2[0])
+	assert.ErrorIs(VerifyContribution(&first, &tampered, &transcript.Contributions[0]), ErrSRSNotWellFormed)
+
+	// proof of another contribution
+	assert.ErrorIs(VerifyContribution(&first, &second, &transcript.Contributions[1]), ErrInvalidUpdateProof)
+
+	// update with an unknown secret: the proof of the first contribution is
+	// replayed on top of an SRS it does not belong to
+	forged, _, err := Contribute(&first, rand.Reader)
+	assert.NoError(err)
+	proof := transcript.Contributions[0]
+	proof.Tau = forged.Pk.G1[1]
+	assert.ErrorIs(VerifyContribution(&first, forged, &proof), ErrInvalidUpdateProof)
+
+	// reordered contributions
+	transcript.Contributions[0], transcript.Contributions[1] = transcript.Contributions[1], transcript.Contributions[0]
+	assert.ErrorIs(VerifyTranscript(transcript), ErrInvalidUpdateProof)
+}
+
+func BenchmarkContribute(b *testing.B) {
+	const size = 1 << 10
+	transcript, err := NewTranscript(size)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err = Contribute(&transcript.SRS, rand.Reader); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyContribution(b *testing.B) {
+	const size = 1 << 10
+	transcript, err := NewTranscript(size)
+	if err != nil {
+		b.Fatal(err)
+	}
+	prev := transcript.SRS
+	if err = transcript.Contribute(rand.Reader); err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err = VerifyContribution(&prev, &transcript.SRS, &transcript.Contributions[0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
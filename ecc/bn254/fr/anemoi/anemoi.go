@@ -0,0 +1,295 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package anemoi
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+var (
+	ErrInvalidSizebuffer = errors.New("the size of the input should match the size of the hash buffer")
+)
+
+const (
+	// generator is the multiplicative generator g of fr, which is the constant β
+	// of the Flystel
+	generator = 5
+
+	// sBoxDegree is the degree α of the s-box x ↦ xᵅ of the Flystel
+	sBoxDegree = 5
+
+	// DefaultNbCols is the default number of columns ℓ, the state having 2ℓ elements
+	DefaultNbCols = 2
+
+	// DefaultNbRounds is the number of rounds ensuring 128 bits of security for
+	// DefaultNbCols columns
+	DefaultNbRounds = 14
+)
+
+// NbRounds returns the number of rounds ensuring 128 bits of security for
+// α = 5 and nbCols columns, following the Anemoi specification.
+func NbRounds(nbCols int) int {
+	switch nbCols {
+	case 1:
+		return 21
+	case 2:
+		return 14
+	default:
+		panic("anemoi: only 1 or 2 columns are supported")
+	}
+}
+
+// the digits of π used to derive the round constants
+const (
+	pi0 = "1415926535897932384626433832795028841971693993751058209749445923078164062862089986280348253421170679"
+	pi1 = "8214808651328230664709384460955058223172535940812848111745028410270193852110555964462294895493038196"
+)
+
+var (
+	// sBoxInvExponent is α⁻¹ (mod r-1), so that x ↦ x^(α⁻¹) is the inverse s-box
+	sBoxInvExponent big.Int
+
+	// beta is g, delta is g⁻¹, the constants of the Flystel
+	beta, delta fr.Element
+
+	initOnce sync.Once
+)
+
+func initConstants() {
+	sBoxInvExponent.SetString("17510594297471420177797124596205820070838691520332827474958563349260646796493", 10)
+	beta.SetUint64(generator)
+	delta.Inverse(&beta)
+}
+
+// Parameters describe parameters of the Anemoi permutation
+type Parameters struct {
+	// number of columns ℓ, the state being made of 2ℓ elements
+	NbCols int
+
+	// number of rounds
+	NbRounds int
+
+	// round constants: C[r] are added to the first ℓ elements of the state,
+	// D[r] to the last ℓ elements
+	C, D [][]fr.Element
+}
+
+// NewParameters returns a new set of parameters for the Anemoi permutation
+// with nbCols columns and nbRounds rounds. The round constants are derived
+// from the digits of π as in the Anemoi specification:
+//
+//	C[r][i] = g·π₀²ʳ + (π₀ʳ + π₁ⁱ)ᵅ
+//	D[r][i] = g·π₁²ⁱ + (π₀ʳ + π₁ⁱ)ᵅ + g⁻¹
+//
+// It panics if nbCols is not 1 or 2.
+func NewParameters(nbCols, nbRounds int) *Parameters {
+	if nbCols != 1 && nbCols != 2 {
+		panic("anemoi: only 1 or 2 columns are supported")
+	}
+	initOnce.Do(initConstants)
+	p := Parameters{NbCols: nbCols, NbRounds: nbRounds}
+	p.initRC()
+	return &p
+}
+
+// String returns a description of the parameters, e.g. "Anemoi-bn254[ℓ=2,N=14,α=5]"
+func (p *Parameters) String() string {
+	return fmt.Sprintf("Anemoi-bn254[ℓ=%d,N=%d,α=%d]", p.NbCols, p.NbRounds, sBoxDegree)
+}
+
+func (p *Parameters) initRC() {
+	var bPi0, bPi1 big.Int
+	bPi0.SetString(pi0, 10)
+	bPi1.SetString(pi1, 10)
+	var piZero, piOne fr.Element
+	piZero.SetBigInt(&bPi0)
+	piOne.SetBigInt(&bPi1)
+
+	// π₁ⁱ for i < ℓ
+	pow1 := make([]fr.Element, p.NbCols)
+	pow1[0].SetOne()
+	for i := 1; i < p.NbCols; i++ {
+		pow1[i].Mul(&pow1[i-1], &piOne)
+	}
+
+	p.C = make([][]fr.Element, p.NbRounds)
+	p.D = make([][]fr.Element, p.NbRounds)
+	var pow0, sum, tmp fr.Element
+	pow0.SetOne()
+	for r := 0; r < p.NbRounds; r++ {
+		p.C[r] = make([]fr.Element, p.NbCols)
+		p.D[r] = make([]fr.Element, p.NbCols)
+		for i := 0; i < p.NbCols; i++ {
+			sum.Add(&pow0, &pow1[i])
+			sBox(&sum)
+
+			tmp.Square(&pow0).Mul(&tmp, &beta)
+			p.C[r][i].Add(&tmp, &sum)
+
+			tmp.Square(&pow1[i]).Mul(&tmp, &beta)
+			p.D[r][i].Add(&tmp, &sum).Add(&p.D[r][i], &delta)
+		}
+		pow0.Mul(&pow0, &piZero)
+	}
+}
+
+// Permutation provides the Anemoi permutation
+type Permutation struct {
+	// parameters describing the instance
+	params *Parameters
+}
+
+// NewPermutation returns a new Anemoi permutation instance with nbCols
+// columns and nbRounds rounds.
+// It panics if nbCols is not 1 or 2.
+func NewPermutation(nbCols, nbRounds int) *Permutation {
+	return &Permutation{params: NewParameters(nbCols, nbRounds)}
+}
+
+// NewPermutationWithParameters returns a new Anemoi permutation instance
+// using the provided parameters.
+func NewPermutationWithParameters(params *Parameters) *Permutation {
+	return &Permutation{params: params}
+}
+
+// Parameters returns the parameters of the permutation.
+func (h *Permutation) Parameters() *Parameters {
+	return h.params
+}
+
+// sBox sets x to xᵅ
+func sBox(x *fr.Element) {
+	var x2 fr.Element
+	x2.Square(x)
+	x2.Square(&x2)
+	x.Mul(x, &x2)
+}
+
+// sBoxInv sets x to x^(α⁻¹)
+func sBoxInv(x *fr.Element) {
+	x.Exp(*x, &sBoxInvExponent)
+}
+
+// flystel applies the open Flystel H on (x, y):
+//
+//	x ← x - g·y²
+//	y ← y - x^(1/α)
+//	x ← x + g·y² + g⁻¹
+func flystel(x, y *fr.Element) {
+	var t fr.Element
+	t.Square(y).Mul(&t, &beta)
+	x.Sub(x, &t)
+
+	t.Set(x)
+	sBoxInv(&t)
+	y.Sub(y, &t)
+
+	t.Square(y).Mul(&t, &beta).Add(&t, &delta)
+	x.Add(x, &t)
+}
+
+// mds applies the matrix M = [[1, g], [g, g²+1]] on (x₀, x₁) when ℓ = 2, and
+// the identity when ℓ = 1.
+func mds(x0, x1 *fr.Element) {
+	var t fr.Element
+	t.Mul(x1, &beta)
+	x0.Add(x0, &t)
+	t.Mul(x0, &beta)
+	x1.Add(x1, &t)
+}
+
+// linearLayer applies the linear layer on the state (X, Y): X ← M·X,
+// Y ← M·ρ(Y) where ρ rotates the entries by one to the left, followed by the
+// Pseudo-Hadamard transform Y ← Y + X, X ← X + Y.
+func (h *Permutation) linearLayer(x, y []fr.Element) {
+	if h.params.NbCols == 2 {
+		mds(&x[0], &x[1])
+		mds(&y[1], &y[0])
+		y[0], y[1] = y[1], y[0]
+	}
+	for i := range x {
+		y[i].Add(&y[i], &x[i])
+		x[i].Add(&x[i], &y[i])
+	}
+}
+
+// Permutation applies the permutation on input, and stores the result in
+// input. The input is the state (X, Y) of 2ℓ elements.
+func (h *Permutation) Permutation(input []fr.Element) error {
+	l := h.params.NbCols
+	if len(input) != 2*l {
+		return ErrInvalidSizebuffer
+	}
+	initOnce.Do(initConstants)
+	x, y := input[:l], input[l:]
+
+	for r := 0; r < h.params.NbRounds; r++ {
+		for i := 0; i < l; i++ {
+			x[i].Add(&x[i], &h.params.C[r][i])
+			y[i].Add(&y[i], &h.params.D[r][i])
+		}
+		h.linearLayer(x, y)
+		for i := 0; i < l; i++ {
+			flystel(&x[i], &y[i])
+		}
+	}
+	h.linearLayer(x, y)
+
+	return nil
+}
+
+// Compress is used in BinaryMerkleTree. The inputs are the big endian
+// encodings of ℓ field elements each, forming the state (X, Y) = (left, right).
+// The output is the encoding of the ℓ elements of the Jive compression:
+//
+//	Xᵢ + Yᵢ + P(X, Y)ᵢ + P(X, Y)ᵢ₊ₗ
+func (h *Permutation) Compress(left []byte, right []byte) ([]byte, error) {
+	l := h.params.NbCols
+	if len(left) != l*fr.Bytes || len(right) != l*fr.Bytes {
+		return nil, ErrInvalidSizebuffer
+	}
+	state := make([]fr.Element, 2*l)
+	for i := 0; i < l; i++ {
+		if err := state[i].SetBytesCanonical(left[i*fr.Bytes : (i+1)*fr.Bytes]); err != nil {
+			return nil, err
+		}
+		if err := state[l+i].SetBytesCanonical(right[i*fr.Bytes : (i+1)*fr.Bytes]); err != nil {
+			return nil, err
+		}
+	}
+	in := make([]fr.Element, 2*l)
+	copy(in, state)
+	if err := h.Permutation(state); err != nil {
+		return nil, err
+	}
+
+	res := make([]byte, 0, l*fr.Bytes)
+	for i := 0; i < l; i++ {
+		var s fr.Element
+		s.Add(&in[i], &in[l+i]).
+			Add(&s, &state[i]).
+			Add(&s, &state[l+i])
+		b := s.Bytes()
+		res = append(res, b[:]...)
+	}
+	return res, nil
+}
@@ -0,0 +1,249 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package anemoi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParameters(t *testing.T) {
+	assert := require.New(t)
+
+	for _, nbCols := range []int{1, 2} {
+		params := NewParameters(nbCols, NbRounds(nbCols))
+		assert.Equal(NbRounds(nbCols), len(params.C))
+		assert.Equal(NbRounds(nbCols), len(params.D))
+		for r := range params.C {
+			assert.Equal(nbCols, len(params.C[r]))
+			assert.Equal(nbCols, len(params.D[r]))
+		}
+	}
+
+	// C[0][0] = g + 2ᵅ, D[0][0] = g + 2ᵅ + g⁻¹
+	params := NewParameters(1, 1)
+	var c, d fr.Element
+	c.SetUint64(generator + 1<<sBoxDegree)
+	d.SetUint64(generator).Inverse(&d).Add(&d, &c)
+	assert.True(c.Equal(&params.C[0][0]))
+	assert.True(d.Equal(&params.D[0][0]))
+
+	assert.Panics(func() { NewParameters(3, DefaultNbRounds) })
+}
+
+// TestSpecificationVectors checks the permutation and the Jive compression,
+// with the number of rounds of the specification, against vectors computed
+// with an independent implementation of https://eprint.iacr.org/2022/840
+func TestSpecificationVectors(t *testing.T) {
+	assert := require.New(t)
+
+	for _, v := range []struct {
+		nbCols      int
+		permutation []string
+		jive        []string
+	}{
+		{
+			nbCols: 1,
+			permutation: []string{
+				"0x0808e3921fc7a9cc2158eab2c805f80d33ff254237fe6b2ce06f83572b833eab",
+				"0x0107063a755b95efa530e745b35b8fbcce2a26d3b92bb12ee2c34b3a92719d01",
+			},
+			jive: []string{
+				"0x090fe9cc95233fbbc689d1f87b6187ca02294c15f12a1c5bc332ce91bdf4dbad",
+			},
+		},
+		{
+			nbCols: 2,
+			permutation: []string{
+				"0x2cb43c79daf0f8fb5e76e76711d860311b0926ffe297b8315c87710eb31864d9",
+				"0x1c01ee71abcbc1adeb777fdd5fcb24fd4e2293d9eb632a54ec63721f381bd2ad",
+				"0x1acd84307c0d7207d8866dbe05090f8a3fa0cde918a2985e92f27820317d652d",
+				"0x1057e76e5f1f4890261614f8f471240616d8c6a1245bff093d36b10f1161dfb3",
+			},
+			jive: []string{
+				"0x171d723775cccad97ead0f6e9560175e32760ca08180dffeab97f39af495ca07",
+				"0x2c59d5e00aeb0a3e118d94d6543c490364fb5a7b0fbf295e299a232e497db264",
+			},
+		},
+	} {
+		h := NewPermutation(v.nbCols, NbRounds(v.nbCols))
+
+		// input (0, 1, .., 2ℓ-1)
+		input := make([]fr.Element, 2*v.nbCols)
+		left := make([]byte, 0, v.nbCols*fr.Bytes)
+		right := make([]byte, 0, v.nbCols*fr.Bytes)
+		for i := range input {
+			input[i].SetUint64(uint64(i))
+			b := input[i].Bytes()
+			if i < v.nbCols {
+				left = append(left, b[:]...)
+			} else {
+				right = append(right, b[:]...)
+			}
+		}
+
+		assert.NoError(h.Permutation(input))
+		for i := range input {
+			var expected fr.Element
+			_, err := expected.SetString(v.permutation[i])
+			assert.NoError(err)
+			assert.True(expected.Equal(&input[i]), "permutation mismatch at index %d, ℓ = %d", i, v.nbCols)
+		}
+
+		res, err := h.Compress(left, right)
+		assert.NoError(err)
+		for i := 0; i < v.nbCols; i++ {
+			var expected fr.Element
+			_, err = expected.SetString(v.jive[i])
+			assert.NoError(err)
+			b := expected.Bytes()
+			assert.Equal(b[:], res[i*fr.Bytes:(i+1)*fr.Bytes], "jive mismatch at index %d, ℓ = %d", i, v.nbCols)
+		}
+	}
+}
+
+func TestFlystel(t *testing.T) {
+	assert := require.New(t)
+
+	// α⁻¹ is the inverse of α modulo r-1
+	initOnce.Do(initConstants)
+	var rMinusOne, prod big.Int
+	rMinusOne.Sub(fr.Modulus(), big.NewInt(1))
+	prod.Mul(&sBoxInvExponent, big.NewInt(sBoxDegree)).Mod(&prod, &rMinusOne)
+	assert.Equal(int64(1), prod.Int64())
+
+	// the open Flystel is a permutation, whose inverse is given by the
+	// closed Flystel
+	var x, y, u, v, t0 fr.Element
+	for i := 0; i < 10; i++ {
+		x.SetRandom()
+		y.SetRandom()
+		u.Set(&x)
+		v.Set(&y)
+		flystel(&u, &v)
+
+		// u - g·v² - g⁻¹ = (y - v)ᵅ
+		t0.Sub(&y, &v)
+		sBox(&t0)
+		var w fr.Element
+		w.Square(&v).Mul(&w, &beta).Add(&w, &delta)
+		w.Sub(&u, &w)
+		assert.True(w.Equal(&t0))
+
+		// x = u - g·v² - g⁻¹ + g·y²
+		w.Square(&y).Mul(&w, &beta).Add(&w, &t0)
+		assert.True(w.Equal(&x))
+	}
+}
+
+func TestPermutation(t *testing.T) {
+	assert := require.New(t)
+
+	for _, nbCols := range []int{1, 2} {
+		h := NewPermutation(nbCols, NbRounds(nbCols))
+		assert.ErrorIs(h.Permutation(make([]fr.Element, 2*nbCols+1)), ErrInvalidSizebuffer)
+
+		a := make([]fr.Element, 2*nbCols)
+		for i := range a {
+			a[i].SetRandom()
+		}
+		b := make([]fr.Element, len(a))
+		copy(b, a)
+		assert.NoError(h.Permutation(a))
+		assert.NotEqual(a, b)
+
+		// deterministic
+		assert.NoError(h.Permutation(b))
+		assert.Equal(a, b)
+	}
+}
+
+func TestCompress(t *testing.T) {
+	assert := require.New(t)
+
+	h := NewPermutation(1, NbRounds(1))
+	var x, y fr.Element
+	x.SetRandom()
+	y.SetRandom()
+	bx, by := x.Bytes(), y.Bytes()
+
+	xy, err := h.Compress(bx[:], by[:])
+	assert.NoError(err)
+	assert.Equal(fr.Bytes, len(xy))
+	yx, err := h.Compress(by[:], bx[:])
+	assert.NoError(err)
+	assert.NotEqual(xy, yx)
+
+	_, err = h.Compress(bx[1:], by[:])
+	assert.ErrorIs(err, ErrInvalidSizebuffer)
+
+	// with 2 columns, the inputs and the output are made of 2 elements
+	h = NewPermutation(2, NbRounds(2))
+	xy, err = h.Compress(append(bx[:], by[:]...), append(by[:], bx[:]...))
+	assert.NoError(err)
+	assert.Equal(2*fr.Bytes, len(xy))
+	_, err = h.Compress(bx[:], by[:])
+	assert.ErrorIs(err, ErrInvalidSizebuffer)
+}
+
+func TestHash(t *testing.T) {
+	assert := require.New(t)
+
+	var x fr.Element
+	x.SetRandom()
+	bx := x.Bytes()
+	var zero [fr.Bytes]byte
+
+	h := NewAnemoi()
+	_, err := h.Write(bx[:])
+	assert.NoError(err)
+	d1 := h.Sum(nil)
+	assert.Equal(h.Size(), len(d1))
+
+	// Sum doesn't change the state
+	assert.Equal(d1, h.Sum(nil))
+
+	// trailing zeroes are not ignored thanks to the padding
+	_, err = h.Write(zero[:])
+	assert.NoError(err)
+	assert.NotEqual(d1, h.Sum(nil))
+
+	h.Reset()
+	_, err = h.Write(bx[:])
+	assert.NoError(err)
+	assert.Equal(d1, h.Sum(nil))
+
+	// invalid input
+	h.Reset()
+	_, err = h.Write(append(bx[:], 1))
+	assert.Error(err)
+}
+
+func BenchmarkPermutation(b *testing.B) {
+	h := NewPermutation(DefaultNbCols, DefaultNbRounds)
+	var tmp [2 * DefaultNbCols]fr.Element
+	for i := range tmp {
+		tmp[i].SetRandom()
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = h.Permutation(tmp[:])
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package anemoi implements the Anemoi permutation
+//
+// Anemoi is a family of arithmetization oriented permutations, see the
+// [original paper] by Bouvier, Briaud, Chaidos, Perrin, Salen, Velichkov and
+// Willems for the full details. The state is made of 2ℓ elements (X, Y), and
+// each round adds round constants, applies a linear layer mixing X and Y, and
+// then the open Flystel s-box on each pair (xᵢ, yᵢ), built from the
+// multiplicative generator g = 5 of fr and the permutation x ↦ x^5.
+//
+// The round constants are derived from the digits of π, and the numbers of
+// rounds ensuring 128 bits of security are the ones of the specification, for
+// ℓ = 1 and ℓ = 2.
+//
+// The package provides:
+//   - the permutation itself (see [NewPermutation] and [NewParameters]);
+//   - the Jive compression function for Merkle trees (see [Permutation.Compress]);
+//   - a sponge construction implementing [hash.Hash] (see [NewAnemoi]).
+//
+// # Hash input format
+//
+// As for MiMC, the sponge absorbs field elements. The input byte slice is
+// interpreted as a sequence of big endian encoded field elements, each of them
+// strictly less than the field modulus.
+//
+// [original paper]: https://eprint.iacr.org/2022/840.pdf
+package anemoi
@@ -0,0 +1,598 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package kzg
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/bn254"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fp"
+	"github.com/consensys/gnark-crypto/ecc/bn254/fr"
+)
+
+// The .ptau file format is the one of snarkjs Powers-of-Tau ceremonies
+// (see https://github.com/iden3/snarkjs/blob/master/src/powersoftau_new.js):
+//
+//	magic "ptau" ∥ version (uint32) ∥ nbSections (uint32) ∥ sections
+//
+// where each section is
+//
+//	id (uint32) ∥ size (uint64) ∥ data
+//
+// All integers are little-endian, field elements are little-endian in
+// Montgomery form and points are affine (x ∥ y), infinity being encoded as
+// zeros. For a ceremony of power p, the sections are the header (1), the
+// 2ᵖ⁺¹-1 points [τⁱ]₁ (2), the 2ᵖ points [τⁱ]₂ (3), [ατⁱ]₁ (4), [βτⁱ]₁ (5),
+// [β]₂ (6) and the contributions (7), possibly followed by the Lagrange forms
+// of a prepared phase 2, which are skipped.
+const (
+	ptauMagic                = "ptau"
+	ptauVersion              = 1
+	ptauNbSections           = 7
+	ptauSectionHeader        = 1
+	ptauSectionTauG1         = 2
+	ptauSectionTauG2         = 3
+	ptauSectionAlphaTauG1    = 4
+	ptauSectionBetaTauG1     = 5
+	ptauSectionBetaG2        = 6
+	ptauSectionContributions = 7
+
+	ptauSizeG1 = 2 * fp.Bytes
+	ptauSizeG2 = 4 * fp.Bytes
+
+	// ptauMaxPower is the largest power accepted in a header
+	ptauMaxPower = 30
+
+	// ptauChunk is the number of points allocated upfront when reading a
+	// section, the slice growing as the points are read
+	ptauChunk = 1 << 16
+)
+
+var ErrInvalidPtau = errors.New("invalid ptau file")
+
+// Ptau holds the sections of a .ptau file that snarkjs reads to set up a
+// circuit, for a ceremony of power p:
+//
+//	TauG1       [τⁱ]₁ for i < 2ᵖ⁺¹-1
+//	TauG2       [τⁱ]₂ for i < 2ᵖ
+//	AlphaTauG1  [ατⁱ]₁ for i < 2ᵖ
+//	BetaTauG1   [βτⁱ]₁ for i < 2ᵖ
+//	BetaG2      [β]₂
+//
+// A KZG SRS only needs the powers [τⁱ]₁, [1]₂ and [τ]₂, which SRS.ReadPtau
+// reads without loading the other sections.
+type Ptau struct {
+	// Power is the power p of the file, and CeremonyPower the power of the
+	// ceremony it comes from, at least p.
+	Power, CeremonyPower uint32
+
+	TauG1      []bn254.G1Affine
+	TauG2      []bn254.G2Affine
+	AlphaTauG1 []bn254.G1Affine
+	BetaTauG1  []bn254.G1Affine
+	BetaG2     bn254.G2Affine
+}
+
+// NewPtau returns the Ptau of the given power for the secrets τ, α and β.
+//
+// In production, a Ptau generated through MPC should be used.
+func NewPtau(power uint32, tau, alpha, beta *big.Int) (*Ptau, error) {
+	if power < 1 || power > ptauMaxPower {
+		return nil, ErrInvalidPtau
+	}
+	var t, a, b fr.Element
+	t.SetBigInt(tau)
+	a.SetBigInt(alpha)
+	b.SetBigInt(beta)
+
+	n := 1 << power
+	taus := make([]fr.Element, 2*n-1)
+	taus[0].SetOne()
+	for i := 1; i < len(taus); i++ {
+		taus[i].Mul(&taus[i-1], &t)
+	}
+	alphaTaus := make([]fr.Element, n)
+	betaTaus := make([]fr.Element, n)
+	for i := 0; i < n; i++ {
+		alphaTaus[i].Mul(&taus[i], &a)
+		betaTaus[i].Mul(&taus[i], &b)
+	}
+
+	_, _, g1, g2 := bn254.Generators()
+	p := Ptau{Power: power, CeremonyPower: power}
+	p.TauG1 = bn254.BatchScalarMultiplicationG1(&g1, taus)
+	p.TauG2 = bn254.BatchScalarMultiplicationG2(&g2, taus[:n])
+	p.AlphaTauG1 = bn254.BatchScalarMultiplicationG1(&g1, alphaTaus)
+	p.BetaTauG1 = bn254.BatchScalarMultiplicationG1(&g1, betaTaus)
+	p.BetaG2.ScalarMultiplication(&g2, beta)
+	return &p, nil
+}
+
+// WritePtau writes p as a .ptau file. As in the files created by snarkjs
+// powersoftau new, the contributions section is empty: snarkjs can contribute
+// to the file, apply a beacon or prepare the phase 2 from it, but not verify
+// the history of the ceremony.
+func (p *Ptau) WritePtau(w io.Writer) error {
+	if p.Power < 1 || p.Power > ptauMaxPower || p.CeremonyPower < p.Power {
+		return ErrInvalidPtau
+	}
+	n := 1 << p.Power
+	if len(p.TauG1) != 2*n-1 || len(p.TauG2) != n || len(p.AlphaTauG1) != n || len(p.BetaTauG1) != n {
+		return ErrInvalidPtau
+	}
+
+	bw := bufio.NewWriter(w)
+	var b [8]byte
+	put32 := func(v uint32) {
+		binary.LittleEndian.PutUint32(b[:4], v)
+		bw.Write(b[:4])
+	}
+	sectionHead := func(id uint32, size uint64) {
+		put32(id)
+		binary.LittleEndian.PutUint64(b[:], size)
+		bw.Write(b[:])
+	}
+	var g1Buf [ptauSizeG1]byte
+	putG1s := func(id uint32, points []bn254.G1Affine) {
+		sectionHead(id, uint64(len(points))*ptauSizeG1)
+		for i := range points {
+			ptauPutG1(&g1Buf, &points[i])
+			bw.Write(g1Buf[:])
+		}
+	}
+	var g2Buf [ptauSizeG2]byte
+	putG2s := func(id uint32, points []bn254.G2Affine) {
+		sectionHead(id, uint64(len(points))*ptauSizeG2)
+		for i := range points {
+			ptauPutG2(&g2Buf, &points[i])
+			bw.Write(g2Buf[:])
+		}
+	}
+
+	bw.WriteString(ptauMagic)
+	put32(ptauVersion)
+	put32(ptauNbSections)
+
+	// header: n8 ∥ q ∥ power ∥ ceremonyPower
+	sectionHead(ptauSectionHeader, 4+fp.Bytes+4+4)
+	put32(fp.Bytes)
+	var q [fp.Bytes]byte
+	fp.Modulus().FillBytes(q[:])
+	for i, j := 0, len(q)-1; i < j; i, j = i+1, j-1 {
+		q[i], q[j] = q[j], q[i]
+	}
+	bw.Write(q[:])
+	put32(p.Power)
+	put32(p.CeremonyPower)
+
+	putG1s(ptauSectionTauG1, p.TauG1)
+	putG2s(ptauSectionTauG2, p.TauG2)
+	putG1s(ptauSectionAlphaTauG1, p.AlphaTauG1)
+	putG1s(ptauSectionBetaTauG1, p.BetaTauG1)
+	putG2s(ptauSectionBetaG2, []bn254.G2Affine{p.BetaG2})
+
+	// no contribution
+	sectionHead(ptauSectionContributions, 4)
+	put32(0)
+
+	return bw.Flush()
+}
+
+// ReadPtau reads a snarkjs Powers-of-Tau (.ptau) file, checks that all points
+// are in the prime order subgroups and that they are consistent powers of τ,
+// α and β, and sets p accordingly.
+//
+// All the points are loaded in memory; SRS.ReadPtau only loads the ones of a
+// KZG SRS.
+func (p *Ptau) ReadPtau(r io.Reader) error {
+	var (
+		res    Ptau
+		read   [ptauSectionBetaG2 + 1]bool
+		n      uint64
+		betaG2 []bn254.G2Affine
+	)
+	err := readPtauSections(r, func(id uint32, size uint64, section io.Reader) (bool, error) {
+		if id > ptauSectionBetaG2 {
+			return false, nil
+		}
+		if read[id] || (id != ptauSectionHeader && !read[ptauSectionHeader]) {
+			return false, ErrInvalidPtau
+		}
+		read[id] = true
+
+		var err error
+		switch id {
+		case ptauSectionHeader:
+			res.Power, res.CeremonyPower, err = readPtauHeader(section)
+			n = 1 << res.Power
+		case ptauSectionTauG1:
+			res.TauG1, err = ptauReadG1s(section, size, 2*n-1)
+		case ptauSectionTauG2:
+			res.TauG2, err = ptauReadG2s(section, size, n)
+		case ptauSectionAlphaTauG1:
+			res.AlphaTauG1, err = ptauReadG1s(section, size, n)
+		case ptauSectionBetaTauG1:
+			res.BetaTauG1, err = ptauReadG1s(section, size, n)
+		case ptauSectionBetaG2:
+			betaG2, err = ptauReadG2s(section, size, 1)
+		}
+		return false, err
+	})
+	if err != nil {
+		return err
+	}
+	for id := ptauSectionHeader; id <= ptauSectionBetaG2; id++ {
+		if !read[id] {
+			return ErrInvalidPtau
+		}
+	}
+	res.BetaG2 = betaG2[0]
+
+	if err := res.verify(); err != nil {
+		return err
+	}
+	*p = res
+	return nil
+}
+
+// verify checks that the points of p are consistent powers of τ, α and β:
+//
+//	[τ⁰]₁ = [1]₁ and [τ⁰]₂ = [1]₂
+//	[τⁱ]₁, [ατⁱ]₁ and [βτⁱ]₁ are powers of τ, see checkPtauPowers
+//	e([τⁱ]₁, [1]₂) = e([1]₁, [τⁱ]₂), batched with random coefficients
+//	e([β]₁, [1]₂) = e([1]₁, [β]₂)
+func (p *Ptau) verify() error {
+	_, _, g1, g2 := bn254.Generators()
+	if !p.TauG1[0].Equal(&g1) || !p.TauG2[0].Equal(&g2) {
+		return ErrSRSNotWellFormed
+	}
+	for _, points := range [][]bn254.G1Affine{p.TauG1, p.AlphaTauG1, p.BetaTauG1} {
+		if err := checkPtauPowers(points, &p.TauG2[1]); err != nil {
+			return err
+		}
+	}
+
+	rhos, err := randomPowers(len(p.TauG2))
+	if err != nil {
+		return err
+	}
+	var left bn254.G1Affine
+	var right bn254.G2Affine
+	config := ecc.MultiExpConfig{}
+	if _, err := left.MultiExp(p.TauG1[:len(p.TauG2)], rhos, config); err != nil {
+		return err
+	}
+	if _, err := right.MultiExp(p.TauG2, rhos, config); err != nil {
+		return err
+	}
+	var negG1 bn254.G1Affine
+	negG1.Neg(&g1)
+	ok, err := bn254.PairingCheck(
+		[]bn254.G1Affine{left, negG1, p.BetaTauG1[0], negG1},
+		[]bn254.G2Affine{g2, right, g2, p.BetaG2},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSRSNotWellFormed
+	}
+	return nil
+}
+
+// checkPtauPowers checks that points are powers of τ, up to a constant
+// factor, that is
+//
+//	e(∑ᵢ ρⁱ points[i], [τ]₂) = e(∑ᵢ ρⁱ points[i+1], [1]₂)
+//
+// for a random ρ.
+func checkPtauPowers(points []bn254.G1Affine, tauG2 *bn254.G2Affine) error {
+	n := len(points)
+	rhos, err := randomPowers(n - 1)
+	if err != nil {
+		return err
+	}
+	var left, right bn254.G1Affine
+	config := ecc.MultiExpConfig{}
+	if _, err := left.MultiExp(points[:n-1], rhos, config); err != nil {
+		return err
+	}
+	if _, err := right.MultiExp(points[1:], rhos, config); err != nil {
+		return err
+	}
+	right.Neg(&right)
+	_, _, _, g2 := bn254.Generators()
+	ok, err := bn254.PairingCheck(
+		[]bn254.G1Affine{left, right},
+		[]bn254.G2Affine{*tauG2, g2},
+	)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrSRSNotWellFormed
+	}
+	return nil
+}
+
+// randomPowers returns ρⁱ for i < n, for a random ρ
+func randomPowers(n int) ([]fr.Element, error) {
+	var rho fr.Element
+	if _, err := rho.SetRandom(); err != nil {
+		return nil, err
+	}
+	res := make([]fr.Element, n)
+	res[0].SetOne()
+	for i := 1; i < n; i++ {
+		res[i].Mul(&res[i-1], &rho)
+	}
+	return res, nil
+}
+
+// ReadPtau reads a snarkjs Powers-of-Tau (.ptau) file, checks that all points
+// are in the prime order subgroups and that the powers of τ are consistent,
+// and sets srs accordingly.
+//
+// If maxPkPoints is provided, the number of points in the ProvingKey will be
+// limited to maxPkPoints.
+func (srs *SRS) ReadPtau(r io.Reader, maxPkPoints ...int) error {
+	var (
+		g1         []bn254.G1Affine
+		g2         []bn254.G2Affine
+		power      uint32
+		readHeader bool
+	)
+	err := readPtauSections(r, func(id uint32, size uint64, section io.Reader) (bool, error) {
+		var err error
+		switch id {
+		case ptauSectionHeader:
+			if readHeader {
+				return false, ErrInvalidPtau
+			}
+			power, _, err = readPtauHeader(section)
+			readHeader = true
+		case ptauSectionTauG1:
+			if !readHeader || g1 != nil {
+				return false, ErrInvalidPtau
+			}
+			n := uint64(2<<power) - 1
+			if size != n*ptauSizeG1 {
+				return false, ErrInvalidPtau
+			}
+			if len(maxPkPoints) > 0 && maxPkPoints[0] > 0 && uint64(maxPkPoints[0]) < n {
+				n = uint64(maxPkPoints[0])
+			}
+			if n < 2 {
+				return false, ErrMinSRSSize
+			}
+			g1, err = ptauReadG1s(section, n*ptauSizeG1, n)
+		case ptauSectionTauG2:
+			if !readHeader || g2 != nil {
+				return false, ErrInvalidPtau
+			}
+			if size != uint64(1<<power)*ptauSizeG2 {
+				return false, ErrInvalidPtau
+			}
+			g2, err = ptauReadG2s(section, 2*ptauSizeG2, 2)
+		}
+		return g1 != nil && g2 != nil, err
+	})
+	if err != nil {
+		return err
+	}
+	if g1 == nil || g2 == nil {
+		return ErrInvalidPtau
+	}
+
+	var res SRS
+	res.Pk.G1 = g1
+	res.Vk.G1 = g1[0]
+	res.Vk.G2 = [2]bn254.G2Affine{g2[0], g2[1]}
+	if err := verifySRS(&res); err != nil {
+		return err
+	}
+	res.Vk.Lines[0] = bn254.PrecomputeLines(res.Vk.G2[0])
+	res.Vk.Lines[1] = bn254.PrecomputeLines(res.Vk.G2[1])
+	*srs = res
+
+	return nil
+}
+
+// readPtauSections reads the header of a .ptau file, then calls read on its
+// sections, with a reader limited to the section, until read returns true.
+// The remainder of each section is skipped.
+func readPtauSections(r io.Reader, read func(id uint32, size uint64, section io.Reader) (bool, error)) error {
+	var header [12]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return err
+	}
+	if string(header[:4]) != ptauMagic || binary.LittleEndian.Uint32(header[4:8]) != ptauVersion {
+		return ErrInvalidPtau
+	}
+	nbSections := binary.LittleEndian.Uint32(header[8:12])
+
+	var sectionHead [12]byte
+	for i := uint32(0); i < nbSections; i++ {
+		if _, err := io.ReadFull(r, sectionHead[:]); err != nil {
+			return err
+		}
+		id := binary.LittleEndian.Uint32(sectionHead[:4])
+		size := binary.LittleEndian.Uint64(sectionHead[4:12])
+		section := &io.LimitedReader{R: r, N: int64(size)}
+
+		done, err := read(id, size, section)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+
+		// skip the remainder of the section, which must be complete
+		if _, err := io.CopyN(io.Discard, section, section.N); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readPtauHeader checks that the header section (n8 ∥ q ∥ power ∥
+// ceremonyPower) describes the base field of bn254, and returns the
+// power of the file and the one of the ceremony.
+func readPtauHeader(r io.Reader) (power, ceremonyPower uint32, err error) {
+	var buf [4 + fp.Bytes + 4 + 4]byte
+	if _, err = io.ReadFull(r, buf[:]); err != nil {
+		return
+	}
+	if binary.LittleEndian.Uint32(buf[:4]) != fp.Bytes {
+		return 0, 0, ErrInvalidPtau
+	}
+	q := buf[4 : 4+fp.Bytes]
+	for i, j := 0, len(q)-1; i < j; i, j = i+1, j-1 {
+		q[i], q[j] = q[j], q[i]
+	}
+	if new(big.Int).SetBytes(q).Cmp(fp.Modulus()) != 0 {
+		return 0, 0, ErrInvalidPtau
+	}
+	power = binary.LittleEndian.Uint32(buf[4+fp.Bytes:])
+	ceremonyPower = binary.LittleEndian.Uint32(buf[8+fp.Bytes:])
+	if power < 1 || power > ptauMaxPower {
+		return 0, 0, ErrInvalidPtau
+	}
+	return power, ceremonyPower, nil
+}
+
+// ptauReadG1s reads the n points of a section of the given size, which must
+// be the size of the points. The slice grows as the points are read, so that
+// the allocations are bounded by the size of the data.
+func ptauReadG1s(r io.Reader, size, n uint64) ([]bn254.G1Affine, error) {
+	if size != n*ptauSizeG1 {
+		return nil, ErrInvalidPtau
+	}
+	c := n
+	if c > ptauChunk {
+		c = ptauChunk
+	}
+	res := make([]bn254.G1Affine, 0, c)
+	var buf [ptauSizeG1]byte
+	for uint64(len(res)) < n {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		var p bn254.G1Affine
+		if err := ptauReadG1(&p, &buf); err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
+
+// ptauReadG2s is ptauReadG1s for points of G₂.
+func ptauReadG2s(r io.Reader, size, n uint64) ([]bn254.G2Affine, error) {
+	if size != n*ptauSizeG2 {
+		return nil, ErrInvalidPtau
+	}
+	c := n
+	if c > ptauChunk {
+		c = ptauChunk
+	}
+	res := make([]bn254.G2Affine, 0, c)
+	var buf [ptauSizeG2]byte
+	for uint64(len(res)) < n {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return nil, err
+		}
+		var p bn254.G2Affine
+		if err := ptauReadG2(&p, &buf); err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
+
+// ptauR is the Montgomery constant 2^(64⋅fp.Limbs) mod q, and ptauRInv its
+// inverse, used to convert to and from the Montgomery form of the encoding.
+var ptauR, ptauRInv = func() (fp.Element, fp.Element) {
+	var r, rInv fp.Element
+	r.SetBigInt(new(big.Int).Lsh(big.NewInt(1), 64*fp.Limbs))
+	rInv.Inverse(&r)
+	return r, rInv
+}()
+
+// ptauReadElement decodes a little-endian Montgomery form field element.
+func ptauReadElement(e *fp.Element, b []byte) error {
+	var buf [fp.Bytes]byte
+	copy(buf[:], b)
+	v, err := fp.LittleEndian.Element(&buf)
+	if err != nil {
+		return ErrInvalidPtau
+	}
+	e.Mul(&v, &ptauRInv)
+	return nil
+}
+
+// ptauPutElement encodes a field element in little-endian Montgomery form.
+func ptauPutElement(b []byte, e *fp.Element) {
+	var v fp.Element
+	v.Mul(e, &ptauR)
+	var buf [fp.Bytes]byte
+	fp.LittleEndian.PutElement(&buf, v)
+	copy(b, buf[:])
+}
+
+func ptauReadG1(p *bn254.G1Affine, b *[ptauSizeG1]byte) error {
+	if err := ptauReadElement(&p.X, b[:fp.Bytes]); err != nil {
+		return err
+	}
+	if err := ptauReadElement(&p.Y, b[fp.Bytes:]); err != nil {
+		return err
+	}
+	if !p.IsInSubGroup() {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
+func ptauReadG2(p *bn254.G2Affine, b *[ptauSizeG2]byte) error {
+	for i, e := range []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1} {
+		if err := ptauReadElement(e, b[i*fp.Bytes:(i+1)*fp.Bytes]); err != nil {
+			return err
+		}
+	}
+	if !p.IsInSubGroup() {
+		return ErrNotInSubgroup
+	}
+	return nil
+}
+
+func ptauPutG1(b *[ptauSizeG1]byte, p *bn254.G1Affine) {
+	ptauPutElement(b[:fp.Bytes], &p.X)
+	ptauPutElement(b[fp.Bytes:], &p.Y)
+}
+
+func ptauPutG2(b *[ptauSizeG2]byte, p *bn254.G2Affine) {
+	for i, e := range []*fp.Element{&p.X.A0, &p.X.A1, &p.Y.A0, &p.Y.A1} {
+		ptauPutElement(b[i*fp.Bytes:(i+1)*fp.Bytes], e)
+	}
+}
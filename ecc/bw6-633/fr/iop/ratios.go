@@ -125,9 +125,8 @@ func BuildRatioShuffledVectors(numerator, denominator []*Polynomial, beta fr.Ele
 	}
 
 	t = fr.BatchInvert(t)
-	for i := 1; i < n; i++ {
-		coeffs[i].Mul(&coeffs[i], &t[i])
-	}
+	ratio := fr.Vector(coeffs[1:])
+	ratio.Mul(ratio, t[1:])
 
 	res := NewPolynomial(&coeffs, expectedForm)
 
@@ -243,9 +242,8 @@ func BuildRatioCopyConstraint(
 		start++
 		end++
 		tInv := fr.BatchInvert(t[start:end])
-		for i := start; i < end; i++ {
-			coeffs[i].Mul(&coeffs[i], &tInv[i-start])
-		}
+		ratio := fr.Vector(coeffs[start:end])
+		ratio.Mul(ratio, tInv)
 	}, nbTasks)
 
 	res := NewPolynomial(&coeffs, expectedForm)
@@ -358,9 +356,8 @@ func getSupportIdentityPermutation(nbCopies int, domain *fft.Domain) []fr.Elemen
 
 		go func() {
 			parallel.Execute(sizePoly, func(start, end int) {
-				for j := start; j < end; j++ {
-					res[i*sizePoly+j].Mul(&res[j], &coset)
-				}
+				dst := fr.Vector(res[i*sizePoly+start : i*sizePoly+end])
+				dst.ScalarMul(res[start:end], &coset)
 			}, (runtime.NumCPU()/(nbCopies-1))+1)
 			wg.Done()
 		}()
@@ -151,13 +151,11 @@ func ProveLookupTables(pk kzg.ProvingKey, f, t []fr.Vector) (ProofLookupTables,
 	}
 	foldedf := make(fr.Vector, nbColumns)
 	foldedt := make(fr.Vector, nbColumns)
-	for i := 0; i < int(nbColumns); i++ {
-		for j := nbRows - 1; j >= 0; j-- {
-			foldedf[i].Mul(&foldedf[i], &lambda).
-				Add(&foldedf[i], &lfs[j][i])
-			foldedt[i].Mul(&foldedt[i], &lambda).
-				Add(&foldedt[i], &lts[j][i])
-		}
+	for j := nbRows - 1; j >= 0; j-- {
+		foldedf.ScalarMul(foldedf, &lambda)
+		foldedf.Add(foldedf, lfs[j])
+		foldedt.ScalarMul(foldedt, &lambda)
+		foldedt.Add(foldedt, lts[j])
 	}
 
 	// generate a proof of permutation of the foldedt and sort(foldedt)
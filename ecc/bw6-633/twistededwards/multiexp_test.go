@@ -0,0 +1,128 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package twistededwards
+
+import (
+	"fmt"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc"
+)
+
+func TestMultiExp(t *testing.T) {
+	t.Parallel()
+
+	params := GetEdwardsCurve()
+	rng := rand.New(rand.NewSource(42)) //#nosec G404 weak rng is fine here
+
+	const nbPoints = 130
+	points := make([]PointAffine, nbPoints)
+	scalars := make([]big.Int, nbPoints)
+	for i := range points {
+		var s big.Int
+		s.Rand(rng, &params.Order)
+		points[i].ScalarMultiplication(&params.Base, &s)
+		scalars[i].Rand(rng, &params.Order)
+	}
+
+	// edge cases: identity, zero, small, negative and large scalars
+	points[3].X.SetZero()
+	points[3].Y.SetOne()
+	points[5].Set(&points[4])
+	scalars[7].SetUint64(0)
+	scalars[8].SetUint64(1)
+	scalars[9].SetInt64(-1)
+	scalars[10].Sub(&params.Order, big.NewInt(1))
+	scalars[11].Lsh(&params.Order, 3).Add(&scalars[11], big.NewInt(5))
+	scalars[12].Neg(&scalars[11])
+
+	for _, n := range []int{nbPoints, 17, 2, 1, 0} {
+		// ∑ [sᵢ]Pᵢ with double-and-add scalar multiplications
+		var expected PointAffine
+		var acc PointExtended
+		acc.setInfinity()
+		for i := 0; i < n; i++ {
+			var s big.Int
+			var q PointExtended
+			s.Mod(&scalars[i], &params.Order)
+			q.FromAffine(&points[i])
+			q.scalarMulWindowed(&q, &s)
+			acc.Add(&acc, &q)
+		}
+		expected.FromExtended(&acc)
+
+		for _, nbTasks := range []int{0, 1, 3} {
+			var res PointAffine
+			if _, err := res.MultiExp(points[:n], scalars[:n], ecc.MultiExpConfig{NbTasks: nbTasks}); err != nil {
+				t.Fatal(err)
+			}
+			if !res.Equal(&expected) {
+				t.Fatalf("n=%d, nbTasks=%d: mismatch with scalar multiplications", n, nbTasks)
+			}
+		}
+	}
+
+	// all the scalars equal to a multiple of the order
+	zeros := make([]big.Int, 4)
+	for i := range zeros {
+		zeros[i].Set(&params.Order)
+	}
+	var res PointExtended
+	if _, err := res.MultiExp(points[:4], zeros, ecc.MultiExpConfig{}); err != nil {
+		t.Fatal(err)
+	}
+	if !res.IsZero() {
+		t.Fatal("expected the identity")
+	}
+
+	if _, err := res.MultiExp(points[:4], scalars[:3], ecc.MultiExpConfig{}); err == nil {
+		t.Fatal("expected an error with len(points) != len(scalars)")
+	}
+	if _, err := res.MultiExp(points, scalars, ecc.MultiExpConfig{NbTasks: 1025}); err == nil {
+		t.Fatal("expected an error with an invalid config")
+	}
+}
+
+func BenchmarkMultiExp(b *testing.B) {
+	params := GetEdwardsCurve()
+	rng := rand.New(rand.NewSource(42)) //#nosec G404 weak rng is fine here
+
+	const nbPoints = 1 << 12
+	points := make([]PointAffine, nbPoints)
+	scalars := make([]big.Int, nbPoints)
+	var s big.Int
+	s.Rand(rng, &params.Order)
+	points[0].ScalarMultiplication(&params.Base, &s)
+	for i := range points {
+		if i > 0 {
+			points[i].Add(&points[i-1], &params.Base)
+		}
+		scalars[i].Rand(rng, &params.Order)
+	}
+
+	var res PointExtended
+	for using := 1 << 6; using <= nbPoints; using <<= 3 {
+		b.Run(fmt.Sprintf("%d points", using), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				res.MultiExp(points[:using], scalars[:using], ecc.MultiExpConfig{})
+			}
+		})
+	}
+}
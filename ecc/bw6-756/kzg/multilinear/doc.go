@@ -0,0 +1,25 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package multilinear provides a KZG commitment scheme for multilinear
+// polynomials, following Papamanthou, Shi and Tamassia
+// (https://eprint.iacr.org/2011/587, PST13).
+//
+// Polynomials are given by their evaluations on the boolean hypercube, as
+// polynomial.MultiLin. A polynomial in n variables is committed to with the n
+// last trapdoors of the SRS, and its opening proof at a point of Fⁿ is made of
+// n G₁ elements, verified with n+1 pairings.
+package multilinear
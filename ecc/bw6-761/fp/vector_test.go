@@ -18,6 +18,7 @@ package fp
 
 import (
 	"bytes"
+	"fmt"
 	"github.com/stretchr/testify/require"
 	"reflect"
 	"sort"
@@ -80,6 +81,152 @@ func TestVectorEmptyRoundTrip(t *testing.T) {
 	assert.True(reflect.DeepEqual(v3, v2))
 }
 
+func TestVectorOps(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 17, 3*minVectorChunk + 5} {
+		t.Run(fmt.Sprintf("n=%d", n), func(t *testing.T) {
+			assert := require.New(t)
+
+			a, b := randomVector(n), randomVector(n)
+			var alpha Element
+			alpha.SetRandom()
+			if n > 0 {
+				// q-1 exercises the final reductions
+				a[n-1].SetOne().Neg(&a[n-1])
+				b[n-1].Set(&a[n-1])
+			}
+
+			// expected results with the element operations
+			var sum, innerProduct, tmp Element
+			add, sub, scalarMul, mul, axpy := make(Vector, n), make(Vector, n), make(Vector, n), make(Vector, n), make(Vector, n)
+			for i := 0; i < n; i++ {
+				add[i].Add(&a[i], &b[i])
+				sub[i].Sub(&a[i], &b[i])
+				scalarMul[i].Mul(&a[i], &alpha)
+				mul[i].Mul(&a[i], &b[i])
+				axpy[i].Mul(&alpha, &a[i]).Add(&axpy[i], &b[i])
+				sum.Add(&sum, &a[i])
+				innerProduct.Add(&innerProduct, tmp.Mul(&a[i], &b[i]))
+			}
+
+			res := make(Vector, n)
+			res.Add(a, b)
+			assert.True(reflect.DeepEqual(add, res), "Add")
+			res.Sub(a, b)
+			assert.True(reflect.DeepEqual(sub, res), "Sub")
+			res.ScalarMul(a, &alpha)
+			assert.True(reflect.DeepEqual(scalarMul, res), "ScalarMul")
+			res.Mul(a, b)
+			assert.True(reflect.DeepEqual(mul, res), "Mul")
+			copy(res, b)
+			res.AXPY(&alpha, a)
+			assert.True(reflect.DeepEqual(axpy, res), "AXPY")
+			resSum, resInnerProduct := a.Sum(), a.InnerProduct(b)
+			assert.True(sum.Equal(&resSum), "Sum")
+			assert.True(innerProduct.Equal(&resInnerProduct), "InnerProduct")
+
+			// the result can be one of the operands
+			copy(res, a)
+			res.Add(res, b)
+			assert.True(reflect.DeepEqual(add, res), "Add in place")
+			copy(res, a)
+			res.Sub(res, b)
+			assert.True(reflect.DeepEqual(sub, res), "Sub in place")
+			if n > 0 {
+				copy(res, a)
+				alpha0 := res[0]
+				res.ScalarMul(res, &res[0])
+				for i := 0; i < n; i++ {
+					tmp.Mul(&a[i], &alpha0)
+					assert.True(res[i].Equal(&tmp), "ScalarMul in place")
+				}
+			}
+		})
+	}
+}
+
+func TestVectorOpsLength(t *testing.T) {
+	assert := require.New(t)
+
+	a, b := make(Vector, 3), make(Vector, 4)
+	var alpha Element
+	assert.Panics(func() { a.Add(a, b) })
+	assert.Panics(func() { a.Sub(b, a) })
+	assert.Panics(func() { a.ScalarMul(b, &alpha) })
+	assert.Panics(func() { a.Mul(a, b) })
+	assert.Panics(func() { a.AXPY(&alpha, b) })
+	assert.Panics(func() { a.InnerProduct(b) })
+}
+
+func BenchmarkVectorOps(b *testing.B) {
+	const n = 1 << 16
+	a1, a2 := randomVector(n), randomVector(n)
+	res := make(Vector, n)
+	var alpha Element
+	alpha.SetRandom()
+
+	b.Run("Add", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			res.Add(a1, a2)
+		}
+	})
+	b.Run("Sub", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			res.Sub(a1, a2)
+		}
+	})
+	b.Run("ScalarMul", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			res.ScalarMul(a1, &alpha)
+		}
+	})
+	b.Run("Mul", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			res.Mul(a1, a2)
+		}
+	})
+	b.Run("AXPY", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			res.AXPY(&alpha, a1)
+		}
+	})
+	b.Run("Sum", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = a1.Sum()
+		}
+	})
+	b.Run("InnerProduct", func(b *testing.B) {
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			_ = a1.InnerProduct(a2)
+		}
+	})
+}
+
+// randomVector returns a random vector of size n, with some elements equal to
+// 0, 1 and -1 to exercise the modular reductions
+func randomVector(n int) Vector {
+	v := make(Vector, n)
+	for i := range v {
+		switch i % 7 {
+		case 0:
+			v[i].SetOne().Neg(&v[i])
+		case 3:
+			v[i].SetZero()
+		case 5:
+			v[i].SetOne()
+		default:
+			v[i].SetRandom()
+		}
+	}
+	return v
+}
+
 func (vector *Vector) unmarshalBinaryAsync(data []byte) error {
 	r := bytes.NewReader(data)
 	_, err, chErr := vector.AsyncReadFrom(r)
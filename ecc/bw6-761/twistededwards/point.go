@@ -539,7 +539,7 @@ func (p *PointExtended) MixedAdd(p1 *PointExtended, p2 *PointAffine) *PointExten
 	B.Mul(&p2.Y, &p1.Z)
 
 	if p1.X.Equal(&A) && p1.Y.Equal(&B) {
-		p.MixedDouble(p1)
+		p.Double(p1)
 		return p
 	}
 
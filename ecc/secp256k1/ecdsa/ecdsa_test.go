@@ -17,8 +17,10 @@
 package ecdsa
 
 import (
+	"bytes"
 	"crypto/rand"
 	"crypto/sha256"
+	"encoding/hex"
 	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
 	"math/big"
 	"testing"
@@ -62,6 +64,32 @@ func TestECDSA(t *testing.T) {
 		},
 	))
 
+	properties.Property("[SECP256K1] test the deterministic signing and verification", prop.ForAll(
+		func() bool {
+
+			privKey, _ := GenerateKey(rand.Reader)
+			publicKey := privKey.PublicKey
+
+			msg := []byte("testing ECDSA")
+			hFunc := sha256.New()
+			sig1, err := privKey.SignDeterministic(msg, hFunc, sha256.New)
+			if err != nil {
+				return false
+			}
+			sig2, err := privKey.SignDeterministic(msg, hFunc, sha256.New)
+			if err != nil {
+				return false
+			}
+			sig3, err := privKey.SignDeterministic([]byte("testing ECDSA."), hFunc, sha256.New)
+			if err != nil {
+				return false
+			}
+			flag, _ := publicKey.Verify(sig1, msg, hFunc)
+
+			return flag && bytes.Equal(sig1, sig2) && !bytes.Equal(sig1, sig3)
+		},
+	))
+
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
 func TestRecoverPublicKey(t *testing.T) {
@@ -89,6 +117,120 @@ func TestRecoverPublicKey(t *testing.T) {
 	))
 	properties.TestingRun(t, gopter.ConsoleReporter(false))
 }
+func TestSignDeterministicVectors(t *testing.T) {
+	t.Parallel()
+
+	// RFC 6979 nonces on secp256k1 with HMAC-SHA256, as used by Bitcoin and
+	// Ethereum. Signers normalize s to the lower half of the order, hence both
+	// s and -s are accepted.
+	orderMinusOne := new(big.Int).Sub(fr.Modulus(), big.NewInt(1))
+	vectors := []struct {
+		sk      *big.Int
+		msg     []byte
+		hashed  bool
+		k, r, s string
+	}{
+		{
+			sk:  big.NewInt(1),
+			msg: []byte("Satoshi Nakamoto"),
+			k:   "8f8a276c19f4149656b280621e358cce24f5f52542772691ee69063b74f15d15",
+			r:   "934b1ea10a4b3c1757e2b0c017d0b6143ce3c9a7e6a4a49860d7a6ab210ee3d8",
+			s:   "2442ce9d2b916064108014783e923ec36b49743e2ffa1c4496f01a512aafd9e5",
+		},
+		{
+			sk:  big.NewInt(1),
+			msg: []byte("All those moments will be lost in time, like tears in rain. Time to die..."),
+			k:   "38aa22d72376b4dbc472e06c3ba403ee0a394da63fc58d88686c611aba98d6b3",
+			r:   "8600dbd41e348fe5c9465ab92d23e3db8b98b873beecd930736488696438cb6b",
+			s:   "547fe64427496db33bf66019dacbf0039c04199abb0122918601db38a72cfc21",
+		},
+		{
+			sk:  orderMinusOne,
+			msg: []byte("Satoshi Nakamoto"),
+			k:   "33a19b60e25fb6f4435af53a3d42d493644827367e6453928554f43e49aa6f90",
+			r:   "fd567d121db66e382991534ada77a6bd3106f0a1098c231e47993447cd6af2d0",
+			s:   "6b39cd0eb1bc8603e159ef5c20a5c8ad685a45b06ce9bebed3f153d10d93bed5",
+		},
+		{
+			sk:  mustHexToInt(t, "f8b8af8ce3c7cca5e300d33939540c10d45ce001b8f252bfbc57ba0342904181"),
+			msg: []byte("Alan Turing"),
+			k:   "525a82b70e67874398067543fd84c83d30c175fdc45fdeee082fe13b1d7cfdf1",
+			r:   "7063ae83e7f62bbb171798131b4a0564b956930092b33b07b395615d9ec7e15c",
+			s:   "58dfcc1e00a35e1572f366ffe34ba0fc47db1e7189759b9fb233c5b05ab388ea",
+		},
+		// EIP-155 example transaction: the message is the keccak256 signing
+		// hash, signed as is.
+		{
+			sk:     mustHexToInt(t, "4646464646464646464646464646464646464646464646464646464646464646"),
+			msg:    mustHexToBytes(t, "daf5a779ae972f972197303d7b574746c7ef83eadac0f2791ad23db92e4c8e53"),
+			hashed: true,
+			r:      "28ef61340bd939bc2195fe537567866003e1a15d3c71ff63e1590620aa636276",
+			s:      "67cbe9d8997f761aecb703304b3800ccf555c9f3dc64214b297fb1966a3b6d83",
+		},
+	}
+
+	for i, v := range vectors {
+		privKey := privateKeyFromScalar(v.sk)
+		hFunc := sha256.New()
+		if v.hashed {
+			hFunc = nil
+		}
+
+		h, err := hashMessage(v.msg, hFunc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v.k != "" {
+			k, err := newRFC6979(privKey.scalar[:], h, sha256.New).next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if k.Cmp(mustHexToInt(t, v.k)) != 0 {
+				t.Fatalf("vector %d: wrong nonce", i)
+			}
+		}
+
+		sigBin, err := privKey.SignDeterministic(v.msg, hFunc, sha256.New)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var sig Signature
+		if _, err = sig.SetBytes(sigBin); err != nil {
+			t.Fatal(err)
+		}
+		r := new(big.Int).SetBytes(sig.R[:])
+		s := new(big.Int).SetBytes(sig.S[:])
+		expectedS := mustHexToInt(t, v.s)
+		if r.Cmp(mustHexToInt(t, v.r)) != 0 {
+			t.Fatalf("vector %d: wrong r", i)
+		}
+		if s.Cmp(expectedS) != 0 && s.Add(s, expectedS).Cmp(fr.Modulus()) != 0 {
+			t.Fatalf("vector %d: wrong s", i)
+		}
+		if ok, _ := privKey.PublicKey.Verify(sigBin, v.msg, hFunc); !ok {
+			t.Fatalf("vector %d: signature should verify", i)
+		}
+	}
+}
+
+func privateKeyFromScalar(sk *big.Int) *PrivateKey {
+	var privKey PrivateKey
+	sk.FillBytes(privKey.scalar[:])
+	privKey.PublicKey.A.ScalarMultiplicationBase(sk)
+	return &privKey
+}
+
+func mustHexToBytes(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func mustHexToInt(t *testing.T, s string) *big.Int {
+	return new(big.Int).SetBytes(mustHexToBytes(t, s))
+}
 
 func TestNonMalleability(t *testing.T) {
 
@@ -178,6 +320,17 @@ func BenchmarkSignECDSA(b *testing.B) {
 	}
 }
 
+func BenchmarkSignDeterministicECDSA(b *testing.B) {
+
+	privKey, _ := GenerateKey(rand.Reader)
+
+	msg := []byte("benchmarking ECDSA sign()")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		privKey.SignDeterministic(msg, nil, sha256.New)
+	}
+}
+
 func BenchmarkVerifyECDSA(b *testing.B) {
 
 	privKey, _ := GenerateKey(rand.Reader)
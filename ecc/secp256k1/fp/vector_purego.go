@@ -0,0 +1,52 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fp
+
+// vectorAdd res = a + b, element-wise
+func vectorAdd(res, a, b Vector) {
+	addVecGeneric(res, a, b)
+}
+
+// vectorSub res = a - b, element-wise
+func vectorSub(res, a, b Vector) {
+	subVecGeneric(res, a, b)
+}
+
+// vectorSum res = a[0] + ... + a[n-1]
+func vectorSum(res *Element, a Vector) {
+	sumVecGeneric(res, a)
+}
+
+// vectorScalarMul res = a * b, element-wise
+func vectorScalarMul(res, a Vector, b *Element) {
+	scalarMulVecGeneric(res, a, b)
+}
+
+// vectorMul res = a * b, element-wise
+func vectorMul(res, a, b Vector) {
+	mulVecGeneric(res, a, b)
+}
+
+// vectorAXPY res = alpha * x + res, element-wise
+func vectorAXPY(res Vector, alpha *Element, x Vector) {
+	axpyVecGeneric(res, alpha, x)
+}
+
+// vectorInnerProduct res = a[0]*b[0] + ... + a[n-1]*b[n-1]
+func vectorInnerProduct(res *Element, a, b Vector) {
+	innerProductVecGeneric(res, a, b)
+}
@@ -0,0 +1,23 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package schnorr provides BIP-340 Schnorr signatures on the secp256k1 curve.
+//
+// Public keys are x-only (32 bytes): the secret scalar is implicitly negated
+// when needed so that the public point has an even y coordinate. Signatures
+// are 64 bytes R||s where R is the x coordinate of the nonce point.
+//
+// Documentation:
+// - BIP-340: https://github.com/bitcoin/bips/blob/master/bip-0340.mediawiki
+package schnorr
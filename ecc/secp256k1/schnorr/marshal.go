@@ -0,0 +1,143 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/subtle"
+	"errors"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+)
+
+var errWrongSize = errors.New("wrong size buffer")
+var errRBiggerThanPMod = errors.New("r >= p_mod")
+var errSBiggerThanRMod = errors.New("s >= r_mod")
+var errScalarBiggerThanRMod = errors.New("scalar >= r_mod")
+var errOddY = errors.New("the scalar yields a point with an odd y coordinate")
+var errPubKeyMismatch = errors.New("the public key does not match the scalar")
+
+// Bytes returns the binary representation of the public key, that is the x
+// coordinate of the point as a 32 bytes big endian integer (x-only public key).
+func (pk *PublicKey) Bytes() []byte {
+	var res [sizePublicKey]byte
+	pkBin := pk.A.X.Bytes()
+	subtle.ConstantTimeCopy(1, res[:sizePublicKey], pkBin[:])
+	return res[:]
+}
+
+// SetBytes sets p from its x-only binary representation in buf, that is the
+// point with x coordinate buf and an even y coordinate.
+// It returns the number of bytes read from the buffer.
+func (pk *PublicKey) SetBytes(buf []byte) (int, error) {
+	if len(buf) < sizePublicKey {
+		return 0, io.ErrShortBuffer
+	}
+	p, err := liftX(buf[:sizePublicKey])
+	if err != nil {
+		return 0, err
+	}
+	pk.A = p
+	return sizePublicKey, nil
+}
+
+// Bytes returns the binary representation of pk,
+// as byte array publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+func (privKey *PrivateKey) Bytes() []byte {
+	var res [sizePrivateKey]byte
+	pubkBin := privKey.PublicKey.Bytes()
+	subtle.ConstantTimeCopy(1, res[:sizePublicKey], pubkBin[:])
+	subtle.ConstantTimeCopy(1, res[sizePublicKey:sizePrivateKey], privKey.scalar[:])
+	return res[:]
+}
+
+// SetBytes sets pk from buf, where buf is interpreted
+// as  publicKey||scalar
+// where publicKey is as publicKey.Bytes(), and
+// scalar is in big endian, of size sizeFr.
+// The public key is recomputed from the scalar: it must have an even y
+// coordinate, as in BIP-340, and match the public key in buf.
+// It returns the number byte read.
+func (privKey *PrivateKey) SetBytes(buf []byte) (int, error) {
+	n := 0
+	if len(buf) < sizePrivateKey {
+		return n, io.ErrShortBuffer
+	}
+	var pk PublicKey
+	if _, err := pk.SetBytes(buf[:sizePublicKey]); err != nil {
+		return 0, err
+	}
+	n += sizePublicKey
+	d := new(big.Int).SetBytes(buf[sizePublicKey:sizePrivateKey])
+	if d.Sign() == 0 {
+		return 0, errZeroScalar
+	}
+	if d.Cmp(fr.Modulus()) != -1 {
+		return 0, errScalarBiggerThanRMod
+	}
+	var p secp256k1.G1Affine
+	p.ScalarMultiplicationBaseConstantTime(d)
+	if !hasEvenY(&p) {
+		return 0, errOddY
+	}
+	if !p.Equal(&pk.A) {
+		return 0, errPubKeyMismatch
+	}
+	privKey.PublicKey = pk
+	subtle.ConstantTimeCopy(1, privKey.scalar[:], buf[sizePublicKey:sizePrivateKey])
+	n += sizeFr
+	return n, nil
+}
+
+// Bytes returns the binary representation of sig
+// as a byte array of size sizeFp+sizeFr r||s
+func (sig *Signature) Bytes() []byte {
+	var res [sizeSignature]byte
+	subtle.ConstantTimeCopy(1, res[:sizeFp], sig.R[:])
+	subtle.ConstantTimeCopy(1, res[sizeFp:], sig.S[:])
+	return res[:]
+}
+
+// SetBytes sets sig from a buffer in binary.
+// buf is read interpreted as r||s
+// It returns the number of bytes read from buf.
+func (sig *Signature) SetBytes(buf []byte) (int, error) {
+	n := 0
+	if len(buf) != sizeSignature {
+		return n, errWrongSize
+	}
+
+	// r < p_mod, s < r_mod (to avoid malleability)
+	bufBigInt := new(big.Int)
+	bufBigInt.SetBytes(buf[:sizeFp])
+	if bufBigInt.Cmp(fp.Modulus()) != -1 {
+		return 0, errRBiggerThanPMod
+	}
+	bufBigInt.SetBytes(buf[sizeFp:])
+	if bufBigInt.Cmp(fr.Modulus()) != -1 {
+		return 0, errSBiggerThanRMod
+	}
+
+	subtle.ConstantTimeCopy(1, sig.R[:], buf[:sizeFp])
+	n += sizeFp
+	subtle.ConstantTimeCopy(1, sig.S[:], buf[sizeFp:])
+	n += sizeFr
+	return n, nil
+}
@@ -0,0 +1,163 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+const (
+	nbFuzzShort = 10
+	nbFuzz      = 100
+)
+
+func TestSerialization(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	if testing.Short() {
+		parameters.MinSuccessfulTests = nbFuzzShort
+	} else {
+		parameters.MinSuccessfulTests = nbFuzz
+	}
+
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("[SECP256K1] Schnorr serialization: SetBytes(Bytes()) should stay the same", prop.ForAll(
+		func() bool {
+			privKey, _ := GenerateKey(rand.Reader)
+
+			var end PrivateKey
+			buf := privKey.Bytes()
+			n, err := end.SetBytes(buf[:])
+			if err != nil {
+				return false
+			}
+			if n != sizePrivateKey {
+				return false
+			}
+
+			return end.PublicKey.Equal(&privKey.PublicKey) && subtle.ConstantTimeCompare(end.scalar[:], privKey.scalar[:]) == 1
+
+		},
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestNonMalleability(t *testing.T) {
+
+	// buffer too big
+	t.Run("buffer_overflow", func(t *testing.T) {
+		bsig := make([]byte, sizeSignature+1)
+		var sig Signature
+		_, err := sig.SetBytes(bsig)
+		if err != errWrongSize {
+			t.Fatal("should raise wrong size error")
+		}
+	})
+
+	// R overflows p_mod
+	t.Run("R_overflow", func(t *testing.T) {
+		bsig := make([]byte, sizeSignature)
+		r := big.NewInt(1)
+		r.Add(r, fp.Modulus())
+		r.FillBytes(bsig[:sizeFp])
+
+		var sig Signature
+		_, err := sig.SetBytes(bsig)
+		if err != errRBiggerThanPMod {
+			t.Fatal("should raise error r >= p_mod")
+		}
+	})
+
+	// S overflows r_mod
+	t.Run("S_overflow", func(t *testing.T) {
+		bsig := make([]byte, sizeSignature)
+		s := big.NewInt(1)
+		s.Add(s, fr.Modulus())
+		s.FillBytes(bsig[sizeFp:])
+
+		var sig Signature
+		_, err := sig.SetBytes(bsig)
+		if err != errSBiggerThanRMod {
+			t.Fatal("should raise error s >= r_mod")
+		}
+	})
+}
+
+func TestPrivateKeySetBytes(t *testing.T) {
+
+	privKey, err := GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the public key of another scalar
+	t.Run("public_key_mismatch", func(t *testing.T) {
+		other, err := GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		buf := privKey.Bytes()
+		copy(buf[:sizePublicKey], other.PublicKey.Bytes())
+
+		var end PrivateKey
+		if _, err := end.SetBytes(buf); err != errPubKeyMismatch {
+			t.Fatal("should raise error public key mismatch")
+		}
+	})
+
+	// r-d has the same x-only public key as d, but yields an odd y
+	t.Run("odd_y", func(t *testing.T) {
+		buf := privKey.Bytes()
+		d := new(big.Int).SetBytes(buf[sizePublicKey:])
+		d.Sub(fr.Modulus(), d).FillBytes(buf[sizePublicKey:])
+
+		var end PrivateKey
+		if _, err := end.SetBytes(buf); err != errOddY {
+			t.Fatal("should raise error odd y")
+		}
+	})
+
+	t.Run("zero_scalar", func(t *testing.T) {
+		buf := privKey.Bytes()
+		for i := sizePublicKey; i < sizePrivateKey; i++ {
+			buf[i] = 0
+		}
+
+		var end PrivateKey
+		if _, err := end.SetBytes(buf); err != errZeroScalar {
+			t.Fatal("should raise error zero scalar")
+		}
+	})
+
+	t.Run("scalar_overflow", func(t *testing.T) {
+		buf := privKey.Bytes()
+		fr.Modulus().FillBytes(buf[sizePublicKey:])
+
+		var end PrivateKey
+		if _, err := end.SetBytes(buf); err != errScalarBiggerThanRMod {
+			t.Fatal("should raise error scalar >= r_mod")
+		}
+	})
+}
@@ -0,0 +1,343 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package schnorr
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"errors"
+	"hash"
+	"io"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fp"
+	"github.com/consensys/gnark-crypto/ecc/secp256k1/fr"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+const (
+	sizeFr         = fr.Bytes
+	sizeFp         = fp.Bytes
+	sizePublicKey  = sizeFp
+	sizePrivateKey = sizePublicKey + sizeFr
+	sizeSignature  = sizeFp + sizeFr
+	sizeAuxRand    = 32
+)
+
+// tags of the tagged hashes
+const (
+	tagAux       = "BIP0340/aux"
+	tagNonce     = "BIP0340/nonce"
+	tagChallenge = "BIP0340/challenge"
+)
+
+var (
+	errZeroScalar    = errors.New("secret scalar is zero")
+	errZeroNonce     = errors.New("nonce is zero")
+	errNotOnCurve    = errors.New("x coordinate is not on the curve")
+	errLenMismatch   = errors.New("number of public keys, signatures and messages mismatch")
+	errNoSignatures  = errors.New("no signature to verify")
+	errInvalidPubKey = errors.New("invalid public key")
+)
+
+var order = fr.Modulus()
+
+// PublicKey represents a BIP-340 public key, that is a point with an even y
+// coordinate
+type PublicKey struct {
+	A secp256k1.G1Affine
+}
+
+// PrivateKey represents a BIP-340 private key
+type PrivateKey struct {
+	PublicKey PublicKey
+	scalar    [sizeFr]byte // secret scalar, in big Endian, such that the public key has an even y
+}
+
+// Signature represents a BIP-340 signature
+type Signature struct {
+	R [sizeFp]byte // x coordinate of the nonce point
+	S [sizeFr]byte
+}
+
+// taggedHash returns SHA256(SHA256(tag) ∥ SHA256(tag) ∥ x[0] ∥ x[1] ∥ ...)
+func taggedHash(tag string, x ...[]byte) [32]byte {
+	t := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(t[:])
+	h.Write(t[:])
+	for i := range x {
+		h.Write(x[i])
+	}
+	var res [32]byte
+	h.Sum(res[:0])
+	return res
+}
+
+// hasEvenY returns true if the y coordinate of p is even
+func hasEvenY(p *secp256k1.G1Affine) bool {
+	yBytes := p.Y.Bytes()
+	return yBytes[sizeFp-1]&1 == 0
+}
+
+// liftX returns the point with x coordinate x and an even y coordinate
+func liftX(x []byte) (secp256k1.G1Affine, error) {
+	var p secp256k1.G1Affine
+	if err := p.X.SetBytesCanonical(x); err != nil {
+		return p, err
+	}
+	// y² = x³ + 7
+	var c fp.Element
+	c.Square(&p.X).Mul(&c, &p.X).Add(&c, new(fp.Element).SetUint64(7))
+	if p.Y.Sqrt(&c) == nil {
+		return p, errNotOnCurve
+	}
+	if !hasEvenY(&p) {
+		p.Y.Neg(&p.Y)
+	}
+	return p, nil
+}
+
+// GenerateKey generates a public and private key pair.
+func GenerateKey(rand io.Reader) (*PrivateKey, error) {
+	b := make([]byte, fr.Bits/8+8)
+	if _, err := io.ReadFull(rand, b); err != nil {
+		return nil, err
+	}
+	k := new(big.Int).SetBytes(b)
+	n := new(big.Int).Sub(order, big.NewInt(1))
+	k.Mod(k, n).Add(k, big.NewInt(1))
+
+	var buf [sizeFr]byte
+	k.FillBytes(buf[:])
+	return NewPrivateKey(buf[:])
+}
+
+// NewPrivateKey returns the private key corresponding to the big endian
+// secret scalar, which must be in [1, r-1].
+func NewPrivateKey(secret []byte) (*PrivateKey, error) {
+	if len(secret) != sizeFr {
+		return nil, io.ErrShortBuffer
+	}
+	d := new(big.Int).SetBytes(secret)
+	if d.Sign() == 0 {
+		return nil, errZeroScalar
+	}
+	if d.Cmp(order) >= 0 {
+		return nil, errScalarBiggerThanRMod
+	}
+
+	privateKey := new(PrivateKey)
+	privateKey.PublicKey.A.ScalarMultiplicationBaseConstantTime(d)
+	if !hasEvenY(&privateKey.PublicKey.A) {
+		d.Sub(order, d)
+		privateKey.PublicKey.A.Neg(&privateKey.PublicKey.A)
+	}
+	d.FillBytes(privateKey.scalar[:])
+	return privateKey, nil
+}
+
+// Equal compares 2 public keys
+func (pub *PublicKey) Equal(x signature.PublicKey) bool {
+	xx, ok := x.(*PublicKey)
+	if !ok {
+		return false
+	}
+	bpk := pub.Bytes()
+	bxx := xx.Bytes()
+	return subtle.ConstantTimeCompare(bpk, bxx) == 1
+}
+
+// Public returns the public key associated to the private key.
+func (privKey *PrivateKey) Public() signature.PublicKey {
+	var pub PublicKey
+	pub.A.Set(&privKey.PublicKey.A)
+	return &pub
+}
+
+// prehash returns hFunc(message) if hFunc is not nil, and message otherwise
+func prehash(message []byte, hFunc hash.Hash) ([]byte, error) {
+	if hFunc == nil {
+		return message, nil
+	}
+	hFunc.Reset()
+	if _, err := hFunc.Write(message); err != nil {
+		return nil, err
+	}
+	return hFunc.Sum(nil), nil
+}
+
+// challenge returns int(hash_BIP0340/challenge(r ∥ bytes(P) ∥ m)) mod n
+func challenge(r, pk, message []byte) *big.Int {
+	h := taggedHash(tagChallenge, r, pk, message)
+	e := new(big.Int).SetBytes(h[:])
+	return e.Mod(e, order)
+}
+
+// Sign performs the BIP-340 signature, with 32 bytes of auxiliary randomness
+// read from crypto/rand.
+//
+// If hFunc is not provided, the message is signed as is (BIP-340 messages
+// are arbitrary byte strings), else hFunc(message) is signed.
+func (privKey *PrivateKey) Sign(message []byte, hFunc hash.Hash) ([]byte, error) {
+	var auxRand [sizeAuxRand]byte
+	if _, err := io.ReadFull(rand.Reader, auxRand[:]); err != nil {
+		return nil, err
+	}
+	return privKey.SignWithAuxRand(message, hFunc, auxRand)
+}
+
+// SignWithAuxRand performs the BIP-340 signature with the given auxiliary
+// randomness. The signature is deterministic for a given auxRand.
+//
+// t = bytes(d) ⊕ hash_BIP0340/aux(a)
+// k = int(hash_BIP0340/nonce(t ∥ bytes(P) ∥ m)) mod n
+// R = k ⋅ G, k negated if R has an odd y
+// e = int(hash_BIP0340/challenge(bytes(R) ∥ bytes(P) ∥ m)) mod n
+// signature = bytes(R) ∥ bytes(k + e ⋅ d mod n)
+func (privKey *PrivateKey) SignWithAuxRand(message []byte, hFunc hash.Hash, auxRand [sizeAuxRand]byte) ([]byte, error) {
+	m, err := prehash(message, hFunc)
+	if err != nil {
+		return nil, err
+	}
+
+	pk := privKey.PublicKey.Bytes()
+	auxHash := taggedHash(tagAux, auxRand[:])
+	var t [sizeFr]byte
+	for i := range t {
+		t[i] = privKey.scalar[i] ^ auxHash[i]
+	}
+	rnd := taggedHash(tagNonce, t[:], pk, m)
+
+	k := new(big.Int).SetBytes(rnd[:])
+	k.Mod(k, order)
+	if k.Sign() == 0 {
+		return nil, errZeroNonce
+	}
+	var R secp256k1.G1Affine
+	R.ScalarMultiplicationBaseConstantTime(k)
+	if !hasEvenY(&R) {
+		k.Sub(order, k)
+	}
+
+	var sig Signature
+	sig.R = R.X.Bytes()
+	e := challenge(sig.R[:], pk, m)
+
+	d := new(big.Int).SetBytes(privKey.scalar[:])
+	s := e.Mul(e, d)
+	s.Add(s, k).Mod(s, order)
+	s.FillBytes(sig.S[:])
+
+	return sig.Bytes(), nil
+}
+
+// Verify validates the BIP-340 signature
+//
+// e = int(hash_BIP0340/challenge(r ∥ bytes(P) ∥ m)) mod n
+// R = s ⋅ G - e ⋅ P
+// R ≠ O, R has an even y and x(R) ?= r
+func (publicKey *PublicKey) Verify(sigBin, message []byte, hFunc hash.Hash) (bool, error) {
+	var sig Signature
+	if _, err := sig.SetBytes(sigBin); err != nil {
+		return false, err
+	}
+	if !publicKey.A.IsOnCurve() || publicKey.A.IsInfinity() || !hasEvenY(&publicKey.A) {
+		return false, errInvalidPubKey
+	}
+	m, err := prehash(message, hFunc)
+	if err != nil {
+		return false, err
+	}
+
+	e := challenge(sig.R[:], publicKey.Bytes(), m)
+	e.Sub(order, e)
+	s := new(big.Int).SetBytes(sig.S[:])
+
+	var R secp256k1.G1Jac
+	R.JointScalarMultiplicationBase(&publicKey.A, s, e)
+	var RAff secp256k1.G1Affine
+	RAff.FromJacobian(&R)
+	if RAff.IsInfinity() || !hasEvenY(&RAff) {
+		return false, nil
+	}
+	rx := RAff.X.Bytes()
+	return subtle.ConstantTimeCompare(rx[:], sig.R[:]) == 1, nil
+}
+
+// BatchVerify validates the BIP-340 signatures sigs[i] of messages[i] by
+// publicKeys[i] all at once, using a random linear combination:
+//
+// (s₁ + a₂s₂ + ... + aᵤsᵤ) ⋅ G ?= R₁ + a₂ ⋅ R₂ + ... + aᵤ ⋅ Rᵤ + e₁ ⋅ P₁ + (a₂e₂) ⋅ P₂ + ... + (aᵤeᵤ) ⋅ Pᵤ
+//
+// where the aᵢ are sampled at random. It returns true if and only if all the
+// signatures are valid (except with negligible probability).
+func BatchVerify(publicKeys []PublicKey, sigs, messages [][]byte, hFunc hash.Hash) (bool, error) {
+	n := len(publicKeys)
+	if n == 0 {
+		return false, errNoSignatures
+	}
+	if len(sigs) != n || len(messages) != n {
+		return false, errLenMismatch
+	}
+
+	points := make([]secp256k1.G1Affine, 2*n)
+	scalars := make([]fr.Element, 2*n)
+	var sum, a, s, e fr.Element
+	for i := 0; i < n; i++ {
+		var sig Signature
+		if _, err := sig.SetBytes(sigs[i]); err != nil {
+			return false, err
+		}
+		if !publicKeys[i].A.IsOnCurve() || publicKeys[i].A.IsInfinity() || !hasEvenY(&publicKeys[i].A) {
+			return false, errInvalidPubKey
+		}
+		m, err := prehash(messages[i], hFunc)
+		if err != nil {
+			return false, err
+		}
+		R, err := liftX(sig.R[:])
+		if err != nil {
+			return false, nil
+		}
+
+		if i == 0 {
+			a.SetOne()
+		} else if _, err := a.SetRandom(); err != nil {
+			return false, err
+		}
+		e.SetBigInt(challenge(sig.R[:], publicKeys[i].Bytes(), m))
+		s.SetBytes(sig.S[:])
+
+		points[i] = R
+		scalars[i] = a
+		points[n+i] = publicKeys[i].A
+		scalars[n+i].Mul(&a, &e)
+		s.Mul(&s, &a)
+		sum.Add(&sum, &s)
+	}
+
+	var rhs secp256k1.G1Jac
+	if _, err := rhs.MultiExp(points, scalars, ecc.MultiExpConfig{}); err != nil {
+		return false, err
+	}
+	var lhs secp256k1.G1Jac
+	lhs.ScalarMultiplicationBase(sum.BigInt(new(big.Int)))
+
+	return lhs.Equal(&rhs), nil
+}
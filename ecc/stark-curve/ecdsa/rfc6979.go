@@ -0,0 +1,100 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package ecdsa
+
+import (
+	"crypto/hmac"
+	"hash"
+	"math/big"
+)
+
+// rfc6979 is the HMAC-DRBG of RFC 6979, Section 3.2, used to derive the
+// signing nonces deterministically from the private key and the message hash.
+type rfc6979 struct {
+	newHash func() hash.Hash
+	k, v    []byte
+}
+
+// newRFC6979 instantiates the HMAC-DRBG with the secret scalar x and the
+// message hash h1 (RFC 6979, Section 3.2, steps a. to g.).
+func newRFC6979(x []byte, h1 []byte, newHash func() hash.Hash) *rfc6979 {
+	hLen := newHash().Size()
+	d := &rfc6979{
+		newHash: newHash,
+		k:       make([]byte, hLen),
+		v:       make([]byte, hLen),
+	}
+	for i := range d.v {
+		d.v[i] = 0x01
+	}
+
+	// bits2octets(h1) = int2octets(bits2int(h1) mod q)
+	h := bits2int(h1)
+	if h.Cmp(order) >= 0 {
+		h.Sub(h, order)
+	}
+	var bh [sizeFr]byte
+	h.FillBytes(bh[:])
+
+	d.k = d.mac(d.v, []byte{0x00}, x, bh[:])
+	d.v = d.mac(d.v)
+	d.k = d.mac(d.v, []byte{0x01}, x, bh[:])
+	d.v = d.mac(d.v)
+
+	return d
+}
+
+// next returns the next candidate nonce k in [1, q-1] (RFC 6979, Section 3.2,
+// step h.). Subsequent calls return fresh candidates, as required when a
+// nonce yields r = 0 or s = 0.
+func (d *rfc6979) next() (*big.Int, error) {
+	t := make([]byte, 0, sizeFr+len(d.v))
+	for {
+		t = t[:0]
+		for len(t)*8 < sizeFrBits {
+			d.v = d.mac(d.v)
+			t = append(t, d.v...)
+		}
+		k := bits2int(t)
+		// the candidate is consumed: update the state so that a subsequent
+		// call (or a retry) yields a different value.
+		d.k = d.mac(d.v, []byte{0x00})
+		d.v = d.mac(d.v)
+		if k.Sign() > 0 && k.Cmp(order) < 0 {
+			return k, nil
+		}
+	}
+}
+
+// mac returns HMAC_K(data[0] ∥ data[1] ∥ …)
+func (d *rfc6979) mac(data ...[]byte) []byte {
+	m := hmac.New(d.newHash, d.k)
+	for _, b := range data {
+		m.Write(b)
+	}
+	return m.Sum(nil)
+}
+
+// bits2int converts a byte string into an integer and keeps its sizeFrBits
+// left-most bits (RFC 6979, Section 2.3.2).
+func bits2int(b []byte) *big.Int {
+	ret := new(big.Int).SetBytes(b)
+	if excess := len(b)*8 - sizeFrBits; excess > 0 {
+		ret.Rsh(ret, uint(excess))
+	}
+	return ret
+}
@@ -0,0 +1,94 @@
+//go:build !purego
+// +build !purego
+
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fp
+
+// vectorAdd res = a + b, element-wise
+func vectorAdd(res, a, b Vector) {
+	addVec(&res[0], &a[0], &b[0], uint64(len(res)))
+}
+
+// vectorSub res = a - b, element-wise
+func vectorSub(res, a, b Vector) {
+	subVec(&res[0], &a[0], &b[0], uint64(len(res)))
+}
+
+// vectorSum res = a[0] + ... + a[n-1]
+func vectorSum(res *Element, a Vector) {
+	sumVec(res, &a[0], uint64(len(a)))
+}
+
+// vectorScalarMul res = a * b, element-wise
+func vectorScalarMul(res, a Vector, b *Element) {
+	if !supportAdx {
+		scalarMulVecGeneric(res, a, b)
+		return
+	}
+	scalarMulVec(&res[0], &a[0], b, uint64(len(res)))
+}
+
+// vectorMul res = a * b, element-wise
+func vectorMul(res, a, b Vector) {
+	if !supportAdx {
+		mulVecGeneric(res, a, b)
+		return
+	}
+	mulVec(&res[0], &a[0], &b[0], uint64(len(res)))
+}
+
+// vectorAXPY res = alpha * x + res, element-wise
+func vectorAXPY(res Vector, alpha *Element, x Vector) {
+	if !supportAdx {
+		axpyVecGeneric(res, alpha, x)
+		return
+	}
+	axpyVec(&res[0], alpha, &x[0], uint64(len(res)))
+}
+
+// vectorInnerProduct res = a[0]*b[0] + ... + a[n-1]*b[n-1]
+func vectorInnerProduct(res *Element, a, b Vector) {
+	if !supportAdx {
+		innerProductVecGeneric(res, a, b)
+		return
+	}
+	innerProdVec(res, &a[0], &b[0], uint64(len(a)))
+}
+
+//go:noescape
+func addVec(res, a, b *Element, n uint64)
+
+//go:noescape
+func subVec(res, a, b *Element, n uint64)
+
+//go:noescape
+func sumVec(res, a *Element, n uint64)
+
+// the multiplication kernels use the ADX and BMI2 instructions
+
+//go:noescape
+func scalarMulVec(res, a, b *Element, n uint64)
+
+//go:noescape
+func mulVec(res, a, b *Element, n uint64)
+
+//go:noescape
+func axpyVec(res, alpha, x *Element, n uint64)
+
+//go:noescape
+func innerProdVec(res, a, b *Element, n uint64)
@@ -0,0 +1,317 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package starkcurve
+
+import (
+	"crypto/subtle"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fp"
+	"github.com/consensys/gnark-crypto/ecc/stark-curve/fr"
+)
+
+// windowSizeConstantTime is the window size of the constant time scalar
+// multiplication, whose table holds the 2ʷ⁻¹ odd multiples of the point.
+const windowSizeConstantTime = 4
+
+// nbDigitsConstantTime is the number of digits of the scalars in the regular
+// recoding of the constant time scalar multiplication.
+const nbDigitsConstantTime = (fr.Bits + windowSizeConstantTime - 1) / windowSizeConstantTime
+
+// traceConstantTimeG1, if set, records the steps of the constant time
+// scalar multiplication which handle the scalar: the selection of its limbs
+// ('k'), the recoding of its digits ('r'), the entries of the table read by the
+// lookups ('s'), the conditional negations ('n') and selections ('z'), and the
+// blocks of field operations of the complete formulas ('a' and 'd'), with an
+// index. It is only used in tests, to check that this sequence does not depend
+// on the scalar.
+var traceConstantTimeG1 func(op byte, i int)
+
+// g1Proj point in projective coordinates
+type g1Proj struct {
+	x, y, z fp.Element
+}
+
+// ScalarMultiplicationConstantTime computes and returns p = [s]a, where a is in
+// the prime order subgroup, in constant time.
+//
+// Unlike ScalarMultiplication, the sequence of field operations and memory
+// accesses does not depend on the scalar, which can then be a secret (a private
+// key or a nonce). It uses a fixed window method on a regular recoding of the
+// scalar, constant time table lookups and complete addition formulas.
+// The scalar is reduced modulo r, which is not done in constant time: secret
+// scalars should be given reduced.
+func (p *G1Affine) ScalarMultiplicationConstantTime(a *G1Affine, s *big.Int) *G1Affine {
+	// the infinity point (0, 0) in affine coordinates is (0, 1, 0) in projective
+	// coordinates
+	var q, res g1Proj
+	var one fp.Element
+	one.SetOne()
+	isInfinity := isZeroConstantTime(a.X[:]) & isZeroConstantTime(a.Y[:])
+	q.x.Set(&a.X)
+	q.y.Select(isInfinity, &a.Y, &one)
+	q.z.Select(isInfinity, &one, &q.z)
+	res.mulConstantTime(&q, s)
+
+	// the inversion is done with Fermat's little theorem to be constant time
+	var zInv fp.Element
+	zInv.Exp(res.z, pMinusTwo())
+	p.X.Mul(&res.x, &zInv)
+	p.Y.Mul(&res.y, &zInv)
+	return p
+}
+
+// ScalarMultiplicationBaseConstantTime computes and returns p = [s]g, where g is
+// the affine point generating the prime subgroup, in constant time.
+//
+// See ScalarMultiplicationConstantTime.
+func (p *G1Affine) ScalarMultiplicationBaseConstantTime(s *big.Int) *G1Affine {
+	return p.ScalarMultiplicationConstantTime(&g1GenAff, s)
+}
+
+// ScalarMultiplicationConstantTime computes and returns p = [s]q, where q is in
+// the prime order subgroup, in constant time.
+//
+// See G1Affine.ScalarMultiplicationConstantTime.
+func (p *G1Jac) ScalarMultiplicationConstantTime(q *G1Jac, s *big.Int) *G1Jac {
+	// (X, Y, Z) in Jacobian coordinates is (XZ, Y, Z³) in projective coordinates
+	var _q, res g1Proj
+	_q.x.Mul(&q.X, &q.Z)
+	_q.y.Set(&q.Y)
+	_q.z.Square(&q.Z).Mul(&_q.z, &q.Z)
+	res.mulConstantTime(&_q, s)
+
+	// and (X, Y, Z) in projective coordinates is (XZ, YZ², Z) in Jacobian
+	// coordinates, the infinity point being (1, 1, 0)
+	var one fp.Element
+	one.SetOne()
+	isInfinity := isZeroConstantTime(res.z[:])
+	p.X.Mul(&res.x, &res.z).Select(isInfinity, &p.X, &one)
+	p.Y.Square(&res.z).Mul(&p.Y, &res.y).Select(isInfinity, &p.Y, &one)
+	p.Z.Set(&res.z)
+	return p
+}
+
+// mulConstantTime sets p to [s]q in constant time, q being in the prime order subgroup.
+func (p *g1Proj) mulConstantTime(q *g1Proj, s *big.Int) *g1Proj {
+	var e, eNeg fr.Element
+	e.SetBigInt(s)
+	eNeg.Neg(&e)
+
+	// the recoding needs an odd scalar: if s is even, r-s (which is odd) is
+	// used instead and the result is negated. If s=0, the result is replaced
+	// by the infinity point.
+	k, kNeg := e.Bits(), eNeg.Bits()
+	isZero := isZeroConstantTime(k[:])
+	isEven := (k[0] & 1) ^ 1
+	mask := -isEven
+	for i := range k {
+		k[i] = k[i]&^mask | kNeg[i]&mask
+		if traceConstantTimeG1 != nil {
+			traceConstantTimeG1('k', i)
+		}
+	}
+	k[0] |= 1
+	digits := recodeConstantTime(k)
+
+	// table[i] = [2i+1]q
+	var table [1 << (windowSizeConstantTime - 1)]g1Proj
+	var q2 g1Proj
+	table[0] = *q
+	q2.doubleComplete(q)
+	for i := 1; i < len(table); i++ {
+		table[i].addComplete(&table[i-1], &q2)
+	}
+
+	var res, tmp g1Proj
+	res.lookupConstantTime(&table, digits[nbDigitsConstantTime-1])
+	for i := nbDigitsConstantTime - 2; i >= 0; i-- {
+		for j := 0; j < windowSizeConstantTime; j++ {
+			res.doubleComplete(&res)
+		}
+		tmp.lookupConstantTime(&table, digits[i])
+		res.addComplete(&res, &tmp)
+	}
+
+	var yNeg fp.Element
+	yNeg.Neg(&res.y)
+	res.y.Select(int(isEven), &res.y, &yNeg)
+	if traceConstantTimeG1 != nil {
+		traceConstantTimeG1('n', 0)
+	}
+
+	var infinity g1Proj
+	infinity.y.SetOne()
+	p.x.Select(isZero, &res.x, &infinity.x)
+	p.y.Select(isZero, &res.y, &infinity.y)
+	p.z.Select(isZero, &res.z, &infinity.z)
+	if traceConstantTimeG1 != nil {
+		traceConstantTimeG1('z', 0)
+	}
+	return p
+}
+
+// recodeConstantTime returns the regular recoding of the odd scalar k, with
+// the odd digits dᵢ ∈ [-(2ʷ-1), 2ʷ-1] such that k = ∑ dᵢ2ʷⁱ, the last one
+// being positive (Joye–Tunstall).
+func recodeConstantTime(k [fr.Limbs]uint64) (digits [nbDigitsConstantTime]int) {
+	const w = windowSizeConstantTime
+	for i := 0; i < nbDigitsConstantTime-1; i++ {
+		// dᵢ = (k mod 2ʷ⁺¹) - 2ʷ and k = (k - dᵢ) / 2ʷ, which stays odd
+		digits[i] = int(k[0]&((1<<(w+1))-1)) - (1 << w)
+		for j := 0; j < fr.Limbs-1; j++ {
+			k[j] = k[j]>>w | k[j+1]<<(64-w)
+		}
+		k[fr.Limbs-1] >>= w
+		k[0] |= 1
+		if traceConstantTimeG1 != nil {
+			traceConstantTimeG1('r', i)
+		}
+	}
+	digits[nbDigitsConstantTime-1] = int(k[0])
+	return
+}
+
+// lookupConstantTime sets p to [d]q, where table[i] = [2i+1]q and d is odd,
+// reading all the entries of the table.
+func (p *g1Proj) lookupConstantTime(table *[1 << (windowSizeConstantTime - 1)]g1Proj, d int) *g1Proj {
+	sign := d >> 63
+	idx := ((d ^ sign) - sign) >> 1
+	for i := range table {
+		c := subtle.ConstantTimeEq(int32(i), int32(idx))
+		p.x.Select(c, &p.x, &table[i].x)
+		p.y.Select(c, &p.y, &table[i].y)
+		p.z.Select(c, &p.z, &table[i].z)
+		if traceConstantTimeG1 != nil {
+			traceConstantTimeG1('s', i)
+		}
+	}
+	var yNeg fp.Element
+	yNeg.Neg(&p.y)
+	p.y.Select(sign&1, &p.y, &yNeg)
+	if traceConstantTimeG1 != nil {
+		traceConstantTimeG1('n', 0)
+	}
+	return p
+}
+
+// addComplete sets p to q+r with the complete addition formulas for a=1
+// https://eprint.iacr.org/2015/1060.pdf (Algorithm 1, with the multiplications by a omitted)
+func (p *g1Proj) addComplete(q, r *g1Proj) *g1Proj {
+	var t0, t1, t2, t3, t4, t5, x3, y3, z3, b3 fp.Element
+	b3.Double(&bCurveCoeff).Add(&b3, &bCurveCoeff)
+
+	t0.Mul(&q.x, &r.x)
+	t1.Mul(&q.y, &r.y)
+	t2.Mul(&q.z, &r.z)
+	t3.Add(&q.x, &q.y)
+	t4.Add(&r.x, &r.y)
+	t3.Mul(&t3, &t4)
+	t4.Add(&t0, &t1)
+	t3.Sub(&t3, &t4)
+	t4.Add(&q.x, &q.z)
+	t5.Add(&r.x, &r.z)
+	t4.Mul(&t4, &t5)
+	t5.Add(&t0, &t2)
+	t4.Sub(&t4, &t5)
+	t5.Add(&q.y, &q.z)
+	x3.Add(&r.y, &r.z)
+	t5.Mul(&t5, &x3)
+	x3.Add(&t1, &t2)
+	t5.Sub(&t5, &x3)
+	z3.Set(&t4)
+	x3.Mul(&b3, &t2)
+	z3.Add(&x3, &z3)
+	x3.Sub(&t1, &z3)
+	z3.Add(&t1, &z3)
+	y3.Mul(&x3, &z3)
+	t1.Double(&t0)
+	t1.Add(&t1, &t0)
+	t4.Mul(&b3, &t4)
+	t1.Add(&t1, &t2)
+	t2.Sub(&t0, &t2)
+	t4.Add(&t4, &t2)
+	t0.Mul(&t1, &t4)
+	y3.Add(&y3, &t0)
+	t0.Mul(&t5, &t4)
+	x3.Mul(&t3, &x3)
+	x3.Sub(&x3, &t0)
+	t0.Mul(&t3, &t1)
+	z3.Mul(&t5, &z3)
+	z3.Add(&z3, &t0)
+
+	p.x, p.y, p.z = x3, y3, z3
+	if traceConstantTimeG1 != nil {
+		traceConstantTimeG1('a', 0)
+	}
+	return p
+}
+
+// doubleComplete sets p to [2]q with the complete doubling formulas for a=1
+// https://eprint.iacr.org/2015/1060.pdf (Algorithm 3, with the multiplications by a omitted)
+func (p *g1Proj) doubleComplete(q *g1Proj) *g1Proj {
+	var t0, t1, t2, t3, x3, y3, z3, b3 fp.Element
+	b3.Double(&bCurveCoeff).Add(&b3, &bCurveCoeff)
+
+	t0.Square(&q.x)
+	t1.Square(&q.y)
+	t2.Square(&q.z)
+	t3.Mul(&q.x, &q.y)
+	t3.Double(&t3)
+	z3.Mul(&q.x, &q.z)
+	z3.Double(&z3)
+	x3.Set(&z3)
+	y3.Mul(&b3, &t2)
+	y3.Add(&x3, &y3)
+	x3.Sub(&t1, &y3)
+	y3.Add(&t1, &y3)
+	y3.Mul(&x3, &y3)
+	x3.Mul(&t3, &x3)
+	z3.Mul(&b3, &z3)
+	t3.Sub(&t0, &t2)
+	t3.Add(&t3, &z3)
+	z3.Double(&t0)
+	t0.Add(&z3, &t0)
+	t0.Add(&t0, &t2)
+	t0.Mul(&t0, &t3)
+	y3.Add(&y3, &t0)
+	t2.Mul(&q.y, &q.z)
+	t2.Double(&t2)
+	t0.Mul(&t2, &t3)
+	x3.Sub(&x3, &t0)
+	z3.Mul(&t2, &t1)
+	z3.Double(&z3).Double(&z3)
+
+	p.x, p.y, p.z = x3, y3, z3
+	if traceConstantTimeG1 != nil {
+		traceConstantTimeG1('d', 0)
+	}
+	return p
+}
+
+// isZeroConstantTime returns 1 if all the limbs are 0, and 0 otherwise.
+func isZeroConstantTime(limbs []uint64) int {
+	var acc uint64
+	for _, l := range limbs {
+		acc |= l
+	}
+	return int(((acc | -acc) >> 63) ^ 1)
+}
+
+// pMinusTwo returns p-2, the exponent of the inversion by Fermat's little theorem
+func pMinusTwo() *big.Int {
+	res := fp.Modulus()
+	return res.Sub(res, big.NewInt(2))
+}
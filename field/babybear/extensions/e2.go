@@ -0,0 +1,380 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package extensions
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/consensys/gnark-crypto/field/babybear"
+)
+
+// E2 is a degree two finite field extension of babybear,
+//
+//	E2 = babybear[u]/(u²-β), β = 11
+type E2 struct {
+	A0, A1 babybear.Element
+}
+
+// BytesE2 number of bytes needed to represent an E2
+const BytesE2 = 2 * babybear.Bytes
+
+// ErrInvalidEncoding is returned when decoding a non canonical encoding of an
+// element of an extension
+var ErrInvalidEncoding = errors.New("invalid encoding of an extension field element")
+
+// beta is the quadratic non-residue β used to build E2
+var beta = babybear.NewElement(11)
+
+// betaInv is β⁻¹
+var betaInv = babybear.NewElement(549072524)
+
+// Equal returns true if z equals x, false otherwise
+func (z *E2) Equal(x *E2) bool {
+	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1)
+}
+
+// Cmp compares (lexicographic order) z and x and returns:
+//
+//	-1 if z <  x
+//	 0 if z == x
+//	+1 if z >  x
+func (z *E2) Cmp(x *E2) int {
+	if a1 := z.A1.Cmp(&x.A1); a1 != 0 {
+		return a1
+	}
+	return z.A0.Cmp(&x.A0)
+}
+
+// LexicographicallyLargest returns true if this element is strictly lexicographically
+// larger than its negation, false otherwise
+func (z *E2) LexicographicallyLargest() bool {
+	// adapted from github.com/zkcrypto/bls12_381
+	if z.A1.IsZero() {
+		return z.A0.LexicographicallyLargest()
+	}
+	return z.A1.LexicographicallyLargest()
+}
+
+// SetString sets a E2 element from strings
+func (z *E2) SetString(s1, s2 string) *E2 {
+	z.A0.SetString(s1)
+	z.A1.SetString(s2)
+	return z
+}
+
+// SetZero sets an E2 elmt to zero
+func (z *E2) SetZero() *E2 {
+	z.A0.SetZero()
+	z.A1.SetZero()
+	return z
+}
+
+// Set sets an E2 from x
+func (z *E2) Set(x *E2) *E2 {
+	z.A0 = x.A0
+	z.A1 = x.A1
+	return z
+}
+
+// SetElement sets z to the element x of babybear
+func (z *E2) SetElement(x *babybear.Element) *E2 {
+	z.A0.Set(x)
+	z.A1.SetZero()
+	return z
+}
+
+// SetOne sets z to 1 in Montgomery form and returns z
+func (z *E2) SetOne() *E2 {
+	z.A0.SetOne()
+	z.A1.SetZero()
+	return z
+}
+
+// SetRandom sets a0 and a1 to random values
+func (z *E2) SetRandom() (*E2, error) {
+	if _, err := z.A0.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A1.SetRandom(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E2) IsZero() bool {
+	return z.A0.IsZero() && z.A1.IsZero()
+}
+
+// IsOne returns true if z is one, false otherwise
+func (z *E2) IsOne() bool {
+	return z.A0.IsOne() && z.A1.IsZero()
+}
+
+// Add adds two elements of E2
+func (z *E2) Add(x, y *E2) *E2 {
+	z.A0.Add(&x.A0, &y.A0)
+	z.A1.Add(&x.A1, &y.A1)
+	return z
+}
+
+// Sub subtracts two elements of E2
+func (z *E2) Sub(x, y *E2) *E2 {
+	z.A0.Sub(&x.A0, &y.A0)
+	z.A1.Sub(&x.A1, &y.A1)
+	return z
+}
+
+// Double doubles an E2 element
+func (z *E2) Double(x *E2) *E2 {
+	z.A0.Double(&x.A0)
+	z.A1.Double(&x.A1)
+	return z
+}
+
+// Neg negates an E2 element
+func (z *E2) Neg(x *E2) *E2 {
+	z.A0.Neg(&x.A0)
+	z.A1.Neg(&x.A1)
+	return z
+}
+
+// Halve sets z to z / 2
+func (z *E2) Halve() {
+	z.A0.Halve()
+	z.A1.Halve()
+}
+
+// String implements Stringer interface for fancy printing
+func (z *E2) String() string {
+	return z.A0.String() + "+" + z.A1.String() + "*u"
+}
+
+// MulByElement multiplies an element in E2 by an element in babybear
+func (z *E2) MulByElement(x *E2, y *babybear.Element) *E2 {
+	var yCopy babybear.Element
+	yCopy.Set(y)
+	z.A0.Mul(&x.A0, &yCopy)
+	z.A1.Mul(&x.A1, &yCopy)
+	return z
+}
+
+// Conjugate conjugates an element in E2
+func (z *E2) Conjugate(x *E2) *E2 {
+	z.A0 = x.A0
+	z.A1.Neg(&x.A1)
+	return z
+}
+
+// mulByBeta sets z to β*x in babybear
+func mulByBeta(z, x *babybear.Element) {
+	z.Mul(x, &beta)
+}
+
+// Mul sets z to the E2-product of x,y, returns z
+func (z *E2) Mul(x, y *E2) *E2 {
+	var a, b, c babybear.Element
+	a.Add(&x.A0, &x.A1)
+	b.Add(&y.A0, &y.A1)
+	a.Mul(&a, &b)
+	b.Mul(&x.A0, &y.A0)
+	c.Mul(&x.A1, &y.A1)
+	z.A1.Sub(&a, &b).Sub(&z.A1, &c)
+	mulByBeta(&c, &c)
+	z.A0.Add(&b, &c)
+	return z
+}
+
+// Square sets z to the E2-product of x,x returns z
+func (z *E2) Square(x *E2) *E2 {
+	var a, b babybear.Element
+	a.Mul(&x.A0, &x.A1)
+	b.Square(&x.A1)
+	mulByBeta(&b, &b)
+	z.A0.Square(&x.A0).Add(&z.A0, &b)
+	z.A1.Double(&a)
+	return z
+}
+
+// Inverse sets z to the E2-inverse of x, returns z
+//
+// if x == 0, sets and returns z = x
+func (z *E2) Inverse(x *E2) *E2 {
+	var t babybear.Element
+	x.norm(&t)
+	t.Inverse(&t)
+	z.A0.Mul(&x.A0, &t)
+	z.A1.Mul(&x.A1, &t).Neg(&z.A1)
+	return z
+}
+
+// norm sets x to the norm of z, that is z*conj(z) = a0²-βa1²
+func (z *E2) norm(x *babybear.Element) {
+	var tmp babybear.Element
+	tmp.Square(&z.A1)
+	mulByBeta(&tmp, &tmp)
+	x.Square(&z.A0).Sub(x, &tmp)
+}
+
+// Legendre returns the Legendre symbol of z
+func (z *E2) Legendre() int {
+	var n babybear.Element
+	z.norm(&n)
+	return n.Legendre()
+}
+
+// Exp sets z=xᵏ (mod q²) and returns it
+func (z *E2) Exp(x E2, k *big.Int) *E2 {
+	if k.IsUint64() && k.Uint64() == 0 {
+		return z.SetOne()
+	}
+
+	e := k
+	if k.Sign() == -1 {
+		// negative k, we invert
+		// if k < 0: xᵏ (mod q²) == (x⁻¹)ᵏ (mod q²)
+		x.Inverse(&x)
+
+		// we negate k in a temp big.Int since
+		// Int.Bit(_) of k and -k is different
+		e = new(big.Int).Neg(k)
+	}
+
+	z.SetOne()
+	b := e.Bytes()
+	for i := 0; i < len(b); i++ {
+		w := b[i]
+		for j := 0; j < 8; j++ {
+			z.Square(z)
+			if (w & (0b10000000 >> j)) != 0 {
+				z.Mul(z, &x)
+			}
+		}
+	}
+
+	return z
+}
+
+// Sqrt z = √x (mod q²) if it exists, otherwise returns nil
+//
+// It uses the complex method: if x = a0+a1u and (x0+x1u)² = x, then
+// x0² = (a0 ± √(a0²-βa1²))/2 and x1 = a1/(2x0).
+func (z *E2) Sqrt(x *E2) *E2 {
+	var x0, x1, t babybear.Element
+
+	if x.A1.IsZero() {
+		// x is in babybear, its square root is either in babybear or in u*babybear
+		if x.A0.Legendre() != -1 {
+			x0.Sqrt(&x.A0)
+			z.A0 = x0
+			z.A1.SetZero()
+			return z
+		}
+		// x = (x1*u)² = β*x1²
+		x1.Mul(&x.A0, &betaInv)
+		x1.Sqrt(&x1)
+		z.A0.SetZero()
+		z.A1 = x1
+		return z
+	}
+
+	x.norm(&t)
+	if t.Sqrt(&t) == nil {
+		return nil
+	}
+	x0.Add(&x.A0, &t)
+	x0.Halve()
+	if x0.Legendre() == -1 {
+		x0.Sub(&x.A0, &t)
+		x0.Halve()
+	}
+	if x0.Sqrt(&x0) == nil {
+		return nil
+	}
+	x1.Double(&x0).Inverse(&x1).Mul(&x1, &x.A1)
+	z.A0 = x0
+	z.A1 = x1
+	return z
+}
+
+// Div divides an element in E2 by an element in E2
+func (z *E2) Div(x *E2, y *E2) *E2 {
+	var r E2
+	r.Inverse(y).Mul(x, &r)
+	return z.Set(&r)
+}
+
+// BatchInvertE2 returns a new slice with every element inverted.
+// Uses Montgomery batch inversion trick
+//
+// if a[i] == 0, returns result[i] = a[i]
+func BatchInvertE2(a []E2) []E2 {
+	res := make([]E2, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E2
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i].Set(&accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	return res
+}
+
+// Bytes returns the big endian encoding of z, a0 first
+func (z *E2) Bytes() (res [BytesE2]byte) {
+	b := z.A0.Bytes()
+	copy(res[:], b[:])
+	b = z.A1.Bytes()
+	copy(res[babybear.Bytes:], b[:])
+	return
+}
+
+// SetBytesCanonical sets z from its encoding, as returned by Bytes. It
+// returns an error if the encoding is not canonical.
+func (z *E2) SetBytesCanonical(e []byte) error {
+	if len(e) != BytesE2 {
+		return ErrInvalidEncoding
+	}
+	if err := z.A0.SetBytesCanonical(e[:babybear.Bytes]); err != nil {
+		return ErrInvalidEncoding
+	}
+	if err := z.A1.SetBytesCanonical(e[babybear.Bytes:]); err != nil {
+		return ErrInvalidEncoding
+	}
+	return nil
+}
@@ -0,0 +1,394 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package extensions
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/babybear"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+// ------------------------------------------------------------
+// tests
+
+func TestE4ReceiverIsOperand(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := GenE4()
+	genB := GenE4()
+	genfp := GenFp()
+
+	properties.Property("Having the receiver as operand (addition) should output the same result", prop.ForAll(
+		func(a, b *E4) bool {
+			var c, d E4
+			d.Set(a)
+			c.Add(a, b)
+			a.Add(a, b)
+			b.Add(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (sub) should output the same result", prop.ForAll(
+		func(a, b *E4) bool {
+			var c, d E4
+			d.Set(a)
+			c.Sub(a, b)
+			a.Sub(a, b)
+			b.Sub(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b *E4) bool {
+			var c, d E4
+			d.Set(a)
+			c.Mul(a, b)
+			a.Mul(a, b)
+			b.Mul(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (square) should output the same result", prop.ForAll(
+		func(a *E4) bool {
+			var b E4
+			b.Square(a)
+			a.Square(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (double) should output the same result", prop.ForAll(
+		func(a *E4) bool {
+			var b E4
+			b.Double(a)
+			a.Double(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (mul by non residue) should output the same result", prop.ForAll(
+		func(a *E4) bool {
+			var b E4
+			b.MulByNonResidue(a)
+			a.MulByNonResidue(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (mul by element) should output the same result", prop.ForAll(
+		func(a *E4, b babybear.Element) bool {
+			var c E4
+			c.MulByElement(a, &b)
+			a.MulByElement(a, &b)
+			return a.Equal(&c)
+		},
+		genA,
+		genfp,
+	))
+
+	properties.Property("Having the receiver as operand (Inverse) should output the same result", prop.ForAll(
+		func(a *E4) bool {
+			var b E4
+			b.Inverse(a)
+			a.Inverse(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (Conjugate) should output the same result", prop.ForAll(
+		func(a *E4) bool {
+			var b E4
+			b.Conjugate(a)
+			a.Conjugate(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (Sqrt) should output the same result", prop.ForAll(
+		func(a *E4) bool {
+			var b, c, d, s E4
+
+			s.Square(a)
+			a.Set(&s)
+			b.Set(&s)
+
+			a.Sqrt(a)
+			b.Sqrt(&b)
+
+			c.Square(a)
+			d.Square(&b)
+			return c.Equal(&d)
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE4Ops(t *testing.T) {
+	t.Parallel()
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := GenE4()
+	genB := GenE4()
+	genfp := GenFp()
+
+	properties.Property("sub & add should leave an element invariant", prop.ForAll(
+		func(a, b *E4) bool {
+			var c E4
+			c.Set(a)
+			c.Add(&c, b).Sub(&c, b)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("mul & inverse should leave an element invariant", prop.ForAll(
+		func(a, b *E4) bool {
+			var c, d E4
+			d.Inverse(b)
+			c.Set(a)
+			c.Mul(&c, b).Mul(&c, &d)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("BatchInvertE4 should output the same result as Inverse", prop.ForAll(
+		func(a, b, c *E4) bool {
+
+			batch := BatchInvertE4([]E4{*a, *b, *c})
+			a.Inverse(a)
+			b.Inverse(b)
+			c.Inverse(c)
+			return a.Equal(&batch[0]) && b.Equal(&batch[1]) && c.Equal(&batch[2])
+		},
+		genA,
+		genA,
+		genB,
+	))
+
+	properties.Property("inverse twice should leave an element invariant", prop.ForAll(
+		func(a *E4) bool {
+			var b E4
+			b.Inverse(a).Inverse(&b)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("square and mul should output the same result", prop.ForAll(
+		func(a *E4) bool {
+			var b, c E4
+			b.Mul(a, a)
+			c.Square(a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("mul by element and mul by its embedding should output the same result", prop.ForAll(
+		func(a *E4, b babybear.Element) bool {
+			var c, d E4
+			c.MulByElement(a, &b)
+			d.SetElement(&b).Mul(&d, a)
+			return c.Equal(&d)
+		},
+		genA,
+		genfp,
+	))
+
+	properties.Property("Legendre on square should output 1", prop.ForAll(
+		func(a *E4) bool {
+			var b E4
+			b.Square(a)
+			c := b.Legendre()
+			return c == 1
+		},
+		genA,
+	))
+
+	properties.Property("square(sqrt) should leave an element invariant", prop.ForAll(
+		func(a *E4) bool {
+			var b, c, d, e E4
+			b.Square(a)
+			c.Sqrt(&b)
+			d.Square(&c)
+			e.Neg(a)
+			return (c.Equal(a) || c.Equal(&e)) && d.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("sqrt should return nil on non squares", prop.ForAll(
+		func(a *E4) bool {
+			var b E4
+			if a.Legendre() != -1 {
+				return b.Sqrt(a) != nil
+			}
+			return b.Sqrt(a) == nil
+		},
+		genA,
+	))
+
+	properties.Property("elements of the subfield should be squares", prop.ForAll(
+		func(a *E4) bool {
+			var b E4
+			a.B1.SetZero()
+			if b.Sqrt(a) == nil {
+				return false
+			}
+			b.Square(&b)
+			return b.Equal(a)
+		},
+		genA,
+	))
+
+	properties.Property("Frobenius of x should be equal to its conjugate", prop.ForAll(
+		func(a *E4) bool {
+			var b, c E4
+			q := babybear.Modulus()
+			q.Exp(q, big.NewInt(2), nil)
+			b.Exp(*a, q)
+			c.Conjugate(a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("Exp should be compatible with the group law", prop.ForAll(
+		func(a *E4, s, t big.Int) bool {
+			var b, c, d E4
+			var u big.Int
+			u.Add(&s, &t)
+			b.Exp(*a, &s)
+			c.Exp(*a, &t)
+			d.Exp(*a, &u)
+			b.Mul(&b, &c)
+			return b.Equal(&d)
+		},
+		genA,
+		GenBigInt(),
+		GenBigInt(),
+	))
+
+	properties.Property("SetBytesCanonical(Bytes) should leave an element invariant", prop.ForAll(
+		func(a *E4) bool {
+			var b E4
+			e := a.Bytes()
+			if err := b.SetBytesCanonical(e[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Exp with a negative exponent should invert", prop.ForAll(
+		func(a *E4, s big.Int) bool {
+			var b, c E4
+			var u big.Int
+			u.Neg(&s)
+			b.Exp(*a, &s)
+			c.Exp(*a, &u)
+			b.Mul(&b, &c)
+			return b.IsOne() || a.IsZero()
+		},
+		genA,
+		GenBigInt(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkE4Add(b *testing.B) {
+	var a, c E4
+	_, _ = a.SetRandom()
+	_, _ = c.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Add(&a, &c)
+	}
+}
+
+func BenchmarkE4Mul(b *testing.B) {
+	var a, c E4
+	_, _ = a.SetRandom()
+	_, _ = c.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Mul(&a, &c)
+	}
+}
+
+func BenchmarkE4Square(b *testing.B) {
+	var a E4
+	_, _ = a.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Square(&a)
+	}
+}
+
+func BenchmarkE4Sqrt(b *testing.B) {
+	var a E4
+	_, _ = a.SetRandom()
+	a.Square(&a)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Sqrt(&a)
+	}
+}
+
+func BenchmarkE4Inverse(b *testing.B) {
+	var a E4
+	_, _ = a.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Inverse(&a)
+	}
+}
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/field/generator"
+	"github.com/consensys/gnark-crypto/field/generator/config"
+)
+
+//go:generate go run main.go
+func main() {
+	const modulus = "0x78000001"
+	babybear, err := config.NewFieldConfig("babybear", "Element", modulus, true)
+	if err != nil {
+		panic(err)
+	}
+	if err := generator.GenerateFF(babybear, "../"); err != nil {
+		panic(err)
+	}
+	fmt.Println("successfully generated babybear field")
+
+	// E4 = babybear[v]/(v⁴-11)
+	e4, err := config.NewE4Config(babybear, "github.com/consensys/gnark-crypto/field/babybear", 11, [2]int64{0, 1})
+	if err != nil {
+		panic(err)
+	}
+	if err := generator.GenerateE4(e4, "../extensions"); err != nil {
+		panic(err)
+	}
+	fmt.Println("successfully generated babybear extensions")
+}
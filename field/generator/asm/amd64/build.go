@@ -158,6 +158,17 @@ func Generate(w io.Writer, F *config.FieldConfig) error {
 	// fft butterflies
 	f.generateButterfly()
 
+	// vector operations
+	if F.ASMVector {
+		f.generateAddVec()
+		f.generateSubVec()
+		f.generateSumVec()
+		f.generateMulVec(false)
+		f.generateMulVec(true)
+		f.generateAxpyVec()
+		f.generateInnerProdVec()
+	}
+
 	return nil
 }
 
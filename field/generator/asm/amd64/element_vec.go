@@ -0,0 +1,429 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package amd64
+
+import (
+	"fmt"
+
+	"github.com/consensys/bavard/amd64"
+)
+
+// addVec res = a + b
+//
+//	func addVec(res, a, b *{{.ElementName}}, n uint64) {
+//		for i := 0; i < n; i++ {
+//			res[i].Add(&a[i], &b[i])
+//		}
+//	}
+func (f *FFAmd64) generateAddVec() {
+	f.Comment("addVec(res, a, b *Element, n uint64) res[0...n] = a[0...n] + b[0...n]")
+
+	const argSize = 4 * 8
+	stackSize := f.StackSize(4+2*f.NbWords, 0, 0)
+	registers := f.FnHeader("addVec", stackSize, argSize)
+	defer f.AssertCleanStack(stackSize, 0)
+
+	// registers
+	addrRes := f.Pop(&registers)
+	addrA := f.Pop(&registers)
+	addrB := f.Pop(&registers)
+	n := f.Pop(&registers)
+	a := f.PopN(&registers)
+	t := f.PopN(&registers)
+
+	loop := f.NewLabel()
+	done := f.NewLabel()
+
+	f.MOVQ("res+0(FP)", addrRes)
+	f.MOVQ("a+8(FP)", addrA)
+	f.MOVQ("b+16(FP)", addrB)
+	f.MOVQ("n+24(FP)", n)
+
+	f.LABEL(loop)
+	f.TESTQ(n, n)
+	f.JEQ(done)
+
+	// a = a + b
+	f.Mov(addrA, a)
+	f.Add(addrB, a)
+
+	// reduce a
+	f.ReduceElement(a, t)
+
+	// res = a
+	f.Mov(a, addrRes)
+
+	f.Comment("increment pointers to visit next element")
+	f.ADDQ(f.elementSize(), addrA)
+	f.ADDQ(f.elementSize(), addrB)
+	f.ADDQ(f.elementSize(), addrRes)
+	f.DECQ(n)
+	f.JMP(loop)
+
+	f.LABEL(done)
+	f.RET()
+
+	f.Push(&registers, a...)
+	f.Push(&registers, t...)
+	f.Push(&registers, addrRes, addrA, addrB, n)
+}
+
+// subVec res = a - b
+//
+//	func subVec(res, a, b *{{.ElementName}}, n uint64) {
+//		for i := 0; i < n; i++ {
+//			res[i].Sub(&a[i], &b[i])
+//		}
+//	}
+func (f *FFAmd64) generateSubVec() {
+	f.Comment("subVec(res, a, b *Element, n uint64) res[0...n] = a[0...n] - b[0...n]")
+
+	const argSize = 4 * 8
+	stackSize := f.StackSize(4+f.NbWords, 0, 0)
+	registers := f.FnHeader("subVec", stackSize, argSize)
+	defer f.AssertCleanStack(stackSize, 0)
+
+	// registers
+	addrRes := f.Pop(&registers)
+	addrA := f.Pop(&registers)
+	addrB := f.Pop(&registers)
+	n := f.Pop(&registers)
+	a := f.PopN(&registers)
+
+	loop := f.NewLabel()
+	noReduce := f.NewLabel()
+	done := f.NewLabel()
+
+	f.MOVQ("res+0(FP)", addrRes)
+	f.MOVQ("a+8(FP)", addrA)
+	f.MOVQ("b+16(FP)", addrB)
+	f.MOVQ("n+24(FP)", n)
+
+	f.LABEL(loop)
+	f.TESTQ(n, n)
+	f.JEQ(done)
+
+	// a = a - b
+	f.Mov(addrA, a)
+	f.Sub(addrB, a)
+
+	// if a - b underflows, add q
+	f.JCC(noReduce)
+	f.ADDQ(f.qAt(0), a[0])
+	for i := 1; i < f.NbWords; i++ {
+		f.ADCQ(f.qAt(i), a[i])
+	}
+
+	f.LABEL(noReduce)
+
+	// res = a
+	f.Mov(a, addrRes)
+
+	f.Comment("increment pointers to visit next element")
+	f.ADDQ(f.elementSize(), addrA)
+	f.ADDQ(f.elementSize(), addrB)
+	f.ADDQ(f.elementSize(), addrRes)
+	f.DECQ(n)
+	f.JMP(loop)
+
+	f.LABEL(done)
+	f.RET()
+
+	f.Push(&registers, a...)
+	f.Push(&registers, addrRes, addrA, addrB, n)
+}
+
+// sumVec res = a[0] + ... + a[n-1]
+//
+//	func sumVec(res, a *{{.ElementName}}, n uint64) {
+//		res.SetZero()
+//		for i := 0; i < n; i++ {
+//			res.Add(res, &a[i])
+//		}
+//	}
+func (f *FFAmd64) generateSumVec() {
+	f.Comment("sumVec(res, a *Element, n uint64) res = a[0] + ... + a[n-1]")
+
+	const argSize = 3 * 8
+	stackSize := f.StackSize(2+2*f.NbWords, 0, 0)
+	registers := f.FnHeader("sumVec", stackSize, argSize)
+	defer f.AssertCleanStack(stackSize, 0)
+
+	// registers
+	addrA := f.Pop(&registers)
+	n := f.Pop(&registers)
+	acc := f.PopN(&registers)
+	t := f.PopN(&registers)
+
+	loop := f.NewLabel()
+	done := f.NewLabel()
+
+	f.MOVQ("a+8(FP)", addrA)
+	f.MOVQ("n+16(FP)", n)
+
+	// acc = 0
+	for i := 0; i < f.NbWords; i++ {
+		f.XORQ(acc[i], acc[i])
+	}
+
+	f.LABEL(loop)
+	f.TESTQ(n, n)
+	f.JEQ(done)
+
+	// acc = acc + a
+	f.Add(addrA, acc)
+
+	// reduce acc
+	f.ReduceElement(acc, t)
+
+	f.Comment("increment pointer to visit next element")
+	f.ADDQ(f.elementSize(), addrA)
+	f.DECQ(n)
+	f.JMP(loop)
+
+	f.LABEL(done)
+
+	// res = acc, through t since acc may be on the stack
+	f.MOVQ("res+0(FP)", addrA)
+	f.Mov(acc, t)
+	f.Mov(t, addrA)
+	f.RET()
+
+	f.Push(&registers, acc...)
+	f.Push(&registers, t...)
+	f.Push(&registers, addrA, n)
+}
+
+// mulVec res = a * b, element-wise
+//
+//	func mulVec(res, a, b *{{.ElementName}}, n uint64) {
+//		for i := 0; i < n; i++ {
+//			res[i].Mul(&a[i], &b[i])
+//		}
+//	}
+//
+// if scalar is set, b is a single element and the function is scalarMulVec
+//
+//	func scalarMulVec(res, a, b *{{.ElementName}}, n uint64) {
+//		for i := 0; i < n; i++ {
+//			res[i].Mul(&a[i], b)
+//		}
+//	}
+//
+// the caller must check that the CPU supports the ADX and BMI2 instructions.
+func (f *FFAmd64) generateMulVec(scalar bool) {
+	fnName := "mulVec"
+	if scalar {
+		fnName = "scalarMulVec"
+		f.Comment("scalarMulVec(res, a, b *Element, n uint64) res[0...n] = a[0...n] * b")
+	} else {
+		f.Comment("mulVec(res, a, b *Element, n uint64) res[0...n] = a[0...n] * b[0...n]")
+	}
+
+	const argSize = 4 * 8
+	stackSize := f.StackSize(4+2*f.NbWords, len(vecReserved), minVecStackSize)
+	registers := f.FnHeader(fnName, stackSize, argSize, vecReserved...)
+	defer f.AssertCleanStack(stackSize, minVecStackSize)
+
+	// registers
+	addrRes := f.Pop(&registers)
+	addrA := f.Pop(&registers)
+	addrB := f.Pop(&registers)
+	n := f.Pop(&registers)
+	t := registers.PopN(f.NbWords)
+
+	loop := f.NewLabel()
+	done := f.NewLabel()
+
+	f.MOVQ("res+0(FP)", addrRes)
+	f.MOVQ("a+8(FP)", addrA)
+	f.MOVQ("b+16(FP)", addrB)
+	f.MOVQ("n+24(FP)", n)
+
+	f.LABEL(loop)
+	f.TESTQ(n, n)
+	f.JEQ(done)
+
+	// t = a * b
+	f.MulADX(&registers, addrA.At, addrB.At, t)
+
+	// reduce t
+	f.Reduce(&registers, t)
+
+	// res = t
+	f.Mov(t, addrRes)
+
+	f.Comment("increment pointers to visit next element")
+	f.ADDQ(f.elementSize(), addrA)
+	if !scalar {
+		f.ADDQ(f.elementSize(), addrB)
+	}
+	f.ADDQ(f.elementSize(), addrRes)
+	f.DECQ(n)
+	f.JMP(loop)
+
+	f.LABEL(done)
+	f.RET()
+
+	f.Push(&registers, t...)
+	f.Push(&registers, addrRes, addrA, addrB, n)
+}
+
+// axpyVec res = alpha * x + res, element-wise
+//
+//	func axpyVec(res, alpha, x *{{.ElementName}}, n uint64) {
+//		var tmp {{.ElementName}}
+//		for i := 0; i < n; i++ {
+//			tmp.Mul(alpha, &x[i])
+//			res[i].Add(&res[i], &tmp)
+//		}
+//	}
+//
+// the caller must check that the CPU supports the ADX and BMI2 instructions.
+func (f *FFAmd64) generateAxpyVec() {
+	f.Comment("axpyVec(res, alpha, x *Element, n uint64) res[0...n] = alpha * x[0...n] + res[0...n]")
+
+	const argSize = 4 * 8
+	stackSize := f.StackSize(4+2*f.NbWords, len(vecReserved), minVecStackSize)
+	registers := f.FnHeader("axpyVec", stackSize, argSize, vecReserved...)
+	defer f.AssertCleanStack(stackSize, minVecStackSize)
+
+	// registers
+	addrRes := f.Pop(&registers)
+	addrAlpha := f.Pop(&registers)
+	addrX := f.Pop(&registers)
+	n := f.Pop(&registers)
+	t := registers.PopN(f.NbWords)
+
+	loop := f.NewLabel()
+	done := f.NewLabel()
+
+	f.MOVQ("res+0(FP)", addrRes)
+	f.MOVQ("alpha+8(FP)", addrAlpha)
+	f.MOVQ("x+16(FP)", addrX)
+	f.MOVQ("n+24(FP)", n)
+
+	f.LABEL(loop)
+	f.TESTQ(n, n)
+	f.JEQ(done)
+
+	// t = x * alpha
+	f.MulADX(&registers, addrX.At, addrAlpha.At, t)
+	f.Reduce(&registers, t)
+
+	// t = t + res
+	f.Add(addrRes, t)
+	f.Reduce(&registers, t)
+
+	// res = t
+	f.Mov(t, addrRes)
+
+	f.Comment("increment pointers to visit next element")
+	f.ADDQ(f.elementSize(), addrX)
+	f.ADDQ(f.elementSize(), addrRes)
+	f.DECQ(n)
+	f.JMP(loop)
+
+	f.LABEL(done)
+	f.RET()
+
+	f.Push(&registers, t...)
+	f.Push(&registers, addrRes, addrAlpha, addrX, n)
+}
+
+// innerProdVec res = a[0]*b[0] + ... + a[n-1]*b[n-1]
+//
+//	func innerProdVec(res, a, b *{{.ElementName}}, n uint64) {
+//		var tmp {{.ElementName}}
+//		res.SetZero()
+//		for i := 0; i < n; i++ {
+//			tmp.Mul(&a[i], &b[i])
+//			res.Add(res, &tmp)
+//		}
+//	}
+//
+// the caller must check that the CPU supports the ADX and BMI2 instructions.
+func (f *FFAmd64) generateInnerProdVec() {
+	f.Comment("innerProdVec(res, a, b *Element, n uint64) res = a[0]*b[0] + ... + a[n-1]*b[n-1]")
+
+	const argSize = 4 * 8
+	stackSize := f.StackSize(3+3*f.NbWords, len(vecReserved), minVecStackSize)
+	registers := f.FnHeader("innerProdVec", stackSize, argSize, vecReserved...)
+	defer f.AssertCleanStack(stackSize, minVecStackSize)
+
+	// registers
+	addrA := f.Pop(&registers)
+	addrB := f.Pop(&registers)
+	n := f.Pop(&registers)
+	t := registers.PopN(f.NbWords)
+	acc := f.PopN(&registers)
+
+	loop := f.NewLabel()
+	done := f.NewLabel()
+
+	f.MOVQ("a+8(FP)", addrA)
+	f.MOVQ("b+16(FP)", addrB)
+	f.MOVQ("n+24(FP)", n)
+
+	// acc = 0
+	for i := 0; i < f.NbWords; i++ {
+		f.MOVQ(0, acc[i])
+	}
+
+	f.LABEL(loop)
+	f.TESTQ(n, n)
+	f.JEQ(done)
+
+	// t = a * b
+	f.MulADX(&registers, addrA.At, addrB.At, t)
+	f.Reduce(&registers, t)
+
+	// acc = acc + t
+	f.Add(acc, t)
+	f.Reduce(&registers, t)
+	f.Mov(t, acc)
+
+	f.Comment("increment pointers to visit next element")
+	f.ADDQ(f.elementSize(), addrA)
+	f.ADDQ(f.elementSize(), addrB)
+	f.DECQ(n)
+	f.JMP(loop)
+
+	f.LABEL(done)
+
+	// res = acc, through t since acc may be on the stack
+	f.MOVQ("res+0(FP)", addrA)
+	f.Mov(acc, t)
+	f.Mov(t, addrA)
+	f.RET()
+
+	f.Push(&registers, acc...)
+	f.Push(&registers, t...)
+	f.Push(&registers, addrA, addrB, n)
+}
+
+// vecReserved are the registers the multiplication kernels can't allocate:
+// AX and DX are used by MULXQ, and R15 is clobbered by a global variable access
+// when dynamic linking, see https://github.com/ConsenSys/gnark-crypto/issues/113
+var vecReserved = []amd64.Register{amd64.AX, amd64.DX, amd64.R15}
+
+// minVecStackSize forces a frame for the multiplication kernels, so that the
+// assembler saves BP, which is clobbered by MulADX.
+const minVecStackSize = 8
+
+// elementSize returns the size in bytes of an element, as an immediate
+func (f *FFAmd64) elementSize() string {
+	return fmt.Sprintf("$%d", 8*f.NbWords)
+}
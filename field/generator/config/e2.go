@@ -0,0 +1,45 @@
+package config
+
+import (
+	"errors"
+	"math/big"
+)
+
+var errReducible = errors.New("the extension polynomial is reducible")
+
+// E2Config precomputed values used in template for code generation of the
+// quadratic extension of a field
+//
+//	E2 = Fp[u]/(u²-β)
+type E2Config struct {
+	*FieldConfig
+	FieldPackagePath string
+	Beta             int64
+	BetaMod          uint64 // β (mod q)
+	BetaInvMod       uint64 // β⁻¹ (mod q)
+}
+
+// NewE2Config returns a data structure with needed information to generate the
+// quadratic extension of the field F, whose package is fieldPackagePath.
+//
+// It returns an error if β is a square in Fp.
+func NewE2Config(F *FieldConfig, fieldPackagePath string, beta int64) (*E2Config, error) {
+	if F.NbWords != 1 {
+		return nil, errors.New("only single word fields are supported")
+	}
+	q := F.ModulusBig
+
+	// u²-β is irreducible iff β is not a square
+	bBeta := new(big.Int).Mod(big.NewInt(beta), q)
+	if big.Jacobi(bBeta, q) != -1 {
+		return nil, errReducible
+	}
+
+	return &E2Config{
+		FieldConfig:      F,
+		FieldPackagePath: fieldPackagePath,
+		Beta:             beta,
+		BetaMod:          bBeta.Uint64(),
+		BetaInvMod:       new(big.Int).ModInverse(bBeta, q).Uint64(),
+	}, nil
+}
@@ -0,0 +1,47 @@
+package config
+
+import (
+	"math/big"
+)
+
+// E3Config precomputed values used in template for code generation of the
+// degree 2 and 3 extensions of a field
+//
+//	E2 = Fp[u]/(u²-β)
+//	E3 = Fp[v]/(v³-γ)
+type E3Config struct {
+	*E2Config
+	Gamma    int64
+	GammaMod uint64 // γ (mod q)
+}
+
+// NewE3Config returns a data structure with needed information to generate the
+// degree 2 and 3 extensions of the field F, whose package is fieldPackagePath.
+//
+// It returns an error if β is a square in Fp, or γ is a cube in Fp.
+func NewE3Config(F *FieldConfig, fieldPackagePath string, beta, gamma int64) (*E3Config, error) {
+	e2, err := NewE2Config(F, fieldPackagePath, beta)
+	if err != nil {
+		return nil, err
+	}
+	q := F.ModulusBig
+
+	// v³-γ is irreducible iff γ is not a cube, that is iff q = 1 (mod 3) and
+	// γ^((q-1)/3) ≠ 1
+	bGamma := new(big.Int).Mod(big.NewInt(gamma), q)
+	var e, t big.Int
+	e.Sub(q, big.NewInt(1))
+	if t.Mod(&e, big.NewInt(3)).Sign() != 0 {
+		return nil, errReducible
+	}
+	e.Div(&e, big.NewInt(3))
+	if t.Exp(bGamma, &e, q).Cmp(big.NewInt(1)) == 0 {
+		return nil, errReducible
+	}
+
+	return &E3Config{
+		E2Config: e2,
+		Gamma:    gamma,
+		GammaMod: bGamma.Uint64(),
+	}, nil
+}
@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewE3Config(t *testing.T) {
+	assert := require.New(t)
+
+	goldilocks, err := NewFieldConfig("goldilocks", "Element", "0xFFFFFFFF00000001", false)
+	assert.NoError(err)
+
+	e3, err := NewE3Config(goldilocks, "github.com/consensys/gnark-crypto/field/goldilocks", 7, 2)
+	assert.NoError(err)
+	assert.Equal(uint64(7), e3.BetaMod)
+	assert.Equal(uint64(2), e3.GammaMod)
+
+	// 8 = 2³ is a cube
+	_, err = NewE3Config(goldilocks, "", 7, 8)
+	assert.ErrorIs(err, errReducible)
+
+	// 4 = 2² is a square
+	_, err = NewE3Config(goldilocks, "", 4, 2)
+	assert.ErrorIs(err, errReducible)
+
+	// there is no irreducible binomial of degree 3 when q ≠ 1 (mod 3)
+	koalabear, err := NewFieldConfig("koalabear", "Element", "0x7f000001", false)
+	assert.NoError(err)
+	_, err = NewE3Config(koalabear, "", 3, 2)
+	assert.ErrorIs(err, errReducible)
+}
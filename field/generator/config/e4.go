@@ -0,0 +1,50 @@
+package config
+
+import (
+	"math/big"
+)
+
+// E4Config precomputed values used in template for code generation of the
+// degree 4 extension of a field, built as the tower
+//
+//	E2 = Fp[u]/(u²-β)
+//	E4 = E2[v]/(v²-ξ), ξ = ξ₀+ξ₁u
+type E4Config struct {
+	*E2Config
+	Xi    [2]int64
+	XiMod [2]uint64 // ξ (mod q)
+	XiIsU bool      // ξ = u, in which case E4 = Fp[v]/(v⁴-β)
+}
+
+// NewE4Config returns a data structure with needed information to generate the
+// degree 4 extension of the field F, whose package is fieldPackagePath.
+//
+// It returns an error if β is a square in Fp, or ξ is a square in E2.
+func NewE4Config(F *FieldConfig, fieldPackagePath string, beta int64, xi [2]int64) (*E4Config, error) {
+	e2, err := NewE2Config(F, fieldPackagePath, beta)
+	if err != nil {
+		return nil, err
+	}
+	q := F.ModulusBig
+	mod := func(v int64) *big.Int {
+		return new(big.Int).Mod(big.NewInt(v), q)
+	}
+
+	// v²-ξ is irreducible iff ξ is not a square in E2, that is iff its norm
+	// ξ₀²-βξ₁² is not a square in Fp
+	bXi0, bXi1 := mod(xi[0]), mod(xi[1])
+	var norm, t big.Int
+	norm.Mul(bXi0, bXi0)
+	t.Mul(bXi1, bXi1).Mul(&t, new(big.Int).SetUint64(e2.BetaMod))
+	norm.Sub(&norm, &t).Mod(&norm, q)
+	if big.Jacobi(&norm, q) != -1 {
+		return nil, errReducible
+	}
+
+	return &E4Config{
+		E2Config: e2,
+		Xi:       xi,
+		XiMod:    [2]uint64{bXi0.Uint64(), bXi1.Uint64()},
+		XiIsU:    xi[0] == 0 && xi[1] == 1,
+	}, nil
+}
@@ -0,0 +1,35 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewE4Config(t *testing.T) {
+	assert := require.New(t)
+
+	babybear, err := NewFieldConfig("babybear", "Element", "0x78000001", false)
+	assert.NoError(err)
+
+	e4, err := NewE4Config(babybear, "github.com/consensys/gnark-crypto/field/babybear", 11, [2]int64{0, 1})
+	assert.NoError(err)
+	assert.True(e4.XiIsU)
+	assert.Equal(uint64(11), e4.BetaMod)
+	assert.Equal(uint64(1), (e4.BetaMod*e4.BetaInvMod)%babybear.ModulusBig.Uint64())
+
+	// 4 = 2² is a square
+	_, err = NewE4Config(babybear, "", 4, [2]int64{0, 1})
+	assert.ErrorIs(err, errReducible)
+
+	// 11 is a square in E2
+	_, err = NewE4Config(babybear, "", 11, [2]int64{11, 0})
+	assert.ErrorIs(err, errReducible)
+
+	m31, err := NewFieldConfig("mersenne31", "Element", "0x7fffffff", false)
+	assert.NoError(err)
+	e4, err = NewE4Config(m31, "github.com/consensys/gnark-crypto/field/mersenne31", -1, [2]int64{2, 1})
+	assert.NoError(err)
+	assert.False(e4.XiIsU)
+	assert.Equal(uint64(0x7ffffffe), e4.BetaMod)
+}
@@ -52,6 +52,7 @@ type FieldConfig struct {
 	QInverse                  []uint64
 	QMinusOneHalvedP          []uint64 // ((q-1) / 2 ) + 1
 	ASM                       bool
+	ASMVector                 bool // amd64 assembly for the element-wise operations on vectors
 	RSquare                   []uint64
 	One, Thirteen             []uint64
 	LegendreExponent          string // big.Int to base16 string
@@ -261,6 +262,9 @@ func NewFieldConfig(packageName, elementName, modulus string, useAddChain bool)
 	// asm code generation for moduli with more than 6 words can be optimized further
 	F.ASM = F.NoCarry && F.NbWords <= 12 && F.NbWords > 1
 
+	// the vector kernels keep two elements and the pointers in registers
+	F.ASMVector = F.ASM && F.NbWords <= 5
+
 	return F, nil
 }
 
@@ -14,6 +14,7 @@ import (
 	"github.com/consensys/gnark-crypto/field/generator/config"
 	"github.com/consensys/gnark-crypto/field/generator/internal/addchain"
 	"github.com/consensys/gnark-crypto/field/generator/internal/templates/element"
+	"github.com/consensys/gnark-crypto/field/generator/internal/templates/extensions"
 )
 
 // GenerateFF will generate go (and .s) files in outputDir for modulus (in base 10)
@@ -218,6 +219,32 @@ func GenerateFF(F *config.FieldConfig, outputDir string) error {
 		}
 	}
 
+	if F.ASMVector {
+		// generate vector_amd64.go
+		pathSrc := filepath.Join(outputDir, "vector_amd64.go")
+		bavardOptsCpy := make([]func(*bavard.Bavard) error, len(bavardOpts))
+		copy(bavardOptsCpy, bavardOpts)
+		bavardOptsCpy = append(bavardOptsCpy, bavard.BuildTag("!purego"))
+		if err := bavard.GenerateFromString(pathSrc, []string{element.VectorOpsAMD64}, F, bavardOptsCpy...); err != nil {
+			return err
+		}
+	} else {
+		_ = os.Remove(filepath.Join(outputDir, "vector_amd64.go"))
+	}
+
+	{
+		// generate vector_purego.go
+		pathSrc := filepath.Join(outputDir, "vector_purego.go")
+		bavardOptsCpy := make([]func(*bavard.Bavard) error, len(bavardOpts))
+		copy(bavardOptsCpy, bavardOpts)
+		if F.ASMVector {
+			bavardOptsCpy = append(bavardOptsCpy, bavard.BuildTag("!amd64 purego"))
+		}
+		if err := bavard.GenerateFromString(pathSrc, []string{element.VectorOpsNoAsm}, F, bavardOptsCpy...); err != nil {
+			return err
+		}
+	}
+
 	{
 		// generate doc.go
 		src := []string{
@@ -267,6 +294,75 @@ func GenerateFF(F *config.FieldConfig, outputDir string) error {
 	return nil
 }
 
+// GenerateE4 will generate go files in outputDir for the package extensions,
+// containing the degree 2 and 4 extensions of the field described by E
+//
+// Example usage
+//
+//	e4, _ = config.NewE4Config(fp, fpPackagePath, 11, [2]int64{0, 1})
+//	generator.GenerateE4(e4, filepath.Join(baseDir, "fp", "extensions"))
+func GenerateE4(E *config.E4Config, outputDir string) error {
+	// the tests of E2 and E4 share the same template
+	testE2 := strings.NewReplacer("EXT", "E2", "TOP", "A1", "HALFDEG", "1", "NONRESIDUE", "true").Replace(extensions.Test)
+	testE4 := strings.NewReplacer("EXT", "E4", "TOP", "B1", "HALFDEG", "2", "NONRESIDUE", "true").Replace(extensions.Test)
+
+	return generateExtensions(E, outputDir, []extensionEntry{
+		{"doc.go", []string{extensions.DocE4}},
+		{"e2.go", []string{extensions.E2}},
+		{"e4.go", []string{extensions.E4}},
+		{"generators_test.go", []string{extensions.Generators, extensions.GenE4}},
+		{"e2_test.go", []string{testE2}},
+		{"e4_test.go", []string{testE4}},
+	})
+}
+
+// GenerateE3 will generate go files in outputDir for the package extensions,
+// containing the degree 2 and 3 extensions of the field described by E
+//
+// Example usage
+//
+//	e3, _ = config.NewE3Config(fp, fpPackagePath, 7, 2)
+//	generator.GenerateE3(e3, filepath.Join(baseDir, "fp", "extensions"))
+func GenerateE3(E *config.E3Config, outputDir string) error {
+	testE2 := strings.NewReplacer("EXT", "E2", "TOP", "A1", "HALFDEG", "1", "NONRESIDUE", "false").Replace(extensions.Test)
+
+	return generateExtensions(E, outputDir, []extensionEntry{
+		{"doc.go", []string{extensions.DocE3}},
+		{"e2.go", []string{extensions.E2}},
+		{"e3.go", []string{extensions.E3}},
+		{"generators_test.go", []string{extensions.Generators, extensions.GenE3}},
+		{"e2_test.go", []string{testE2}},
+		{"e3_test.go", []string{extensions.TestE3}},
+	})
+}
+
+// extensionEntry is a file of the package extensions, and its templates
+type extensionEntry struct {
+	file      string
+	templates []string
+}
+
+// generateExtensions executes the templates of the entries with data, in outputDir
+func generateExtensions(data interface{}, outputDir string, entries []extensionEntry) error {
+	bavardOpts := []func(*bavard.Bavard) error{
+		bavard.Apache2("ConsenSys Software Inc.", 2020),
+		bavard.Package("extensions"),
+		bavard.GeneratedBy("consensys/gnark-crypto"),
+	}
+
+	for _, entry := range entries {
+		if err := bavard.GenerateFromString(filepath.Join(outputDir, entry.file), entry.templates, data, bavardOpts...); err != nil {
+			return err
+		}
+	}
+
+	// run go fmt on whole directory
+	cmd := exec.Command("gofmt", "-s", "-w", outputDir)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
 func shorten(input string) string {
 	const maxLen = 15
 	if len(input) > maxLen {
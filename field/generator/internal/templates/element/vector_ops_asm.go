@@ -0,0 +1,80 @@
+package element
+
+// VectorOpsAMD64 is included with AMD64 builds when the field has assembly kernels for vectors
+const VectorOpsAMD64 = `
+
+// vectorAdd res = a + b, element-wise
+func vectorAdd(res, a, b Vector) {
+	addVec(&res[0], &a[0], &b[0], uint64(len(res)))
+}
+
+// vectorSub res = a - b, element-wise
+func vectorSub(res, a, b Vector) {
+	subVec(&res[0], &a[0], &b[0], uint64(len(res)))
+}
+
+// vectorSum res = a[0] + ... + a[n-1]
+func vectorSum(res *{{.ElementName}}, a Vector) {
+	sumVec(res, &a[0], uint64(len(a)))
+}
+
+// vectorScalarMul res = a * b, element-wise
+func vectorScalarMul(res, a Vector, b *{{.ElementName}}) {
+	if !supportAdx {
+		scalarMulVecGeneric(res, a, b)
+		return
+	}
+	scalarMulVec(&res[0], &a[0], b, uint64(len(res)))
+}
+
+// vectorMul res = a * b, element-wise
+func vectorMul(res, a, b Vector) {
+	if !supportAdx {
+		mulVecGeneric(res, a, b)
+		return
+	}
+	mulVec(&res[0], &a[0], &b[0], uint64(len(res)))
+}
+
+// vectorAXPY res = alpha * x + res, element-wise
+func vectorAXPY(res Vector, alpha *{{.ElementName}}, x Vector) {
+	if !supportAdx {
+		axpyVecGeneric(res, alpha, x)
+		return
+	}
+	axpyVec(&res[0], alpha, &x[0], uint64(len(res)))
+}
+
+// vectorInnerProduct res = a[0]*b[0] + ... + a[n-1]*b[n-1]
+func vectorInnerProduct(res *{{.ElementName}}, a, b Vector) {
+	if !supportAdx {
+		innerProductVecGeneric(res, a, b)
+		return
+	}
+	innerProdVec(res, &a[0], &b[0], uint64(len(a)))
+}
+
+//go:noescape
+func addVec(res, a, b *{{.ElementName}}, n uint64)
+
+//go:noescape
+func subVec(res, a, b *{{.ElementName}}, n uint64)
+
+//go:noescape
+func sumVec(res, a *{{.ElementName}}, n uint64)
+
+// the multiplication kernels use the ADX and BMI2 instructions
+
+//go:noescape
+func scalarMulVec(res, a, b *{{.ElementName}}, n uint64)
+
+//go:noescape
+func mulVec(res, a, b *{{.ElementName}}, n uint64)
+
+//go:noescape
+func axpyVec(res, alpha, x *{{.ElementName}}, n uint64)
+
+//go:noescape
+func innerProdVec(res, a, b *{{.ElementName}}, n uint64)
+
+`
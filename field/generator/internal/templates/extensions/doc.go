@@ -0,0 +1,30 @@
+package extensions
+
+const DocE4 = `
+// Package extensions provides the degree 2 and 4 extensions of {{.PackageName}}, built as the tower
+//
+//	E2 = {{.PackageName}}[u]/(u²-β), β = {{.Beta}}
+//	E4 = E2[v]/(v²-ξ), ξ = {{index .Xi 0}}+{{index .Xi 1}}*u
+//
+// The API mirrors the one of the towers of the pairing friendly curves.
+//
+// # Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+package extensions
+`
+
+const DocE3 = `
+// Package extensions provides the degree 2 and 3 extensions of {{.PackageName}}
+//
+//	E2 = {{.PackageName}}[u]/(u²-β), β = {{.Beta}}
+//	E3 = {{.PackageName}}[v]/(v³-γ), γ = {{.Gamma}}
+//
+// They are typically used to draw the random challenges of protocols over
+// {{.PackageName}} (such as FRI) from a field large enough for their soundness.
+//
+// # Warning
+//
+// This code has not been audited and is provided as-is. In particular, there is no security guarantees such as constant time implementation or side-channel attack resistance.
+package extensions
+`
@@ -0,0 +1,356 @@
+package extensions
+
+const E3 = `
+import (
+	"math/big"
+
+	"{{.FieldPackagePath}}"
+)
+
+{{- $fp := print .PackageName "." .ElementName}}
+
+// E3 is a degree three finite field extension of {{.PackageName}},
+//
+//	E3 = {{.PackageName}}[v]/(v³-γ), γ = {{.Gamma}}
+type E3 struct {
+	A0, A1, A2 {{$fp}}
+}
+
+// BytesE3 number of bytes needed to represent an E3
+const BytesE3 = 3 * {{.PackageName}}.Bytes
+
+{{- if ne .Gamma 2}}
+
+// gamma is the cubic non-residue γ used to build E3
+var gamma = {{.PackageName}}.NewElement({{.GammaMod}})
+{{- end}}
+
+// Equal returns true if z equals x, false otherwise
+func (z *E3) Equal(x *E3) bool {
+	return z.A0.Equal(&x.A0) && z.A1.Equal(&x.A1) && z.A2.Equal(&x.A2)
+}
+
+// SetString sets a E3 element from strings
+func (z *E3) SetString(s0, s1, s2 string) *E3 {
+	z.A0.SetString(s0)
+	z.A1.SetString(s1)
+	z.A2.SetString(s2)
+	return z
+}
+
+// SetZero sets an E3 elmt to zero
+func (z *E3) SetZero() *E3 {
+	z.A0.SetZero()
+	z.A1.SetZero()
+	z.A2.SetZero()
+	return z
+}
+
+// Set sets an E3 from x
+func (z *E3) Set(x *E3) *E3 {
+	z.A0 = x.A0
+	z.A1 = x.A1
+	z.A2 = x.A2
+	return z
+}
+
+// SetElement sets z to the element x of {{.PackageName}}
+func (z *E3) SetElement(x *{{$fp}}) *E3 {
+	z.A0.Set(x)
+	z.A1.SetZero()
+	z.A2.SetZero()
+	return z
+}
+
+// SetOne sets z to 1 in Montgomery form and returns z
+func (z *E3) SetOne() *E3 {
+	z.A0.SetOne()
+	z.A1.SetZero()
+	z.A2.SetZero()
+	return z
+}
+
+// SetRandom sets a0, a1 and a2 to random values
+func (z *E3) SetRandom() (*E3, error) {
+	if _, err := z.A0.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A1.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.A2.SetRandom(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E3) IsZero() bool {
+	return z.A0.IsZero() && z.A1.IsZero() && z.A2.IsZero()
+}
+
+// IsOne returns true if z is one, false otherwise
+func (z *E3) IsOne() bool {
+	return z.A0.IsOne() && z.A1.IsZero() && z.A2.IsZero()
+}
+
+// Add adds two elements of E3
+func (z *E3) Add(x, y *E3) *E3 {
+	z.A0.Add(&x.A0, &y.A0)
+	z.A1.Add(&x.A1, &y.A1)
+	z.A2.Add(&x.A2, &y.A2)
+	return z
+}
+
+// Sub subtracts two elements of E3
+func (z *E3) Sub(x, y *E3) *E3 {
+	z.A0.Sub(&x.A0, &y.A0)
+	z.A1.Sub(&x.A1, &y.A1)
+	z.A2.Sub(&x.A2, &y.A2)
+	return z
+}
+
+// Double doubles an E3 element
+func (z *E3) Double(x *E3) *E3 {
+	z.A0.Double(&x.A0)
+	z.A1.Double(&x.A1)
+	z.A2.Double(&x.A2)
+	return z
+}
+
+// Neg negates an E3 element
+func (z *E3) Neg(x *E3) *E3 {
+	z.A0.Neg(&x.A0)
+	z.A1.Neg(&x.A1)
+	z.A2.Neg(&x.A2)
+	return z
+}
+
+// Halve sets z to z / 2
+func (z *E3) Halve() {
+	z.A0.Halve()
+	z.A1.Halve()
+	z.A2.Halve()
+}
+
+// String implements Stringer interface for fancy printing
+func (z *E3) String() string {
+	return z.A0.String() + "+(" + z.A1.String() + ")*v+(" + z.A2.String() + ")*v**2"
+}
+
+// MulByElement multiplies an element in E3 by an element in {{.PackageName}}
+func (z *E3) MulByElement(x *E3, y *{{$fp}}) *E3 {
+	var yCopy {{$fp}}
+	yCopy.Set(y)
+	z.A0.Mul(&x.A0, &yCopy)
+	z.A1.Mul(&x.A1, &yCopy)
+	z.A2.Mul(&x.A2, &yCopy)
+	return z
+}
+
+// mulByGamma sets z to γ*x in {{.PackageName}}
+func mulByGamma(z, x *{{$fp}}) {
+	{{- if eq .Gamma 2}}
+	z.Double(x)
+	{{- else}}
+	z.Mul(x, &gamma)
+	{{- end}}
+}
+
+// MulByNonResidue multiplies a E3 by v, where v³ = γ
+func (z *E3) MulByNonResidue(x *E3) *E3 {
+	var a {{$fp}}
+	mulByGamma(&a, &x.A2)
+	z.A2 = x.A1
+	z.A1 = x.A0
+	z.A0 = a
+	return z
+}
+
+// Mul sets z to the E3-product of x,y, returns z
+func (z *E3) Mul(x, y *E3) *E3 {
+	// Karatsuba, with v³ = γ
+	var v0, v1, v2, t0, t1, c0, c1, c2 {{$fp}}
+	v0.Mul(&x.A0, &y.A0)
+	v1.Mul(&x.A1, &y.A1)
+	v2.Mul(&x.A2, &y.A2)
+
+	t0.Add(&x.A1, &x.A2)
+	t1.Add(&y.A1, &y.A2)
+	c0.Mul(&t0, &t1).Sub(&c0, &v1).Sub(&c0, &v2)
+	mulByGamma(&c0, &c0)
+	c0.Add(&c0, &v0)
+
+	t0.Add(&x.A0, &x.A1)
+	t1.Add(&y.A0, &y.A1)
+	c1.Mul(&t0, &t1).Sub(&c1, &v0).Sub(&c1, &v1)
+	mulByGamma(&t0, &v2)
+	c1.Add(&c1, &t0)
+
+	t0.Add(&x.A0, &x.A2)
+	t1.Add(&y.A0, &y.A2)
+	c2.Mul(&t0, &t1).Sub(&c2, &v0).Sub(&c2, &v2).Add(&c2, &v1)
+
+	z.A0 = c0
+	z.A1 = c1
+	z.A2 = c2
+	return z
+}
+
+// Square sets z to the E3-product of x,x, returns z
+func (z *E3) Square(x *E3) *E3 {
+	// Chung-Hasan SQR2, with v³ = γ
+	var s0, s1, s2, s3, s4 {{$fp}}
+	s0.Square(&x.A0)
+	s1.Mul(&x.A0, &x.A1).Double(&s1)
+	s2.Sub(&x.A0, &x.A1).Add(&s2, &x.A2).Square(&s2)
+	s3.Mul(&x.A1, &x.A2).Double(&s3)
+	s4.Square(&x.A2)
+
+	z.A2.Add(&s1, &s2).Add(&z.A2, &s3).Sub(&z.A2, &s0).Sub(&z.A2, &s4)
+	mulByGamma(&s3, &s3)
+	z.A0.Add(&s0, &s3)
+	mulByGamma(&s4, &s4)
+	z.A1.Add(&s1, &s4)
+	return z
+}
+
+// Inverse sets z to the E3-inverse of x, returns z
+//
+// if x == 0, sets and returns z = x
+func (z *E3) Inverse(x *E3) *E3 {
+	// x⁻¹ = (c0 + c1*v + c2*v²) / (a0c0 + γ(a2c1 + a1c2)), where
+	// c0 = a0²-γa1a2, c1 = γa2²-a0a1 and c2 = a1²-a0a2.
+	var c0, c1, c2, t, det {{$fp}}
+	c0.Mul(&x.A1, &x.A2)
+	mulByGamma(&c0, &c0)
+	t.Square(&x.A0)
+	c0.Sub(&t, &c0)
+
+	c1.Square(&x.A2)
+	mulByGamma(&c1, &c1)
+	t.Mul(&x.A0, &x.A1)
+	c1.Sub(&c1, &t)
+
+	c2.Square(&x.A1)
+	t.Mul(&x.A0, &x.A2)
+	c2.Sub(&c2, &t)
+
+	det.Mul(&x.A2, &c1)
+	t.Mul(&x.A1, &c2)
+	det.Add(&det, &t)
+	mulByGamma(&det, &det)
+	t.Mul(&x.A0, &c0)
+	det.Add(&det, &t)
+	det.Inverse(&det)
+
+	z.A0.Mul(&c0, &det)
+	z.A1.Mul(&c1, &det)
+	z.A2.Mul(&c2, &det)
+	return z
+}
+
+// Exp sets z=xᵏ (mod q³) and returns it
+func (z *E3) Exp(x E3, k *big.Int) *E3 {
+	if k.IsUint64() && k.Uint64() == 0 {
+		return z.SetOne()
+	}
+
+	e := k
+	if k.Sign() == -1 {
+		// negative k, we invert
+		// if k < 0: xᵏ (mod q³) == (x⁻¹)ᵏ (mod q³)
+		x.Inverse(&x)
+
+		// we negate k in a temp big.Int since
+		// Int.Bit(_) of k and -k is different
+		e = new(big.Int).Neg(k)
+	}
+
+	z.SetOne()
+	b := e.Bytes()
+	for i := 0; i < len(b); i++ {
+		w := b[i]
+		for j := 0; j < 8; j++ {
+			z.Square(z)
+			if (w & (0b10000000 >> j)) != 0 {
+				z.Mul(z, &x)
+			}
+		}
+	}
+
+	return z
+}
+
+// Div divides an element in E3 by an element in E3
+func (z *E3) Div(x *E3, y *E3) *E3 {
+	var r E3
+	r.Inverse(y).Mul(x, &r)
+	return z.Set(&r)
+}
+
+// BatchInvertE3 returns a new slice with every element inverted.
+// Uses Montgomery batch inversion trick
+//
+// if a[i] == 0, returns result[i] = a[i]
+func BatchInvertE3(a []E3) []E3 {
+	res := make([]E3, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E3
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i].Set(&accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	return res
+}
+
+// Bytes returns the big endian encoding of z, a0 first
+func (z *E3) Bytes() (res [BytesE3]byte) {
+	b := z.A0.Bytes()
+	copy(res[:], b[:])
+	b = z.A1.Bytes()
+	copy(res[{{.PackageName}}.Bytes:], b[:])
+	b = z.A2.Bytes()
+	copy(res[2*{{.PackageName}}.Bytes:], b[:])
+	return
+}
+
+// SetBytesCanonical sets z from its encoding, as returned by Bytes. It
+// returns an error if the encoding is not canonical.
+func (z *E3) SetBytesCanonical(e []byte) error {
+	if len(e) != BytesE3 {
+		return ErrInvalidEncoding
+	}
+	if err := z.A0.SetBytesCanonical(e[:{{.PackageName}}.Bytes]); err != nil {
+		return ErrInvalidEncoding
+	}
+	if err := z.A1.SetBytesCanonical(e[{{.PackageName}}.Bytes : 2*{{.PackageName}}.Bytes]); err != nil {
+		return ErrInvalidEncoding
+	}
+	if err := z.A2.SetBytesCanonical(e[2*{{.PackageName}}.Bytes:]); err != nil {
+		return ErrInvalidEncoding
+	}
+	return nil
+}
+`
@@ -0,0 +1,397 @@
+package extensions
+
+const E4 = `
+import (
+	"math/big"
+
+	"{{.FieldPackagePath}}"
+)
+
+{{- $fp := print .PackageName "." .ElementName}}
+
+// E4 is a degree two finite field extension of E2,
+//
+//	E4 = E2[v]/(v²-ξ), ξ = {{index .Xi 0}}+{{index .Xi 1}}*u
+{{- if .XiIsU}}
+//
+// that is E4 = {{.PackageName}}[v]/(v⁴-β).
+{{- end}}
+type E4 struct {
+	B0, B1 E2
+}
+
+// BytesE4 number of bytes needed to represent an E4
+const BytesE4 = 2 * BytesE2
+
+{{- if not .XiIsU}}
+
+// xi is the quadratic non-residue ξ of E2 used to build E4
+var xi = E2{
+	A0: {{.PackageName}}.NewElement({{index .XiMod 0}}),
+	A1: {{.PackageName}}.NewElement({{index .XiMod 1}}),
+}
+{{- end}}
+
+// MulByNonResidue multiplies a E2 by the quadratic non-residue ξ = {{index .Xi 0}}+{{index .Xi 1}}*u used to build E4
+func (z *E2) MulByNonResidue(x *E2) *E2 {
+	{{- if .XiIsU}}
+	var a {{$fp}}
+	mulByBeta(&a, &x.A1)
+	z.A1 = x.A0
+	z.A0 = a
+	return z
+	{{- else}}
+	return z.Mul(x, &xi)
+	{{- end}}
+}
+
+// Equal returns true if z equals x, false otherwise
+func (z *E4) Equal(x *E4) bool {
+	return z.B0.Equal(&x.B0) && z.B1.Equal(&x.B1)
+}
+
+// Cmp compares (lexicographic order) z and x and returns:
+//
+//	-1 if z <  x
+//	 0 if z == x
+//	+1 if z >  x
+func (z *E4) Cmp(x *E4) int {
+	if a1 := z.B1.Cmp(&x.B1); a1 != 0 {
+		return a1
+	}
+	return z.B0.Cmp(&x.B0)
+}
+
+// LexicographicallyLargest returns true if this element is strictly lexicographically
+// larger than its negation, false otherwise
+func (z *E4) LexicographicallyLargest() bool {
+	// adapted from github.com/zkcrypto/bls12_381
+	if z.B1.IsZero() {
+		return z.B0.LexicographicallyLargest()
+	}
+	return z.B1.LexicographicallyLargest()
+}
+
+// String puts E4 in string form
+func (z *E4) String() string {
+	return (z.B0.String() + "+(" + z.B1.String() + ")*v")
+}
+
+// SetString sets a E4 from string
+func (z *E4) SetString(s0, s1, s2, s3 string) *E4 {
+	z.B0.SetString(s0, s1)
+	z.B1.SetString(s2, s3)
+	return z
+}
+
+// Set copies x into z and returns z
+func (z *E4) Set(x *E4) *E4 {
+	z.B0 = x.B0
+	z.B1 = x.B1
+	return z
+}
+
+// SetElement sets z to the element x of {{.PackageName}}
+func (z *E4) SetElement(x *{{$fp}}) *E4 {
+	z.B0.SetElement(x)
+	z.B1.SetZero()
+	return z
+}
+
+// SetZero sets an E4 elmt to zero
+func (z *E4) SetZero() *E4 {
+	z.B0.SetZero()
+	z.B1.SetZero()
+	return z
+}
+
+// SetOne sets z to 1 in Montgomery form and returns z
+func (z *E4) SetOne() *E4 {
+	*z = E4{}
+	z.B0.A0.SetOne()
+	return z
+}
+
+// MulByElement multiplies an element in E4 by an element in {{.PackageName}}
+func (z *E4) MulByElement(x *E4, y *{{$fp}}) *E4 {
+	var yCopy {{$fp}}
+	yCopy.Set(y)
+	z.B0.MulByElement(&x.B0, &yCopy)
+	z.B1.MulByElement(&x.B1, &yCopy)
+	return z
+}
+
+// MulByE2 multiplies an element in E4 by an element in E2
+func (z *E4) MulByE2(x *E4, y *E2) *E4 {
+	var yCopy E2
+	yCopy.Set(y)
+	z.B0.Mul(&x.B0, &yCopy)
+	z.B1.Mul(&x.B1, &yCopy)
+	return z
+}
+
+// Add set z=x+y in E4 and return z
+func (z *E4) Add(x, y *E4) *E4 {
+	z.B0.Add(&x.B0, &y.B0)
+	z.B1.Add(&x.B1, &y.B1)
+	return z
+}
+
+// Sub sets z to x sub y and return z
+func (z *E4) Sub(x, y *E4) *E4 {
+	z.B0.Sub(&x.B0, &y.B0)
+	z.B1.Sub(&x.B1, &y.B1)
+	return z
+}
+
+// Double sets z=2*x and returns z
+func (z *E4) Double(x *E4) *E4 {
+	z.B0.Double(&x.B0)
+	z.B1.Double(&x.B1)
+	return z
+}
+
+// Neg negates an E4 element
+func (z *E4) Neg(x *E4) *E4 {
+	z.B0.Neg(&x.B0)
+	z.B1.Neg(&x.B1)
+	return z
+}
+
+// Halve sets z to z / 2
+func (z *E4) Halve() {
+	z.B0.Halve()
+	z.B1.Halve()
+}
+
+// SetRandom used only in tests
+func (z *E4) SetRandom() (*E4, error) {
+	if _, err := z.B0.SetRandom(); err != nil {
+		return nil, err
+	}
+	if _, err := z.B1.SetRandom(); err != nil {
+		return nil, err
+	}
+	return z, nil
+}
+
+// IsZero returns true if z is zero, false otherwise
+func (z *E4) IsZero() bool {
+	return z.B0.IsZero() && z.B1.IsZero()
+}
+
+// IsOne returns true if z is one, false otherwise
+func (z *E4) IsOne() bool {
+	return z.B0.IsOne() && z.B1.IsZero()
+}
+
+// MulByNonResidue mul x by (0,1)
+func (z *E4) MulByNonResidue(x *E4) *E4 {
+	z.B1, z.B0 = x.B0, x.B1
+	z.B0.MulByNonResidue(&z.B0)
+	return z
+}
+
+// Mul set z=x*y in E4 and return z
+func (z *E4) Mul(x, y *E4) *E4 {
+	var a, b, c E2
+	a.Add(&x.B0, &x.B1)
+	b.Add(&y.B0, &y.B1)
+	a.Mul(&a, &b)
+	b.Mul(&x.B0, &y.B0)
+	c.Mul(&x.B1, &y.B1)
+	z.B1.Sub(&a, &b).Sub(&z.B1, &c)
+	z.B0.MulByNonResidue(&c).Add(&z.B0, &b)
+	return z
+}
+
+// Square set z=x*x in E4 and return z
+func (z *E4) Square(x *E4) *E4 {
+
+	//Algorithm 22 from https://eprint.iacr.org/2010/354.pdf
+	var c0, c2, c3 E2
+	c0.Sub(&x.B0, &x.B1)
+	c3.MulByNonResidue(&x.B1).Sub(&x.B0, &c3)
+	c2.Mul(&x.B0, &x.B1)
+	c0.Mul(&c0, &c3).Add(&c0, &c2)
+	z.B1.Double(&c2)
+	c2.MulByNonResidue(&c2)
+	z.B0.Add(&c0, &c2)
+
+	return z
+}
+
+// Inverse set z to the inverse of x in E4 and return z
+//
+// if x == 0, sets and returns z = x
+func (z *E4) Inverse(x *E4) *E4 {
+	// Algorithm 23 from https://eprint.iacr.org/2010/354.pdf
+
+	var t0, t1 E2
+	x.norm(&t0)
+	t1.Inverse(&t0)
+	z.B0.Mul(&x.B0, &t1)
+	z.B1.Mul(&x.B1, &t1).Neg(&z.B1)
+
+	return z
+}
+
+// Exp sets z=xᵏ (mod q⁴) and returns it
+func (z *E4) Exp(x E4, k *big.Int) *E4 {
+	if k.IsUint64() && k.Uint64() == 0 {
+		return z.SetOne()
+	}
+
+	e := k
+	if k.Sign() == -1 {
+		// negative k, we invert
+		// if k < 0: xᵏ (mod q⁴) == (x⁻¹)ᵏ (mod q⁴)
+		x.Inverse(&x)
+
+		// we negate k in a temp big.Int since
+		// Int.Bit(_) of k and -k is different
+		e = new(big.Int).Neg(k)
+	}
+
+	z.SetOne()
+	b := e.Bytes()
+	for i := 0; i < len(b); i++ {
+		w := b[i]
+		for j := 0; j < 8; j++ {
+			z.Square(z)
+			if (w & (0b10000000 >> j)) != 0 {
+				z.Mul(z, &x)
+			}
+		}
+	}
+
+	return z
+}
+
+// Conjugate set z to x conjugated and return z
+func (z *E4) Conjugate(x *E4) *E4 {
+	z.B0 = x.B0
+	z.B1.Neg(&x.B1)
+	return z
+}
+
+// norm sets x to the norm of z, that is z*conj(z) = b0²-ξb1²
+func (z *E4) norm(x *E2) {
+	var tmp E2
+	tmp.Square(&z.B1).MulByNonResidue(&tmp)
+	x.Square(&z.B0).Sub(x, &tmp)
+}
+
+// Legendre returns the Legendre symbol of z
+func (z *E4) Legendre() int {
+	var n E2
+	z.norm(&n)
+	return n.Legendre()
+}
+
+// Sqrt z = √x (mod q⁴) if it exists, otherwise returns nil
+//
+// It uses the complex method: if x = b0+b1v and (x0+x1v)² = x, then
+// x0² = (b0 ± √(b0²-ξb1²))/2 and x1 = b1/(2x0).
+func (z *E4) Sqrt(x *E4) *E4 {
+	var x0, x1, t E2
+
+	if x.B1.IsZero() {
+		// x is in E2, its square root is either in E2 or in v*E2
+		if x.B0.Legendre() != -1 {
+			x0.Sqrt(&x.B0)
+			z.B0 = x0
+			z.B1.SetZero()
+			return z
+		}
+		// x = (x1*v)² = ξ*x1²
+		t.SetOne().MulByNonResidue(&t).Inverse(&t)
+		x1.Mul(&x.B0, &t).Sqrt(&x1)
+		z.B0.SetZero()
+		z.B1 = x1
+		return z
+	}
+
+	x.norm(&t)
+	if t.Sqrt(&t) == nil {
+		return nil
+	}
+	x0.Add(&x.B0, &t)
+	x0.Halve()
+	if x0.Legendre() == -1 {
+		x0.Sub(&x.B0, &t)
+		x0.Halve()
+	}
+	if x0.Sqrt(&x0) == nil {
+		return nil
+	}
+	x1.Double(&x0).Inverse(&x1).Mul(&x1, &x.B1)
+	z.B0 = x0
+	z.B1 = x1
+	return z
+}
+
+// BatchInvertE4 returns a new slice with every element inverted.
+// Uses Montgomery batch inversion trick
+//
+// if a[i] == 0, returns result[i] = a[i]
+func BatchInvertE4(a []E4) []E4 {
+	res := make([]E4, len(a))
+	if len(a) == 0 {
+		return res
+	}
+
+	zeroes := make([]bool, len(a))
+	var accumulator E4
+	accumulator.SetOne()
+
+	for i := 0; i < len(a); i++ {
+		if a[i].IsZero() {
+			zeroes[i] = true
+			continue
+		}
+		res[i].Set(&accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	accumulator.Inverse(&accumulator)
+
+	for i := len(a) - 1; i >= 0; i-- {
+		if zeroes[i] {
+			continue
+		}
+		res[i].Mul(&res[i], &accumulator)
+		accumulator.Mul(&accumulator, &a[i])
+	}
+
+	return res
+}
+
+// Div divides an element in E4 by an element in E4
+func (z *E4) Div(x *E4, y *E4) *E4 {
+	var r E4
+	r.Inverse(y).Mul(x, &r)
+	return z.Set(&r)
+}
+
+// Bytes returns the big endian encoding of z, b0 first
+func (z *E4) Bytes() (res [BytesE4]byte) {
+	b := z.B0.Bytes()
+	copy(res[:], b[:])
+	b = z.B1.Bytes()
+	copy(res[BytesE2:], b[:])
+	return
+}
+
+// SetBytesCanonical sets z from its encoding, as returned by Bytes. It
+// returns an error if the encoding is not canonical.
+func (z *E4) SetBytesCanonical(e []byte) error {
+	if len(e) != BytesE4 {
+		return ErrInvalidEncoding
+	}
+	if err := z.B0.SetBytesCanonical(e[:BytesE2]); err != nil {
+		return err
+	}
+	return z.B1.SetBytesCanonical(e[BytesE2:])
+}
+`
@@ -0,0 +1,794 @@
+package extensions
+
+const Generators = `
+import (
+	"crypto/rand"
+	"math/big"
+
+	"{{.FieldPackagePath}}"
+	"github.com/leanovate/gopter"
+)
+
+{{- $fp := print .PackageName "." .ElementName}}
+
+// GenFp generates an Fp element
+func GenFp() gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		var elmt {{$fp}}
+
+		if _, err := elmt.SetRandom(); err != nil {
+			panic(err)
+		}
+		genResult := gopter.NewGenResult(elmt, gopter.NoShrinker)
+		return genResult
+	}
+}
+
+// E2 generates an E2 elmt
+func GenE2() gopter.Gen {
+	return gopter.CombineGens(
+		GenFp(),
+		GenFp(),
+	).Map(func(values []interface{}) *E2 {
+		return &E2{A0: values[0].({{$fp}}), A1: values[1].({{$fp}})}
+	})
+}
+
+// GenBigInt generates a big.Int
+func GenBigInt() gopter.Gen {
+	return func(genParams *gopter.GenParameters) *gopter.GenResult {
+		var s big.Int
+		var b [{{.PackageName}}.Bytes]byte
+		_, err := rand.Read(b[:]) //#nosec G404 weak rng is fine here
+		if err != nil {
+			panic(err)
+		}
+		s.SetBytes(b[:])
+		genResult := gopter.NewGenResult(s, gopter.NoShrinker)
+		return genResult
+	}
+}
+`
+
+// GenE4 is appended to Generators when the package provides E4
+const GenE4 = `
+// E4 generates an E4 elmt
+func GenE4() gopter.Gen {
+	return gopter.CombineGens(
+		GenE2(),
+		GenE2(),
+	).Map(func(values []interface{}) *E4 {
+		return &E4{B0: *values[0].(*E2), B1: *values[1].(*E2)}
+	})
+}
+`
+
+// GenE3 is appended to Generators when the package provides E3
+const GenE3 = `
+// E3 generates an E3 elmt
+func GenE3() gopter.Gen {
+	return gopter.CombineGens(
+		GenFp(),
+		GenFp(),
+		GenFp(),
+	).Map(func(values []interface{}) *E3 {
+		return &E3{A0: values[0].({{print .PackageName "." .ElementName}}), A1: values[1].({{print .PackageName "." .ElementName}}), A2: values[2].({{print .PackageName "." .ElementName}})}
+	})
+}
+`
+
+// Test is the template of the tests of the extension EXT, whose elements have
+// their TOP coordinate zero when they belong to the subfield. The conjugation
+// is the Frobenius map x -> x^(q^HALFDEG). MulByNonResidue is tested if
+// NONRESIDUE is true.
+const Test = `
+import (
+	"math/big"
+	"testing"
+
+	"{{.FieldPackagePath}}"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+{{- $fp := print .PackageName "." .ElementName}}
+
+// ------------------------------------------------------------
+// tests
+
+func TestEXTReceiverIsOperand(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := GenEXT()
+	genB := GenEXT()
+	genfp := GenFp()
+
+	properties.Property("Having the receiver as operand (addition) should output the same result", prop.ForAll(
+		func(a, b *EXT) bool {
+			var c, d EXT
+			d.Set(a)
+			c.Add(a, b)
+			a.Add(a, b)
+			b.Add(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (sub) should output the same result", prop.ForAll(
+		func(a, b *EXT) bool {
+			var c, d EXT
+			d.Set(a)
+			c.Sub(a, b)
+			a.Sub(a, b)
+			b.Sub(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b *EXT) bool {
+			var c, d EXT
+			d.Set(a)
+			c.Mul(a, b)
+			a.Mul(a, b)
+			b.Mul(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (square) should output the same result", prop.ForAll(
+		func(a *EXT) bool {
+			var b EXT
+			b.Square(a)
+			a.Square(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (double) should output the same result", prop.ForAll(
+		func(a *EXT) bool {
+			var b EXT
+			b.Double(a)
+			a.Double(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	{{- if NONRESIDUE}}
+
+	properties.Property("Having the receiver as operand (mul by non residue) should output the same result", prop.ForAll(
+		func(a *EXT) bool {
+			var b EXT
+			b.MulByNonResidue(a)
+			a.MulByNonResidue(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+	{{- end}}
+
+	properties.Property("Having the receiver as operand (mul by element) should output the same result", prop.ForAll(
+		func(a *EXT, b {{$fp}}) bool {
+			var c EXT
+			c.MulByElement(a, &b)
+			a.MulByElement(a, &b)
+			return a.Equal(&c)
+		},
+		genA,
+		genfp,
+	))
+
+	properties.Property("Having the receiver as operand (Inverse) should output the same result", prop.ForAll(
+		func(a *EXT) bool {
+			var b EXT
+			b.Inverse(a)
+			a.Inverse(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (Conjugate) should output the same result", prop.ForAll(
+		func(a *EXT) bool {
+			var b EXT
+			b.Conjugate(a)
+			a.Conjugate(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (Sqrt) should output the same result", prop.ForAll(
+		func(a *EXT) bool {
+			var b, c, d, s EXT
+
+			s.Square(a)
+			a.Set(&s)
+			b.Set(&s)
+
+			a.Sqrt(a)
+			b.Sqrt(&b)
+
+			c.Square(a)
+			d.Square(&b)
+			return c.Equal(&d)
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestEXTOps(t *testing.T) {
+	t.Parallel()
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := GenEXT()
+	genB := GenEXT()
+	genfp := GenFp()
+
+	properties.Property("sub & add should leave an element invariant", prop.ForAll(
+		func(a, b *EXT) bool {
+			var c EXT
+			c.Set(a)
+			c.Add(&c, b).Sub(&c, b)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("mul & inverse should leave an element invariant", prop.ForAll(
+		func(a, b *EXT) bool {
+			var c, d EXT
+			d.Inverse(b)
+			c.Set(a)
+			c.Mul(&c, b).Mul(&c, &d)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("BatchInvertEXT should output the same result as Inverse", prop.ForAll(
+		func(a, b, c *EXT) bool {
+
+			batch := BatchInvertEXT([]EXT{*a, *b, *c})
+			a.Inverse(a)
+			b.Inverse(b)
+			c.Inverse(c)
+			return a.Equal(&batch[0]) && b.Equal(&batch[1]) && c.Equal(&batch[2])
+		},
+		genA,
+		genA,
+		genB,
+	))
+
+	properties.Property("inverse twice should leave an element invariant", prop.ForAll(
+		func(a *EXT) bool {
+			var b EXT
+			b.Inverse(a).Inverse(&b)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("square and mul should output the same result", prop.ForAll(
+		func(a *EXT) bool {
+			var b, c EXT
+			b.Mul(a, a)
+			c.Square(a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("mul by element and mul by its embedding should output the same result", prop.ForAll(
+		func(a *EXT, b {{$fp}}) bool {
+			var c, d EXT
+			c.MulByElement(a, &b)
+			d.SetElement(&b).Mul(&d, a)
+			return c.Equal(&d)
+		},
+		genA,
+		genfp,
+	))
+
+	properties.Property("Legendre on square should output 1", prop.ForAll(
+		func(a *EXT) bool {
+			var b EXT
+			b.Square(a)
+			c := b.Legendre()
+			return c == 1
+		},
+		genA,
+	))
+
+	properties.Property("square(sqrt) should leave an element invariant", prop.ForAll(
+		func(a *EXT) bool {
+			var b, c, d, e EXT
+			b.Square(a)
+			c.Sqrt(&b)
+			d.Square(&c)
+			e.Neg(a)
+			return (c.Equal(a) || c.Equal(&e)) && d.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("sqrt should return nil on non squares", prop.ForAll(
+		func(a *EXT) bool {
+			var b EXT
+			if a.Legendre() != -1 {
+				return b.Sqrt(a) != nil
+			}
+			return b.Sqrt(a) == nil
+		},
+		genA,
+	))
+
+	properties.Property("elements of the subfield should be squares", prop.ForAll(
+		func(a *EXT) bool {
+			var b EXT
+			a.TOP.SetZero()
+			if b.Sqrt(a) == nil {
+				return false
+			}
+			b.Square(&b)
+			return b.Equal(a)
+		},
+		genA,
+	))
+
+	properties.Property("Frobenius of x should be equal to its conjugate", prop.ForAll(
+		func(a *EXT) bool {
+			var b, c EXT
+			q := {{.PackageName}}.Modulus()
+			q.Exp(q, big.NewInt(HALFDEG), nil)
+			b.Exp(*a, q)
+			c.Conjugate(a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("Exp should be compatible with the group law", prop.ForAll(
+		func(a *EXT, s, t big.Int) bool {
+			var b, c, d EXT
+			var u big.Int
+			u.Add(&s, &t)
+			b.Exp(*a, &s)
+			c.Exp(*a, &t)
+			d.Exp(*a, &u)
+			b.Mul(&b, &c)
+			return b.Equal(&d)
+		},
+		genA,
+		GenBigInt(),
+		GenBigInt(),
+	))
+
+	properties.Property("SetBytesCanonical(Bytes) should leave an element invariant", prop.ForAll(
+		func(a *EXT) bool {
+			var b EXT
+			e := a.Bytes()
+			if err := b.SetBytesCanonical(e[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Exp with a negative exponent should invert", prop.ForAll(
+		func(a *EXT, s big.Int) bool {
+			var b, c EXT
+			var u big.Int
+			u.Neg(&s)
+			b.Exp(*a, &s)
+			c.Exp(*a, &u)
+			b.Mul(&b, &c)
+			return b.IsOne() || a.IsZero()
+		},
+		genA,
+		GenBigInt(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkEXTAdd(b *testing.B) {
+	var a, c EXT
+	_, _ = a.SetRandom()
+	_, _ = c.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Add(&a, &c)
+	}
+}
+
+func BenchmarkEXTMul(b *testing.B) {
+	var a, c EXT
+	_, _ = a.SetRandom()
+	_, _ = c.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Mul(&a, &c)
+	}
+}
+
+func BenchmarkEXTSquare(b *testing.B) {
+	var a EXT
+	_, _ = a.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Square(&a)
+	}
+}
+
+func BenchmarkEXTSqrt(b *testing.B) {
+	var a EXT
+	_, _ = a.SetRandom()
+	a.Square(&a)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Sqrt(&a)
+	}
+}
+
+func BenchmarkEXTInverse(b *testing.B) {
+	var a EXT
+	_, _ = a.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Inverse(&a)
+	}
+}
+`
+
+// TestE3 is the template of the tests of E3
+const TestE3 = `
+import (
+	"math/big"
+	"testing"
+
+	"{{.FieldPackagePath}}"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+{{- $fp := print .PackageName "." .ElementName}}
+
+// ------------------------------------------------------------
+// tests
+
+func TestE3ReceiverIsOperand(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := GenE3()
+	genB := GenE3()
+	genfp := GenFp()
+
+	properties.Property("Having the receiver as operand (addition) should output the same result", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Set(a)
+			c.Add(a, b)
+			a.Add(a, b)
+			b.Add(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (sub) should output the same result", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Set(a)
+			c.Sub(a, b)
+			a.Sub(a, b)
+			b.Sub(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Set(a)
+			c.Mul(a, b)
+			a.Mul(a, b)
+			b.Mul(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (square) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Square(a)
+			a.Square(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (double) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Double(a)
+			a.Double(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (mul by non residue) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.MulByNonResidue(a)
+			a.MulByNonResidue(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (mul by element) should output the same result", prop.ForAll(
+		func(a *E3, b {{$fp}}) bool {
+			var c E3
+			c.MulByElement(a, &b)
+			a.MulByElement(a, &b)
+			return a.Equal(&c)
+		},
+		genA,
+		genfp,
+	))
+
+	properties.Property("Having the receiver as operand (Inverse) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Inverse(a)
+			a.Inverse(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE3Ops(t *testing.T) {
+	t.Parallel()
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := GenE3()
+	genB := GenE3()
+	genfp := GenFp()
+
+	properties.Property("sub & add should leave an element invariant", prop.ForAll(
+		func(a, b *E3) bool {
+			var c E3
+			c.Set(a)
+			c.Add(&c, b).Sub(&c, b)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("mul & inverse should leave an element invariant", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Inverse(b)
+			c.Set(a)
+			c.Mul(&c, b).Mul(&c, &d)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("BatchInvertE3 should output the same result as Inverse", prop.ForAll(
+		func(a, b, c *E3) bool {
+
+			batch := BatchInvertE3([]E3{*a, *b, *c})
+			a.Inverse(a)
+			b.Inverse(b)
+			c.Inverse(c)
+			return a.Equal(&batch[0]) && b.Equal(&batch[1]) && c.Equal(&batch[2])
+		},
+		genA,
+		genA,
+		genB,
+	))
+
+	properties.Property("inverse twice should leave an element invariant", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Inverse(a).Inverse(&b)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("square and mul should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			b.Mul(a, a)
+			c.Square(a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("mul by non residue and mul by v should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b, c, v E3
+			v.A1.SetOne()
+			b.MulByNonResidue(a)
+			c.Mul(a, &v)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("mul by element and mul by its embedding should output the same result", prop.ForAll(
+		func(a *E3, b {{$fp}}) bool {
+			var c, d E3
+			c.MulByElement(a, &b)
+			d.SetElement(&b).Mul(&d, a)
+			return c.Equal(&d)
+		},
+		genA,
+		genfp,
+	))
+
+	properties.Property("Frobenius of order 3 should leave an element invariant", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			q := {{.PackageName}}.Modulus()
+			q.Exp(q, big.NewInt(3), nil)
+			b.Exp(*a, q)
+			return b.Equal(a)
+		},
+		genA,
+	))
+
+	properties.Property("Exp should be compatible with the group law", prop.ForAll(
+		func(a *E3, s, t big.Int) bool {
+			var b, c, d E3
+			var u big.Int
+			u.Add(&s, &t)
+			b.Exp(*a, &s)
+			c.Exp(*a, &t)
+			d.Exp(*a, &u)
+			b.Mul(&b, &c)
+			return b.Equal(&d)
+		},
+		genA,
+		GenBigInt(),
+		GenBigInt(),
+	))
+
+	properties.Property("Exp with a negative exponent should invert", prop.ForAll(
+		func(a *E3, s big.Int) bool {
+			var b, c E3
+			var u big.Int
+			u.Neg(&s)
+			b.Exp(*a, &s)
+			c.Exp(*a, &u)
+			b.Mul(&b, &c)
+			return b.IsOne() || a.IsZero()
+		},
+		genA,
+		GenBigInt(),
+	))
+
+	properties.Property("SetBytesCanonical(Bytes) should leave an element invariant", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			e := a.Bytes()
+			if err := b.SetBytesCanonical(e[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE3NonResidue(t *testing.T) {
+	// v³ = γ
+	var v, g E3
+	v.A1.SetOne()
+	g.Square(&v).Mul(&g, &v)
+
+	var expected {{$fp}}
+	expected.SetInt64({{.Gamma}})
+	if !g.A0.Equal(&expected) || !g.A1.IsZero() || !g.A2.IsZero() {
+		t.Fatal("v³ should be equal to γ")
+	}
+
+	// γ is not a cube
+	var e big.Int
+	e.Sub({{.PackageName}}.Modulus(), big.NewInt(1)).Div(&e, big.NewInt(3))
+	if expected.Exp(expected, &e).IsOne() {
+		t.Fatal("γ should not be a cube")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkE3Add(b *testing.B) {
+	var a, c E3
+	_, _ = a.SetRandom()
+	_, _ = c.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Add(&a, &c)
+	}
+}
+
+func BenchmarkE3Mul(b *testing.B) {
+	var a, c E3
+	_, _ = a.SetRandom()
+	_, _ = c.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Mul(&a, &c)
+	}
+}
+
+func BenchmarkE3Square(b *testing.B) {
+	var a E3
+	_, _ = a.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Square(&a)
+	}
+}
+
+func BenchmarkE3Inverse(b *testing.B) {
+	var a E3
+	_, _ = a.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Inverse(&a)
+	}
+}
+`
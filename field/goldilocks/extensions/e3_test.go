@@ -0,0 +1,340 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package extensions
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/prop"
+)
+
+// ------------------------------------------------------------
+// tests
+
+func TestE3ReceiverIsOperand(t *testing.T) {
+	t.Parallel()
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := GenE3()
+	genB := GenE3()
+	genfp := GenFp()
+
+	properties.Property("Having the receiver as operand (addition) should output the same result", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Set(a)
+			c.Add(a, b)
+			a.Add(a, b)
+			b.Add(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (sub) should output the same result", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Set(a)
+			c.Sub(a, b)
+			a.Sub(a, b)
+			b.Sub(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (mul) should output the same result", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Set(a)
+			c.Mul(a, b)
+			a.Mul(a, b)
+			b.Mul(&d, b)
+			return a.Equal(b) && a.Equal(&c) && b.Equal(&c)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("Having the receiver as operand (square) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Square(a)
+			a.Square(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (double) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Double(a)
+			a.Double(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (mul by non residue) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.MulByNonResidue(a)
+			a.MulByNonResidue(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("Having the receiver as operand (mul by element) should output the same result", prop.ForAll(
+		func(a *E3, b goldilocks.Element) bool {
+			var c E3
+			c.MulByElement(a, &b)
+			a.MulByElement(a, &b)
+			return a.Equal(&c)
+		},
+		genA,
+		genfp,
+	))
+
+	properties.Property("Having the receiver as operand (Inverse) should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Inverse(a)
+			a.Inverse(a)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE3Ops(t *testing.T) {
+	t.Parallel()
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+
+	properties := gopter.NewProperties(parameters)
+
+	genA := GenE3()
+	genB := GenE3()
+	genfp := GenFp()
+
+	properties.Property("sub & add should leave an element invariant", prop.ForAll(
+		func(a, b *E3) bool {
+			var c E3
+			c.Set(a)
+			c.Add(&c, b).Sub(&c, b)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("mul & inverse should leave an element invariant", prop.ForAll(
+		func(a, b *E3) bool {
+			var c, d E3
+			d.Inverse(b)
+			c.Set(a)
+			c.Mul(&c, b).Mul(&c, &d)
+			return c.Equal(a)
+		},
+		genA,
+		genB,
+	))
+
+	properties.Property("BatchInvertE3 should output the same result as Inverse", prop.ForAll(
+		func(a, b, c *E3) bool {
+
+			batch := BatchInvertE3([]E3{*a, *b, *c})
+			a.Inverse(a)
+			b.Inverse(b)
+			c.Inverse(c)
+			return a.Equal(&batch[0]) && b.Equal(&batch[1]) && c.Equal(&batch[2])
+		},
+		genA,
+		genA,
+		genB,
+	))
+
+	properties.Property("inverse twice should leave an element invariant", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			b.Inverse(a).Inverse(&b)
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.Property("square and mul should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b, c E3
+			b.Mul(a, a)
+			c.Square(a)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("mul by non residue and mul by v should output the same result", prop.ForAll(
+		func(a *E3) bool {
+			var b, c, v E3
+			v.A1.SetOne()
+			b.MulByNonResidue(a)
+			c.Mul(a, &v)
+			return b.Equal(&c)
+		},
+		genA,
+	))
+
+	properties.Property("mul by element and mul by its embedding should output the same result", prop.ForAll(
+		func(a *E3, b goldilocks.Element) bool {
+			var c, d E3
+			c.MulByElement(a, &b)
+			d.SetElement(&b).Mul(&d, a)
+			return c.Equal(&d)
+		},
+		genA,
+		genfp,
+	))
+
+	properties.Property("Frobenius of order 3 should leave an element invariant", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			q := goldilocks.Modulus()
+			q.Exp(q, big.NewInt(3), nil)
+			b.Exp(*a, q)
+			return b.Equal(a)
+		},
+		genA,
+	))
+
+	properties.Property("Exp should be compatible with the group law", prop.ForAll(
+		func(a *E3, s, t big.Int) bool {
+			var b, c, d E3
+			var u big.Int
+			u.Add(&s, &t)
+			b.Exp(*a, &s)
+			c.Exp(*a, &t)
+			d.Exp(*a, &u)
+			b.Mul(&b, &c)
+			return b.Equal(&d)
+		},
+		genA,
+		GenBigInt(),
+		GenBigInt(),
+	))
+
+	properties.Property("Exp with a negative exponent should invert", prop.ForAll(
+		func(a *E3, s big.Int) bool {
+			var b, c E3
+			var u big.Int
+			u.Neg(&s)
+			b.Exp(*a, &s)
+			c.Exp(*a, &u)
+			b.Mul(&b, &c)
+			return b.IsOne() || a.IsZero()
+		},
+		genA,
+		GenBigInt(),
+	))
+
+	properties.Property("SetBytesCanonical(Bytes) should leave an element invariant", prop.ForAll(
+		func(a *E3) bool {
+			var b E3
+			e := a.Bytes()
+			if err := b.SetBytesCanonical(e[:]); err != nil {
+				return false
+			}
+			return a.Equal(&b)
+		},
+		genA,
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestE3NonResidue(t *testing.T) {
+	// v³ = γ
+	var v, g E3
+	v.A1.SetOne()
+	g.Square(&v).Mul(&g, &v)
+
+	var expected goldilocks.Element
+	expected.SetInt64(2)
+	if !g.A0.Equal(&expected) || !g.A1.IsZero() || !g.A2.IsZero() {
+		t.Fatal("v³ should be equal to γ")
+	}
+
+	// γ is not a cube
+	var e big.Int
+	e.Sub(goldilocks.Modulus(), big.NewInt(1)).Div(&e, big.NewInt(3))
+	if expected.Exp(expected, &e).IsOne() {
+		t.Fatal("γ should not be a cube")
+	}
+}
+
+// ------------------------------------------------------------
+// benches
+
+func BenchmarkE3Add(b *testing.B) {
+	var a, c E3
+	_, _ = a.SetRandom()
+	_, _ = c.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Add(&a, &c)
+	}
+}
+
+func BenchmarkE3Mul(b *testing.B) {
+	var a, c E3
+	_, _ = a.SetRandom()
+	_, _ = c.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Mul(&a, &c)
+	}
+}
+
+func BenchmarkE3Square(b *testing.B) {
+	var a E3
+	_, _ = a.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Square(&a)
+	}
+}
+
+func BenchmarkE3Inverse(b *testing.B) {
+	var a E3
+	_, _ = a.SetRandom()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		a.Inverse(&a)
+	}
+}
@@ -0,0 +1,28 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+// Package fri provides the FRI (multiplicative) commitment scheme over the
+// goldilocks field.
+//
+// The committed polynomials have coefficients in goldilocks, but the folding
+// challenges are drawn from its degree 3 extension (see package extensions), so
+// that the soundness of the proof doesn't depend on the small size of the
+// field: all the folded polynomials have their coefficients in the extension.
+//
+// The API is the same as the one of the FRI implementations on the scalar
+// fields of the curves, such as ecc/bn254/fr/fri, except that RADIX_2_FRI
+// uses the same proof format as RADIX_4_FRI and RADIX_8_FRI.
+package fri
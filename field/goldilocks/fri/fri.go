@@ -0,0 +1,827 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"math/big"
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/accumulator/merkletree"
+	"github.com/consensys/gnark-crypto/ecc"
+	fiatshamir "github.com/consensys/gnark-crypto/fiat-shamir"
+	fr "github.com/consensys/gnark-crypto/field/goldilocks"
+	"github.com/consensys/gnark-crypto/field/goldilocks/extensions"
+	"github.com/consensys/gnark-crypto/field/goldilocks/fft"
+)
+
+var (
+	ErrLowDegree            = errors.New("the fully folded polynomial in not of degree 1")
+	ErrProximityTestFolding = errors.New("one round of interaction failed")
+	ErrOddSize              = errors.New("the size should be even")
+	ErrMerkleRoot           = errors.New("merkle roots of the opening and the proof of proximity don't coincide")
+	ErrMerklePath           = errors.New("merkle path proof is wrong")
+	ErrRangePosition        = errors.New("the asked opening position is out of range")
+	ErrProofOfWork          = errors.New("the proof of work is invalid")
+	ErrInvalidProof         = errors.New("the proof of proximity is malformed")
+	ErrClaimedValue         = errors.New("the claimed value doesn't match the committed evaluation")
+)
+
+// rho default factor ρ = size_code_word/size_polynomial
+const rho = 8
+
+// defaultNbQueries default number of queries of the verifier
+const defaultNbQueries = 1
+
+// challengeDST domain separation tag used to map the Fiat Shamir challenges
+// to the extension field
+var challengeDST = []byte("GOLDILOCKS-FRI-E3")
+
+// Digest commitment of a polynomial.
+type Digest []byte
+
+// MerkleProof helper structure to build the merkle proof
+// At each step, the verifier queries a fiber of the folding map x -> xʳ, that
+// is r evaluations of the folded polynomial which are stored in a single leaf.
+type MerkleProof struct {
+
+	// ProofSet stores [leaf ∥ node_1 ∥ .. ∥ merkleRoot ], where the leaf is not
+	// hashed. The leaf is the concatenation of the r evaluations of the fiber,
+	// in goldilocks for the first step and in the extension for the next ones.
+	ProofSet [][]byte
+}
+
+// MerkleProof used to open a polynomial
+type OpeningProof struct {
+
+	// those fields are private since they are only needed for
+	// the verification, which is abstracted in the VerifyOpening
+	// method.
+	merkleRoot []byte
+	ProofSet   [][]byte
+	numLeaves  uint64
+	index      uint64
+
+	// ClaimedValue value of the leaf. This field is exported
+	// because it's needed for protocols using polynomial commitment
+	// schemes (to verify an algebraic relation).
+	ClaimedValue fr.Element
+}
+
+// IOPP Interactive Oracle Proof of Proximity
+type IOPP uint
+
+const (
+	// Multiplicative version of FRI, using the map x->x², on a
+	// power of 2 subgroup of goldilocks^{*}.
+	RADIX_2_FRI IOPP = iota
+
+	// Multiplicative version of FRI, using the map x->x⁴, on a
+	// power of 2 subgroup of goldilocks^{*}.
+	RADIX_4_FRI
+
+	// Multiplicative version of FRI, using the map x->x⁸, on a
+	// power of 2 subgroup of goldilocks^{*}.
+	RADIX_8_FRI
+)
+
+// Query contains the data corresponding to a single query of the verifier.
+// It consists of a list of Interactions between the prover and the verifier,
+// one per folding step, where each interaction contains the Merkle proof of the
+// fiber of the folding map queried at this step.
+type Query struct {
+
+	// Interactions[i] is the opening of the i-th folded polynomial on the fiber
+	// queried by the verifier.
+	Interactions []MerkleProof
+}
+
+// ProofOfProximity proof of proximity, attesting that
+// a function is d-close to a low degree polynomial.
+//
+// It is composed of a series of Interactions, emulated with Fiat Shamir,
+type ProofOfProximity struct {
+
+	// ID unique ID attached to the proof of proximity. It's needed for
+	// protocols using Fiat Shamir for instance, where challenges are derived
+	// from the proof of proximity.
+	ID []byte
+
+	// Commitments[i] is the Merkle root of the evaluations of the i-th folded
+	// polynomial. The first one is the commitment of the polynomial.
+	Commitments [][]byte
+
+	// Evaluation stores the evaluation of the fully folded polynomial.
+	// The fully folded polynomial is constant, so only one evaluation is given.
+	Evaluation extensions.E3
+
+	// Nonce is the solution of the proof of work, which is required before the
+	// queries are derived.
+	Nonce uint64
+
+	// Queries contains the openings answering each query of the verifier.
+	Queries []Query
+}
+
+// Iopp interface that an iopp should implement
+type Iopp interface {
+
+	// BuildProofOfProximity creates a proof of proximity that p is d-close to a polynomial
+	// of degree len(p). The proof is built non interactively using Fiat Shamir.
+	BuildProofOfProximity(p []fr.Element) (ProofOfProximity, error)
+
+	// VerifyProofOfProximity verifies the proof of proximity. It returns an error if the
+	// verification fails.
+	VerifyProofOfProximity(proof ProofOfProximity) error
+
+	// Opens a polynomial at gⁱ where i = position.
+	Open(p []fr.Element, position uint64) (OpeningProof, error)
+
+	// Verifies the opening of a polynomial at gⁱ where i = position.
+	VerifyOpening(position uint64, openingProof OpeningProof, pp ProofOfProximity) error
+}
+
+// GetRho returns the default factor ρ = size_code_word/size_polynomial
+func GetRho() int {
+	return rho
+}
+
+// New creates a new IOPP capable to handle degree(size) polynomials.
+//
+// By default, the blowup factor is ρ = GetRho(), the verifier makes a single
+// query and there is no proof of work. See Option to change those parameters.
+func (iopp IOPP) New(size uint64, h hash.Hash, opts ...Option) Iopp {
+	switch iopp {
+	case RADIX_2_FRI:
+		return newRadixFri(size, h, 1, opts...)
+	case RADIX_4_FRI:
+		return newRadixFri(size, h, 2, opts...)
+	case RADIX_8_FRI:
+		return newRadixFri(size, h, 3, opts...)
+	default:
+		panic("iopp name is not recognized")
+	}
+}
+
+// radixFri implements the multiplicative FRI, where the polynomial is folded
+// by a factor r = 2^logRadix at each step, using the map x->xʳ.
+type radixFri struct {
+
+	// hash function that is used for Fiat Shamir and for committing to
+	// the oracles.
+	h hash.Hash
+
+	// logRadices[i] is the logarithm of the folding factor of the i-th step.
+	// They are all equal to logRadix, except the last one which is smaller if
+	// the size of the polynomial is not a power of the radix.
+	logRadices []int
+
+	// nbQueries number of queries of the verifier
+	nbQueries int
+
+	// grindingBits number of zero bits required by the proof of work
+	grindingBits int
+
+	// domain used to build the Reed Solomon code from the given polynomial.
+	// The size of the domain is ρ*size_polynomial.
+	domain *fft.Domain
+}
+
+func newRadixFri(size uint64, h hash.Hash, logRadix int, opts ...Option) radixFri {
+
+	cfg := friOptions(opts...)
+	checkBlowup(cfg.rho)
+	if cfg.nbQueries < 1 {
+		panic("the number of queries should be positive")
+	}
+	if cfg.grindingBits < 0 || cfg.grindingBits > 8*h.Size() {
+		panic("the number of grinding bits should be between 0 and the size of the hash")
+	}
+
+	var res radixFri
+	res.nbQueries = cfg.nbQueries
+	res.grindingBits = cfg.grindingBits
+
+	// computing the folding steps. The polynomial is folded until it is
+	// constant, a constant polynomial being folded once.
+	n := ecc.NextPowerOfTwo(size)
+	logN := bits.TrailingZeros64(n)
+	if logN == 0 {
+		logN = 1
+	}
+	for logN > 0 {
+		l := logRadix
+		if l > logN {
+			l = logN
+		}
+		res.logRadices = append(res.logRadices, l)
+		logN -= l
+	}
+
+	// extending the domain
+	n = n * uint64(cfg.rho)
+
+	// building the domains
+	res.domain = fft.NewDomain(n)
+
+	// hash function
+	res.h = h
+
+	return res
+}
+
+// challenges returns the names of the Fiat Shamir challenges: the folding
+// challenges xᵢ, the seed s0 of the proof of work, then one challenge qⱼ per
+// query.
+func (s radixFri) challenges() []string {
+	nbSteps := len(s.logRadices)
+	res := make([]string, nbSteps+1+s.nbQueries)
+	for i := 0; i < nbSteps; i++ {
+		res[i] = fmt.Sprintf("x%d", i)
+	}
+	res[nbSteps] = "s0"
+	for i := 0; i < s.nbQueries; i++ {
+		res[nbSteps+1+i] = fmt.Sprintf("q%d", i)
+	}
+	return res
+}
+
+// challengeToE3 maps a Fiat Shamir challenge to the extension, with a
+// negligible bias.
+func challengeToE3(x *extensions.E3, challenge []byte) error {
+	coordinates, err := fr.Hash(challenge, challengeDST, 3)
+	if err != nil {
+		return err
+	}
+	x.A0 = coordinates[0]
+	x.A1 = coordinates[1]
+	x.A2 = coordinates[2]
+	return nil
+}
+
+// checkProofOfWork returns true if H(seed ∥ nonce) starts with grindingBits zero bits.
+func (s radixFri) checkProofOfWork(seed []byte, nonce uint64) (bool, error) {
+	if s.grindingBits == 0 {
+		return true, nil
+	}
+	var bNonce [8]byte
+	binary.BigEndian.PutUint64(bNonce[:], nonce)
+	s.h.Reset()
+	if _, err := s.h.Write(seed); err != nil {
+		return false, err
+	}
+	if _, err := s.h.Write(bNonce[:]); err != nil {
+		return false, err
+	}
+	digest := s.h.Sum(nil)
+	s.h.Reset()
+
+	nbZeros := 0
+	for _, b := range digest {
+		nbZeros += bits.LeadingZeros8(b)
+		if b != 0 || nbZeros >= s.grindingBits {
+			break
+		}
+	}
+	return nbZeros >= s.grindingBits, nil
+}
+
+// grind returns the smallest nonce solving the proof of work for seed.
+func (s radixFri) grind(seed []byte) (uint64, error) {
+	for nonce := uint64(0); ; nonce++ {
+		ok, err := s.checkProofOfWork(seed, nonce)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return nonce, nil
+		}
+	}
+}
+
+// deriveQueries binds the nonce of the proof of work to the transcript, and
+// derives the indices of the leaves of the first Merkle tree that the verifier
+// queries.
+func (s radixFri) deriveQueries(fs *fiatshamir.Transcript, challenges []string, nonce uint64) ([]int, error) {
+
+	qis := challenges[len(s.logRadices)+1:]
+
+	var bNonce [8]byte
+	binary.BigEndian.PutUint64(bNonce[:], nonce)
+	if err := fs.Bind(qis[0], bNonce[:]); err != nil {
+		return nil, err
+	}
+
+	var bPos, bNbLeaves big.Int
+	bNbLeaves.SetUint64(s.domain.Cardinality >> s.logRadices[0])
+	res := make([]int, s.nbQueries)
+	for i := range res {
+		binSeed, err := fs.ComputeChallenge(qis[i])
+		if err != nil {
+			return nil, err
+		}
+		bPos.SetBytes(binSeed)
+		bPos.Mod(&bPos, &bNbLeaves)
+		res[i] = int(bPos.Uint64())
+	}
+	return res, nil
+}
+
+// deriveQueriesPositions derives the indices of the leaves that the verifier
+// opens at each step, from the index pos of the leaf opened in the first tree.
+//
+// The leaf j of the i-th tree contains the evaluations of the i-th folded
+// polynomial on the fiber {gᵢ^{j+k*nᵢ/r}, k<r} of x -> xʳ, where nᵢ is the size
+// of the i-th domain and gᵢ its generator. Folding it gives the evaluation of
+// the next polynomial at g_{i+1}ʲ, which is in the leaf j mod n_{i+1}/r' of the
+// next tree.
+func (s radixFri) deriveQueriesPositions(pos int) []int {
+
+	res := make([]int, len(s.logRadices))
+	res[0] = pos
+	n := int(s.domain.Cardinality) >> s.logRadices[0]
+	for i := 1; i < len(res); i++ {
+		nbLeaves := n >> s.logRadices[i]
+		res[i] = res[i-1] % nbLeaves
+		n = nbLeaves
+	}
+
+	return res
+}
+
+// buildMerkleTree commits to the evaluations of a polynomial on a domain of
+// size n, the j-th leaf being the concatenation of the evaluations at
+// g^{j+k*n/r} for k < r = 2^logRadix.
+func (s radixFri) buildMerkleTree(evaluations []fr.Element, logRadix int) *merkletree.StoredTree {
+	tree := merkletree.NewStoredTree(s.h)
+	m := len(evaluations) >> logRadix
+	leaf := make([]byte, fr.Bytes<<logRadix)
+	for j := 0; j < m; j++ {
+		for k := 0; k < 1<<logRadix; k++ {
+			b := evaluations[j+k*m].Bytes()
+			copy(leaf[k*fr.Bytes:], b[:])
+		}
+		tree.Push(leaf)
+	}
+	return tree
+}
+
+// buildMerkleTreeE3 is buildMerkleTree for evaluations in the extension.
+func (s radixFri) buildMerkleTreeE3(evaluations []extensions.E3, logRadix int) *merkletree.StoredTree {
+	tree := merkletree.NewStoredTree(s.h)
+	m := len(evaluations) >> logRadix
+	leaf := make([]byte, extensions.BytesE3<<logRadix)
+	for j := 0; j < m; j++ {
+		for k := 0; k < 1<<logRadix; k++ {
+			b := evaluations[j+k*m].Bytes()
+			copy(leaf[k*extensions.BytesE3:], b[:])
+		}
+		tree.Push(leaf)
+	}
+	return tree
+}
+
+// decodeLeaf decodes the r = 2^logRadix evaluations stored in a leaf of the
+// first tree.
+func decodeLeaf(leaf []byte, logRadix int) ([]fr.Element, error) {
+	if len(leaf) != fr.Bytes<<logRadix {
+		return nil, ErrInvalidProof
+	}
+	res := make([]fr.Element, 1<<logRadix)
+	for k := range res {
+		if err := res[k].SetBytesCanonical(leaf[k*fr.Bytes : (k+1)*fr.Bytes]); err != nil {
+			return nil, ErrInvalidProof
+		}
+	}
+	return res, nil
+}
+
+// decodeLeafE3 decodes the r = 2^logRadix evaluations stored in a leaf of the
+// i-th tree, lifting them to the extension if i = 0.
+func decodeLeafE3(leaf []byte, logRadix int, i int) ([]extensions.E3, error) {
+	res := make([]extensions.E3, 1<<logRadix)
+	if i == 0 {
+		fiber, err := decodeLeaf(leaf, logRadix)
+		if err != nil {
+			return nil, err
+		}
+		for k := range res {
+			res[k].SetElement(&fiber[k])
+		}
+		return res, nil
+	}
+	if len(leaf) != extensions.BytesE3<<logRadix {
+		return nil, ErrInvalidProof
+	}
+	for k := range res {
+		if err := res[k].SetBytesCanonical(leaf[k*extensions.BytesE3 : (k+1)*extensions.BytesE3]); err != nil {
+			return nil, ErrInvalidProof
+		}
+	}
+	return res, nil
+}
+
+// Opens a polynomial at gⁱ where i = position.
+func (s radixFri) Open(p []fr.Element, position uint64) (OpeningProof, error) {
+
+	// check that position is in the correct range
+	if position >= s.domain.Cardinality {
+		return OpeningProof{}, ErrRangePosition
+	}
+
+	// put q in evaluation form
+	q := make([]fr.Element, s.domain.Cardinality)
+	copy(q, p)
+	s.domain.FFT(q, fft.DIF)
+	fft.BitReverse(q)
+
+	// build the Merkle proof of the leaf containing gⁱ, which is the leaf
+	// position mod nbLeaves, at the slot position / nbLeaves.
+	nbLeaves := s.domain.Cardinality >> s.logRadices[0]
+	tree := s.buildMerkleTree(q, s.logRadices[0])
+
+	var res OpeningProof
+	var err error
+	res.index = position % nbLeaves
+	res.merkleRoot, res.ProofSet, res.numLeaves, err = tree.Prove(res.index)
+	if err != nil {
+		return OpeningProof{}, err
+	}
+
+	// set the claimed value, which is stored in the first entry of the Merkle proof
+	slot := position / nbLeaves
+	res.ClaimedValue.SetBytes(res.ProofSet[0][slot*fr.Bytes : (slot+1)*fr.Bytes])
+
+	return res, nil
+}
+
+// Verifies the opening of a polynomial.
+// * position the point at which the proof is opened (the point is gⁱ where i = position)
+// * openingProof Merkle path proof
+// * pp proof of proximity, needed because before opening Merkle path proof one should be sure that the
+// committed values come from a polynomial. During the verification of the Merkle path proof, the root
+// hash of the Merkle path is compared to the commitment of the polynomial in the proof of proximity,
+// those should be equal, if not an error is raised.
+func (s radixFri) VerifyOpening(position uint64, openingProof OpeningProof, pp ProofOfProximity) error {
+
+	// check that position is in the correct range
+	if position >= s.domain.Cardinality {
+		return ErrRangePosition
+	}
+
+	// check that the merkle roots coincide
+	if len(pp.Commitments) == 0 || !bytes.Equal(openingProof.merkleRoot, pp.Commitments[0]) {
+		return ErrMerkleRoot
+	}
+
+	// check the Merkle proof
+	nbLeaves := s.domain.Cardinality >> s.logRadices[0]
+	if len(openingProof.ProofSet) == 0 {
+		return ErrMerklePath
+	}
+	fiber, err := decodeLeaf(openingProof.ProofSet[0], s.logRadices[0])
+	if err != nil {
+		return ErrMerklePath
+	}
+	res := merkletree.VerifyProof(s.h, openingProof.merkleRoot, openingProof.ProofSet, position%nbLeaves, nbLeaves)
+	if !res {
+		return ErrMerklePath
+	}
+
+	// check the claimed value
+	if !fiber[position/nbLeaves].Equal(&openingProof.ClaimedValue) {
+		return ErrClaimedValue
+	}
+
+	return nil
+
+}
+
+// foldingTwiddles returns ω⁻ᵏ for k < r = 2^logRadix, and r⁻¹, where
+// ω = g^{n/r} is the primitive r-th root of unity of the domain of size n
+// generated by g.
+func foldingTwiddles(gInv fr.Element, n, logRadix int) ([]fr.Element, fr.Element) {
+	var omegaInv, rInv fr.Element
+	omegaInv.Exp(gInv, big.NewInt(int64(n>>logRadix)))
+	res := make([]fr.Element, 1<<logRadix)
+	res[0].SetOne()
+	for k := 1; k < len(res); k++ {
+		res[k].Mul(&res[k-1], &omegaInv)
+	}
+	rInv.SetUint64(uint64(len(res))).Inverse(&rInv)
+	return res, rInv
+}
+
+// foldFiber folds the evaluations e of a polynomial p on a fiber {x*ωᵏ, k<r}
+// of x -> xʳ, for the challenge α, where β = α*x⁻¹.
+//
+// Writing p(X) = ∑ₘ Xᵐpₘ(Xʳ), the evaluations of the pₘ at xʳ are given by the
+// inverse Fourier transform pₘ(xʳ) = r⁻¹x⁻ᵐ∑ₖ eₖω⁻ᵏᵐ, and the result is
+// ∑ₘ αᵐpₘ(xʳ) = r⁻¹∑ₘ βᵐ∑ₖ eₖω⁻ᵏᵐ.
+func foldFiber(e []extensions.E3, twiddles []fr.Element, beta *extensions.E3, rInv *fr.Element) extensions.E3 {
+	r := len(e)
+	var res, c, t extensions.E3
+	for m := r - 1; m >= 0; m-- {
+		c.SetZero()
+		for k := 0; k < r; k++ {
+			t.MulByElement(&e[k], &twiddles[(k*m)%r])
+			c.Add(&c, &t)
+		}
+		res.Mul(&res, beta).Add(&res, &c)
+	}
+	res.MulByElement(&res, rInv)
+	return res
+}
+
+// foldPolynomialLagrangeBasisRadix folds a polynomial p, expressed in Lagrange basis,
+// by a factor r = 2^logRadix.
+//
+// E[X]/(Xⁿ-1) is a free module of rank r on E[Y]/(Y^{n/r}-1), where E is the
+// extension. If p∈ E[X]/(Xⁿ-1), expressed in Lagrange basis, the function finds
+// the coordinates p₀, .., p_{r-1} of p in E[Y]/(Y^{n/r}-1), expressed in Lagrange
+// basis. Finally, it computes ∑ₘ xᵐpₘ and returns it.
+//
+// * p is the polynomial to fold, in Lagrange basis, in natural order: p = [p(1),p(g),p(g²),...]
+// * logRadix is the logarithm of the folding factor r
+// * gInv is the inverse of a generator of the subgroup of goldilocks^{*} of size len(p)
+// * x is the folding challenge x, used to return ∑ₘ xᵐpₘ
+func foldPolynomialLagrangeBasisRadix(p []extensions.E3, logRadix int, gInv fr.Element, x extensions.E3) []extensions.E3 {
+
+	// the fiber of g^{rj} is {gʲ⁺ᵏᵐ, k < r} where m = n/r, that is
+	// {gʲωᵏ, k < r} where ω = gᵐ.
+	n := len(p)
+	m := n >> logRadix
+	twiddles, rInv := foldingTwiddles(gInv, n, logRadix)
+
+	res := make([]extensions.E3, m)
+	fiber := make([]extensions.E3, 1<<logRadix)
+
+	var acc fr.Element
+	var beta extensions.E3
+	acc.SetOne()
+
+	for j := 0; j < m; j++ {
+
+		for k := range fiber {
+			fiber[k] = p[j+k*m]
+		}
+		beta.MulByElement(&x, &acc)
+		res[j] = foldFiber(fiber, twiddles, &beta, &rInv)
+
+		acc.Mul(&acc, &gInv)
+
+	}
+
+	return res
+}
+
+// BuildProofOfProximity generates a proof that a function, given as an oracle from
+// the verifier point of view, is in fact δ-close to a polynomial.
+func (s radixFri) BuildProofOfProximity(p []fr.Element) (ProofOfProximity, error) {
+
+	nbSteps := len(s.logRadices)
+
+	// Fiat Shamir transcript to derive the challenges. The xᵢ are used to fold the
+	// polynomials.
+	// During the i-th step, the prover has a polynomial P of degree n. The verifier sends
+	// xᵢ∈ E to the prover. The prover expresses F in E[X,Y]/<Y-Xʳ> as
+	// ∑ₘ XᵐPₘ(Y) where the Pₘ are of degree n/r, and he then folds the polynomial
+	// by replacing X by xᵢ.
+	challenges := s.challenges()
+	fs := fiatshamir.NewTranscript(s.h, challenges...)
+
+	var proof ProofOfProximity
+	proof.Commitments = make([][]byte, nbSteps)
+
+	// step 1 : fold the polynomial using the xi
+
+	// evaluate p
+	_p := make([]fr.Element, s.domain.Cardinality)
+	copy(_p, p)
+	s.domain.FFT(_p, fft.DIF)
+	fft.BitReverse(_p)
+
+	// the folded polynomials have their coefficients in the extension
+	_pE := make([]extensions.E3, len(_p))
+	for i := range _p {
+		_pE[i].SetElement(&_p[i])
+	}
+
+	// trees stores the Merkle trees of the evaluations at each step, to prove
+	// the queries once they are derived
+	trees := make([]*merkletree.StoredTree, nbSteps)
+
+	// gInv inverse of the generator of the cyclic group of size the size of the polynomial.
+	// The size of the cyclic group is ρ*s.domainSize, and not s.domainSize.
+	var gInv fr.Element
+	gInv.Set(&s.domain.GeneratorInv)
+
+	for i := 0; i < nbSteps; i++ {
+
+		// compute the root hash, needed to derive xi
+		if i == 0 {
+			trees[i] = s.buildMerkleTree(_p, s.logRadices[i])
+		} else {
+			trees[i] = s.buildMerkleTreeE3(_pE, s.logRadices[i])
+		}
+		proof.Commitments[i] = trees[i].Root()
+		err := fs.Bind(challenges[i], proof.Commitments[i])
+		if err != nil {
+			return proof, err
+		}
+
+		// derive the challenge
+		bxi, err := fs.ComputeChallenge(challenges[i])
+		if err != nil {
+			return proof, err
+		}
+		var xi extensions.E3
+		if err := challengeToE3(&xi, bxi); err != nil {
+			return proof, err
+		}
+
+		// fold _p
+		_pE = foldPolynomialLagrangeBasisRadix(_pE, s.logRadices[i], gInv, xi)
+
+		// g <- gʳ
+		gInv.Exp(gInv, big.NewInt(int64(1)<<s.logRadices[i]))
+
+	}
+
+	// last step, provide the evaluation. The fully folded polynomial should be constant
+	// on the remaining domain.
+	proof.Evaluation.Set(&_pE[0])
+
+	// step 2: solve the proof of work and derive the verifier queries
+	bEvaluation := proof.Evaluation.Bytes()
+	err := fs.Bind(challenges[nbSteps], bEvaluation[:])
+	if err != nil {
+		return proof, err
+	}
+	binSeed, err := fs.ComputeChallenge(challenges[nbSteps])
+	if err != nil {
+		return proof, err
+	}
+	proof.Nonce, err = s.grind(binSeed)
+	if err != nil {
+		return proof, err
+	}
+	positions, err := s.deriveQueries(fs, challenges, proof.Nonce)
+	if err != nil {
+		return proof, err
+	}
+
+	// step 3: provide the Merkle proofs of the queries
+	proof.Queries = make([]Query, s.nbQueries)
+	for q := range positions {
+		si := s.deriveQueriesPositions(positions[q])
+		proof.Queries[q].Interactions = make([]MerkleProof, nbSteps)
+		for i := 0; i < nbSteps; i++ {
+			_, proofSet, _, err := trees[i].Prove(uint64(si[i]))
+			if err != nil {
+				return proof, err
+			}
+			proof.Queries[q].Interactions[i] = MerkleProof{ProofSet: proofSet}
+		}
+	}
+
+	return proof, nil
+}
+
+// VerifyProofOfProximity verifies the proof of proximity. It returns an error if the
+// verification fails.
+func (s radixFri) VerifyProofOfProximity(proof ProofOfProximity) error {
+
+	nbSteps := len(s.logRadices)
+	if len(proof.Commitments) != nbSteps || len(proof.Queries) != s.nbQueries {
+		return ErrInvalidProof
+	}
+
+	// Fiat Shamir transcript to derive the challenges
+	challenges := s.challenges()
+	fs := fiatshamir.NewTranscript(s.h, challenges...)
+
+	xi := make([]extensions.E3, nbSteps)
+	for i := 0; i < nbSteps; i++ {
+		err := fs.Bind(challenges[i], proof.Commitments[i])
+		if err != nil {
+			return err
+		}
+		bxi, err := fs.ComputeChallenge(challenges[i])
+		if err != nil {
+			return err
+		}
+		if err := challengeToE3(&xi[i], bxi); err != nil {
+			return err
+		}
+	}
+
+	// check the proof of work and derive the verifier queries
+	bEvaluation := proof.Evaluation.Bytes()
+	err := fs.Bind(challenges[nbSteps], bEvaluation[:])
+	if err != nil {
+		return err
+	}
+	binSeed, err := fs.ComputeChallenge(challenges[nbSteps])
+	if err != nil {
+		return err
+	}
+	ok, err := s.checkProofOfWork(binSeed, proof.Nonce)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrProofOfWork
+	}
+	positions, err := s.deriveQueries(fs, challenges, proof.Nonce)
+	if err != nil {
+		return err
+	}
+
+	// gInvs[i] inverse of the generator of the i-th domain, of size sizes[i]
+	gInvs := make([]fr.Element, nbSteps)
+	sizes := make([]uint64, nbSteps+1)
+	twiddles := make([][]fr.Element, nbSteps)
+	rInvs := make([]fr.Element, nbSteps)
+	gInvs[0].Set(&s.domain.GeneratorInv)
+	sizes[0] = s.domain.Cardinality
+	for i := 0; i < nbSteps; i++ {
+		twiddles[i], rInvs[i] = foldingTwiddles(gInvs[i], int(sizes[i]), s.logRadices[i])
+		sizes[i+1] = sizes[i] >> s.logRadices[i]
+		if i+1 < nbSteps {
+			gInvs[i+1].Exp(gInvs[i], big.NewInt(int64(1)<<s.logRadices[i]))
+		}
+	}
+
+	// for each query check the Merkle proofs and the correctness of the folding
+	for q := range positions {
+
+		if len(proof.Queries[q].Interactions) != nbSteps {
+			return ErrInvalidProof
+		}
+		si := s.deriveQueriesPositions(positions[q])
+
+		var folded, beta extensions.E3
+		var gInvPos fr.Element
+		for i := 0; i < nbSteps; i++ {
+
+			// correctness of Merkle proof
+			proofSet := proof.Queries[q].Interactions[i].ProofSet
+			if len(proofSet) == 0 {
+				return ErrInvalidProof
+			}
+			fiber, err := decodeLeafE3(proofSet[0], s.logRadices[i], i)
+			if err != nil {
+				return err
+			}
+			nbLeaves := sizes[i+1]
+			res := merkletree.VerifyProof(
+				s.h,
+				proof.Commitments[i],
+				proofSet,
+				uint64(si[i]),
+				nbLeaves,
+			)
+			if !res {
+				return ErrMerklePath
+			}
+
+			// the value folded at the previous step is the evaluation at
+			// gᵢ^{si[i-1]}, which is in the slot si[i-1]/nbLeaves of the fiber.
+			if i > 0 && !fiber[uint64(si[i-1])/nbLeaves].Equal(&folded) {
+				return ErrProximityTestFolding
+			}
+
+			// correctness of the folding: the fiber is {gᵢ^{si[i]}ωᵏ}
+			gInvPos.Exp(gInvs[i], big.NewInt(int64(si[i])))
+			beta.MulByElement(&xi[i], &gInvPos)
+			folded = foldFiber(fiber, twiddles[i], &beta, &rInvs[i])
+		}
+
+		// Last step: the final evaluation should be the evaluation of a degree 0 polynomial,
+		// so it must be constant.
+		if !folded.Equal(&proof.Evaluation) {
+			return ErrProximityTestFolding
+		}
+	}
+
+	return nil
+}
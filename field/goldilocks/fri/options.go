@@ -0,0 +1,106 @@
+// Copyright 2020 Consensys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by consensys/gnark-crypto DO NOT EDIT
+
+package fri
+
+import (
+	"math/bits"
+)
+
+// Option defines option for altering the parameters of an IOPP.
+// See the descriptions of functions returning instances of this type for
+// particular options.
+type Option func(*friConfig)
+
+type friConfig struct {
+	rho           int
+	nbQueries     int
+	securityLevel int
+	grindingBits  int
+}
+
+// WithBlowup sets the factor ρ = size_code_word/size_polynomial. It must be a power
+// of 2 greater than 1. A larger ρ makes each query more sound, hence proofs shorter,
+// at the cost of a larger evaluation domain for the prover. Default is 8.
+func WithBlowup(rho int) Option {
+	return func(opt *friConfig) {
+		opt.rho = rho
+	}
+}
+
+// WithNbQueries sets the number of queries made by the verifier. Default is 1.
+func WithNbQueries(nbQueries int) Option {
+	return func(opt *friConfig) {
+		opt.nbQueries = nbQueries
+	}
+}
+
+// WithSecurityLevel sets the number of queries to reach securityLevel bits of security,
+// as given by NbQueries for the blowup factor and the grinding of the IOPP.
+// It overrides WithNbQueries.
+func WithSecurityLevel(securityLevel int) Option {
+	return func(opt *friConfig) {
+		opt.securityLevel = securityLevel
+	}
+}
+
+// WithGrinding requires the prover to find a nonce such that the hash of the transcript
+// and the nonce starts with grindingBits zero bits, before the queries are derived.
+// Each bit of grinding doubles the expected work of the prover to find the nonce,
+// and adds a bit of security. Default is 0.
+func WithGrinding(grindingBits int) Option {
+	return func(opt *friConfig) {
+		opt.grindingBits = grindingBits
+	}
+}
+
+// NbQueries returns the number of queries needed to reach securityLevel bits of
+// security with the blowup factor ρ, when the prover grinds grindingBits bits.
+//
+// It relies on the conjectured soundness of FRI, where each query adds log₂(ρ) bits of
+// security. ρ must be a power of 2 greater than 1.
+func NbQueries(securityLevel, rho, grindingBits int) int {
+	checkBlowup(rho)
+	logRho := bits.TrailingZeros(uint(rho))
+	res := (securityLevel - grindingBits + logRho - 1) / logRho
+	if res < 1 {
+		res = 1
+	}
+	return res
+}
+
+// checkBlowup panics if rho is not a power of 2 greater than 1.
+func checkBlowup(rho int) {
+	if rho < 2 || rho&(rho-1) != 0 {
+		panic("the blowup factor should be a power of 2 greater than 1")
+	}
+}
+
+// default options
+func friOptions(opts ...Option) friConfig {
+	// apply options
+	opt := friConfig{
+		rho:       rho,
+		nbQueries: defaultNbQueries,
+	}
+	for _, option := range opts {
+		option(&opt)
+	}
+	if opt.securityLevel > 0 {
+		opt.nbQueries = NbQueries(opt.securityLevel, opt.rho, opt.grindingBits)
+	}
+	return opt
+}
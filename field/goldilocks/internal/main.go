@@ -18,4 +18,14 @@ func main() {
 		panic(err)
 	}
 	fmt.Println("successfully generated goldilocks field")
+
+	// E2 = goldilocks[u]/(u²-7), E3 = goldilocks[v]/(v³-2)
+	e3, err := config.NewE3Config(goldilocks, "github.com/consensys/gnark-crypto/field/goldilocks", 7, 2)
+	if err != nil {
+		panic(err)
+	}
+	if err := generator.GenerateE3(e3, "../extensions"); err != nil {
+		panic(err)
+	}
+	fmt.Println("successfully generated goldilocks extensions")
 }
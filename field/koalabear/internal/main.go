@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/field/generator"
+	"github.com/consensys/gnark-crypto/field/generator/config"
+)
+
+//go:generate go run main.go
+func main() {
+	const modulus = "0x7f000001"
+	koalabear, err := config.NewFieldConfig("koalabear", "Element", modulus, true)
+	if err != nil {
+		panic(err)
+	}
+	if err := generator.GenerateFF(koalabear, "../"); err != nil {
+		panic(err)
+	}
+	fmt.Println("successfully generated koalabear field")
+
+	// E4 = koalabear[v]/(v⁴-3)
+	e4, err := config.NewE4Config(koalabear, "github.com/consensys/gnark-crypto/field/koalabear", 3, [2]int64{0, 1})
+	if err != nil {
+		panic(err)
+	}
+	if err := generator.GenerateE4(e4, "../extensions"); err != nil {
+		panic(err)
+	}
+	fmt.Println("successfully generated koalabear extensions")
+}
@@ -0,0 +1,30 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package circlefft provides in-place Fourier transforms on the circle group of
+// the Mersenne-31 field.
+//
+// The multiplicative group of mersenne31 has 2-adicity 1, so the usual radix 2
+// FFT does not apply. Instead, following [Circle STARKs], the circle curve
+// x²+y²=1, whose group of points has order p+1 = 2³¹, plays the role of the
+// roots of unity. A polynomial of the circle FFT space of size n = 2ᵏ is written
+// in the basis
+//
+//	bⱼ(x, y) = y^{j₀}⋅v₁(x)^{j₁}⋯v_{k-1}(x)^{j_{k-1}}
+//
+// where j = ∑ jᵢ2ⁱ, v₁(x) = x and vᵢ₊₁(x) = 2vᵢ(x)²-1, and is evaluated on a
+// standard position coset of size n, see Domain.
+//
+// [Circle STARKs]: https://eprint.iacr.org/2024/278
+package circlefft
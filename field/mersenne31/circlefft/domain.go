@@ -0,0 +1,121 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circlefft
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	"github.com/consensys/gnark-crypto/field/mersenne31"
+)
+
+// Domain is a standard position coset of the circle group, of size n = 2ᵏ.
+//
+// If h is a point of order 2n, the domain is D = h+⟨2h⟩, which is stable by
+// the conjugation J: (x, y) -> (x, -y), and whose image by the doubling map is
+// the standard position coset of size n/2. Its points are ordered so that the
+// pairs of points exchanged by J, and then the pairs of abscissas ±x, are half a
+// block apart at each step of the FFT:
+//
+//	D[t] = h+t⋅(4h), D[t+n/2] = -D[t], for t < n/2
+type Domain struct {
+	Cardinality    uint64
+	CardinalityInv mersenne31.Element
+
+	// Shift is the point h of order 2n defining the domain
+	Shift Point
+
+	// twiddles[0] are the ordinates of the points D[t], t < n/2, used to split
+	// a function on the circle into two functions of the abscissa.
+	// twiddles[i], i ≥ 1, are the abscissas of the points 2^{i-1}D[t], t < n/2^{i+1},
+	// used to split a function of x into two functions of π(x) = 2x²-1.
+	twiddles [][]mersenne31.Element
+
+	// twiddlesInv are the inverses of the twiddles
+	twiddlesInv [][]mersenne31.Element
+}
+
+// NewDomain returns the standard position coset of cardinality
+// max(2, NextPowerOfTwo(m)). It panics if m > 2³⁰.
+func NewDomain(m uint64) *Domain {
+	n := ecc.NextPowerOfTwo(m)
+	if n < 2 {
+		n = 2
+	}
+	logN := bits.TrailingZeros64(n)
+	if logN >= LogOrder {
+		panic("the circle group has no standard position coset of this size")
+	}
+
+	domain := &Domain{}
+	domain.Cardinality = n
+	domain.CardinalityInv.SetUint64(n).Inverse(&domain.CardinalityInv)
+	domain.Shift = SubgroupGenerator(logN + 1)
+	domain.preComputeTwiddles()
+
+	return domain
+}
+
+func (d *Domain) preComputeTwiddles() {
+	logN := bits.TrailingZeros64(d.Cardinality)
+	d.twiddles = make([][]mersenne31.Element, logN)
+	d.twiddlesInv = make([][]mersenne31.Element, logN)
+
+	// points D[t] for t < n/2
+	half := d.Cardinality / 2
+	var step Point
+	step.Double(&d.Shift).Double(&step)
+	points := make([]Point, half)
+	points[0].Set(&d.Shift)
+	for t := uint64(1); t < half; t++ {
+		points[t].Add(&points[t-1], &step)
+	}
+
+	d.twiddles[0] = make([]mersenne31.Element, half)
+	for t := range points {
+		d.twiddles[0][t] = points[t].Y
+	}
+
+	// abscissas, doubled at each step
+	if logN > 1 {
+		d.twiddles[1] = make([]mersenne31.Element, half/2)
+		for t := range d.twiddles[1] {
+			d.twiddles[1][t] = points[t].X
+		}
+	}
+	for i := 2; i < logN; i++ {
+		prev := d.twiddles[i-1]
+		d.twiddles[i] = make([]mersenne31.Element, len(prev)/2)
+		for t := range d.twiddles[i] {
+			d.twiddles[i][t].Square(&prev[t]).Double(&d.twiddles[i][t]).Sub(&d.twiddles[i][t], &one)
+		}
+	}
+
+	for i := range d.twiddles {
+		d.twiddlesInv[i] = mersenne31.BatchInvert(d.twiddles[i])
+	}
+}
+
+// Point returns the i-th point of the domain
+func (d *Domain) Point(i uint64) Point {
+	half := d.Cardinality / 2
+	var res, step Point
+	step.Double(&d.Shift).Double(&step)
+	res.ScalarMultiplication(&step, i%half).Add(&res, &d.Shift)
+	if i >= half {
+		res.Neg(&res)
+	}
+	return res
+}
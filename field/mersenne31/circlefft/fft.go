@@ -0,0 +1,138 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circlefft
+
+import (
+	"math/bits"
+
+	"github.com/consensys/gnark-crypto/field/mersenne31"
+)
+
+// FFT computes the evaluations on the domain of the polynomial whose
+// coefficients in the circle basis are a, in natural order. The evaluations
+// are stored in place of a, in the order of the domain.
+//
+// It panics if len(a) differs from the cardinality of the domain.
+func (d *Domain) FFT(a []mersenne31.Element) {
+	d.checkSize(a)
+	bitReverse(a)
+
+	// f(x) = f₀(π(x)) + x⋅f₁(π(x)), from the innermost layer
+	for i := len(d.twiddles) - 1; i >= 1; i-- {
+		butterflies(a, d.twiddles[i])
+	}
+
+	// f(x, y) = f₀(x) + y⋅f₁(x)
+	butterflies(a, d.twiddles[0])
+}
+
+// FFTInverse computes the coefficients in the circle basis, in natural order,
+// of the polynomial whose evaluations on the domain are a. The coefficients are
+// stored in place of a.
+//
+// It panics if len(a) differs from the cardinality of the domain.
+func (d *Domain) FFTInverse(a []mersenne31.Element) {
+	d.checkSize(a)
+
+	// f₀(x) = (f(x, y)+f(x, -y))/2, f₁(x) = (f(x, y)-f(x, -y))/2y
+	butterfliesInverse(a, d.twiddlesInv[0])
+
+	// f₀(π(x)) = (f(x)+f(-x))/2, f₁(π(x)) = (f(x)-f(-x))/2x
+	for i := 1; i < len(d.twiddlesInv); i++ {
+		butterfliesInverse(a, d.twiddlesInv[i])
+	}
+
+	// the halvings are done once for all
+	for i := range a {
+		a[i].Mul(&a[i], &d.CardinalityInv)
+	}
+	bitReverse(a)
+}
+
+func (d *Domain) checkSize(a []mersenne31.Element) {
+	if uint64(len(a)) != d.Cardinality {
+		panic("the size of the vector should be the cardinality of the domain")
+	}
+}
+
+// butterflies applies (u, v) -> (u+tv, u-tv) to the pairs of entries of each
+// block of a which are half a block apart, the blocks being of size 2*len(twiddles).
+func butterflies(a, twiddles []mersenne31.Element) {
+	m := len(twiddles)
+	for s := 0; s < len(a); s += 2 * m {
+		for t := 0; t < m; t++ {
+			var v mersenne31.Element
+			v.Mul(&a[s+t+m], &twiddles[t])
+			a[s+t+m].Sub(&a[s+t], &v)
+			a[s+t].Add(&a[s+t], &v)
+		}
+	}
+}
+
+// butterfliesInverse applies (u, v) -> (u+v, t(u-v)) to the pairs of entries
+// of each block of a which are half a block apart, the blocks being of size
+// 2*len(twiddles).
+func butterfliesInverse(a, twiddles []mersenne31.Element) {
+	m := len(twiddles)
+	for s := 0; s < len(a); s += 2 * m {
+		for t := 0; t < m; t++ {
+			mersenne31.Butterfly(&a[s+t], &a[s+t+m])
+			a[s+t+m].Mul(&a[s+t+m], &twiddles[t])
+		}
+	}
+}
+
+// Evaluate returns the evaluation at p of the polynomial whose coefficients in
+// the circle basis of size len(coefficients) are given, in natural order.
+//
+// It panics if len(coefficients) is not a power of 2.
+func Evaluate(coefficients []mersenne31.Element, p *Point) mersenne31.Element {
+	n := len(coefficients)
+	if n == 0 || n&(n-1) != 0 {
+		panic("the number of coefficients should be a power of 2")
+	}
+
+	res := make([]mersenne31.Element, n)
+	copy(res, coefficients)
+
+	// the factors of the basis are y, v₁(x) = x, v₂(x), ..., v_{k-1}(x)
+	factor := p.Y
+	v := p.X
+	for m := n / 2; m >= 1; m /= 2 {
+		for t := 0; t < m; t++ {
+			var u mersenne31.Element
+			u.Mul(&res[2*t+1], &factor)
+			res[t].Add(&res[2*t], &u)
+		}
+		factor = v
+		v.Square(&v).Double(&v).Sub(&v, &one)
+	}
+
+	return res[0]
+}
+
+// bitReverse applies the bit-reversal permutation to a.
+// len(a) must be a power of 2 (as in every single function in this file)
+func bitReverse(a []mersenne31.Element) {
+	n := uint64(len(a))
+	nn := uint64(64 - bits.TrailingZeros64(n))
+
+	for i := uint64(0); i < n; i++ {
+		irev := bits.Reverse64(i) >> nn
+		if irev > i {
+			a[i], a[irev] = a[irev], a[i]
+		}
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circlefft
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/consensys/gnark-crypto/field/mersenne31"
+	"github.com/leanovate/gopter"
+	"github.com/leanovate/gopter/gen"
+	"github.com/leanovate/gopter/prop"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoint(t *testing.T) {
+	assert := require.New(t)
+
+	g := Generator()
+	assert.True(g.IsOnCurve())
+
+	// g is of order 2³¹
+	var p Point
+	p.Set(&g)
+	for i := 0; i < LogOrder-1; i++ {
+		p.Double(&p)
+	}
+	assert.False(p.IsIdentity())
+	assert.True(p.Double(&p).IsIdentity())
+
+	parameters := gopter.DefaultTestParameters()
+	parameters.MinSuccessfulTests = 100
+	properties := gopter.NewProperties(parameters)
+
+	properties.Property("double and add should output the same result", prop.ForAll(
+		func(s uint64) bool {
+			var p, q, r Point
+			p.ScalarMultiplication(&g, s)
+			q.Double(&p)
+			r.Add(&p, &p)
+			return q.Equal(&r) && q.IsOnCurve()
+		},
+		gen.UInt64(),
+	))
+
+	properties.Property("scalar multiplication should be compatible with addition", prop.ForAll(
+		func(s, t uint32) bool {
+			var p, q, r Point
+			p.ScalarMultiplication(&g, uint64(s))
+			q.ScalarMultiplication(&g, uint64(t))
+			p.Add(&p, &q)
+			r.ScalarMultiplication(&g, uint64(s)+uint64(t))
+			return p.Equal(&r)
+		},
+		gen.UInt32(),
+		gen.UInt32(),
+	))
+
+	properties.Property("p-p should be the identity", prop.ForAll(
+		func(s uint64) bool {
+			var p, q Point
+			p.ScalarMultiplication(&g, s)
+			q.Neg(&p).Add(&q, &p)
+			return q.IsIdentity()
+		},
+		gen.UInt64(),
+	))
+
+	properties.TestingRun(t, gopter.ConsoleReporter(false))
+}
+
+func TestDomain(t *testing.T) {
+	assert := require.New(t)
+
+	assert.Equal(uint64(2), NewDomain(0).Cardinality)
+	assert.Equal(uint64(16), NewDomain(9).Cardinality)
+	assert.Panics(func() { NewDomain(1 << 31) })
+
+	for _, n := range []uint64{2, 4, 64} {
+		d := NewDomain(n)
+
+		// the points are distinct, on the curve, and half of them are the
+		// conjugates of the other half
+		seen := make(map[Point]struct{})
+		for i := uint64(0); i < n; i++ {
+			p := d.Point(i)
+			assert.True(p.IsOnCurve())
+			seen[p] = struct{}{}
+			if i < n/2 {
+				var q Point
+				q.Neg(&p)
+				r := d.Point(i + n/2)
+				assert.True(q.Equal(&r))
+			}
+		}
+		assert.Equal(int(n), len(seen))
+
+		// the domain is a coset of the subgroup of order n
+		var q Point
+		q.ScalarMultiplication(&d.Shift, 2*n)
+		assert.True(q.IsIdentity())
+		q.ScalarMultiplication(&d.Shift, n)
+		assert.False(q.IsIdentity())
+	}
+}
+
+func TestFFT(t *testing.T) {
+	assert := require.New(t)
+
+	for _, n := range []int{2, 4, 8, 64, 1024} {
+		d := NewDomain(uint64(n))
+
+		coefficients := make([]mersenne31.Element, n)
+		for i := range coefficients {
+			coefficients[i].SetRandom()
+		}
+		evaluations := make([]mersenne31.Element, n)
+		copy(evaluations, coefficients)
+
+		d.FFT(evaluations)
+		for i := 0; i < n; i += 1 + n/64 {
+			p := d.Point(uint64(i))
+			expected := Evaluate(coefficients, &p)
+			assert.True(expected.Equal(&evaluations[i]), "n=%d, i=%d", n, i)
+		}
+
+		d.FFTInverse(evaluations)
+		assert.Equal(coefficients, evaluations, "n=%d", n)
+	}
+}
+
+func TestFFTInverseBasis(t *testing.T) {
+	assert := require.New(t)
+
+	// x and y are the basis elements of index 2 and 1
+	d := NewDomain(16)
+	xs := make([]mersenne31.Element, d.Cardinality)
+	ys := make([]mersenne31.Element, d.Cardinality)
+	for i := range xs {
+		p := d.Point(uint64(i))
+		xs[i], ys[i] = p.X, p.Y
+	}
+	d.FFTInverse(xs)
+	d.FFTInverse(ys)
+	for i := range xs {
+		assert.Equal(i == 2, xs[i].IsOne(), "x, index %d", i)
+		assert.Equal(i == 2, !xs[i].IsZero(), "x, index %d", i)
+		assert.Equal(i == 1, ys[i].IsOne(), "y, index %d", i)
+		assert.Equal(i == 1, !ys[i].IsZero(), "y, index %d", i)
+	}
+
+	assert.Panics(func() { d.FFT(make([]mersenne31.Element, 8)) })
+}
+
+func BenchmarkFFT(b *testing.B) {
+	for _, logN := range []int{16, 20} {
+		d := NewDomain(1 << logN)
+		a := make([]mersenne31.Element, d.Cardinality)
+		for i := range a {
+			a[i].SetRandom()
+		}
+		b.Run(fmt.Sprintf("size 2^%d", logN), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				d.FFT(a)
+			}
+		})
+		b.Run(fmt.Sprintf("inverse size 2^%d", logN), func(b *testing.B) {
+			b.ResetTimer()
+			for j := 0; j < b.N; j++ {
+				d.FFTInverse(a)
+			}
+		})
+	}
+}
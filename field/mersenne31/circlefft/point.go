@@ -0,0 +1,134 @@
+// Copyright 2020 ConsenSys Software Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package circlefft
+
+import (
+	"github.com/consensys/gnark-crypto/field/mersenne31"
+)
+
+// LogOrder is the logarithm of the order p+1 of the circle group
+const LogOrder = 31
+
+// Point is a point of the circle curve x²+y²=1 over mersenne31.
+//
+// The group law is (x₀, y₀)⋅(x₁, y₁) = (x₀x₁-y₀y₁, x₀y₁+y₀x₁), with identity
+// (1, 0). It is written additively in the API, like the elliptic curves groups.
+type Point struct {
+	X, Y mersenne31.Element
+}
+
+// Generator returns a generator of the circle group, of order 2³¹.
+func Generator() Point {
+	return Point{
+		X: mersenne31.NewElement(2),
+		Y: mersenne31.NewElement(1268011823),
+	}
+}
+
+// SubgroupGenerator returns a generator of the subgroup of order 2ˡᵒᵍᴺ of the
+// circle group. It panics if logN > LogOrder.
+func SubgroupGenerator(logN int) Point {
+	if logN < 0 || logN > LogOrder {
+		panic("the circle group has no subgroup of this order")
+	}
+	g := Generator()
+	for i := logN; i < LogOrder; i++ {
+		g.Double(&g)
+	}
+	return g
+}
+
+// SetIdentity sets p to the identity (1, 0) and returns p
+func (p *Point) SetIdentity() *Point {
+	p.X.SetOne()
+	p.Y.SetZero()
+	return p
+}
+
+// IsIdentity returns true if p is the identity
+func (p *Point) IsIdentity() bool {
+	return p.X.IsOne() && p.Y.IsZero()
+}
+
+// IsOnCurve returns true if x²+y²=1
+func (p *Point) IsOnCurve() bool {
+	var x2, y2 mersenne31.Element
+	x2.Square(&p.X)
+	y2.Square(&p.Y)
+	x2.Add(&x2, &y2)
+	return x2.IsOne()
+}
+
+// Equal returns true if p and q are equal
+func (p *Point) Equal(q *Point) bool {
+	return p.X.Equal(&q.X) && p.Y.Equal(&q.Y)
+}
+
+// Set sets p to q and returns p
+func (p *Point) Set(q *Point) *Point {
+	p.X, p.Y = q.X, q.Y
+	return p
+}
+
+// Add sets p to p₁+p₂ and returns p
+func (p *Point) Add(p1, p2 *Point) *Point {
+	var x, y, t mersenne31.Element
+	x.Mul(&p1.X, &p2.X)
+	t.Mul(&p1.Y, &p2.Y)
+	x.Sub(&x, &t)
+	y.Mul(&p1.X, &p2.Y)
+	t.Mul(&p1.Y, &p2.X)
+	y.Add(&y, &t)
+	p.X, p.Y = x, y
+	return p
+}
+
+// Double sets p to 2p₁ and returns p. The abscissa of 2p₁ is π(x₁) = 2x₁²-1.
+func (p *Point) Double(p1 *Point) *Point {
+	var x, y mersenne31.Element
+	x.Square(&p1.X).Double(&x)
+	x.Sub(&x, &one)
+	y.Mul(&p1.X, &p1.Y).Double(&y)
+	p.X, p.Y = x, y
+	return p
+}
+
+// Neg sets p to -p₁ = (x₁, -y₁), that is the conjugate of p₁, and returns p
+func (p *Point) Neg(p1 *Point) *Point {
+	p.X = p1.X
+	p.Y.Neg(&p1.Y)
+	return p
+}
+
+// ScalarMultiplication sets p to [s]p₁ and returns p
+func (p *Point) ScalarMultiplication(p1 *Point, s uint64) *Point {
+	var res, q Point
+	res.SetIdentity()
+	q.Set(p1)
+	for ; s != 0; s >>= 1 {
+		if s&1 == 1 {
+			res.Add(&res, &q)
+		}
+		q.Double(&q)
+	}
+	return p.Set(&res)
+}
+
+// String returns the string representation (x, y) of p
+func (p *Point) String() string {
+	return "(" + p.X.String() + "," + p.Y.String() + ")"
+}
+
+var one = mersenne31.One()
@@ -0,0 +1,31 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/consensys/gnark-crypto/field/generator"
+	"github.com/consensys/gnark-crypto/field/generator/config"
+)
+
+//go:generate go run main.go
+func main() {
+	const modulus = "0x7fffffff"
+	mersenne31, err := config.NewFieldConfig("mersenne31", "Element", modulus, true)
+	if err != nil {
+		panic(err)
+	}
+	if err := generator.GenerateFF(mersenne31, "../"); err != nil {
+		panic(err)
+	}
+	fmt.Println("successfully generated mersenne31 field")
+
+	// E2 = mersenne31[i]/(i²+1) and E4 = E2[v]/(v²-2-i)
+	e4, err := config.NewE4Config(mersenne31, "github.com/consensys/gnark-crypto/field/mersenne31", -1, [2]int64{2, 1})
+	if err != nil {
+		panic(err)
+	}
+	if err := generator.GenerateE4(e4, "../extensions"); err != nil {
+		panic(err)
+	}
+	fmt.Println("successfully generated mersenne31 extensions")
+}
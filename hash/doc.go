@@ -1,4 +1,4 @@
-// Package hash provides MiMC hash function defined over implemented curves
+// Package hash provides MiMC and Poseidon2 hash functions defined over implemented curves
 //
 // # Length extension attack
 //
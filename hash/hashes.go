@@ -26,6 +26,30 @@ import (
 	bw633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/mimc"
 	bw756 "github.com/consensys/gnark-crypto/ecc/bw6-756/fr/mimc"
 	bw761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/mimc"
+
+	poseidon2bls377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/poseidon2"
+	poseidon2bls378 "github.com/consensys/gnark-crypto/ecc/bls12-378/fr/poseidon2"
+	poseidon2bls381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/poseidon2"
+	poseidon2bls315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/poseidon2"
+	poseidon2bls317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/poseidon2"
+	poseidon2bn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/poseidon2"
+	poseidon2bw633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/poseidon2"
+	poseidon2bw756 "github.com/consensys/gnark-crypto/ecc/bw6-756/fr/poseidon2"
+	poseidon2bw761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/poseidon2"
+
+	rpobls377 "github.com/consensys/gnark-crypto/ecc/bls12-377/fr/rpo"
+	rpobls378 "github.com/consensys/gnark-crypto/ecc/bls12-378/fr/rpo"
+	rpobls381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/rpo"
+	rpobls315 "github.com/consensys/gnark-crypto/ecc/bls24-315/fr/rpo"
+	rpobls317 "github.com/consensys/gnark-crypto/ecc/bls24-317/fr/rpo"
+	rpobn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/rpo"
+	rpobw633 "github.com/consensys/gnark-crypto/ecc/bw6-633/fr/rpo"
+	rpobw756 "github.com/consensys/gnark-crypto/ecc/bw6-756/fr/rpo"
+	rpobw761 "github.com/consensys/gnark-crypto/ecc/bw6-761/fr/rpo"
+	rpogoldilocks "github.com/consensys/gnark-crypto/field/goldilocks/rpo"
+
+	anemoibls381 "github.com/consensys/gnark-crypto/ecc/bls12-381/fr/anemoi"
+	anemoibn254 "github.com/consensys/gnark-crypto/ecc/bn254/fr/anemoi"
 )
 
 // Hash defines an unique identifier for a hash function.
@@ -50,6 +74,51 @@ const (
 	MIMC_BW6_633
 	// MIMC_BW6_756 is the MiMC hash function for the BW6-756 curve.
 	MIMC_BW6_756
+
+	// POSEIDON2_BN254 is the Poseidon2 hash function for the BN254 curve.
+	POSEIDON2_BN254
+	// POSEIDON2_BLS12_381 is the Poseidon2 hash function for the BLS12-381 curve.
+	POSEIDON2_BLS12_381
+	// POSEIDON2_BLS12_377 is the Poseidon2 hash function for the BLS12-377 curve.
+	POSEIDON2_BLS12_377
+	// POSEIDON2_BLS12_378 is the Poseidon2 hash function for the BLS12-378 curve.
+	POSEIDON2_BLS12_378
+	// POSEIDON2_BW6_761 is the Poseidon2 hash function for the BW6-761 curve.
+	POSEIDON2_BW6_761
+	// POSEIDON2_BLS24_315 is the Poseidon2 hash function for the BLS24-315 curve.
+	POSEIDON2_BLS24_315
+	// POSEIDON2_BLS24_317 is the Poseidon2 hash function for the BLS24-317 curve.
+	POSEIDON2_BLS24_317
+	// POSEIDON2_BW6_633 is the Poseidon2 hash function for the BW6-633 curve.
+	POSEIDON2_BW6_633
+	// POSEIDON2_BW6_756 is the Poseidon2 hash function for the BW6-756 curve.
+	POSEIDON2_BW6_756
+
+	// RPO_BN254 is the Rescue-Prime Optimized hash function for the BN254 curve.
+	RPO_BN254
+	// RPO_BLS12_381 is the Rescue-Prime Optimized hash function for the BLS12-381 curve.
+	RPO_BLS12_381
+	// RPO_BLS12_377 is the Rescue-Prime Optimized hash function for the BLS12-377 curve.
+	RPO_BLS12_377
+	// RPO_BLS12_378 is the Rescue-Prime Optimized hash function for the BLS12-378 curve.
+	RPO_BLS12_378
+	// RPO_BW6_761 is the Rescue-Prime Optimized hash function for the BW6-761 curve.
+	RPO_BW6_761
+	// RPO_BLS24_315 is the Rescue-Prime Optimized hash function for the BLS24-315 curve.
+	RPO_BLS24_315
+	// RPO_BLS24_317 is the Rescue-Prime Optimized hash function for the BLS24-317 curve.
+	RPO_BLS24_317
+	// RPO_BW6_633 is the Rescue-Prime Optimized hash function for the BW6-633 curve.
+	RPO_BW6_633
+	// RPO_BW6_756 is the Rescue-Prime Optimized hash function for the BW6-756 curve.
+	RPO_BW6_756
+	// RPO_GOLDILOCKS is the Rescue-Prime Optimized hash function RPO-256 over the Goldilocks field.
+	RPO_GOLDILOCKS
+
+	// ANEMOI_BN254 is the Anemoi hash function for the BN254 curve.
+	ANEMOI_BN254
+	// ANEMOI_BLS12_381 is the Anemoi hash function for the BLS12-381 curve.
+	ANEMOI_BLS12_381
 )
 
 // size of digests in bytes
@@ -63,6 +132,30 @@ var digestSize = []uint8{
 	MIMC_BLS24_317: 48,
 	MIMC_BW6_633:   80,
 	MIMC_BW6_756:   96,
+
+	POSEIDON2_BN254:     32,
+	POSEIDON2_BLS12_381: 32,
+	POSEIDON2_BLS12_377: 32,
+	POSEIDON2_BLS12_378: 32,
+	POSEIDON2_BW6_761:   48,
+	POSEIDON2_BLS24_315: 32,
+	POSEIDON2_BLS24_317: 32,
+	POSEIDON2_BW6_633:   40,
+	POSEIDON2_BW6_756:   48,
+
+	RPO_BN254:      32,
+	RPO_BLS12_381:  32,
+	RPO_BLS12_377:  32,
+	RPO_BLS12_378:  32,
+	RPO_BW6_761:    48,
+	RPO_BLS24_315:  32,
+	RPO_BLS24_317:  32,
+	RPO_BW6_633:    40,
+	RPO_BW6_756:    48,
+	RPO_GOLDILOCKS: 32,
+
+	ANEMOI_BN254:     32,
+	ANEMOI_BLS12_381: 32,
 }
 
 // New initializes the hash function.
@@ -86,8 +179,50 @@ func (m Hash) New() hash.Hash {
 		return bw633.NewMiMC()
 	case MIMC_BW6_756:
 		return bw756.NewMiMC()
+	case POSEIDON2_BN254:
+		return poseidon2bn254.NewPoseidon2()
+	case POSEIDON2_BLS12_381:
+		return poseidon2bls381.NewPoseidon2()
+	case POSEIDON2_BLS12_377:
+		return poseidon2bls377.NewPoseidon2()
+	case POSEIDON2_BLS12_378:
+		return poseidon2bls378.NewPoseidon2()
+	case POSEIDON2_BW6_761:
+		return poseidon2bw761.NewPoseidon2()
+	case POSEIDON2_BLS24_315:
+		return poseidon2bls315.NewPoseidon2()
+	case POSEIDON2_BLS24_317:
+		return poseidon2bls317.NewPoseidon2()
+	case POSEIDON2_BW6_633:
+		return poseidon2bw633.NewPoseidon2()
+	case POSEIDON2_BW6_756:
+		return poseidon2bw756.NewPoseidon2()
+	case RPO_BN254:
+		return rpobn254.NewRPO()
+	case RPO_BLS12_381:
+		return rpobls381.NewRPO()
+	case RPO_BLS12_377:
+		return rpobls377.NewRPO()
+	case RPO_BLS12_378:
+		return rpobls378.NewRPO()
+	case RPO_BW6_761:
+		return rpobw761.NewRPO()
+	case RPO_BLS24_315:
+		return rpobls315.NewRPO()
+	case RPO_BLS24_317:
+		return rpobls317.NewRPO()
+	case RPO_BW6_633:
+		return rpobw633.NewRPO()
+	case RPO_BW6_756:
+		return rpobw756.NewRPO()
+	case RPO_GOLDILOCKS:
+		return rpogoldilocks.NewRPO()
+	case ANEMOI_BN254:
+		return anemoibn254.NewAnemoi()
+	case ANEMOI_BLS12_381:
+		return anemoibls381.NewAnemoi()
 	default:
-		panic("Unknown mimc ID")
+		panic("Unknown hash ID")
 	}
 }
 
@@ -112,8 +247,50 @@ func (m Hash) String() string {
 		return "MIMC_BW633"
 	case MIMC_BW6_756:
 		return "MIMC_BW756"
+	case POSEIDON2_BN254:
+		return "POSEIDON2_BN254"
+	case POSEIDON2_BLS12_381:
+		return "POSEIDON2_BLS381"
+	case POSEIDON2_BLS12_377:
+		return "POSEIDON2_BLS377"
+	case POSEIDON2_BLS12_378:
+		return "POSEIDON2_BLS378"
+	case POSEIDON2_BW6_761:
+		return "POSEIDON2_BW761"
+	case POSEIDON2_BLS24_315:
+		return "POSEIDON2_BLS315"
+	case POSEIDON2_BLS24_317:
+		return "POSEIDON2_BLS317"
+	case POSEIDON2_BW6_633:
+		return "POSEIDON2_BW633"
+	case POSEIDON2_BW6_756:
+		return "POSEIDON2_BW756"
+	case RPO_BN254:
+		return "RPO_BN254"
+	case RPO_BLS12_381:
+		return "RPO_BLS381"
+	case RPO_BLS12_377:
+		return "RPO_BLS377"
+	case RPO_BLS12_378:
+		return "RPO_BLS378"
+	case RPO_BW6_761:
+		return "RPO_BW761"
+	case RPO_BLS24_315:
+		return "RPO_BLS315"
+	case RPO_BLS24_317:
+		return "RPO_BLS317"
+	case RPO_BW6_633:
+		return "RPO_BW633"
+	case RPO_BW6_756:
+		return "RPO_BW756"
+	case RPO_GOLDILOCKS:
+		return "RPO_GOLDILOCKS"
+	case ANEMOI_BN254:
+		return "ANEMOI_BN254"
+	case ANEMOI_BLS12_381:
+		return "ANEMOI_BLS381"
 	default:
-		panic("Unknown mimc ID")
+		panic("Unknown hash ID")
 	}
 }
 
@@ -0,0 +1,49 @@
+package bls
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+type variant struct {
+	config.Curve
+
+	MinPk bool
+
+	// groups of the public keys and the signatures, "G1" or "G2"
+	PkGroup  string
+	SigGroup string
+
+	// CipherSuiteCurve is the curve identifier of the ciphersuite, e.g. "BLS12381"
+	CipherSuiteCurve string
+}
+
+// Generate generates the BLS signature packages (minimal-pubkey-size and
+// minimal-signature-size variants) for the curve.
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	suiteCurve := strings.ToUpper(strings.ReplaceAll(conf.Name, "-", ""))
+	variants := []variant{
+		{Curve: conf, MinPk: true, PkGroup: "G1", SigGroup: "G2", CipherSuiteCurve: suiteCurve},
+		{Curve: conf, MinPk: false, PkGroup: "G2", SigGroup: "G1", CipherSuiteCurve: suiteCurve},
+	}
+	variants[0].Package = "minpk"
+	variants[1].Package = "minsig"
+
+	for _, v := range variants {
+		dir := filepath.Join(baseDir, "bls", v.Package)
+		entries := []bavard.Entry{
+			{File: filepath.Join(dir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+			{File: filepath.Join(dir, "bls.go"), Templates: []string{"bls.go.tmpl"}},
+			{File: filepath.Join(dir, "bls_test.go"), Templates: []string{"bls.test.go.tmpl"}},
+			{File: filepath.Join(dir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+			{File: filepath.Join(dir, "marshal_test.go"), Templates: []string{"marshal.test.go.tmpl"}},
+		}
+		if err := bgen.Generate(v, v.Package, "./bls/template", entries...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
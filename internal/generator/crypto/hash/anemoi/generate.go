@@ -0,0 +1,117 @@
+package anemoi
+
+import (
+	"fmt"
+	"math/big"
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+// anemoiConfig describes the instance of Anemoi generated for a curve
+type anemoiConfig struct {
+	config.Curve
+	Generator uint64 // multiplicative generator g of fr, used in the Flystel
+	Alpha     uint64
+	AlphaInv  string // α⁻¹ (mod r-1), decimal
+
+	// Vectors are the images of (0, 1, .., 2ℓ-1) by the permutation and by the
+	// Jive compression, with the number of rounds of the specification
+	Vectors []testVector
+}
+
+type testVector struct {
+	NbCols      int
+	Permutation []string
+	Jive        []string
+}
+
+// generators of the multiplicative groups of the fields fr, as chosen by the
+// Anemoi reference instances
+var generators = map[string]uint64{
+	config.BN254.Name:     5,
+	config.BLS12_381.Name: 7,
+}
+
+// testVectors were computed with an independent implementation of the
+// specification https://eprint.iacr.org/2022/840
+var testVectors = map[string][]testVector{
+	config.BN254.Name: {
+		{
+			NbCols: 1,
+			Permutation: []string{
+				"0x0808e3921fc7a9cc2158eab2c805f80d33ff254237fe6b2ce06f83572b833eab",
+				"0x0107063a755b95efa530e745b35b8fbcce2a26d3b92bb12ee2c34b3a92719d01",
+			},
+			Jive: []string{
+				"0x090fe9cc95233fbbc689d1f87b6187ca02294c15f12a1c5bc332ce91bdf4dbad",
+			},
+		},
+		{
+			NbCols: 2,
+			Permutation: []string{
+				"0x2cb43c79daf0f8fb5e76e76711d860311b0926ffe297b8315c87710eb31864d9",
+				"0x1c01ee71abcbc1adeb777fdd5fcb24fd4e2293d9eb632a54ec63721f381bd2ad",
+				"0x1acd84307c0d7207d8866dbe05090f8a3fa0cde918a2985e92f27820317d652d",
+				"0x1057e76e5f1f4890261614f8f471240616d8c6a1245bff093d36b10f1161dfb3",
+			},
+			Jive: []string{
+				"0x171d723775cccad97ead0f6e9560175e32760ca08180dffeab97f39af495ca07",
+				"0x2c59d5e00aeb0a3e118d94d6543c490364fb5a7b0fbf295e299a232e497db264",
+			},
+		},
+	},
+	config.BLS12_381.Name: {
+		{
+			NbCols: 1,
+			Permutation: []string{
+				"0x019ea09bf18332c14411e27d2a654837a188f8b718d13faa824730fa20350684",
+				"0x68ae6629a63203e1fc2c8ecbfc72eb940a63a0f7ed9bf9d64bec32dec5217cc0",
+			},
+			Jive: []string{
+				"0x6a4d06c597b536a3403e714926d833cbabec99af066d3980ce3363d8e5568345",
+			},
+		},
+		{
+			NbCols: 2,
+			Permutation: []string{
+				"0x103198778534d584c4e960834939b6549ade65a64facdf0d75742ddc1e1755b6",
+				"0x504b9b2827c9426bca315935a415895bc787f21b2137cafa259ea66992b416d2",
+				"0x0abe38e4a44d3ca4ce4fd3470129bfe01e2317f98522899420615d4363b4242a",
+				"0x674dfdef1d8e6c4600141c587d5ba59b466df8d0fe675474dfc10861fadb0424",
+			},
+			Jive: []string{
+				"0x1aefd15c29821229933933ca4a637634b9017d9fd4cf68a195d58b1f81cb79e2",
+				"0x43abf1c41bba3169970b9d8617cf56f1ba3846e91fa0c370055faecc8d8f1af9",
+			},
+		},
+	},
+}
+
+// Generate generates Anemoi on the field fr of the curve. Only BN254 and
+// BLS12-381 are supported.
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	g, ok := generators[conf.Name]
+	if !ok {
+		return fmt.Errorf("anemoi: unsupported curve %s", conf.Name)
+	}
+	conf.Package = "anemoi"
+	c := anemoiConfig{Curve: conf, Generator: g, Alpha: 5, Vectors: testVectors[conf.Name]}
+
+	var rMinusOne, gcd big.Int
+	rMinusOne.Sub(conf.FrInfo.Modulus(), big.NewInt(1))
+	alpha := new(big.Int).SetUint64(c.Alpha)
+	if gcd.GCD(nil, nil, alpha, &rMinusOne).Cmp(big.NewInt(1)) != 0 {
+		return fmt.Errorf("anemoi: x ↦ x^%d is not a permutation of fr", c.Alpha)
+	}
+	c.AlphaInv = new(big.Int).ModInverse(alpha, &rMinusOne).String()
+
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "anemoi.go"), Templates: []string{"anemoi.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash.go"), Templates: []string{"hash.go.tmpl"}},
+		{File: filepath.Join(baseDir, "anemoi_test.go"), Templates: []string{"anemoi.test.go.tmpl"}},
+	}
+	return bgen.Generate(c, conf.Package, "./crypto/hash/anemoi/template", entries...)
+}
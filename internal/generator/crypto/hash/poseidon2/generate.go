@@ -0,0 +1,218 @@
+package poseidon2
+
+import (
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+// poseidon2Config is the data passed to the templates
+type poseidon2Config struct {
+	config.Curve
+
+	// Vectors are the images of (0, 1, .., t-1) by the permutation with the
+	// default number of rounds
+	Vectors []referenceVector
+}
+
+type referenceVector struct {
+	Width    int
+	RoundKey string // first round key
+	Expected []string
+}
+
+// referenceVectors are the test vectors of the permutation. The vectors of
+// bn254 (width 3) and bls12-381 are the ones of the reference implementation
+// https://github.com/HorizenLabs/poseidon2, which has no instance on the other
+// fields: their vectors were computed with an independent implementation of
+// the reference (Grain LFSR round keys and plain permutation), which matches
+// the reference vectors above.
+var referenceVectors = map[string][]referenceVector{
+	"bls12-377": {
+		{
+			Width:    2,
+			RoundKey: "0x0a98f8c2a1e98b96b78d7db2f9e2d0a3fe6803e9784b68fea2e4f3eca21c4c2e",
+			Expected: []string{
+				"0x088a31db828c4e54cf7a09099b93cac6a1a51f33e1de65d162ffb96a518593c2",
+				"0x08831f785eba1b59b66330f842e553bcab749a0e98f3b0f38a17a7d639a34ca0",
+			},
+		},
+		{
+			Width:    3,
+			RoundKey: "0x0307d480bee26c7209f492a4fe798a26d79f6be92f3b4910fedb02a23316c7c3",
+			Expected: []string{
+				"0x0b88ecada2b0231972ab29f6fc4b671c3ca8361e61dd989bdac506a3e89b60d6",
+				"0x0688962c41e803e8e5ffe421f75979295c00f58cc659a2bb0ba8d8448af6d1fe",
+				"0x10bf370aad7e946ff3ca27466b464444d9ce11b2d293e54e3bf31da1e8dd21b5",
+			},
+		},
+	},
+	"bls12-378": {
+		{
+			Width:    2,
+			RoundKey: "0x09c46e9ec68e9bd4fe1faaba294cba38a71aa177534cdd1b6c7dc0dbd0abd7a7",
+			Expected: []string{
+				"0x185b439f7fabf49eec8db1c1df639fd11d998359d557f8e34ac58be4eddf4794",
+				"0x0572a0ea06d33ac8752f82ab3a537b1d4b86cdc078c3abd2108ebfc94ec7d7e0",
+			},
+		},
+		{
+			Width:    3,
+			RoundKey: "0x1d066a255517b7fd8bddd3a93f7804ef7f8fcde48bb4c37a59a09a1a97052816",
+			Expected: []string{
+				"0x20db7c1bf9319f26d40945ec4014159f3988348ff56a708671affc3e4c404521",
+				"0x0b67c5520db840bba4d87a364e7ee5832c4f238f72cc91963a827467221eb4b3",
+				"0x13bc9592e9073adef34f4f9f7562c799ff1cc3ed6b5c30f8cb152a4c46cefd40",
+			},
+		},
+	},
+	"bls12-381": {
+		{
+			Width:    2,
+			RoundKey: "0x6267f5556c88257324c1c8b00d5871b2eba13cc39d72aa10dde6b69bc44c41c7",
+			Expected: []string{
+				"0x73c46dd530e248a87b61d19e67fa1b4ed30fc3d09f16531fe189fb945a15ce4e",
+				"0x1f0e305ee21c9366d5793b80251405032a3fee32b9dd0b5f4578262891b043b4",
+			},
+		},
+		{
+			Width:    3,
+			RoundKey: "0x6f007a551156b3a449e44936b7c093644a0ed33f33eaccc628e942e836c1a875",
+			Expected: []string{
+				"0x1b152349b1950b6a8ca75ee4407b6e26ca5cca5650534e56ef3fd45761fbf5f0",
+				"0x4c5793c87d51bdc2c08a32108437dc0000bd0275868f09ebc5f36919af5b3891",
+				"0x1fc8ed171e67902ca49863159fe5ba6325318843d13976143b8125f08b50dc6b",
+			},
+		},
+	},
+	"bls24-315": {
+		{
+			Width:    2,
+			RoundKey: "0x05fe293f8e9b86d6ced3d2a5d93abd66cd5b4c1e0fc511004884b25e726e21b9",
+			Expected: []string{
+				"0x08c982b43b7649727dfaf3daef6e4973f318cba55f4350ac69fc3ba8b055b880",
+				"0x16def5de36b535cb878d363cad3840c764d02e690f67e20d36d6653e318fef6c",
+			},
+		},
+		{
+			Width:    3,
+			RoundKey: "0x14a9d929143de04dadeba5f60ac1aa3af575f7f842acac7f3e6ffcb8a182b525",
+			Expected: []string{
+				"0x058c0b63e1ac674c45e51aec010e215ae55a9da913d916aedc38221f8852a9b1",
+				"0x0cd229748b3b4b578f242ab377d5aea7a71667e2754b49ac10b17c4435a6ca35",
+				"0x05f5cff24d7eb7a428ae9f82334d4e61715b54749f02bee88935d7540c3bb6f5",
+			},
+		},
+	},
+	"bls24-317": {
+		{
+			Width:    2,
+			RoundKey: "0x0a93f75f7c23ed03825b44a5d38374c83c120efd1b0ac0ba0b2170902d3dfaa5",
+			Expected: []string{
+				"0x26016730e8597e68abcd843b476c7088c9be28f534c2086e3dd5da43075caea2",
+				"0x3170ec8a486512524764413b05f6e271ea55ad70fd087e8f08385d30ae8fa479",
+			},
+		},
+		{
+			Width:    3,
+			RoundKey: "0x3a4e82b1b6d28e8fded0eb60dc11c74edf0cf96edf8763b5574e95020024ae14",
+			Expected: []string{
+				"0x3e392bea8d5028a387586eeba19ae5e28fc391493a18e5f7ad14d7b6e6b64e3c",
+				"0x1d148251442329b02b5c991c32cd96079a677fb0ca880f304a07f44818e9d772",
+				"0x188640f6459619becec73a41d651368aec06016f5c4859f278aae0a8d547c680",
+			},
+		},
+	},
+	"bn254": {
+		{
+			Width:    2,
+			RoundKey: "0x09c46e9ec68e9bd4fe1faaba294cba38a71aa177534cdd1b6c7dc0dbd0abd7a7",
+			Expected: []string{
+				"0x1d01e56f49579cec72319e145f06f6177f6c5253206e78c2689781452a31878b",
+				"0x0d189ec589c41b8cffa88cfc523618a055abe8192c70f75aa72fc514560f6c61",
+			},
+		},
+		{
+			Width:    3,
+			RoundKey: "0x1d066a255517b7fd8bddd3a93f7804ef7f8fcde48bb4c37a59a09a1a97052816",
+			Expected: []string{
+				"0x0bb61d24daca55eebcb1929a82650f328134334da98ea4f847f760054f4a3033",
+				"0x303b6f7c86d043bfcbcc80214f26a30277a15d3f74ca654992defe7ff8d03570",
+				"0x1ed25194542b12eef8617361c3ba7c52e660b145994427cc86296242cf766ec8",
+			},
+		},
+	},
+	"bw6-633": {
+		{
+			Width:    2,
+			RoundKey: "0x0176fff78f5d0095d809478d82b2d88ee5b6969ed9decea607fb5d891206ec683422d70581f41b2b",
+			Expected: []string{
+				"0x014abb69111876dbf3bbe0ec2a7e13dee613ff47cb657fa2b3ef096d86c3eb9846af1d54c35812ee",
+				"0x0439f3199f36a3fd26f2d562252270b9d19ad9ba08e40e5abd4ae38a5aca0cac7b8e2a8e5fa4baee",
+			},
+		},
+		{
+			Width:    3,
+			RoundKey: "0x04b65e9cdfdafe7d5ad3d15f0d230ee53d4f94526621bfe29d645052f975880ccfb5599ce14662c3",
+			Expected: []string{
+				"0x048051bbd88aba33fde79fcabe61336e3ea1a65ee11a61398569ab11de084106349cd6f28c77625d",
+				"0x044fb5c2afc450a6d01969e8a474cda1294ed056753a153eb665e26e17905b9387bdda231e6b9e68",
+				"0x020e11c345ca97df87c0ca3574adcecb5b836d0b709454a01c79f2fc7b897fdab4a3de57479d13d2",
+			},
+		},
+	},
+	"bw6-756": {
+		{
+			Width:    2,
+			RoundKey: "0x033271f64fce579c46b484676f45ddb8fea5ba218ee623b2381ae42e66b65c12352577049adf4767154ba143145405ab",
+			Expected: []string{
+				"0x033109eab0374df908c1932d019f28032388cd7cf2ec9ab61a03e9d88f2ff4742905c632a35659398084c2ad6df09a57",
+				"0x00d41e1e87b41095765bc784d5af73cd0bd9e5942a7f5055c509eb52c4fa9c3f5707d1ebd2e7765d96da40baa7339a69",
+			},
+		},
+		{
+			Width:    3,
+			RoundKey: "0x03a6f0ae971838b5c8c39f41615f26d5bc02dbf8f113bca164758055b4bf6286cfc261e960db09b7bf0581c0278f8112",
+			Expected: []string{
+				"0x01c3da1db7d8133bc6ad6dc626ac55b00beefe2d77ad16f4edfa92dbecac1d8aba1067bcba17d09ddb52751daf7417a8",
+				"0x021f435b2c37cffadbc91e03c513a5eed785f3dbb8b5f67f1d5e4eb611f353520e51562c11032e5eb57a9892d7908021",
+				"0x00cc9f8b3c92998f5b5374d07a7a5cf0a01af14c171919e1abe40dd9a3b7caadd89c7ef76d6e5d42634194e7b9adf027",
+			},
+		},
+	},
+	"bw6-761": {
+		{
+			Width:    2,
+			RoundKey: "0x00220a0adef4959539547c65abc32e7212b0d9a8c43eb7db8a60d977846e875f185c607f22f6f3682c2f2dd325ff5bb5",
+			Expected: []string{
+				"0x00032fa28deb8e7eba2b1b2a83d66ef68471022e4ac9070dc2d1fcc18c11d7f22aad68a55f8b8eebc4c57887915b18ea",
+				"0x007ad2f19c2594e8f5f0da62787f615c5101f4e774ed71df3ca7c1da0480c2863b801534850013b987fcc882a8a020b4",
+			},
+		},
+		{
+			Width:    3,
+			RoundKey: "0x016459e35fcfa367c842dc40935289915332895c129d33092d67d51dcec53339306b10c3028b4d30837d4be092d18e00",
+			Expected: []string{
+				"0x00e8876fcebcaeb170b03a130a01ce112dec4a65042f295665bc74ca057c20c451e9c2c9ead43288a6ec94fd84e714d3",
+				"0x0098a8b5e845c1d9943cf9499f08d710d459dfbac6bec3baf77a5ad1ed07acabe9cb7c89eda40582620f6d5eb448d2f8",
+				"0x00824f422b926b93b0f0501116ea57a09110e8cc4e7eca97db9e3e9c4e617d510b7d593a661f91f1cfc7dbdde3a1c671",
+			},
+		},
+	},
+}
+
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+
+	conf.Package = "poseidon2"
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "poseidon2.go"), Templates: []string{"poseidon2.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash.go"), Templates: []string{"hash.go.tmpl"}},
+		{File: filepath.Join(baseDir, "poseidon2_test.go"), Templates: []string{"poseidon2.test.go.tmpl"}},
+	}
+
+	data := poseidon2Config{Curve: conf, Vectors: referenceVectors[conf.Name]}
+	return bgen.Generate(data, conf.Package, "./crypto/hash/poseidon2/template", entries...)
+
+}
@@ -0,0 +1,106 @@
+package rpo
+
+import (
+	"math/big"
+	"path/filepath"
+
+	"github.com/consensys/bavard"
+	"github.com/consensys/gnark-crypto/internal/generator/config"
+)
+
+// securityLevel is the security level λ targeted by the instances
+const securityLevel = 128
+
+// rpoConfig describes the instance of RPO generated for a field
+type rpoConfig struct {
+	Name             string // curve or field name, used in the descriptions
+	Package          string
+	FieldPackagePath string
+	Modulus          string // decimal, used to derive the round constants
+
+	Width, Capacity, DigestSize int
+	NbRounds                    int
+	Alpha                       uint64
+	AlphaInv                    string // α⁻¹ (mod p-1), decimal
+
+	// CirculantMDS is the first row of the circulant MDS matrix; if empty, the
+	// MDS matrix is the Cauchy matrix 1/(i+j+1)
+	CirculantMDS []uint64
+}
+
+// Generate generates RPO on the field fr of the curve, with a state of
+// width 3, and a capacity of 1 element.
+func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	c := newRPOConfig(conf.Name, "github.com/consensys/gnark-crypto/ecc/"+conf.Name+"/fr", conf.FrInfo.Modulus(), 3, 1, 1)
+	return generate(c, baseDir, bgen)
+}
+
+// GenerateGoldilocks generates RPO on goldilocks with the parameters of RPO-256:
+// a state of width 12, a capacity of 4 elements, and digests of 4 elements.
+func GenerateGoldilocks(baseDir string, bgen *bavard.BatchGenerator) error {
+	modulus, _ := new(big.Int).SetString("18446744069414584321", 10)
+	c := newRPOConfig("goldilocks", "github.com/consensys/gnark-crypto/field/goldilocks", modulus, 12, 4, 4)
+	c.CirculantMDS = []uint64{7, 23, 8, 26, 13, 10, 9, 7, 6, 22, 21, 8}
+	return generate(c, baseDir, bgen)
+}
+
+func generate(c rpoConfig, baseDir string, bgen *bavard.BatchGenerator) error {
+	entries := []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "rpo.go"), Templates: []string{"rpo.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash.go"), Templates: []string{"hash.go.tmpl"}},
+		{File: filepath.Join(baseDir, "rpo_test.go"), Templates: []string{"rpo.test.go.tmpl"}},
+	}
+	return bgen.Generate(c, c.Package, "./crypto/hash/rpo/template", entries...)
+}
+
+func newRPOConfig(name, fieldPackagePath string, modulus *big.Int, width, capacity, digestSize int) rpoConfig {
+	c := rpoConfig{
+		Name:             name,
+		Package:          "rpo",
+		FieldPackagePath: fieldPackagePath,
+		Modulus:          modulus.String(),
+		Width:            width,
+		Capacity:         capacity,
+		DigestSize:       digestSize,
+	}
+
+	// α is the smallest integer such that x ↦ xᵅ is a permutation
+	var pMinusOne, gcd, alpha big.Int
+	pMinusOne.Sub(modulus, big.NewInt(1))
+	for a := int64(3); ; a++ {
+		alpha.SetInt64(a)
+		if gcd.GCD(nil, nil, &alpha, &pMinusOne).IsInt64() && gcd.Int64() == 1 {
+			break
+		}
+	}
+	c.Alpha = alpha.Uint64()
+	c.AlphaInv = new(big.Int).ModInverse(&alpha, &pMinusOne).String()
+	c.NbRounds = nbRounds(width, capacity, c.Alpha)
+
+	return c
+}
+
+// nbRounds returns the number of rounds ensuring securityLevel bits of security
+// against Gröbner basis attacks, following the Rescue-Prime specification, with
+// the 40% security margin of RPO (which gives the 7 rounds of RPO-256).
+func nbRounds(m, capacity int, alpha uint64) int {
+	rate := m - capacity
+	var target, b big.Int
+	target.Lsh(big.NewInt(1), securityLevel)
+
+	l1 := 1
+	for ; l1 < 25; l1++ {
+		v := int64(m*(l1-1) + rate)
+		dcon := int64((int(alpha)-1)*m*(l1-1)/2 + 2)
+		b.Binomial(v+dcon, v)
+		b.Mul(&b, &b)
+		if b.Cmp(&target) > 0 {
+			break
+		}
+	}
+	if l1 < 5 {
+		l1 = 5
+	}
+	return (14*l1 + 9) / 10
+}
@@ -58,6 +58,11 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 		// entries = append(entries, bavard.Entry{File: filepath.Join(baseDir, "g1_lagrange_test.go"), Templates: []string{"tests/lagrange.go.tmpl"}})
 	}
 
+	entries = append(entries,
+		bavard.Entry{File: filepath.Join(baseDir, "g1_constant_time.go"), Templates: []string{"point_constant_time.go.tmpl"}},
+		bavard.Entry{File: filepath.Join(baseDir, "g1_constant_time_test.go"), Templates: []string{"tests/point_constant_time.go.tmpl"}},
+	)
+
 	g1 := pconf{conf, conf.G1}
 	if err := bgen.Generate(g1, packageName, "./ecc/template", entries...); err != nil {
 		return err
@@ -182,6 +187,8 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	entries = []bavard.Entry{
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal_test.go"), Templates: []string{"tests/marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_table.go"), Templates: []string{"multiexp_table.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_table_test.go"), Templates: []string{"tests/multiexp_table.go.tmpl"}},
 	}
 
 	marshal := []func(*bavard.Bavard) error{bavard.Funcs(funcs)}
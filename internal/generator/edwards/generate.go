@@ -1,19 +1,100 @@
 package edwards
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
 	"path/filepath"
 
 	"github.com/consensys/bavard"
+	field "github.com/consensys/gnark-crypto/field/generator/config"
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 )
 
+// edwardsConfig extends the curve description with the constants of the
+// hash to curve
+type edwardsConfig struct {
+	config.TwistedEdwardsCurve
+
+	// Z is the non-square of the Elligator 2 map, as in RFC 9380 (Appendix H.3)
+	Z int64
+
+	// CofactorLog2 is log₂(cofactor), the cofactor being cleared by doublings
+	CofactorLog2 int
+
+	// Fr is the base field, which is the scalar field of the outer curve
+	Fr *field.FieldConfig
+}
+
 func Generate(conf config.TwistedEdwardsCurve, baseDir string, bgen *bavard.BatchGenerator) error {
+	c, err := newEdwardsConfig(conf)
+	if err != nil {
+		return err
+	}
+
 	entries := []bavard.Entry{
 		{File: filepath.Join(baseDir, "point.go"), Templates: []string{"point.go.tmpl"}},
 		{File: filepath.Join(baseDir, "point_test.go"), Templates: []string{"tests/point.go.tmpl"}},
 		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
 		{File: filepath.Join(baseDir, "curve.go"), Templates: []string{"curve.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash_to_curve.go"), Templates: []string{"hash_to_curve.go.tmpl"}},
+		{File: filepath.Join(baseDir, "hash_to_curve_test.go"), Templates: []string{"tests/hash_to_curve.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp.go"), Templates: []string{"multiexp.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multiexp_test.go"), Templates: []string{"tests/multiexp.go.tmpl"}},
+		{File: filepath.Join(baseDir, "point_constant_time.go"), Templates: []string{"point_constant_time.go.tmpl"}},
+		{File: filepath.Join(baseDir, "point_constant_time_test.go"), Templates: []string{"tests/point_constant_time.go.tmpl"}},
+	}
+
+	return bgen.Generate(c, conf.Package, "./edwards/template", entries...)
+}
+
+func newEdwardsConfig(conf config.TwistedEdwardsCurve) (edwardsConfig, error) {
+	c := edwardsConfig{TwistedEdwardsCurve: conf}
+
+	var modulus *big.Int
+	for _, curve := range config.Curves {
+		if curve.Name == conf.Name {
+			modulus = curve.FrInfo.Modulus()
+			var err error
+			if c.Fr, err = field.NewFieldConfig("fr", "Element", curve.FrModulus, false); err != nil {
+				return c, err
+			}
+		}
+	}
+	if modulus == nil {
+		return c, fmt.Errorf("unknown base field for %s/%s", conf.Name, conf.Package)
+	}
+
+	// Z is the non-square of smallest absolute value, positive first
+	for ctr := int64(1); ; ctr++ {
+		var z big.Int
+		if z.SetInt64(ctr); big.Jacobi(&z, modulus) == -1 {
+			c.Z = ctr
+			break
+		}
+		if z.SetInt64(-ctr).Mod(&z, modulus); big.Jacobi(&z, modulus) == -1 {
+			c.Z = -ctr
+			break
+		}
+	}
+
+	// the Elligator 2 map requires J ≠ 0, where J = 2(a+d)/(a-d) is the
+	// coefficient of the Montgomery form. When ad is a square (e.g. on
+	// Bandersnatch) the Montgomery curve has all its points of order 2, and
+	// the exceptional cases of the rational map are sent to the identity.
+	var a, d big.Int
+	a.SetString(conf.A, 10)
+	d.SetString(conf.D, 10)
+	if a.Add(&a, &d).Mod(&a, modulus).Sign() == 0 {
+		return c, fmt.Errorf("the Elligator 2 map is not defined on %s/%s", conf.Name, conf.Package)
+	}
+
+	var cofactor big.Int
+	cofactor.SetString(conf.Cofactor, 10)
+	if cofactor.BitLen() == 0 || cofactor.TrailingZeroBits() != uint(cofactor.BitLen()-1) {
+		return c, errors.New("the cofactor should be a power of 2")
 	}
+	c.CofactorLog2 = cofactor.BitLen() - 1
 
-	return bgen.Generate(conf, conf.Package, "./edwards/template", entries...)
+	return c, nil
 }
@@ -59,7 +59,7 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	entries = []bavard.Entry{
 		{File: filepath.Join(frDir, "generator.go"), Templates: []string{"fr.generator.go.tmpl"}},
 	}
-	return bgen.GenerateWithOptions(conf, "fr", "./fft/template/", bavardOpts, entries...)
+	return bgen.GenerateWithOptions(conf, filepath.Base(frDir), "./fft/template/", bavardOpts, entries...)
 }
 
 func anyToUint64(x any) uint64 {
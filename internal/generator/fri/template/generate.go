@@ -7,15 +7,59 @@ import (
 	"github.com/consensys/gnark-crypto/internal/generator/config"
 )
 
+// friConfig describes the field on which FRI is generated
+type friConfig struct {
+	Name             string // curve or field name
+	Package          string
+	FieldPackagePath string
+	FieldPackageName string
+	FFTPackagePath   string
+
+	// ExtensionDegree is the degree of the extension from which the folding
+	// challenges are drawn, 1 if they are drawn from the field itself.
+	// Only degrees 1, 2 and 3 are supported.
+	ExtensionDegree      int
+	ExtensionPackagePath string
+}
+
+// Generate generates FRI on the field fr of the curve.
 func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) error {
+	frPath := "github.com/consensys/gnark-crypto/ecc/" + conf.Name + "/fr"
+	c := friConfig{
+		Name:             conf.Name,
+		FieldPackagePath: frPath,
+		FieldPackageName: "fr",
+		FFTPackagePath:   frPath + "/fft",
+		ExtensionDegree:  1,
+	}
+	return generate(c, baseDir, bgen)
+}
+
+// GenerateGoldilocks generates FRI on goldilocks, with the folding challenges
+// drawn from its cubic extension.
+func GenerateGoldilocks(baseDir string, bgen *bavard.BatchGenerator) error {
+	const path = "github.com/consensys/gnark-crypto/field/goldilocks"
+	c := friConfig{
+		Name:                 "goldilocks",
+		FieldPackagePath:     path,
+		FieldPackageName:     "goldilocks",
+		FFTPackagePath:       path + "/fft",
+		ExtensionDegree:      3,
+		ExtensionPackagePath: path + "/extensions",
+	}
+	return generate(c, baseDir, bgen)
+}
+
+func generate(c friConfig, baseDir string, bgen *bavard.BatchGenerator) error {
 
 	// fri commitment scheme
-	conf.Package = "fri"
+	c.Package = "fri"
 	entries := []bavard.Entry{
 		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
 		{File: filepath.Join(baseDir, "fri.go"), Templates: []string{"fri.go.tmpl"}},
+		{File: filepath.Join(baseDir, "options.go"), Templates: []string{"options.go.tmpl"}},
 		{File: filepath.Join(baseDir, "fri_test.go"), Templates: []string{"fri.test.go.tmpl"}},
 	}
-	return bgen.Generate(conf, conf.Package, "./fri/template/", entries...)
+	return bgen.Generate(c, c.Package, "./fri/template/", entries...)
 
 }
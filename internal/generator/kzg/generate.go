@@ -13,11 +13,45 @@ func Generate(conf config.Curve, baseDir string, bgen *bavard.BatchGenerator) er
 	conf.Package = "kzg"
 	entries := []bavard.Entry{
 		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ceremony.go"), Templates: []string{"ceremony.go.tmpl"}},
+		{File: filepath.Join(baseDir, "ceremony_test.go"), Templates: []string{"ceremony.test.go.tmpl"}},
+		{File: filepath.Join(baseDir, "fk20.go"), Templates: []string{"fk20.go.tmpl"}},
+		{File: filepath.Join(baseDir, "fk20_test.go"), Templates: []string{"fk20.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "kzg.go"), Templates: []string{"kzg.go.tmpl"}},
 		{File: filepath.Join(baseDir, "kzg_test.go"), Templates: []string{"kzg.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "shplonk.go"), Templates: []string{"shplonk.go.tmpl"}},
+		{File: filepath.Join(baseDir, "shplonk_test.go"), Templates: []string{"shplonk.test.go.tmpl"}},
 		{File: filepath.Join(baseDir, "utils.go"), Templates: []string{"utils.go.tmpl"}},
 	}
-	return bgen.Generate(conf, conf.Package, "./kzg/template/", entries...)
+
+	// importers and exporters of snarkjs Powers-of-Tau ceremonies, on the
+	// curves supported by snarkjs
+	if conf.Equal(config.BN254) || conf.Equal(config.BLS12_381) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "ptau.go"), Templates: []string{"ptau.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "ptau_test.go"), Templates: []string{"ptau.test.go.tmpl"}},
+		)
+	}
+	if conf.Equal(config.BLS12_381) {
+		entries = append(entries,
+			bavard.Entry{File: filepath.Join(baseDir, "ethereum.go"), Templates: []string{"ethereum.go.tmpl"}},
+			bavard.Entry{File: filepath.Join(baseDir, "ethereum_test.go"), Templates: []string{"ethereum.test.go.tmpl"}},
+		)
+	}
+	if err := bgen.Generate(conf, conf.Package, "./kzg/template/", entries...); err != nil {
+		return err
+	}
+
+	// multilinear kzg commitment scheme
+	conf.Package = "multilinear"
+	baseDir = filepath.Join(baseDir, conf.Package)
+	entries = []bavard.Entry{
+		{File: filepath.Join(baseDir, "doc.go"), Templates: []string{"doc.go.tmpl"}},
+		{File: filepath.Join(baseDir, "marshal.go"), Templates: []string{"marshal.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multilinear.go"), Templates: []string{"multilinear.go.tmpl"}},
+		{File: filepath.Join(baseDir, "multilinear_test.go"), Templates: []string{"multilinear.test.go.tmpl"}},
+	}
+	return bgen.Generate(conf, conf.Package, "./kzg/template/multilinear/", entries...)
 
 }
@@ -0,0 +1,216 @@
+// Writes the .ptau test vectors of bn254 and bls12-381, independently of the
+// Go code: the field and curve arithmetic is done here with BigInt, and the
+// file layout follows the writer of snarkjs (powersoftau_new.js).
+//
+// Usage: node ptau.js
+
+"use strict";
+
+const fs = require("fs");
+const path = require("path");
+
+// secrets and powers of the vectors
+const POWER = 2;
+const CEREMONY_POWER = 28;
+const TAU = 5n;
+const ALPHA = 3n;
+const BETA = 2n;
+
+const curves = {
+    bn254: {
+        q: 0x30644e72e131a029b85045b68181585d97816a916871ca8d3c208c16d87cfd47n,
+        r: 0x30644e72e131a029b85045b68181585d2833e84879b9709143e1f593f0000001n,
+        g1: [1n, 2n],
+        g2: [
+            [10857046999023057135944570762232829481370756359578518086990519993285655852781n,
+                11559732032986387107991004021392285783925812861821192530917403151452391805634n],
+            [8495653923123431417604973247489272438418190587263600148770280649306958101930n,
+                4082367875863433681332203403145435568316851327593401208105741076214120093531n],
+        ],
+    },
+    "bls12-381": {
+        q: 0x1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaabn,
+        r: 0x73eda753299d7d483339d80809a1d80553bda402fffe5bfeffffffff00000001n,
+        g1: [
+            0x17f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bbn,
+            0x08b3f481e3aaa0f1a09e30ed741d8ae4fcf5e095d5d00af600db18cb2c04b3edd03cc744a2888ae40caa232946c5e7e1n,
+        ],
+        g2: [
+            [0x024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8n,
+                0x13e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7en],
+            [0x0ce5d527727d6e118cc9cdc6da2e351aadfd9baa8cbdd3a76d429a695160d12c923ac9cc3baca289e193548608b82801n,
+                0x0606c4a02ea734cc32acd2b02bc28b99cb3e287e85a763af267492ab572e99ab3f370d275cec1da1aaa9075ff05f79ben],
+        ],
+    },
+};
+
+function mod(a, q) {
+    const r = a % q;
+    return r < 0n ? r + q : r;
+}
+
+function pow(b, e, q) {
+    let res = 1n;
+    b = mod(b, q);
+    while (e > 0n) {
+        if (e & 1n) res = (res * b) % q;
+        b = (b * b) % q;
+        e >>= 1n;
+    }
+    return res;
+}
+
+// Fp and Fp2 = Fp[u]/(u²+1), elements of Fp2 being [c0, c1]
+function fields(q) {
+    const fp = {
+        zero: 0n,
+        isZero: (a) => a === 0n,
+        eq: (a, b) => a === b,
+        add: (a, b) => mod(a + b, q),
+        sub: (a, b) => mod(a - b, q),
+        mul: (a, b) => mod(a * b, q),
+        inv: (a) => pow(a, q - 2n, q),
+        small: (k) => mod(k, q),
+    };
+    const fp2 = {
+        zero: [0n, 0n],
+        isZero: (a) => a[0] === 0n && a[1] === 0n,
+        eq: (a, b) => a[0] === b[0] && a[1] === b[1],
+        add: (a, b) => [mod(a[0] + b[0], q), mod(a[1] + b[1], q)],
+        sub: (a, b) => [mod(a[0] - b[0], q), mod(a[1] - b[1], q)],
+        mul: (a, b) => [mod(a[0] * b[0] - a[1] * b[1], q), mod(a[0] * b[1] + a[1] * b[0], q)],
+        inv: (a) => {
+            const n = fp.inv(mod(a[0] * a[0] + a[1] * a[1], q));
+            return [mod(a[0] * n, q), mod(-a[1] * n, q)];
+        },
+        small: (k) => [mod(k, q), 0n],
+    };
+    return { fp, fp2 };
+}
+
+// affine points of y² = x³ + b, null being the point at infinity
+function group(F) {
+    const add = (p, s) => {
+        if (p === null) return s;
+        if (s === null) return p;
+        let l;
+        if (F.eq(p[0], s[0])) {
+            if (!F.eq(p[1], s[1]) || F.isZero(p[1])) return null;
+            const x2 = F.mul(p[0], p[0]);
+            l = F.mul(F.add(F.add(x2, x2), x2), F.inv(F.add(p[1], p[1])));
+        } else {
+            l = F.mul(F.sub(s[1], p[1]), F.inv(F.sub(s[0], p[0])));
+        }
+        const x = F.sub(F.sub(F.mul(l, l), p[0]), s[0]);
+        const y = F.sub(F.mul(l, F.sub(p[0], x)), p[1]);
+        return [x, y];
+    };
+    const mul = (p, k) => {
+        let res = null;
+        for (let i = BigInt(k.toString(2).length) - 1n; i >= 0n; i--) {
+            res = add(res, res);
+            if ((k >> i) & 1n) res = add(res, p);
+        }
+        return res;
+    };
+    return { add, mul };
+}
+
+// writer of the binary file, with the integers in little-endian
+class Writer {
+    constructor() {
+        this.chunks = [];
+    }
+    bytes(b) {
+        this.chunks.push(Buffer.from(b));
+    }
+    u32(v) {
+        const b = Buffer.alloc(4);
+        b.writeUInt32LE(v);
+        this.bytes(b);
+    }
+    u64(v) {
+        const b = Buffer.alloc(8);
+        b.writeBigUInt64LE(BigInt(v));
+        this.bytes(b);
+    }
+    // bigint writes v on n8 bytes, little-endian
+    bigint(v, n8) {
+        const b = Buffer.alloc(n8);
+        for (let i = 0; i < n8; i++) {
+            b[i] = Number(v & 0xffn);
+            v >>= 8n;
+        }
+        this.bytes(b);
+    }
+    section(id, f) {
+        const w = new Writer();
+        f(w);
+        const content = Buffer.concat(w.chunks);
+        this.u32(id);
+        this.u64(content.length);
+        this.bytes(content);
+    }
+    buffer() {
+        return Buffer.concat(this.chunks);
+    }
+}
+
+function ptau(c) {
+    const { fp, fp2 } = fields(c.q);
+    const G1 = group(fp);
+    const G2 = group(fp2);
+
+    // as ffjavascript: n8 bytes per element, in Montgomery form 2^(8⋅n8)
+    const n8 = (Math.floor((c.q.toString(2).length - 1) / 64) + 1) * 8;
+    const R = mod(1n << BigInt(8 * n8), c.q);
+    const putFp = (w, e) => w.bigint(mod(e * R, c.q), n8);
+    const putG1 = (w, p) => {
+        if (p === null) p = [0n, 0n];
+        putFp(w, p[0]);
+        putFp(w, p[1]);
+    };
+    const putG2 = (w, p) => {
+        if (p === null) p = [[0n, 0n], [0n, 0n]];
+        putFp(w, p[0][0]);
+        putFp(w, p[0][1]);
+        putFp(w, p[1][0]);
+        putFp(w, p[1][1]);
+    };
+
+    // [c⋅τⁱ] for i < n
+    const powers = (G, g, k, n) => {
+        const res = [];
+        let s = mod(k, c.r);
+        for (let i = 0; i < n; i++) {
+            res.push(G.mul(g, s));
+            s = mod(s * TAU, c.r);
+        }
+        return res;
+    };
+
+    const n = 2 ** POWER;
+    const w = new Writer();
+    w.bytes(Buffer.from("ptau"));
+    w.u32(1);
+    w.u32(7);
+    w.section(1, (s) => {
+        s.u32(n8);
+        s.bigint(c.q, n8);
+        s.u32(POWER);
+        s.u32(CEREMONY_POWER);
+    });
+    w.section(2, (s) => powers(G1, c.g1, 1n, 2 * n - 1).forEach((p) => putG1(s, p)));
+    w.section(3, (s) => powers(G2, c.g2, 1n, n).forEach((p) => putG2(s, p)));
+    w.section(4, (s) => powers(G1, c.g1, ALPHA, n).forEach((p) => putG1(s, p)));
+    w.section(5, (s) => powers(G1, c.g1, BETA, n).forEach((p) => putG1(s, p)));
+    w.section(6, (s) => putG2(s, G2.mul(c.g2, BETA)));
+    w.section(7, (s) => s.u32(0));
+    return w.buffer();
+}
+
+for (const name of Object.keys(curves)) {
+    const file = path.join(__dirname, name + ".ptau");
+    fs.writeFileSync(file, ptau(curves[name]));
+    console.log("wrote " + file);
+}
@@ -10,8 +10,12 @@ import (
 	"github.com/consensys/bavard"
 	"github.com/consensys/gnark-crypto/field/generator"
 	field "github.com/consensys/gnark-crypto/field/generator/config"
+	"github.com/consensys/gnark-crypto/internal/generator/bls"
 	"github.com/consensys/gnark-crypto/internal/generator/config"
+	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/anemoi"
 	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/mimc"
+	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/poseidon2"
+	"github.com/consensys/gnark-crypto/internal/generator/crypto/hash/rpo"
 	"github.com/consensys/gnark-crypto/internal/generator/ecc"
 	"github.com/consensys/gnark-crypto/internal/generator/ecdsa"
 	"github.com/consensys/gnark-crypto/internal/generator/edwards"
@@ -86,6 +90,11 @@ func main() {
 			// generate pairing tests
 			assertNoError(pairing.Generate(conf, curveDir, bgen))
 
+			if conf.Equal(config.BLS12_381) || conf.Equal(config.BLS12_377) {
+				// generate bls signatures
+				assertNoError(bls.Generate(conf, curveDir, bgen))
+			}
+
 			// generate fri on fr
 			assertNoError(fri.Generate(conf, filepath.Join(curveDir, "fr", "fri"), bgen))
 
@@ -111,6 +120,17 @@ func main() {
 			// generate mimc on fr
 			assertNoError(mimc.Generate(conf, filepath.Join(curveDir, "fr", "mimc"), bgen))
 
+			// generate poseidon2 on fr
+			assertNoError(poseidon2.Generate(conf, filepath.Join(curveDir, "fr", "poseidon2"), bgen))
+
+			// generate rpo on fr
+			assertNoError(rpo.Generate(conf, filepath.Join(curveDir, "fr", "rpo"), bgen))
+
+			if conf.Equal(config.BN254) || conf.Equal(config.BLS12_381) {
+				// generate anemoi on fr
+				assertNoError(anemoi.Generate(conf, filepath.Join(curveDir, "fr", "anemoi"), bgen))
+			}
+
 			frInfo := config.FieldDependency{
 				FieldPackagePath: "github.com/consensys/gnark-crypto/ecc/" + conf.Name + "/fr",
 				FieldPackageName: "fr",
@@ -172,6 +192,39 @@ func main() {
 
 	}
 
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		// generate fft on goldilocks
+		conf := config.Curve{Name: "goldilocks"}
+		assertNoError(fft.Generate(conf, filepath.Join(baseDir, "field", "goldilocks", "fft"), bgen))
+
+		// generate polynomial on goldilocks
+		goldilocksInfo := config.FieldDependency{
+			FieldPackagePath: "github.com/consensys/gnark-crypto/field/goldilocks",
+			FieldPackageName: "goldilocks",
+			ElementType:      "goldilocks.Element",
+		}
+		assertNoError(polynomial.Generate(goldilocksInfo, filepath.Join(baseDir, "field", "goldilocks", "polynomial"), true, bgen))
+
+		// generate rpo on goldilocks
+		assertNoError(rpo.GenerateGoldilocks(filepath.Join(baseDir, "field", "goldilocks", "rpo"), bgen))
+
+		// generate fri on goldilocks
+		assertNoError(fri.GenerateGoldilocks(filepath.Join(baseDir, "field", "goldilocks", "fri"), bgen))
+	}()
+
+	// generate fft on the 31-bit fields with a large enough 2-adicity
+	for _, name := range []string{"babybear", "koalabear"} {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			conf := config.Curve{Name: name}
+			assertNoError(fft.Generate(conf, filepath.Join(baseDir, "field", name, "fft"), bgen))
+		}(name)
+	}
+
 	wg.Add(1)
 	go func() {
 		defer wg.Done()
@@ -0,0 +1,54 @@
+/*
+Copyright © 2020 ConsenSys
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bls
+
+import (
+	"io"
+
+	"github.com/consensys/gnark-crypto/ecc"
+	minpk_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/bls/minpk"
+	minsig_bls12377 "github.com/consensys/gnark-crypto/ecc/bls12-377/bls/minsig"
+	minpk_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/bls/minpk"
+	minsig_bls12381 "github.com/consensys/gnark-crypto/ecc/bls12-381/bls/minsig"
+	"github.com/consensys/gnark-crypto/signature"
+)
+
+// New takes a source of randomness and returns a new key pair for BLS
+// signatures with public keys in G1 and signatures in G2 (minimal-pubkey-size variant)
+func New(ss ecc.ID, r io.Reader) (signature.Signer, error) {
+	switch ss {
+	case ecc.BLS12_381:
+		return minpk_bls12381.GenerateKey(r)
+	case ecc.BLS12_377:
+		return minpk_bls12377.GenerateKey(r)
+	default:
+		panic("not implemented")
+	}
+}
+
+// NewMinSig takes a source of randomness and returns a new key pair for BLS
+// signatures with public keys in G2 and signatures in G1 (minimal-signature-size variant)
+func NewMinSig(ss ecc.ID, r io.Reader) (signature.Signer, error) {
+	switch ss {
+	case ecc.BLS12_381:
+		return minsig_bls12381.GenerateKey(r)
+	case ecc.BLS12_377:
+		return minsig_bls12377.GenerateKey(r)
+	default:
+		panic("not implemented")
+	}
+}
@@ -0,0 +1,70 @@
+package testutils
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// TimingLeakage looks for a dependency of the execution time of f on the class
+// (0 or 1) of its input, in the style of dudect (Reparaz, Balasch and
+// Verbauwhede, https://eprint.iacr.org/2016/1123).
+//
+// f(class, i) is called n times for each class, in a random order, i being the
+// index of the call in its class. The execution times are cropped at several
+// percentiles, to remove the measurements disturbed by the environment, and
+// the largest absolute value of the statistic of Welch's t-test between the
+// two classes is returned. A value above 4.5 is evidence of a leak.
+func TimingLeakage(n int, f func(class, i int)) float64 {
+	classes := make([]int, 2*n)
+	for i := n; i < len(classes); i++ {
+		classes[i] = 1
+	}
+	rand.Shuffle(len(classes), func(i, j int) { classes[i], classes[j] = classes[j], classes[i] })
+
+	times := make([]float64, len(classes))
+	var counts [2]int
+	for k, class := range classes {
+		start := time.Now()
+		f(class, counts[class])
+		times[k] = float64(time.Since(start))
+		counts[class]++
+	}
+
+	sorted := append([]float64(nil), times...)
+	sort.Float64s(sorted)
+	res := 0.0
+	for _, p := range []float64{0.5, 0.75, 0.9, 0.95, 0.99, 1} {
+		threshold := sorted[int(p*float64(len(sorted)-1))]
+		if t := math.Abs(welchT(times, classes, threshold)); t > res {
+			res = t
+		}
+	}
+	return res
+}
+
+// welchT returns the statistic of Welch's t-test between the times of the
+// classes 0 and 1 which are not above threshold.
+func welchT(times []float64, classes []int, threshold float64) float64 {
+	var n, mean, m2 [2]float64
+	for k, x := range times {
+		if x > threshold {
+			continue
+		}
+		// Welford's online algorithm
+		c := classes[k]
+		n[c]++
+		delta := x - mean[c]
+		mean[c] += delta / n[c]
+		m2[c] += delta * (x - mean[c])
+	}
+	if n[0] < 2 || n[1] < 2 {
+		return 0
+	}
+	v := m2[0]/(n[0]-1)/n[0] + m2[1]/(n[1]-1)/n[1]
+	if v == 0 {
+		return 0
+	}
+	return (mean[0] - mean[1]) / math.Sqrt(v)
+}